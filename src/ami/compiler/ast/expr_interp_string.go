@@ -0,0 +1,15 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// InterpStringLit represents an interpolated string literal: "a ${x} b".
+// Parts holds the literal text around the embedded expressions, so
+// len(Parts) == len(Exprs)+1; the value is Parts[0] + Exprs[0] + Parts[1] + ...
+type InterpStringLit struct {
+    Pos   source.Position
+    Parts []string
+    Exprs []Expr
+}
+
+func (*InterpStringLit) isNode() {}
+func (*InterpStringLit) isExpr() {}
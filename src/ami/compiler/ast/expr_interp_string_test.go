@@ -0,0 +1,8 @@
+package ast
+
+import "testing"
+
+func Test_expr_interp_string_Exists(t *testing.T) {
+    var e Expr = &InterpStringLit{Parts: []string{"a ", ""}, Exprs: []Expr{&IdentExpr{Name: "x"}}}
+    _ = e
+}
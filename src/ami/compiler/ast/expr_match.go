@@ -0,0 +1,17 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// MatchExpr represents: match X { pattern[, pattern] => body ... }
+// As a statement it appears as the X of an ExprStmt and arms may use blocks;
+// as an expression (var init, assignment, return) every arm yields a Value.
+type MatchExpr struct {
+    Pos    source.Position
+    X      Expr
+    LBrace source.Position
+    Arms   []MatchArm
+    RBrace source.Position
+}
+
+func (*MatchExpr) isNode() {}
+func (*MatchExpr) isExpr() {}
@@ -0,0 +1,8 @@
+package ast
+
+import "testing"
+
+func Test_expr_match_Exists(t *testing.T) {
+	var e Expr = &MatchExpr{X: &IdentExpr{Name: "c"}, Arms: []MatchArm{{Patterns: []MatchPattern{{Kind: "wildcard", Name: "_"}}, Value: &NumberLit{Text: "0"}}}}
+	_ = e
+}
@@ -0,0 +1,13 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// PropagateExpr represents the error propagation operator: X?
+// X is a call whose last result is an error; a non-nil error returns early.
+type PropagateExpr struct {
+    Pos source.Position // position of '?'
+    X   Expr
+}
+
+func (*PropagateExpr) isNode() {}
+func (*PropagateExpr) isExpr() {}
@@ -0,0 +1,8 @@
+package ast
+
+import "testing"
+
+func Test_expr_propagate_Exists(t *testing.T) {
+    var e Expr = &PropagateExpr{X: &CallExpr{Name: "f"}}
+    _ = e
+}
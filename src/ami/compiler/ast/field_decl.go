@@ -0,0 +1,11 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// FieldDecl is a single named field within a struct type declaration.
+type FieldDecl struct {
+    NamePos source.Position
+    Name    string
+    Type    string
+    TypePos source.Position
+}
@@ -0,0 +1,7 @@
+package ast
+
+import "testing"
+
+func Test_field_decl_Exists(t *testing.T) {
+	_ = FieldDecl{Name: "id", Type: "string"}
+}
@@ -0,0 +1,17 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// MatchPattern is a single pattern of a match arm. Kind is one of:
+//   "wildcard"  _
+//   "none"      None (Optional absent)
+//   "some"      Some(binding) (Optional present)
+//   "variant"   Type(binding) (union alternative, value bound)
+//   "name"      Name or Enum.Member (enum member or union alternative)
+type MatchPattern struct {
+    Pos        source.Position
+    Kind       string
+    Name       string
+    Binding    string
+    BindingPos source.Position
+}
@@ -0,0 +1,7 @@
+package ast
+
+import "testing"
+
+func Test_match_pattern_Exists(t *testing.T) {
+	_ = MatchPattern{Kind: "some", Name: "Some", Binding: "v"}
+}
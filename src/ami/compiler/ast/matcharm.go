@@ -0,0 +1,12 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// MatchArm is one arm of a match: Patterns => Body | Value.
+// Exactly one of Body (a block) and Value (an expression) is set.
+type MatchArm struct {
+    Pos      source.Position
+    Patterns []MatchPattern
+    Body     *BlockStmt
+    Value    Expr
+}
@@ -0,0 +1,7 @@
+package ast
+
+import "testing"
+
+func Test_matcharm_Exists(t *testing.T) {
+	_ = MatchArm{Body: &BlockStmt{}}
+}
@@ -0,0 +1,12 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// BreakStmt represents: break
+type BreakStmt struct {
+    Pos     source.Position
+    Leading []Comment
+}
+
+func (*BreakStmt) isNode() {}
+func (*BreakStmt) isStmt() {}
@@ -0,0 +1,5 @@
+package ast
+
+import "testing"
+
+func Test_stmt_break_Exists(t *testing.T) {}
@@ -0,0 +1,12 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// ContinueStmt represents: continue
+type ContinueStmt struct {
+    Pos     source.Position
+    Leading []Comment
+}
+
+func (*ContinueStmt) isNode() {}
+func (*ContinueStmt) isStmt() {}
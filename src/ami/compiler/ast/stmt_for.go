@@ -0,0 +1,15 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// ForStmt represents: for [<cond>] { ... }
+// A nil Cond denotes an infinite loop that exits via break or return.
+type ForStmt struct {
+    Pos     source.Position
+    Leading []Comment
+    Cond    Expr // optional
+    Body    *BlockStmt
+}
+
+func (*ForStmt) isNode() {}
+func (*ForStmt) isStmt() {}
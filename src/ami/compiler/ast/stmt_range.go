@@ -0,0 +1,20 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// RangeStmt represents: for <key>[, <value>] := range <x> { ... }
+// Slices bind index and element, maps bind key and value, and sets bind
+// only the element (as Key).
+type RangeStmt struct {
+    Pos      source.Position
+    Leading  []Comment
+    Key      string
+    KeyPos   source.Position
+    Value    string // optional
+    ValuePos source.Position
+    X        Expr
+    Body     *BlockStmt
+}
+
+func (*RangeStmt) isNode() {}
+func (*RangeStmt) isStmt() {}
@@ -0,0 +1,5 @@
+package ast
+
+import "testing"
+
+func Test_stmt_range_Exists(t *testing.T) {}
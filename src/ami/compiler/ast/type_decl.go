@@ -0,0 +1,23 @@
+package ast
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/source"
+
+// TypeDecl represents a package-level type declaration:
+//   type Name struct { field Type ... }
+//   type Name <type>      (defined type)
+//   type Name = <type>    (alias)
+// Type holds the canonical type text; struct bodies are rendered as
+// "Struct{field:Type,...}" in declaration order. Fields is populated only for
+// struct declarations.
+type TypeDecl struct {
+    Pos     source.Position
+    NamePos source.Position
+    Name    string
+    Alias   bool
+    Type    string
+    TypePos source.Position
+    Fields  []FieldDecl
+    Leading []Comment
+}
+
+func (*TypeDecl) isNode() {}
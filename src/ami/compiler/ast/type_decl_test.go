@@ -0,0 +1,18 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func Test_type_decl_Exists(t *testing.T) {
+	var d Decl = &TypeDecl{
+		Pos:     source.Position{},
+		NamePos: source.Position{},
+		Name:    "Order",
+		Type:    "Struct{id:string}",
+		Fields:  []FieldDecl{{Name: "id", Type: "string"}},
+	}
+	_ = d
+}
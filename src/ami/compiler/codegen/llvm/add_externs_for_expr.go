@@ -1,6 +1,7 @@
 package llvm
 
 import (
+    "fmt"
     "strings"
     "github.com/sam-caldwell/ami/src/ami/compiler/ir"
 )
@@ -19,8 +20,20 @@ func addExternsForExpr(e *ModuleEmitter, ex ir.Expr) {
         e.RequireExtern("declare ptr @ami_rt_owned_ptr(ptr)")
     case "ami_rt_owned_new":
         e.RequireExtern("declare ptr @ami_rt_owned_new(i8*, i64)")
-    case "ami_rt_string_len":
-        e.RequireExtern("declare i64 @ami_rt_string_len(ptr)")
+    case "ami_rt_slice_len":
+        e.RequireExtern("declare i64 @ami_rt_slice_len(ptr)")
+    case "ami_rt_set_len":
+        e.RequireExtern("declare i64 @ami_rt_set_len(ptr)")
+    case "ami_rt_map_len":
+        e.RequireExtern("declare i64 @ami_rt_map_len(ptr)")
+    case "ami_rt_error_is_set":
+        e.RequireExtern("declare i1 @ami_rt_error_is_set(ptr)")
+    case "ami_rt_error_make":
+        e.RequireExtern("declare ptr @ami_rt_error_make(ptr, ptr)")
+    case "ami_rt_error_make_data":
+        e.RequireExtern("declare ptr @ami_rt_error_make_data(ptr, ptr, ptr)")
+    case "ami_rt_error_code_str", "ami_rt_error_message", "ami_rt_error_data":
+        e.RequireExtern("declare ptr @" + ex.Callee + "(ptr)")
     case "ami_rt_zeroize_owned":
         e.RequireExtern("declare void @ami_rt_zeroize_owned(ptr)")
     case "ami_rt_sleep_ms":
@@ -121,5 +134,22 @@ func addExternsForExpr(e *ModuleEmitter, ex ir.Expr) {
         e.RequireExtern("declare ptr @ami_rt_bufio_scanner_bytes(i64)")
     case "ami_rt_bufio_scanner_err":
         e.RequireExtern("declare ptr @ami_rt_bufio_scanner_err(i64)")
+    default:
+        // Range loop element accessors: ami_rt_<slice_at|set_at|map_key|map_val>_<abi>
+        if ret := rangeAccessorRet(ex.Callee); ret != "" {
+            e.RequireExtern(fmt.Sprintf("declare %s @%s(ptr, i64)", ret, ex.Callee))
+        }
+        // Match tag readers and payload accessors: ami_rt_<kind>_tag, ami_rt_<union|optional>_val_<abi>
+        if isMatchTag(ex.Callee) {
+            e.RequireExtern(fmt.Sprintf("declare i64 @%s(ptr)", ex.Callee))
+        }
+        if ret := matchAccessorRet(ex.Callee); ret != "" {
+            e.RequireExtern(fmt.Sprintf("declare %s @%s(ptr)", ret, ex.Callee))
+        }
+        // String helpers for interpolation and the strings stdlib; literals copy into Owned handles
+        if decl := stringHelperDecl(ex.Callee); decl != "" { e.RequireExtern(decl) }
+        if _, ok := stringLit(ex); ok { e.RequireExtern("declare ptr @ami_rt_owned_new(i8*, i64)") }
+        // json stdlib helpers: ami_rt_json_<decode|encode>_<abi>
+        if decl := jsonHelperDecl(ex.Callee); decl != "" { e.RequireExtern(decl) }
     }
 }
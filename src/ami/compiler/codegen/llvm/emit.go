@@ -71,7 +71,10 @@ func EmitModuleLLVMForTarget(m ir.Module, triple string) (string, error) {
             ir.Expr{Op: "call", Callee: "ami_rt_opencl_devices"},
             ir.Return{},
         }}}}
-        _ = e.AddFunction(ref)
+        // Private: every unit carries one, and a package's units link together.
+        if s, err := lowerFunction(ref); err == nil {
+            e.funcs = append(e.funcs, strings.Replace(s, "define void", "define private void", 1))
+        }
     }
     // Emit worker core JSON-ABI wrappers for worker-shaped functions
     emitWorkerCores(e, m.Functions)
@@ -1,6 +1,10 @@
 package llvm
 
-import ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+import (
+    "strconv"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
 
 // buildModuleMetaJSON assembles a compact JSON string summarizing module-level
 // runtime-relevant metadata for downstream runtimes (scheduler, buffers, etc.).
@@ -64,7 +68,30 @@ func buildModuleMetaJSON(m ir.Module) string {
                         if mp.PartitionBy != "" { appendQuoted("partitionBy"); appendStr(":"); appendQuoted(mp.PartitionBy); appendStr(",") }
                         if mp.Key != "" { appendQuoted("key"); appendStr(":"); appendQuoted(mp.Key); appendStr(",") }
                         if mp.DedupField != "" { appendQuoted("dedupField"); appendStr(":"); appendQuoted(mp.DedupField); appendStr(",") }
+                        if mp.DedupTTLMs > 0 { appendQuoted("dedupTTLMs"); appendStr(":"); appendInt(mp.DedupTTLMs); appendStr(",") }
+                        if mp.DedupMaxKeys > 0 { appendQuoted("dedupMaxKeys"); appendStr(":"); appendInt(mp.DedupMaxKeys); appendStr(",") }
+                        if mp.DedupMode != "" { appendQuoted("dedupMode"); appendStr(":"); appendQuoted(mp.DedupMode); appendStr(",") }
+                        if mp.DedupFPRate > 0 { appendQuoted("dedupFPRate"); appendStr(":"); appendStr(strconv.FormatFloat(mp.DedupFPRate, 'g', -1, 64)); appendStr(",") }
                         if mp.LatePolicy != "" { appendQuoted("latePolicy"); appendStr(":"); appendQuoted(mp.LatePolicy); appendStr(",") }
+                        // EventWindow/Aggregates
+                        if w := mp.EventWindow; w != nil {
+                            appendQuoted("eventWindow"); appendStr(":{"); appendQuoted("kind"); appendStr(":"); appendQuoted(w.Kind)
+                            if w.SizeMs > 0 { appendStr(","); appendQuoted("sizeMs"); appendStr(":"); appendInt(w.SizeMs) }
+                            if w.SlideMs > 0 { appendStr(","); appendQuoted("slideMs"); appendStr(":"); appendInt(w.SlideMs) }
+                            if w.GapMs > 0 { appendStr(","); appendQuoted("gapMs"); appendStr(":"); appendInt(w.GapMs) }
+                            appendStr("},")
+                        }
+                        if len(mp.Aggregates) > 0 {
+                            appendQuoted("aggregates"); appendStr(":[")
+                            for k, a := range mp.Aggregates {
+                                if k > 0 { appendStr(",") }
+                                appendStr("{"); appendQuoted("fn"); appendStr(":"); appendQuoted(a.Fn)
+                                if a.Field != "" { appendStr(","); appendQuoted("field"); appendStr(":"); appendQuoted(a.Field) }
+                                if a.As != "" { appendStr(","); appendQuoted("as"); appendStr(":"); appendQuoted(a.As) }
+                                appendStr("}")
+                            }
+                            appendStr("],")
+                        }
                         // trim trailing comma if present
                         if len(b) > 0 && b[len(b)-1] == ',' { b = b[:len(b)-1] }
                         appendStr("}")
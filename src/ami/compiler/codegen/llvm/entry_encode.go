@@ -1,7 +1,7 @@
 package llvm
 
 // encodeCString encodes s into an LLVM IR-compatible c"..." byte string, ending with \00.
-// It uses octal escapes (\NNN) and avoids C-style \" for quotes to prevent IR parse issues.
+// It uses hex escapes (\XX) and avoids C-style \" for quotes to prevent IR parse issues.
 func encodeCString(s string) string {
     // append terminator
     b := make([]byte, 0, len(s)+4)
@@ -22,11 +22,9 @@ func encodeCString(s string) string {
             b = append(b, c)
             continue
         }
-        // other bytes: 3-digit octal escape
-        o2 := '0' + ((c >> 6) & 0x7)
-        o1 := '0' + ((c >> 3) & 0x7)
-        o0 := '0' + (c & 0x7)
-        b = append(b, '\\', byte(o2), byte(o1), byte(o0))
+        // other bytes: 2-digit hex escape
+        const hex = "0123456789ABCDEF"
+        b = append(b, '\\', hex[c>>4], hex[c&0xF])
     }
     // trailing NUL
     b = append(b, '\\', '0', '0')
@@ -0,0 +1,39 @@
+package llvm
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestEmitter_ErrorPropagation_ExternsZeroAndRuntime(t *testing.T) {
+    e := ir.Value{ID: "e", Type: "error"}
+    set := ir.Value{ID: "set", Type: "bool"}
+    z := ir.Value{ID: "z", Type: "int"}
+    nilv := ir.Value{ID: "nil0", Type: "ptr"}
+    fn := ir.Function{Name: "F", Results: []ir.Value{{Type: "int"}, {Type: "error"}}, Blocks: []ir.Block{
+        {Name: "entry", Instr: []ir.Instruction{
+            ir.Expr{Op: "call", Callee: "ami_rt_error_make", Args: []ir.Value{{ID: "c", Type: "string"}, {ID: "m", Type: "string"}}, Result: &e},
+            ir.Expr{Op: "call", Callee: "ami_rt_error_is_set", Args: []ir.Value{e}, Result: &set},
+            ir.CondBr{Cond: set, TrueLabel: "perr0", FalseLabel: "pok0"},
+        }},
+        {Name: "perr0", Instr: []ir.Instruction{ir.Expr{Op: "zero", Result: &z}, ir.Return{Values: []ir.Value{z, e}}}},
+        {Name: "pok0", Instr: []ir.Instruction{ir.Expr{Op: "zero", Result: &nilv}, ir.Return{Values: []ir.Value{z, nilv}}}},
+    }}
+    out, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{fn}})
+    if err != nil { t.Fatalf("emit: %v", err) }
+    for _, want := range []string{
+        "declare ptr @ami_rt_error_make(ptr, ptr)",
+        "declare i1 @ami_rt_error_is_set(ptr)",
+        "%set = call i1 @ami_rt_error_is_set(",
+        "%z = add i64 0, 0",
+        "%nil0 = getelementptr i8, ptr null, i64 0",
+    } {
+        if !strings.Contains(out, want) { t.Fatalf("missing %q:\n%s", want, out) }
+    }
+    rt := RuntimeLL("", false)
+    for _, want := range []string{"define i1 @ami_rt_error_is_set(ptr %e)", "define ptr @ami_rt_error_make_data(", "define ptr @ami_rt_error_message("} {
+        if !strings.Contains(rt, want) { t.Fatalf("runtime missing %q", want) }
+    }
+}
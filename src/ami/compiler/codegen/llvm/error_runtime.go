@@ -0,0 +1,70 @@
+package llvm
+
+// errorRuntimeLL returns the error handle ABI and the structured error helpers
+// used by the errors stdlib and compiled workers.
+//
+// Layout: { i32 code; i8* msg; i64 len; ptr codeStr; ptr data }. codeStr is an
+// Owned copy of the errors.New code and data the errors.NewWithData handle; both
+// are null for errors built by ami_rt_error_new. Strings and data are handles in
+// the same form string_to_json and structured_to_json consume.
+//
+// ami_rt_error_to_cstring renders an error with a code as an errors.v1 object
+// ({"schema":"errors.v1","code":...,"message":...[,"data":...]}) and an error
+// without one as its message bytes. The result is NUL-terminated; outlen
+// excludes the terminator.
+func errorRuntimeLL() string {
+    head := "{\"schema\":\"errors.v1\",\"code\":"
+    msgKey := ",\"message\":"
+    dataKey := ",\"data\":"
+    s := "%Error = type { i32, i8*, i64, ptr, ptr }\n\n"
+    s += "@.err.json.head = private constant [" + itoa(len(head)+1) + " x i8] c\"" + encodeCString(head) + "\"\n"
+    s += "@.err.json.message = private constant [" + itoa(len(msgKey)+1) + " x i8] c\"" + encodeCString(msgKey) + "\"\n"
+    s += "@.err.json.data = private constant [" + itoa(len(dataKey)+1) + " x i8] c\"" + encodeCString(dataKey) + "\"\n\n"
+    s += "define ptr @ami_rt_error_new(i32 %code, i8* %msg, i64 %len) {\n" +
+        "entry:\n  %mem = call ptr @malloc(i64 40)\n  ; store code\n  store i32 %code, ptr %mem, align 4\n  ; allocate and copy message\n  %buf = call ptr @malloc(i64 %len)\n  call void @llvm.memcpy.p0.p0.i64(ptr %buf, ptr %msg, i64 %len, i1 false)\n  %mp = getelementptr i8, ptr %mem, i64 8\n  store ptr %buf, ptr %mp, align 8\n  %lp = getelementptr i8, ptr %mem, i64 16\n  store i64 %len, ptr %lp, align 8\n  ; no code string or data yet\n  %cp = getelementptr i8, ptr %mem, i64 24\n  store ptr null, ptr %cp, align 8\n  %dp = getelementptr i8, ptr %mem, i64 32\n  store ptr null, ptr %dp, align 8\n  ret ptr %mem\n}\n\n"
+    s += "define i32 @ami_rt_error_code(ptr %e) {\n" +
+        "entry:\n  %c = load i32, ptr %e, align 4\n  ret i32 %c\n}\n\n"
+    s += "define ptr @ami_rt_error_msg(ptr %e) {\n" +
+        "entry:\n  %mp = getelementptr i8, ptr %e, i64 8\n  %p = load ptr, ptr %mp, align 8\n  ret ptr %p\n}\n\n"
+    s += "define i64 @ami_rt_error_len(ptr %e) {\n" +
+        "entry:\n  %lp = getelementptr i8, ptr %e, i64 16\n  %l = load i64, ptr %lp, align 8\n  ret i64 %l\n}\n\n"
+    // The code string is owned by the error; data belongs to the caller.
+    s += "define void @ami_rt_error_free(ptr %e) {\n" +
+        "entry:\n  %p = call ptr @ami_rt_error_msg(ptr %e)\n  call void @free(ptr %p)\n  %c = call ptr @ami_rt_error_code_str(ptr %e)\n  %hasc = icmp ne ptr %c, null\n  br i1 %hasc, label %freec, label %done\n" +
+        "freec:\n  %cb = call ptr @ami_rt_owned_ptr(ptr %c)\n  call void @free(ptr %cb)\n  call void @free(ptr %c)\n  br label %done\n" +
+        "done:\n  call void @free(ptr %e)\n  ret void\n}\n\n"
+    s += "define i1 @ami_rt_error_is_set(ptr %e) {\nentry:\n  %set = icmp ne ptr %e, null\n  ret i1 %set\n}\n\n"
+    s += "define ptr @ami_rt_error_make(ptr %code, ptr %msg) {\n" +
+        "entry:\n  %hasm = icmp ne ptr %msg, null\n  br i1 %hasm, label %withmsg, label %make\n" +
+        "withmsg:\n  %mb = call ptr @ami_rt_owned_ptr(ptr %msg)\n  %ml = call i64 @ami_rt_owned_len(ptr %msg)\n  br label %make\n" +
+        "make:\n  %p = phi ptr [ null, %entry ], [ %mb, %withmsg ]\n  %n = phi i64 [ 0, %entry ], [ %ml, %withmsg ]\n  %e = call ptr @ami_rt_error_new(i32 0, i8* %p, i64 %n)\n  %hasc = icmp ne ptr %code, null\n  br i1 %hasc, label %withcode, label %done\n" +
+        "withcode:\n  %cb = call ptr @ami_rt_owned_ptr(ptr %code)\n  %cl = call i64 @ami_rt_owned_len(ptr %code)\n  %c = call ptr @ami_rt_owned_new(i8* %cb, i64 %cl)\n  %cp = getelementptr i8, ptr %e, i64 24\n  store ptr %c, ptr %cp, align 8\n  br label %done\n" +
+        "done:\n  ret ptr %e\n}\n\n"
+    s += "define ptr @ami_rt_error_make_data(ptr %code, ptr %msg, ptr %data) {\n" +
+        "entry:\n  %e = call ptr @ami_rt_error_make(ptr %code, ptr %msg)\n  %dp = getelementptr i8, ptr %e, i64 32\n  store ptr %data, ptr %dp, align 8\n  ret ptr %e\n}\n\n"
+    s += "define ptr @ami_rt_error_code_str(ptr %e) {\n" +
+        "entry:\n  %cp = getelementptr i8, ptr %e, i64 24\n  %c = load ptr, ptr %cp, align 8\n  ret ptr %c\n}\n\n"
+    s += "define ptr @ami_rt_error_message(ptr %e) {\n" +
+        "entry:\n  %p = call ptr @ami_rt_error_msg(ptr %e)\n  ret ptr %p\n}\n\n"
+    s += "define ptr @ami_rt_error_data(ptr %e) {\n" +
+        "entry:\n  %dp = getelementptr i8, ptr %e, i64 32\n  %d = load ptr, ptr %dp, align 8\n  ret ptr %d\n}\n\n"
+    // Convert an error handle to a malloc'd, NUL-terminated byte string and set outlen.
+    s += "define ptr @ami_rt_error_to_cstring(ptr %e, i32* %outlen) {\n" +
+        "entry:\n  %tmp = alloca i32, align 4\n  %msg = call ptr @ami_rt_error_msg(ptr %e)\n  %len64 = call i64 @ami_rt_error_len(ptr %e)\n  %code = call ptr @ami_rt_error_code_str(ptr %e)\n  %hasc = icmp ne ptr %code, null\n  br i1 %hasc, label %structured, label %plain\n" +
+        "plain:\n  %len = trunc i64 %len64 to i32\n  %size = add i64 %len64, 1\n  %buf = call ptr @malloc(i64 %size)\n  call void @llvm.memcpy.p0.p0.i64(ptr %buf, ptr %msg, i64 %len64, i1 false)\n  %nul = getelementptr i8, ptr %buf, i64 %len64\n  store i8 0, ptr %nul, align 1\n  store i32 %len, ptr %outlen, align 4\n  ret ptr %buf\n" +
+        "structured:\n  %cj = call ptr @ami_rt_string_to_json(ptr %code, i32* %tmp)\n  %cl32 = load i32, ptr %tmp, align 4\n  %cl = zext i32 %cl32 to i64\n" +
+        "  %mh = call ptr @ami_rt_owned_new(i8* %msg, i64 %len64)\n  %mj = call ptr @ami_rt_string_to_json(ptr %mh, i32* %tmp)\n  %ml32 = load i32, ptr %tmp, align 4\n  %ml = zext i32 %ml32 to i64\n  %mhb = call ptr @ami_rt_owned_ptr(ptr %mh)\n  call void @free(ptr %mhb)\n  call void @free(ptr %mh)\n" +
+        "  %data = call ptr @ami_rt_error_data(ptr %e)\n  %hasd = icmp ne ptr %data, null\n  br i1 %hasd, label %withdata, label %assemble\n" +
+        "withdata:\n  %dj0 = call ptr @ami_rt_structured_to_json(ptr %data, i32* %tmp)\n  %dl32 = load i32, ptr %tmp, align 4\n  %dl0 = zext i32 %dl32 to i64\n  br label %assemble\n" +
+        "assemble:\n  %dj = phi ptr [ null, %structured ], [ %dj0, %withdata ]\n  %dl = phi i64 [ 0, %structured ], [ %dl0, %withdata ]\n  %dk = select i1 %hasd, i64 " + itoa(len(dataKey)) + ", i64 0\n" +
+        "  %t0 = add i64 " + itoa(len(head)+len(msgKey)+1) + ", %cl\n  %t1 = add i64 %t0, %ml\n  %t2 = add i64 %t1, %dk\n  %total = add i64 %t2, %dl\n  %osize = add i64 %total, 1\n  %out = call ptr @malloc(i64 %osize)\n" +
+        "  call void @llvm.memcpy.p0.p0.i64(ptr %out, ptr @.err.json.head, i64 " + itoa(len(head)) + ", i1 false)\n" +
+        "  %o1 = getelementptr i8, ptr %out, i64 " + itoa(len(head)) + "\n  call void @llvm.memcpy.p0.p0.i64(ptr %o1, ptr %cj, i64 %cl, i1 false)\n" +
+        "  %o2 = getelementptr i8, ptr %o1, i64 %cl\n  call void @llvm.memcpy.p0.p0.i64(ptr %o2, ptr @.err.json.message, i64 " + itoa(len(msgKey)) + ", i1 false)\n" +
+        "  %o3 = getelementptr i8, ptr %o2, i64 " + itoa(len(msgKey)) + "\n  call void @llvm.memcpy.p0.p0.i64(ptr %o3, ptr %mj, i64 %ml, i1 false)\n" +
+        "  %o4 = getelementptr i8, ptr %o3, i64 %ml\n  call void @llvm.memcpy.p0.p0.i64(ptr %o4, ptr @.err.json.data, i64 %dk, i1 false)\n" +
+        "  %o5 = getelementptr i8, ptr %o4, i64 %dk\n  call void @llvm.memcpy.p0.p0.i64(ptr %o5, ptr %dj, i64 %dl, i1 false)\n" +
+        "  %o6 = getelementptr i8, ptr %o5, i64 %dl\n  store i8 125, ptr %o6, align 1\n  %o7 = getelementptr i8, ptr %o6, i64 1\n  store i8 0, ptr %o7, align 1\n" +
+        "  call void @free(ptr %cj)\n  call void @free(ptr %mj)\n  call void @free(ptr %dj)\n  %total32 = trunc i64 %total to i32\n  store i32 %total32, ptr %outlen, align 4\n  ret ptr %out\n}\n\n"
+    return s
+}
@@ -0,0 +1,65 @@
+package llvm
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestErrorRuntimeLL_LayoutAndHelpers(t *testing.T) {
+    rt := RuntimeLL("", false)
+    for _, want := range []string{
+        "%Error = type { i32, i8*, i64, ptr, ptr }",
+        "c\"{\\22schema\\22:\\22errors.v1\\22,\\22code\\22:\\00\"",
+        "define ptr @ami_rt_error_code_str(ptr %e)",
+        "define ptr @ami_rt_error_data(ptr %e)",
+        "call ptr @ami_rt_structured_to_json(ptr %data, i32* %tmp)",
+    } {
+        if !strings.Contains(rt, want) { t.Fatalf("runtime missing %q", want) }
+    }
+}
+
+// Runs a pure-IR harness that renders structured and plain errors; prefers lli,
+// falling back to clang.
+func TestErrorRuntime_ToCString_PureIRHarness(t *testing.T) {
+    dir := t.TempDir()
+    orig := os.Getenv("AMI_GPU_BACKENDS")
+    _ = os.Setenv("AMI_GPU_BACKENDS", "cuda,opencl")
+    defer os.Setenv("AMI_GPU_BACKENDS", orig)
+    llp, err := WriteRuntimeLL(dir, DefaultTriple, false)
+    if err != nil { t.Fatalf("WriteRuntimeLL: %v", err) }
+    rtIR, err := os.ReadFile(llp)
+    if err != nil { t.Fatalf("read runtime.ll: %v", err) }
+    h := "\ndeclare i64 @write(i32, ptr, i64)\ndeclare i64 @strlen(ptr)\n" +
+        irConstBytes("code", 0, []byte("E_X")) +
+        irConstBytes("msg", 0, []byte("bad \"q\"")) +
+        irConstBytes("data", 0, []byte(`{"k":1}`)) +
+        irConstBytes("nl", 0, []byte("\n")) +
+        "define void @emit(ptr %e) {\nentry:\n  %n = alloca i32, align 4\n  %s = call ptr @ami_rt_error_to_cstring(ptr %e, i32* %n)\n  %n32 = load i32, ptr %n, align 4\n  %n64 = zext i32 %n32 to i64\n  ; write only when the string is NUL-terminated at outlen\n  %sl = call i64 @strlen(ptr %s)\n  %nul = icmp eq i64 %sl, %n64\n  %wn = select i1 %nul, i64 %n64, i64 0\n  %w0 = call i64 @write(i32 1, ptr %s, i64 %wn)\n  %w1 = call i64 @write(i32 1, ptr @.nl.0, i64 1)\n  ret void\n}\n" +
+        "define i32 @main() {\nentry:\n  %c = call ptr @ami_rt_owned_new(i8* @.code.0, i64 3)\n  %m = call ptr @ami_rt_owned_new(i8* @.msg.0, i64 7)\n  %d = call ptr @ami_rt_owned_new(i8* @.data.0, i64 7)\n" +
+        "  %e1 = call ptr @ami_rt_error_make_data(ptr %c, ptr %m, ptr %d)\n  call void @emit(ptr %e1)\n" +
+        "  %e2 = call ptr @ami_rt_error_make(ptr %c, ptr %m)\n  call void @emit(ptr %e2)\n" +
+        "  %e3 = call ptr @ami_rt_error_make(ptr null, ptr %m)\n  call void @emit(ptr %e3)\n" +
+        "  call void @ami_rt_error_free(ptr %e1)\n  ret i32 0\n}\n"
+    comb := filepath.Join(dir, "combined.ll")
+    if err := os.WriteFile(comb, append(rtIR, h...), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    want := "{\"schema\":\"errors.v1\",\"code\":\"E_X\",\"message\":\"bad \\\"q\\\"\",\"data\":{\"k\":1}}\n" +
+        "{\"schema\":\"errors.v1\",\"code\":\"E_X\",\"message\":\"bad \\\"q\\\"\"}\n" +
+        "bad \"q\"\n"
+    var out []byte
+    if path, _ := exec.LookPath("lli"); path != "" {
+        out, err = exec.Command(path, comb).Output()
+    }
+    if out == nil || err != nil {
+        clang, cerr := FindClang()
+        if cerr != nil { t.Skip("lli/clang unavailable; skipping") }
+        bin := filepath.Join(dir, "harness.bin")
+        if o, err := exec.Command(clang, "-x", "ir", comb, "-o", bin, "-target", DefaultTriple).CombinedOutput(); err != nil {
+            t.Skipf("clang link failed: %v, out=%s", err, string(o))
+        }
+        if out, err = exec.Command(bin).Output(); err != nil { t.Fatalf("run: %v", err) }
+    }
+    if string(out) != want { t.Fatalf("output:\n%s\nwant:\n%s", out, want) }
+}
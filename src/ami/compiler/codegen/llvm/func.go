@@ -48,13 +48,18 @@ func lowerFunction(fn ir.Function) (string, error) {
         if name == "" { name = fmt.Sprintf("b%d", i) }
         fmt.Fprintf(&b, "%s:\n", name)
         for _, ins := range blk.Instr {
+            if err := checkRuntimeCall(fn.Name, ins); err != nil { return "", err }
             switch v := ins.(type) {
             case ir.Var:
                 b.WriteString(lowerVar(v))
             case ir.Assign:
                 b.WriteString(lowerAssign(v))
             case ir.Expr:
-                b.WriteString(lowerExpr(v))
+                if _, ok := stringLit(v); ok {
+                    b.WriteString(lowerStringLit(fn.Name, v))
+                } else {
+                    b.WriteString(lowerExpr(v))
+                }
             case ir.Phi:
                 b.WriteString(lowerPhi(v))
             case ir.CondBr:
@@ -30,6 +30,19 @@ func lowerExpr(e ir.Expr) string {
             return fmt.Sprintf("  %%%s = add i64 0, %s\n", e.Result.ID, val)
         }
     }
+    // Zero value of the result type: nil handles and early-return placeholders.
+    if e.Op == "zero" && e.Result != nil && e.Result.ID != "" {
+        switch ty := mapType(e.Result.Type); ty {
+        case "ptr", "void":
+            return fmt.Sprintf("  %%%s = getelementptr i8, ptr null, i64 0\n", e.Result.ID)
+        case "i1":
+            return fmt.Sprintf("  %%%s = icmp ne i1 0, 1\n", e.Result.ID)
+        case "double":
+            return fmt.Sprintf("  %%%s = fadd double 0.0, 0.0\n", e.Result.ID)
+        default:
+            return fmt.Sprintf("  %%%s = add %s 0, 0\n", e.Result.ID, ty)
+        }
+    }
     // Field projection: op form "field.<path>"; define a result of appropriate type.
     if strings.HasPrefix(e.Op, "field.") {
         // Use base argument type to compute layout offsets when possible
@@ -133,6 +146,7 @@ func lowerExpr(e ir.Expr) string {
         return "  ; expr event.payload.field\n"
     }
     if strings.EqualFold(e.Op, "call") {
+        if pre, ex, ok := widenIntToStringArg(e); ok { return pre + lowerExpr(ex) }
         // Return type resolution
         // - Runtime helpers: use their true ABI regardless of whether result captured
         // - User functions: map via ABI, avoid raw ptr exposure when captured
@@ -198,7 +212,9 @@ func lowerExpr(e ir.Expr) string {
                 ret = "double"
             case "ami_rt_math_ilogb":
                 ret = "i64"
-            case "ami_rt_string_len", "ami_rt_slice_len":
+            case "ami_rt_string_len", "ami_rt_slice_len", "ami_rt_set_len", "ami_rt_map_len":
+                ret = "i64"
+            case "ami_rt_enum_tag", "ami_rt_union_tag", "ami_rt_optional_tag":
                 ret = "i64"
             case "ami_rt_alloc", "ami_rt_owned_ptr", "ami_rt_owned_new":
                 ret = "ptr"
@@ -224,7 +240,12 @@ func lowerExpr(e ir.Expr) string {
                 ret = "ptr"
             default:
                 // fall back to result type when provided
-                if len(e.Results) > 1 {
+                if sr := stringHelperRet(callee); sr != "" {
+                    ret = sr
+                } else if jr := jsonHelperRet(callee); jr != "" {
+                    // json helpers return the error handle as ptr, matching their declaration
+                    ret = jr
+                } else if len(e.Results) > 1 {
                     // Multi-result runtime calls return an aggregate
                     var parts []string
                     for _, r := range e.Results { parts = append(parts, abiType(r.Type)) }
@@ -7,26 +7,41 @@ import (
 )
 
 // lowerReturn emits a return instruction; single-value returns only in scaffold.
+// Values are returned in their public ABI types (see abiType) so the return
+// matches the function signature: pointer-like values are passed as i64 handles.
 func lowerReturn(r ir.Return) (string, error) {
+    var b strings.Builder
+    // abiOperand returns the operand for v in its ABI type, converting handles first.
+    abiOperand := func(v ir.Value) string {
+        if mapType(v.Type) == "ptr" && abiType(v.Type) == "i64" {
+            fmt.Fprintf(&b, "  %%%s.abi = ptrtoint ptr %%%s to i64\n", v.ID, v.ID)
+            return "%" + v.ID + ".abi"
+        }
+        return "%" + v.ID
+    }
     switch len(r.Values) {
     case 0:
         return "  ret void\n", nil
     case 1:
         v := r.Values[0]
-        return fmt.Sprintf("  ret %s %%%s\n", mapType(v.Type), v.ID), nil
+        op := abiOperand(v)
+        fmt.Fprintf(&b, "  ret %s %s\n", abiType(v.Type), op)
+        return b.String(), nil
     default:
         // Build aggregate {T0,T1,...} using insertvalue chain
-        var parts []string
-        for _, v := range r.Values { parts = append(parts, mapType(v.Type)) }
+        var parts, ops []string
+        for _, v := range r.Values {
+            parts = append(parts, abiType(v.Type))
+            ops = append(ops, abiOperand(v))
+        }
         aggTy := "{" + strings.Join(parts, ", ") + "}"
         // Start with undef
-        var b strings.Builder
         name := fmt.Sprintf("ret_agg_%s", r.Values[0].ID)
-        fmt.Fprintf(&b, "  %%%s0 = insertvalue %s undef, %s %%%s, 0\n", name, aggTy, mapType(r.Values[0].Type), r.Values[0].ID)
+        fmt.Fprintf(&b, "  %%%s0 = insertvalue %s undef, %s %s, 0\n", name, aggTy, parts[0], ops[0])
         prev := fmt.Sprintf("%%%s0", name)
         for i := 1; i < len(r.Values); i++ {
             cur := fmt.Sprintf("%%%s%d", name, i)
-            fmt.Fprintf(&b, "  %s = insertvalue %s %s, %s %%%s, %d\n", cur, aggTy, prev, mapType(r.Values[i].Type), r.Values[i].ID, i)
+            fmt.Fprintf(&b, "  %s = insertvalue %s %s, %s %s, %d\n", cur, aggTy, prev, parts[i], ops[i], i)
             prev = cur
         }
         fmt.Fprintf(&b, "  ret %s %s\n", aggTy, prev)
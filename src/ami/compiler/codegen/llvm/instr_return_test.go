@@ -22,3 +22,12 @@ func Test_lowerReturn_Multi(t *testing.T) {
     if !strings.Contains(s, "insertvalue {i64, i64, i64} undef, i64 %a, 0") { t.Fatalf("agg start: %s", s) }
     if !strings.Contains(s, "ret {i64, i64, i64} %ret_agg_a2") { t.Fatalf("agg ret: %s", s) }
 }
+
+func Test_lowerReturn_HandlesMatchABI(t *testing.T) {
+    s, err := lowerReturn(ir.Return{Values: []ir.Value{{ID: "n", Type: "int"}, {ID: "e", Type: "error"}}})
+    if err != nil { t.Fatalf("err: %v", err) }
+    if !strings.Contains(s, "%e.abi = ptrtoint ptr %e to i64") { t.Fatalf("handle not converted: %s", s) }
+    if !strings.Contains(s, "insertvalue {i64, i64} %ret_agg_n0, i64 %e.abi, 1") || !strings.Contains(s, "ret {i64, i64} %ret_agg_n1") {
+        t.Fatalf("aggregate not in ABI types: %s", s)
+    }
+}
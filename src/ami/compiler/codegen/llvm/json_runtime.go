@@ -0,0 +1,355 @@
+package llvm
+
+import (
+    "fmt"
+    "strings"
+)
+
+// jsonHelpers lists the json stdlib helpers the runtime defines. Decode helpers
+// take the document and the target's type descriptor and return the value and
+// an error handle; encode helpers take the value and return the JSON text and
+// an error handle. They follow runtime/host/json: bool, int, int64, float64 and
+// string targets decode; other scalar targets fail with E_JSON_TYPE
+// ("unsupported target type") through ami_rt_json_decode_unsupported_<abi>.
+var jsonHelpers = map[string]stringHelperSig{
+    "ami_rt_json_decode_i1":     {"{ i1, ptr }", "ptr, ptr"},
+    "ami_rt_json_decode_i64":    {"{ i64, ptr }", "ptr, ptr"},
+    "ami_rt_json_decode_double": {"{ double, ptr }", "ptr, ptr"},
+    "ami_rt_json_decode_string": {"{ ptr, ptr }", "ptr, ptr"},
+    "ami_rt_json_encode_i1":     {"{ ptr, ptr }", "i1"},
+    "ami_rt_json_encode_i64":    {"{ ptr, ptr }", "i64"},
+    "ami_rt_json_encode_u64":    {"{ ptr, ptr }", "i64"},
+    "ami_rt_json_encode_double": {"{ ptr, ptr }", "double"},
+    "ami_rt_json_encode_string": {"{ ptr, ptr }", "ptr"},
+}
+
+// jsonUnsupportedABIs are the scalar ABIs of the decode_unsupported helpers.
+var jsonUnsupportedABIs = []string{"i1", "i8", "i16", "i32", "i64", "double"}
+
+// jsonUnsupported lists the json helpers the backend cannot lower yet, with the
+// reason reported by checkRuntimeCall.
+var jsonUnsupported = map[string]string{
+    "ami_rt_json_decode_ptr": "decoding JSON into structs, enums, Optionals, unions and containers needs the container ABI",
+    "ami_rt_json_encode_ptr": "encoding structs, enums, Optionals, unions, containers and Time as JSON needs runtime type information",
+}
+
+// jsonHelperSig returns the signature of a json helper; ok is false when callee
+// is not one the runtime defines.
+func jsonHelperSig(callee string) (stringHelperSig, bool) {
+    if sig, ok := jsonHelpers[callee]; ok { return sig, true }
+    abi := strings.TrimPrefix(callee, "ami_rt_json_decode_unsupported_")
+    if abi == callee { return stringHelperSig{}, false }
+    for _, x := range jsonUnsupportedABIs {
+        if x == abi { return stringHelperSig{ret: "{ " + abi + ", ptr }", params: "ptr, ptr"}, true }
+    }
+    return stringHelperSig{}, false
+}
+
+// jsonHelperRet returns the aggregate a json helper returns, or "" when callee
+// is not one.
+func jsonHelperRet(callee string) string {
+    sig, ok := jsonHelperSig(callee)
+    if !ok { return "" }
+    return sig.ret
+}
+
+// jsonHelperDecl returns the extern declaration for a json helper, or "" when
+// callee is not one.
+func jsonHelperDecl(callee string) string {
+    sig, ok := jsonHelperSig(callee)
+    if !ok { return "" }
+    return fmt.Sprintf("declare %s @%s(%s)", sig.ret, callee, sig.params)
+}
+
+// jsonConst returns a private NUL-terminated constant holding s.
+func jsonConst(name, s string) string {
+    return fmt.Sprintf("@.json.%s = private constant [%d x i8] c\"%s\"\n", name, len(s)+1, encodeCString(s))
+}
+
+// jsonRuntimeLL returns definitions for the json helpers. Documents are checked
+// against the full JSON grammar before the root value is converted, and errors
+// carry runtime/host/json's codes and messages without the code prefix:
+// E_JSON_SYNTAX ("offset N: ..."), E_JSON_TYPE ("document: expected T, got K")
+// and E_JSON_ENCODE ("value: unsupported float NaN"). Decoded strings resolve
+// every escape, replacing unpaired surrogates with U+FFFD; encoded strings are
+// not HTML-escaped. Other bytes, including invalid UTF-8, are copied unchanged.
+func jsonRuntimeLL() string {
+    kinds := []string{"null", "bool", "number", "string", "array", "object"}
+    var b strings.Builder
+    b.WriteString(jsonConst("code.syntax", "E_JSON_SYNTAX"))
+    b.WriteString(jsonConst("code.type", "E_JSON_TYPE"))
+    b.WriteString(jsonConst("code.encode", "E_JSON_ENCODE"))
+    b.WriteString(jsonConst("fmt.syntax", "offset %lld: %s"))
+    b.WriteString(jsonConst("fmt.type", "document: expected %.*s, got %s"))
+    b.WriteString(jsonConst("fmt.number", "document: expected %.*s, got number %.*s"))
+    b.WriteString(jsonConst("fmt.float", "value: unsupported float %.*s"))
+    b.WriteString(jsonConst("fmt.uint", "%llu"))
+    b.WriteString(jsonConst("eof", "unexpected end of input"))
+    b.WriteString(jsonConst("char", "invalid character"))
+    b.WriteString(jsonConst("trail", "unexpected data after top-level value"))
+    b.WriteString(jsonConst("unsupported", "unsupported target type"))
+    b.WriteString(jsonConst("space", " \t\n\r"))
+    b.WriteString(jsonConst("simple", "\"\\/bfnrt"))
+    b.WriteString(jsonConst("hex", "0123456789abcdef"))
+    for _, k := range kinds { b.WriteString(jsonConst("kind."+k, k)) }
+    // the true, false and null literals reuse the payload constants in runtime.go
+    b.WriteString("\n")
+    // Errors: code and message are copied by error_make.
+    b.WriteString("define ptr @ami_rt_json_error(ptr %code, i64 %cn, ptr %msg, i64 %n) {\n" +
+        "entry:\n  %c = call ptr @ami_rt_owned_new(i8* %code, i64 %cn)\n  %m = call ptr @ami_rt_owned_new(i8* %msg, i64 %n)\n  %e = call ptr @ami_rt_error_make(ptr %c, ptr %m)\n  %cb = call ptr @ami_rt_owned_ptr(ptr %c)\n  call void @free(ptr %cb)\n  call void @free(ptr %c)\n  %mb = call ptr @ami_rt_owned_ptr(ptr %m)\n  call void @free(ptr %mb)\n  call void @free(ptr %m)\n  ret ptr %e\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_syntax_error(i64 %off, ptr %detail) {\n" +
+        "entry:\n  %buf = call ptr @malloc(i64 96)\n  %n32 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 96, ptr @.json.fmt.syntax, i64 %off, ptr %detail)\n  %n = sext i32 %n32 to i64\n  %e = call ptr @ami_rt_json_error(ptr @.json.code.syntax, i64 13, ptr %buf, i64 %n)\n  call void @free(ptr %buf)\n  ret ptr %e\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_type_error(ptr %desc, ptr %got) {\n" +
+        "entry:\n  %dn = call i64 @ami_rt_string_len(ptr %desc)\n  %dp = call ptr @ami_rt_string_ptr(ptr %desc)\n  %dn32 = trunc i64 %dn to i32\n  %size = add i64 %dn, 64\n  %buf = call ptr @malloc(i64 %size)\n  %n32 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 %size, ptr @.json.fmt.type, i32 %dn32, ptr %dp, ptr %got)\n  %n = sext i32 %n32 to i64\n  %e = call ptr @ami_rt_json_error(ptr @.json.code.type, i64 11, ptr %buf, i64 %n)\n  call void @free(ptr %buf)\n  ret ptr %e\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_number_error(ptr %desc, ptr %p, i64 %len) {\n" +
+        "entry:\n  %dn = call i64 @ami_rt_string_len(ptr %desc)\n  %dp = call ptr @ami_rt_string_ptr(ptr %desc)\n  %dn32 = trunc i64 %dn to i32\n  %len32 = trunc i64 %len to i32\n  %sum = add i64 %dn, %len\n  %size = add i64 %sum, 64\n  %buf = call ptr @malloc(i64 %size)\n  %n32 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 %size, ptr @.json.fmt.number, i32 %dn32, ptr %dp, i32 %len32, ptr %p)\n  %n = sext i32 %n32 to i64\n  %e = call ptr @ami_rt_json_error(ptr @.json.code.type, i64 11, ptr %buf, i64 %n)\n  call void @free(ptr %buf)\n  ret ptr %e\n}\n\n")
+    // Scanning: every skip helper returns the position after the value it checked,
+    // or null after storing the offending position (end for truncated input) in %bad.
+    b.WriteString("define ptr @ami_rt_json_ws(ptr %p, ptr %end) {\n" +
+        "entry:\n  br label %loop\n" +
+        "loop:\n  %q = phi ptr [ %p, %entry ], [ %q1, %skip ]\n  %at = icmp eq ptr %q, %end\n  br i1 %at, label %done, label %peek\n" +
+        "peek:\n  %c = load i8, ptr %q, align 1\n  %ws = call i1 @ami_rt_string_byte_in(i8 %c, ptr @.json.space, i64 4)\n  br i1 %ws, label %skip, label %done\n" +
+        "skip:\n  %q1 = getelementptr i8, ptr %q, i64 1\n  br label %loop\n" +
+        "done:\n  ret ptr %q\n}\n\n")
+    b.WriteString("define i1 @ami_rt_json_at(ptr %p, ptr %end, i8 %c) {\n" +
+        "entry:\n  %at = icmp eq ptr %p, %end\n  br i1 %at, label %no, label %peek\n" +
+        "no:\n  ret i1 false\n" +
+        "peek:\n  %b = load i8, ptr %p, align 1\n  %eq = icmp eq i8 %b, %c\n  ret i1 %eq\n}\n\n")
+    b.WriteString("define i1 @ami_rt_json_digit_at(ptr %p, ptr %end) {\n" +
+        "entry:\n  %at = icmp eq ptr %p, %end\n  br i1 %at, label %no, label %peek\n" +
+        "no:\n  ret i1 false\n" +
+        "peek:\n  %b = load i8, ptr %p, align 1\n  %d = sub i8 %b, 48\n  %ok = icmp ult i8 %d, 10\n  ret i1 %ok\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_digits(ptr %p, ptr %end) {\n" +
+        "entry:\n  br label %loop\n" +
+        "loop:\n  %q = phi ptr [ %p, %entry ], [ %q1, %loop ]\n  %q1 = getelementptr i8, ptr %q, i64 1\n  %d = call i1 @ami_rt_json_digit_at(ptr %q, ptr %end)\n  br i1 %d, label %loop, label %done\n" +
+        "done:\n  ret ptr %q\n}\n\n")
+    // Value of one hex digit, or -1.
+    b.WriteString("define i32 @ami_rt_json_hexval(i8 %c) {\n" +
+        "entry:\n  %c32 = zext i8 %c to i32\n  %d = sub i32 %c32, 48\n  %isd = icmp ult i32 %d, 10\n  br i1 %isd, label %digit, label %letter\n" +
+        "digit:\n  ret i32 %d\n" +
+        "letter:\n  %lc = or i32 %c32, 32\n  %l = sub i32 %lc, 97\n  %isl = icmp ult i32 %l, 6\n  %v = add i32 %l, 10\n  %r = select i1 %isl, i32 %v, i32 -1\n  ret i32 %r\n}\n\n")
+    // Value of the four hex digits at p, or -1 when fewer remain or one is not hex.
+    b.WriteString("define i32 @ami_rt_json_hex4(ptr %p, ptr %end) {\n" +
+        "entry:\n  %pi = ptrtoint ptr %p to i64\n  %ei = ptrtoint ptr %end to i64\n  %left = sub i64 %ei, %pi\n  %short = icmp slt i64 %left, 4\n  br i1 %short, label %bad, label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %i1, %digit ]\n  %acc = phi i32 [ 0, %entry ], [ %acc1, %digit ]\n  %fin = icmp eq i64 %i, 4\n  br i1 %fin, label %done, label %body\n" +
+        "body:\n  %cp = getelementptr i8, ptr %p, i64 %i\n  %c = load i8, ptr %cp, align 1\n  %h = call i32 @ami_rt_json_hexval(i8 %c)\n  %neg = icmp slt i32 %h, 0\n  br i1 %neg, label %bad, label %digit\n" +
+        "digit:\n  %sh = shl i32 %acc, 4\n  %acc1 = or i32 %sh, %h\n  %i1 = add i64 %i, 1\n  br label %loop\n" +
+        "done:\n  ret i32 %acc\n" +
+        "bad:\n  ret i32 -1\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_skip_lit(ptr %p, ptr %end, ptr %bad, ptr %lit, i64 %n) {\n" +
+        "entry:\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %i1, %match ]\n  %fin = icmp eq i64 %i, %n\n  br i1 %fin, label %done, label %body\n" +
+        "body:\n  %q = getelementptr i8, ptr %p, i64 %i\n  %at = icmp eq ptr %q, %end\n  br i1 %at, label %fail, label %cmp\n" +
+        "cmp:\n  %c = load i8, ptr %q, align 1\n  %lp = getelementptr i8, ptr %lit, i64 %i\n  %l = load i8, ptr %lp, align 1\n  %eq = icmp eq i8 %c, %l\n  %i1 = add i64 %i, 1\n  br i1 %eq, label %match, label %fail\n" +
+        "match:\n  br label %loop\n" +
+        "fail:\n  store ptr %q, ptr %bad, align 8\n  ret ptr null\n" +
+        "done:\n  %r = getelementptr i8, ptr %p, i64 %n\n  ret ptr %r\n}\n\n")
+    // -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+    b.WriteString("define ptr @ami_rt_json_skip_number(ptr %p, ptr %end, ptr %bad) {\n" +
+        "entry:\n  %minus = call i1 @ami_rt_json_at(ptr %p, ptr %end, i8 45)\n  %p1 = getelementptr i8, ptr %p, i64 1\n  %q0 = select i1 %minus, ptr %p1, ptr %p\n  %zero = call i1 @ami_rt_json_at(ptr %q0, ptr %end, i8 48)\n  br i1 %zero, label %afterzero, label %lead\n" +
+        "afterzero:\n  %qz = getelementptr i8, ptr %q0, i64 1\n  br label %frac\n" +
+        "lead:\n  %d0 = call i1 @ami_rt_json_digit_at(ptr %q0, ptr %end)\n  br i1 %d0, label %intdigits, label %fail\n" +
+        "intdigits:\n  %qi = call ptr @ami_rt_json_digits(ptr %q0, ptr %end)\n  br label %frac\n" +
+        "frac:\n  %q1 = phi ptr [ %qz, %afterzero ], [ %qi, %intdigits ]\n  %dot = call i1 @ami_rt_json_at(ptr %q1, ptr %end, i8 46)\n  br i1 %dot, label %fracdigits, label %exp\n" +
+        "fracdigits:\n  %qd = getelementptr i8, ptr %q1, i64 1\n  %d1 = call i1 @ami_rt_json_digit_at(ptr %qd, ptr %end)\n  br i1 %d1, label %fracrest, label %fail\n" +
+        "fracrest:\n  %qf = call ptr @ami_rt_json_digits(ptr %qd, ptr %end)\n  br label %exp\n" +
+        "exp:\n  %q2 = phi ptr [ %q1, %frac ], [ %qf, %fracrest ]\n  %e1 = call i1 @ami_rt_json_at(ptr %q2, ptr %end, i8 101)\n  %e2 = call i1 @ami_rt_json_at(ptr %q2, ptr %end, i8 69)\n  %ise = or i1 %e1, %e2\n  br i1 %ise, label %expsign, label %done\n" +
+        "expsign:\n  %qe = getelementptr i8, ptr %q2, i64 1\n  %s1 = call i1 @ami_rt_json_at(ptr %qe, ptr %end, i8 43)\n  %s2 = call i1 @ami_rt_json_at(ptr %qe, ptr %end, i8 45)\n  %sg = or i1 %s1, %s2\n  %qe1 = getelementptr i8, ptr %qe, i64 1\n  %qs = select i1 %sg, ptr %qe1, ptr %qe\n  %d2 = call i1 @ami_rt_json_digit_at(ptr %qs, ptr %end)\n  br i1 %d2, label %exprest, label %fail\n" +
+        "exprest:\n  %qx = call ptr @ami_rt_json_digits(ptr %qs, ptr %end)\n  br label %done\n" +
+        "done:\n  %r = phi ptr [ %q2, %exp ], [ %qx, %exprest ]\n  ret ptr %r\n" +
+        "fail:\n  %at = phi ptr [ %q0, %lead ], [ %qd, %fracdigits ], [ %qs, %expsign ]\n  store ptr %at, ptr %bad, align 8\n  ret ptr null\n}\n\n")
+    // p is at the opening quote.
+    b.WriteString("define ptr @ami_rt_json_skip_string(ptr %p, ptr %end, ptr %bad) {\n" +
+        "entry:\n  %q0 = getelementptr i8, ptr %p, i64 1\n  br label %loop\n" +
+        "loop:\n  %q = phi ptr [ %q0, %entry ], [ %qn, %plain ], [ %qs, %simple ], [ %qu, %unicode ]\n  %at = icmp eq ptr %q, %end\n  br i1 %at, label %failq, label %body\n" +
+        "body:\n  %c = load i8, ptr %q, align 1\n  %qn = getelementptr i8, ptr %q, i64 1\n  %close = icmp eq i8 %c, 34\n  br i1 %close, label %done, label %chkesc\n" +
+        "chkesc:\n  %isesc = icmp eq i8 %c, 92\n  br i1 %isesc, label %escape, label %chkctl\n" +
+        "chkctl:\n  %ctl = icmp ult i8 %c, 32\n  br i1 %ctl, label %failq, label %plain\n" +
+        "plain:\n  br label %loop\n" +
+        "escape:\n  %ate = icmp eq ptr %qn, %end\n  br i1 %ate, label %faile, label %escchar\n" +
+        "escchar:\n  %ec = load i8, ptr %qn, align 1\n  %qs = getelementptr i8, ptr %qn, i64 1\n  %isu = icmp eq i8 %ec, 117\n  br i1 %isu, label %hex, label %chksimple\n" +
+        "chksimple:\n  %smp = call i1 @ami_rt_string_byte_in(i8 %ec, ptr @.json.simple, i64 8)\n  br i1 %smp, label %simple, label %faile\n" +
+        "simple:\n  br label %loop\n" +
+        "hex:\n  %h = call i32 @ami_rt_json_hex4(ptr %qs, ptr %end)\n  %badh = icmp slt i32 %h, 0\n  %qu = getelementptr i8, ptr %qs, i64 4\n  br i1 %badh, label %failh, label %unicode\n" +
+        "unicode:\n  br label %loop\n" +
+        "done:\n  ret ptr %qn\n" +
+        "failh:\n  %ei = ptrtoint ptr %end to i64\n  %ui = ptrtoint ptr %qu to i64\n  %past = icmp ugt i64 %ui, %ei\n  %fh = select i1 %past, ptr %end, ptr %qs\n  br label %fail\n" +
+        "failq:\n  br label %fail\n" +
+        "faile:\n  br label %fail\n" +
+        "fail:\n  %fp = phi ptr [ %q, %failq ], [ %qn, %faile ], [ %fh, %failh ]\n  store ptr %fp, ptr %bad, align 8\n  ret ptr null\n}\n\n")
+    // p is at the first byte of a value; nesting deeper than 10000 is rejected as
+    // encoding/json does.
+    b.WriteString("define ptr @ami_rt_json_skip_value(ptr %p, ptr %end, ptr %bad, i64 %depth) {\n" +
+        "entry:\n  %at = icmp eq ptr %p, %end\n  br i1 %at, label %fail, label %peek\n" +
+        "peek:\n  %c = load i8, ptr %p, align 1\n  switch i8 %c, label %number [ i8 123, label %nest i8 91, label %nest i8 34, label %string i8 116, label %littrue i8 102, label %litfalse i8 110, label %litnull ]\n" +
+        "nest:\n  %deep = icmp sge i64 %depth, 10000\n  br i1 %deep, label %fail, label %container\n" +
+        "container:\n  %d1 = add i64 %depth, 1\n  %isobj = icmp eq i8 %c, 123\n  br i1 %isobj, label %object, label %array\n" +
+        "object:\n  %ro = call ptr @ami_rt_json_skip_object(ptr %p, ptr %end, ptr %bad, i64 %d1)\n  ret ptr %ro\n" +
+        "array:\n  %ra = call ptr @ami_rt_json_skip_array(ptr %p, ptr %end, ptr %bad, i64 %d1)\n  ret ptr %ra\n" +
+        "string:\n  %rs = call ptr @ami_rt_json_skip_string(ptr %p, ptr %end, ptr %bad)\n  ret ptr %rs\n" +
+        "littrue:\n  %rt = call ptr @ami_rt_json_skip_lit(ptr %p, ptr %end, ptr %bad, ptr @.json.true, i64 4)\n  ret ptr %rt\n" +
+        "litfalse:\n  %rf = call ptr @ami_rt_json_skip_lit(ptr %p, ptr %end, ptr %bad, ptr @.json.false, i64 5)\n  ret ptr %rf\n" +
+        "litnull:\n  %rn = call ptr @ami_rt_json_skip_lit(ptr %p, ptr %end, ptr %bad, ptr @.json.null, i64 4)\n  ret ptr %rn\n" +
+        "number:\n  %minus = icmp eq i8 %c, 45\n  %d = sub i8 %c, 48\n  %digit = icmp ult i8 %d, 10\n  %num = or i1 %minus, %digit\n  br i1 %num, label %scan, label %fail\n" +
+        "scan:\n  %rnum = call ptr @ami_rt_json_skip_number(ptr %p, ptr %end, ptr %bad)\n  ret ptr %rnum\n" +
+        "fail:\n  store ptr %p, ptr %bad, align 8\n  ret ptr null\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_skip_array(ptr %p, ptr %end, ptr %bad, i64 %depth) {\n" +
+        "entry:\n  %p1 = getelementptr i8, ptr %p, i64 1\n  %q = call ptr @ami_rt_json_ws(ptr %p1, ptr %end)\n  %empty = call i1 @ami_rt_json_at(ptr %q, ptr %end, i8 93)\n  br i1 %empty, label %close, label %elem\n" +
+        "elem:\n  %v = phi ptr [ %q, %entry ], [ %vn, %comma ]\n  %r = call ptr @ami_rt_json_skip_value(ptr %v, ptr %end, ptr %bad, i64 %depth)\n  %failed = icmp eq ptr %r, null\n  br i1 %failed, label %propagate, label %sep\n" +
+        "sep:\n  %s = call ptr @ami_rt_json_ws(ptr %r, ptr %end)\n  %more = call i1 @ami_rt_json_at(ptr %s, ptr %end, i8 44)\n  br i1 %more, label %comma, label %last\n" +
+        "comma:\n  %s1 = getelementptr i8, ptr %s, i64 1\n  %vn = call ptr @ami_rt_json_ws(ptr %s1, ptr %end)\n  br label %elem\n" +
+        "last:\n  %shut = call i1 @ami_rt_json_at(ptr %s, ptr %end, i8 93)\n  br i1 %shut, label %close, label %fail\n" +
+        "close:\n  %c = phi ptr [ %q, %entry ], [ %s, %last ]\n  %rc = getelementptr i8, ptr %c, i64 1\n  ret ptr %rc\n" +
+        "fail:\n  store ptr %s, ptr %bad, align 8\n  br label %propagate\n" +
+        "propagate:\n  ret ptr null\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_skip_object(ptr %p, ptr %end, ptr %bad, i64 %depth) {\n" +
+        "entry:\n  %p1 = getelementptr i8, ptr %p, i64 1\n  %q = call ptr @ami_rt_json_ws(ptr %p1, ptr %end)\n  %empty = call i1 @ami_rt_json_at(ptr %q, ptr %end, i8 125)\n  br i1 %empty, label %close, label %member\n" +
+        "member:\n  %k = phi ptr [ %q, %entry ], [ %kn, %comma ]\n  %iskey = call i1 @ami_rt_json_at(ptr %k, ptr %end, i8 34)\n  br i1 %iskey, label %key, label %fail\n" +
+        "key:\n  %rk = call ptr @ami_rt_json_skip_string(ptr %k, ptr %end, ptr %bad)\n  %kfail = icmp eq ptr %rk, null\n  br i1 %kfail, label %propagate, label %colon\n" +
+        "colon:\n  %c = call ptr @ami_rt_json_ws(ptr %rk, ptr %end)\n  %iscolon = call i1 @ami_rt_json_at(ptr %c, ptr %end, i8 58)\n  br i1 %iscolon, label %value, label %fail\n" +
+        "value:\n  %c1 = getelementptr i8, ptr %c, i64 1\n  %v = call ptr @ami_rt_json_ws(ptr %c1, ptr %end)\n  %rv = call ptr @ami_rt_json_skip_value(ptr %v, ptr %end, ptr %bad, i64 %depth)\n  %vfail = icmp eq ptr %rv, null\n  br i1 %vfail, label %propagate, label %sep\n" +
+        "sep:\n  %s = call ptr @ami_rt_json_ws(ptr %rv, ptr %end)\n  %more = call i1 @ami_rt_json_at(ptr %s, ptr %end, i8 44)\n  br i1 %more, label %comma, label %last\n" +
+        "comma:\n  %s1 = getelementptr i8, ptr %s, i64 1\n  %kn = call ptr @ami_rt_json_ws(ptr %s1, ptr %end)\n  br label %member\n" +
+        "last:\n  %shut = call i1 @ami_rt_json_at(ptr %s, ptr %end, i8 125)\n  br i1 %shut, label %close, label %fail\n" +
+        "close:\n  %cl = phi ptr [ %q, %entry ], [ %s, %last ]\n  %rc = getelementptr i8, ptr %cl, i64 1\n  ret ptr %rc\n" +
+        "fail:\n  %fp = phi ptr [ %k, %member ], [ %c, %colon ], [ %s, %last ]\n  store ptr %fp, ptr %bad, align 8\n  br label %propagate\n" +
+        "propagate:\n  ret ptr null\n}\n\n")
+    // Checks that s holds exactly one JSON value; on success stores its bounds in
+    // %vp and %ve and returns null, otherwise returns an E_JSON_SYNTAX error.
+    // Offsets count the bytes read up to and including the offending one.
+    b.WriteString("define ptr @ami_rt_json_parse(ptr %s, ptr %vp, ptr %ve) {\n" +
+        "entry:\n  %badp = alloca ptr, align 8\n  %n = call i64 @ami_rt_string_len(ptr %s)\n  %p = call ptr @ami_rt_string_ptr(ptr %s)\n  %end = getelementptr i8, ptr %p, i64 %n\n  %v = call ptr @ami_rt_json_ws(ptr %p, ptr %end)\n  %r = call ptr @ami_rt_json_skip_value(ptr %v, ptr %end, ptr %badp, i64 0)\n  %failed = icmp eq ptr %r, null\n  br i1 %failed, label %syntax, label %rest\n" +
+        "rest:\n  %t = call ptr @ami_rt_json_ws(ptr %r, ptr %end)\n  %whole = icmp eq ptr %t, %end\n  br i1 %whole, label %ok, label %trailing\n" +
+        "ok:\n  store ptr %v, ptr %vp, align 8\n  store ptr %r, ptr %ve, align 8\n  ret ptr null\n" +
+        "trailing:\n  %ti = ptrtoint ptr %t to i64\n  %pi = ptrtoint ptr %p to i64\n  %toff = sub i64 %ti, %pi\n  %et = call ptr @ami_rt_json_syntax_error(i64 %toff, ptr @.json.trail)\n  ret ptr %et\n" +
+        "syntax:\n  %b = load ptr, ptr %badp, align 8\n  %eof = icmp eq ptr %b, %end\n  %bi = ptrtoint ptr %b to i64\n  %pi2 = ptrtoint ptr %p to i64\n  %boff = sub i64 %bi, %pi2\n  %boff1 = add i64 %boff, 1\n  %off = select i1 %eof, i64 %n, i64 %boff1\n  %detail = select i1 %eof, ptr @.json.eof, ptr @.json.char\n  %es = call ptr @ami_rt_json_syntax_error(i64 %off, ptr %detail)\n  ret ptr %es\n}\n\n")
+    // Names the JSON kind of the value starting with c for type errors.
+    b.WriteString("define ptr @ami_rt_json_kind(i8 %c) {\n" +
+        "entry:\n  switch i8 %c, label %number [ i8 110, label %null i8 116, label %bool i8 102, label %bool i8 34, label %string i8 91, label %array i8 123, label %object ]\n" +
+        "null:\n  ret ptr @.json.kind.null\n" +
+        "bool:\n  ret ptr @.json.kind.bool\n" +
+        "string:\n  ret ptr @.json.kind.string\n" +
+        "array:\n  ret ptr @.json.kind.array\n" +
+        "object:\n  ret ptr @.json.kind.object\n" +
+        "number:\n  ret ptr @.json.kind.number\n}\n\n")
+    // Writes code point cp as UTF-8 at d and returns the byte count.
+    b.WriteString("define i64 @ami_rt_json_put_utf8(ptr %d, i32 %cp) {\n" +
+        "entry:\n  %one = icmp ult i32 %cp, 128\n  br i1 %one, label %b1, label %chk2\n" +
+        "b1:\n  %c1 = trunc i32 %cp to i8\n  store i8 %c1, ptr %d, align 1\n  ret i64 1\n" +
+        "chk2:\n  %two = icmp ult i32 %cp, 2048\n  br i1 %two, label %b2, label %chk3\n" +
+        "b2:\n  %h2 = lshr i32 %cp, 6\n  %h2o = or i32 %h2, 192\n  %h2b = trunc i32 %h2o to i8\n  store i8 %h2b, ptr %d, align 1\n  %l2 = and i32 %cp, 63\n  %l2o = or i32 %l2, 128\n  %l2b = trunc i32 %l2o to i8\n  %d21 = getelementptr i8, ptr %d, i64 1\n  store i8 %l2b, ptr %d21, align 1\n  ret i64 2\n" +
+        "chk3:\n  %three = icmp ult i32 %cp, 65536\n  br i1 %three, label %b3, label %b4\n" +
+        "b3:\n  %h3 = lshr i32 %cp, 12\n  %h3o = or i32 %h3, 224\n  %h3b = trunc i32 %h3o to i8\n  store i8 %h3b, ptr %d, align 1\n  %m3 = lshr i32 %cp, 6\n  %m3a = and i32 %m3, 63\n  %m3o = or i32 %m3a, 128\n  %m3b = trunc i32 %m3o to i8\n  %d31 = getelementptr i8, ptr %d, i64 1\n  store i8 %m3b, ptr %d31, align 1\n  %l3 = and i32 %cp, 63\n  %l3o = or i32 %l3, 128\n  %l3b = trunc i32 %l3o to i8\n  %d32 = getelementptr i8, ptr %d, i64 2\n  store i8 %l3b, ptr %d32, align 1\n  ret i64 3\n" +
+        "b4:\n  %h4 = lshr i32 %cp, 18\n  %h4o = or i32 %h4, 240\n  %h4b = trunc i32 %h4o to i8\n  store i8 %h4b, ptr %d, align 1\n  %m4 = lshr i32 %cp, 12\n  %m4a = and i32 %m4, 63\n  %m4o = or i32 %m4a, 128\n  %m4b = trunc i32 %m4o to i8\n  %d41 = getelementptr i8, ptr %d, i64 1\n  store i8 %m4b, ptr %d41, align 1\n  %n4 = lshr i32 %cp, 6\n  %n4a = and i32 %n4, 63\n  %n4o = or i32 %n4a, 128\n  %n4b = trunc i32 %n4o to i8\n  %d42 = getelementptr i8, ptr %d, i64 2\n  store i8 %n4b, ptr %d42, align 1\n  %l4 = and i32 %cp, 63\n  %l4o = or i32 %l4, 128\n  %l4b = trunc i32 %l4o to i8\n  %d43 = getelementptr i8, ptr %d, i64 3\n  store i8 %l4b, ptr %d43, align 1\n  ret i64 4\n}\n\n")
+    // Maps a simple escape letter to its byte.
+    b.WriteString("define i8 @ami_rt_json_unescape_byte(i8 %c) {\n" +
+        "entry:\n  switch i8 %c, label %same [ i8 98, label %bs i8 102, label %ff i8 110, label %nl i8 114, label %cr i8 116, label %tab ]\n" +
+        "same:\n  ret i8 %c\n" +
+        "bs:\n  ret i8 8\n" +
+        "ff:\n  ret i8 12\n" +
+        "nl:\n  ret i8 10\n" +
+        "cr:\n  ret i8 13\n" +
+        "tab:\n  ret i8 9\n}\n\n")
+    // Decodes the already validated string literal whose content spans [p, end).
+    // The result never exceeds the escaped length: \uXXXX (6 bytes) becomes at
+    // most 3 bytes and a surrogate pair (12 bytes) 4.
+    b.WriteString("define ptr @ami_rt_json_unquote(ptr %p, ptr %end) {\n" +
+        "entry:\n  %pi = ptrtoint ptr %p to i64\n  %ei = ptrtoint ptr %end to i64\n  %n = sub i64 %ei, %pi\n  %buf = call ptr @malloc(i64 %n)\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %i1, %plain ], [ %i2, %simple ], [ %iu, %unicode ]\n  %j = phi i64 [ 0, %entry ], [ %j1, %plain ], [ %j1, %simple ], [ %ju, %unicode ]\n  %fin = icmp uge i64 %i, %n\n  br i1 %fin, label %done, label %body\n" +
+        "body:\n  %sp = getelementptr i8, ptr %p, i64 %i\n  %c = load i8, ptr %sp, align 1\n  %dp = getelementptr i8, ptr %buf, i64 %j\n  %j1 = add i64 %j, 1\n  %isesc = icmp eq i8 %c, 92\n  br i1 %isesc, label %escape, label %plain\n" +
+        "plain:\n  store i8 %c, ptr %dp, align 1\n  %i1 = add i64 %i, 1\n  br label %loop\n" +
+        "escape:\n  %ep = getelementptr i8, ptr %sp, i64 1\n  %ec = load i8, ptr %ep, align 1\n  %i2 = add i64 %i, 2\n  %isu = icmp eq i8 %ec, 117\n  br i1 %isu, label %hex, label %simple\n" +
+        "simple:\n  %ub = call i8 @ami_rt_json_unescape_byte(i8 %ec)\n  store i8 %ub, ptr %dp, align 1\n  br label %loop\n" +
+        "hex:\n  %hp = getelementptr i8, ptr %sp, i64 2\n  %cp = call i32 @ami_rt_json_hex4(ptr %hp, ptr %end)\n  %i6 = add i64 %i, 6\n  %hs = and i32 %cp, 64512\n  %ishigh = icmp eq i32 %hs, 55296\n  br i1 %ishigh, label %pair, label %single\n" +
+        "pair:\n  %np = getelementptr i8, ptr %sp, i64 6\n  %b1 = call i1 @ami_rt_json_at(ptr %np, ptr %end, i8 92)\n  %np1 = getelementptr i8, ptr %np, i64 1\n  %b2 = call i1 @ami_rt_json_at(ptr %np1, ptr %end, i8 117)\n  %bu = and i1 %b1, %b2\n  br i1 %bu, label %low, label %single\n" +
+        "low:\n  %lp = getelementptr i8, ptr %np, i64 2\n  %lo = call i32 @ami_rt_json_hex4(ptr %lp, ptr %end)\n  %ls = and i32 %lo, 64512\n  %islow = icmp eq i32 %ls, 56320\n  br i1 %islow, label %combine, label %single\n" +
+        "combine:\n  %hoff = sub i32 %cp, 55296\n  %hsh = shl i32 %hoff, 10\n  %loff = sub i32 %lo, 56320\n  %sum = add i32 %hsh, %loff\n  %full = add i32 %sum, 65536\n  %i12 = add i64 %i, 12\n  br label %unicode\n" +
+        "single:\n  %surr = icmp eq i32 %hs, 55296\n  %ls2 = and i32 %cp, 63488\n  %anysurr = icmp eq i32 %ls2, 55296\n  %bad = or i1 %surr, %anysurr\n  %one = select i1 %bad, i32 65533, i32 %cp\n  br label %unicode\n" +
+        "unicode:\n  %u = phi i32 [ %full, %combine ], [ %one, %single ]\n  %iu = phi i64 [ %i12, %combine ], [ %i6, %single ]\n  %w = call i64 @ami_rt_json_put_utf8(ptr %dp, i32 %u)\n  %ju = add i64 %j, %w\n  br label %loop\n" +
+        "done:\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %j)\n  ret ptr %h\n}\n\n")
+    // Quotes s as encoding/json does with HTML escaping off: \" \\ \b \f \n \r \t,
+    // \u00xx for other control bytes and \u2028 and \u2029 for the line and paragraph
+    // separators. At most 6 bytes are written per input byte.
+    b.WriteString("define i8 @ami_rt_json_escape_byte(i8 %c) {\n" +
+        "entry:\n  switch i8 %c, label %none [ i8 34, label %same i8 92, label %same i8 8, label %bs i8 12, label %ff i8 10, label %nl i8 13, label %cr i8 9, label %tab ]\n" +
+        "none:\n  ret i8 0\n" +
+        "same:\n  ret i8 %c\n" +
+        "bs:\n  ret i8 98\n" +
+        "ff:\n  ret i8 102\n" +
+        "nl:\n  ret i8 110\n" +
+        "cr:\n  ret i8 114\n" +
+        "tab:\n  ret i8 116\n}\n\n")
+    b.WriteString("define void @ami_rt_json_put_u(ptr %d, i32 %cp) {\n" +
+        "entry:\n  store i8 92, ptr %d, align 1\n  %d1 = getelementptr i8, ptr %d, i64 1\n  store i8 117, ptr %d1, align 1\n  br label %loop\n" +
+        "loop:\n  %k = phi i64 [ 0, %entry ], [ %k1, %loop ]\n  %sh64 = sub i64 12, %k\n  %sh = trunc i64 %sh64 to i32\n  %nib = lshr i32 %cp, %sh\n  %x = and i32 %nib, 15\n  %xi = zext i32 %x to i64\n  %hp = getelementptr i8, ptr @.json.hex, i64 %xi\n  %hc = load i8, ptr %hp, align 1\n  %pos = udiv i64 %k, 4\n  %at = add i64 %pos, 2\n  %dp = getelementptr i8, ptr %d, i64 %at\n  store i8 %hc, ptr %dp, align 1\n  %k1 = add i64 %k, 4\n  %more = icmp ult i64 %k1, 16\n  br i1 %more, label %loop, label %done\n" +
+        "done:\n  ret void\n}\n\n")
+    b.WriteString("define ptr @ami_rt_json_quote(ptr %s) {\n" +
+        "entry:\n  %n = call i64 @ami_rt_string_len(ptr %s)\n  %src = call ptr @ami_rt_string_ptr(ptr %s)\n  %six = mul i64 %n, 6\n  %max = add i64 %six, 2\n  %buf = call ptr @malloc(i64 %max)\n  store i8 34, ptr %buf, align 1\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %inext, %next ]\n  %j = phi i64 [ 1, %entry ], [ %jnext, %next ]\n  %fin = icmp uge i64 %i, %n\n  br i1 %fin, label %done, label %body\n" +
+        "body:\n  %sp = getelementptr i8, ptr %src, i64 %i\n  %c = load i8, ptr %sp, align 1\n  %dp = getelementptr i8, ptr %buf, i64 %j\n  %i1 = add i64 %i, 1\n  %sc = call i8 @ami_rt_json_escape_byte(i8 %c)\n  %isshort = icmp ne i8 %sc, 0\n  br i1 %isshort, label %short, label %chkctl\n" +
+        "short:\n  store i8 92, ptr %dp, align 1\n  %dp1 = getelementptr i8, ptr %dp, i64 1\n  store i8 %sc, ptr %dp1, align 1\n  %js = add i64 %j, 2\n  br label %next\n" +
+        "chkctl:\n  %ctl = icmp ult i8 %c, 32\n  br i1 %ctl, label %ctlesc, label %chksep\n" +
+        "ctlesc:\n  %c32 = zext i8 %c to i32\n  call void @ami_rt_json_put_u(ptr %dp, i32 %c32)\n  %jc = add i64 %j, 6\n  br label %next\n" +
+        "chksep:\n  %lead = icmp eq i8 %c, -30\n  %i3 = add i64 %i, 3\n  %room = icmp ule i64 %i3, %n\n  %maybe = and i1 %lead, %room\n  br i1 %maybe, label %sep, label %copy\n" +
+        "sep:\n  %p1 = getelementptr i8, ptr %sp, i64 1\n  %c1 = load i8, ptr %p1, align 1\n  %p2 = getelementptr i8, ptr %sp, i64 2\n  %c2 = load i8, ptr %p2, align 1\n  %mid = icmp eq i8 %c1, -128\n  %c2m = and i8 %c2, -2\n  %tail = icmp eq i8 %c2m, -88\n  %issep = and i1 %mid, %tail\n  br i1 %issep, label %sepesc, label %copy\n" +
+        "sepesc:\n  %c2z = zext i8 %c2 to i32\n  %lastnib = and i32 %c2z, 1\n  %cpsep = add i32 8232, %lastnib\n  call void @ami_rt_json_put_u(ptr %dp, i32 %cpsep)\n  %jsep = add i64 %j, 6\n  br label %next\n" +
+        "copy:\n  store i8 %c, ptr %dp, align 1\n  %jcopy = add i64 %j, 1\n  br label %next\n" +
+        "next:\n  %inext = phi i64 [ %i1, %short ], [ %i1, %ctlesc ], [ %i3, %sepesc ], [ %i1, %copy ]\n  %jnext = phi i64 [ %js, %short ], [ %jc, %ctlesc ], [ %jsep, %sepesc ], [ %jcopy, %copy ]\n  br label %loop\n" +
+        "done:\n  %ep = getelementptr i8, ptr %buf, i64 %j\n  store i8 34, ptr %ep, align 1\n  %len = add i64 %j, 1\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %len)\n  ret ptr %h\n}\n\n")
+    // Decode helpers: the root value must be of the target's JSON kind.
+    b.WriteString("define { i1, ptr } @ami_rt_json_decode_i1(ptr %s, ptr %desc) {\n" +
+        "entry:\n  %vp = alloca ptr, align 8\n  %ve = alloca ptr, align 8\n  %err = call ptr @ami_rt_json_parse(ptr %s, ptr %vp, ptr %ve)\n  %failed = icmp ne ptr %err, null\n  br i1 %failed, label %fail, label %kind\n" +
+        "kind:\n  %v = load ptr, ptr %vp, align 8\n  %c = load i8, ptr %v, align 1\n  %ist = icmp eq i8 %c, 116\n  %isf = icmp eq i8 %c, 102\n  %isb = or i1 %ist, %isf\n  br i1 %isb, label %ok, label %mismatch\n" +
+        "ok:\n  %r = insertvalue { i1, ptr } zeroinitializer, i1 %ist, 0\n  ret { i1, ptr } %r\n" +
+        "mismatch:\n  %k = call ptr @ami_rt_json_kind(i8 %c)\n  %et = call ptr @ami_rt_json_type_error(ptr %desc, ptr %k)\n  br label %fail\n" +
+        "fail:\n  %e = phi ptr [ %err, %entry ], [ %et, %mismatch ]\n  %rf = insertvalue { i1, ptr } zeroinitializer, ptr %e, 1\n  ret { i1, ptr } %rf\n}\n\n")
+    b.WriteString("define { ptr, ptr } @ami_rt_json_decode_string(ptr %s, ptr %desc) {\n" +
+        "entry:\n  %vp = alloca ptr, align 8\n  %ve = alloca ptr, align 8\n  %err = call ptr @ami_rt_json_parse(ptr %s, ptr %vp, ptr %ve)\n  %failed = icmp ne ptr %err, null\n  br i1 %failed, label %fail, label %kind\n" +
+        "kind:\n  %v = load ptr, ptr %vp, align 8\n  %c = load i8, ptr %v, align 1\n  %isstr = icmp eq i8 %c, 34\n  br i1 %isstr, label %ok, label %mismatch\n" +
+        "ok:\n  %e0 = load ptr, ptr %ve, align 8\n  %body = getelementptr i8, ptr %v, i64 1\n  %close = getelementptr i8, ptr %e0, i64 -1\n  %h = call ptr @ami_rt_json_unquote(ptr %body, ptr %close)\n  %r = insertvalue { ptr, ptr } zeroinitializer, ptr %h, 0\n  ret { ptr, ptr } %r\n" +
+        "mismatch:\n  %k = call ptr @ami_rt_json_kind(i8 %c)\n  %et = call ptr @ami_rt_json_type_error(ptr %desc, ptr %k)\n  br label %fail\n" +
+        "fail:\n  %e = phi ptr [ %err, %entry ], [ %et, %mismatch ]\n  %rf = insertvalue { ptr, ptr } zeroinitializer, ptr %e, 1\n  ret { ptr, ptr } %rf\n}\n\n")
+    // int and int64 targets need an integral number in range, as strconv.ParseInt.
+    b.WriteString("define { i64, ptr } @ami_rt_json_decode_i64(ptr %s, ptr %desc) {\n" +
+        "entry:\n  %vp = alloca ptr, align 8\n  %ve = alloca ptr, align 8\n  %err = call ptr @ami_rt_json_parse(ptr %s, ptr %vp, ptr %ve)\n  %failed = icmp ne ptr %err, null\n  br i1 %failed, label %fail, label %kind\n" +
+        "kind:\n  %v = load ptr, ptr %vp, align 8\n  %c = load i8, ptr %v, align 1\n  %k = call ptr @ami_rt_json_kind(i8 %c)\n  %isnum = icmp eq ptr %k, @.json.kind.number\n  br i1 %isnum, label %number, label %mismatch\n" +
+        "number:\n  %e0 = load ptr, ptr %ve, align 8\n  %vi = ptrtoint ptr %v to i64\n  %ei = ptrtoint ptr %e0 to i64\n  %len = sub i64 %ei, %vi\n  %pr = call { i64, i1 } @ami_rt_parse_i64(ptr %v, i64 %len)\n  %ok = extractvalue { i64, i1 } %pr, 1\n  br i1 %ok, label %good, label %notint\n" +
+        "good:\n  %x = extractvalue { i64, i1 } %pr, 0\n  %r = insertvalue { i64, ptr } zeroinitializer, i64 %x, 0\n  ret { i64, ptr } %r\n" +
+        "notint:\n  %en = call ptr @ami_rt_json_number_error(ptr %desc, ptr %v, i64 %len)\n  br label %fail\n" +
+        "mismatch:\n  %et = call ptr @ami_rt_json_type_error(ptr %desc, ptr %k)\n  br label %fail\n" +
+        "fail:\n  %e = phi ptr [ %err, %entry ], [ %en, %notint ], [ %et, %mismatch ]\n  %rf = insertvalue { i64, ptr } zeroinitializer, ptr %e, 1\n  ret { i64, ptr } %rf\n}\n\n")
+    // Numbers beyond the float64 range are a type error, as in encoding/json.
+    b.WriteString("define { double, ptr } @ami_rt_json_decode_double(ptr %s, ptr %desc) {\n" +
+        "entry:\n  %vp = alloca ptr, align 8\n  %ve = alloca ptr, align 8\n  %err = call ptr @ami_rt_json_parse(ptr %s, ptr %vp, ptr %ve)\n  %failed = icmp ne ptr %err, null\n  br i1 %failed, label %fail, label %kind\n" +
+        "kind:\n  %v = load ptr, ptr %vp, align 8\n  %c = load i8, ptr %v, align 1\n  %k = call ptr @ami_rt_json_kind(i8 %c)\n  %isnum = icmp eq ptr %k, @.json.kind.number\n  br i1 %isnum, label %number, label %mismatch\n" +
+        "number:\n  %e0 = load ptr, ptr %ve, align 8\n  %vi = ptrtoint ptr %v to i64\n  %ei = ptrtoint ptr %e0 to i64\n  %len = sub i64 %ei, %vi\n  %buf = call ptr @ami_rt_dup_nul(ptr %v, i64 %len)\n  %x = call double @strtod(ptr %buf, ptr null)\n  call void @free(ptr %buf)\n  %pinf = fcmp oeq double %x, 0x7FF0000000000000\n  %ninf = fcmp oeq double %x, 0xFFF0000000000000\n  %inf = or i1 %pinf, %ninf\n  br i1 %inf, label %mismatch, label %good\n" +
+        "good:\n  %r = insertvalue { double, ptr } zeroinitializer, double %x, 0\n  ret { double, ptr } %r\n" +
+        "mismatch:\n  %et = call ptr @ami_rt_json_type_error(ptr %desc, ptr %k)\n  br label %fail\n" +
+        "fail:\n  %e = phi ptr [ %err, %entry ], [ %et, %mismatch ]\n  %rf = insertvalue { double, ptr } zeroinitializer, ptr %e, 1\n  ret { double, ptr } %rf\n}\n\n")
+    for _, abi := range jsonUnsupportedABIs {
+        ret := "{ " + abi + ", ptr }"
+        fmt.Fprintf(&b, "define %s @ami_rt_json_decode_unsupported_%s(ptr %%s, ptr %%desc) {\n"+
+            "entry:\n  %%vp = alloca ptr, align 8\n  %%ve = alloca ptr, align 8\n  %%err = call ptr @ami_rt_json_parse(ptr %%s, ptr %%vp, ptr %%ve)\n  %%failed = icmp ne ptr %%err, null\n  br i1 %%failed, label %%fail, label %%unsupported\n"+
+            "unsupported:\n  %%et = call ptr @ami_rt_json_type_error(ptr %%desc, ptr @.json.unsupported)\n  br label %%fail\n"+
+            "fail:\n  %%e = phi ptr [ %%err, %%entry ], [ %%et, %%unsupported ]\n  %%rf = insertvalue %s zeroinitializer, ptr %%e, 1\n  ret %s %%rf\n}\n\n", ret, abi, ret, ret)
+    }
+    // Encode helpers
+    b.WriteString("define { ptr, ptr } @ami_rt_json_encode_i1(i1 %v) {\n" +
+        "entry:\n  %h = call ptr @ami_rt_bool_to_string(i1 %v)\n  %r = insertvalue { ptr, ptr } zeroinitializer, ptr %h, 0\n  ret { ptr, ptr } %r\n}\n\n")
+    b.WriteString("define { ptr, ptr } @ami_rt_json_encode_i64(i64 %v) {\n" +
+        "entry:\n  %h = call ptr @ami_rt_int_to_string(i64 %v)\n  %r = insertvalue { ptr, ptr } zeroinitializer, ptr %h, 0\n  ret { ptr, ptr } %r\n}\n\n")
+    b.WriteString("define { ptr, ptr } @ami_rt_json_encode_u64(i64 %v) {\n" +
+        "entry:\n  %buf = call ptr @malloc(i64 24)\n  %n32 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 24, ptr @.json.fmt.uint, i64 %v)\n  %n = sext i32 %n32 to i64\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %n)\n  %r = insertvalue { ptr, ptr } zeroinitializer, ptr %h, 0\n  ret { ptr, ptr } %r\n}\n\n")
+    // Exponent form below 1e-6 or from 1e21 with single-digit negative exponents
+    // unpadded (1e-7), as encoding/json writes floats.
+    b.WriteString("define { ptr, ptr } @ami_rt_json_encode_double(double %v) {\n" +
+        "entry:\n  %nan = fcmp uno double %v, %v\n  %pinf = fcmp oeq double %v, 0x7FF0000000000000\n  %ninf = fcmp oeq double %v, 0xFFF0000000000000\n  %inf = or i1 %pinf, %ninf\n  %bad = or i1 %nan, %inf\n  br i1 %bad, label %unsupported, label %format\n" +
+        "unsupported:\n  %t = call ptr @ami_rt_float_to_string(double %v)\n  %tn = call i64 @ami_rt_string_len(ptr %t)\n  %tp = call ptr @ami_rt_string_ptr(ptr %t)\n  %tn32 = trunc i64 %tn to i32\n  %buf = call ptr @malloc(i64 48)\n  %mn32 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 48, ptr @.json.fmt.float, i32 %tn32, ptr %tp)\n  %mn = sext i32 %mn32 to i64\n  %e = call ptr @ami_rt_json_error(ptr @.json.code.encode, i64 13, ptr %buf, i64 %mn)\n  call void @free(ptr %buf)\n  call void @free(ptr %tp)\n  call void @free(ptr %t)\n  %rf = insertvalue { ptr, ptr } zeroinitializer, ptr %e, 1\n  ret { ptr, ptr } %rf\n" +
+        "format:\n  %h = call ptr @ami_rt_float_format(double %v, i64 -6, i64 21)\n  %n = call i64 @ami_rt_string_len(ptr %h)\n  %p = call ptr @ami_rt_string_ptr(ptr %h)\n  %long = icmp uge i64 %n, 4\n  br i1 %long, label %check, label %done\n" +
+        "check:\n  %i4 = sub i64 %n, 4\n  %p4 = getelementptr i8, ptr %p, i64 %i4\n  %c4 = load i8, ptr %p4, align 1\n  %p3 = getelementptr i8, ptr %p4, i64 1\n  %c3 = load i8, ptr %p3, align 1\n  %p2 = getelementptr i8, ptr %p4, i64 2\n  %c2 = load i8, ptr %p2, align 1\n  %ise = icmp eq i8 %c4, 101\n  %isneg = icmp eq i8 %c3, 45\n  %iszero = icmp eq i8 %c2, 48\n  %en = and i1 %ise, %isneg\n  %pad = and i1 %en, %iszero\n  br i1 %pad, label %trim, label %done\n" +
+        "trim:\n  %p1 = getelementptr i8, ptr %p4, i64 3\n  %c1 = load i8, ptr %p1, align 1\n  store i8 %c1, ptr %p2, align 1\n  %n1 = sub i64 %n, 1\n  %lp = getelementptr i8, ptr %h, i64 8\n  store i64 %n1, ptr %lp, align 8\n  br label %done\n" +
+        "done:\n  %r = insertvalue { ptr, ptr } zeroinitializer, ptr %h, 0\n  ret { ptr, ptr } %r\n}\n\n")
+    b.WriteString("define { ptr, ptr } @ami_rt_json_encode_string(ptr %s) {\n" +
+        "entry:\n  %h = call ptr @ami_rt_json_quote(ptr %s)\n  %r = insertvalue { ptr, ptr } zeroinitializer, ptr %h, 0\n  ret { ptr, ptr } %r\n}\n\n")
+    return b.String()
+}
@@ -0,0 +1,177 @@
+package llvm
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestJSONHelperDecl(t *testing.T) {
+    if got := jsonHelperDecl("ami_rt_json_decode_double"); got != "declare { double, ptr } @ami_rt_json_decode_double(ptr, ptr)" { t.Fatalf("decode: %q", got) }
+    if got := jsonHelperDecl("ami_rt_json_decode_string"); got != "declare { ptr, ptr } @ami_rt_json_decode_string(ptr, ptr)" { t.Fatalf("decode string: %q", got) }
+    if got := jsonHelperDecl("ami_rt_json_decode_unsupported_i32"); got != "declare { i32, ptr } @ami_rt_json_decode_unsupported_i32(ptr, ptr)" { t.Fatalf("unsupported: %q", got) }
+    if got := jsonHelperDecl("ami_rt_json_encode_u64"); got != "declare { ptr, ptr } @ami_rt_json_encode_u64(i64)" { t.Fatalf("encode: %q", got) }
+    for _, c := range []string{"ami_rt_json_decode", "ami_rt_json_decode_ptr", "ami_rt_json_decode_unsupported_ptr", "ami_rt_json_to_event"} {
+        if got := jsonHelperDecl(c); got != "" { t.Fatalf("%s: unexpected decl %q", c, got) }
+    }
+}
+
+// Call sites use the declared aggregate, narrow integers widen for encode_i64 and
+// structured values are rejected until the container ABI exists.
+func TestEmitter_JSONHelpers_CallMatchesDecl(t *testing.T) {
+    s := ir.Value{ID: "s", Type: "string"}
+    d := ir.Value{ID: "d", Type: "string"}
+    n := ir.Value{ID: "n", Type: "uint8"}
+    fn := ir.Function{Name: "F", Params: []ir.Value{s, d, n}, Blocks: []ir.Block{{Name: "entry", Instr: []ir.Instruction{
+        ir.Expr{Op: "call", Callee: "ami_rt_json_decode_i64", Args: []ir.Value{s, d}, Results: []ir.Value{{ID: "x", Type: "int"}, {ID: "err", Type: "error"}}},
+        ir.Expr{Op: "call", Callee: "ami_rt_error_is_set", Args: []ir.Value{{ID: "err", Type: "error"}}, Result: &ir.Value{ID: "bad", Type: "bool"}},
+        ir.Expr{Op: "call", Callee: "ami_rt_json_encode_i64", Args: []ir.Value{n}, Results: []ir.Value{{ID: "out", Type: "string"}, {ID: "eerr", Type: "error"}}},
+        ir.Return{},
+    }}}}
+    out, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{fn}})
+    if err != nil { t.Fatalf("emit: %v", err) }
+    for _, want := range []string{
+        "declare { i64, ptr } @ami_rt_json_decode_i64(ptr, ptr)",
+        "%call_tup_x = call { i64, ptr } @ami_rt_json_decode_i64(ptr %s, ptr %d)",
+        "%err = extractvalue { i64, ptr } %call_tup_x, 1",
+        "%bad = call i1 @ami_rt_error_is_set(ptr %err)",
+        "%out.arg = zext i8 %n to i64",
+        "call { ptr, ptr } @ami_rt_json_encode_i64(i64 %out.arg)",
+    } {
+        if !strings.Contains(out, want) { t.Fatalf("missing %q:\n%s", want, out) }
+    }
+    rt := RuntimeLL("", false)
+    for name := range jsonHelpers {
+        if !strings.Contains(rt, "@"+name+"(") { t.Fatalf("runtime does not define %s", name) }
+    }
+    for _, abi := range jsonUnsupportedABIs {
+        if !strings.Contains(rt, "@ami_rt_json_decode_unsupported_"+abi+"(") { t.Fatalf("runtime does not define unsupported_%s", abi) }
+    }
+    for _, callee := range []string{"ami_rt_json_decode_ptr", "ami_rt_json_encode_ptr"} {
+        bad := ir.Function{Name: "G", Params: []ir.Value{s, d}, Blocks: []ir.Block{{Name: "entry", Instr: []ir.Instruction{
+            ir.Expr{Op: "call", Callee: callee, Args: []ir.Value{s, d}, Results: []ir.Value{{ID: "o", Type: "Order"}, {ID: "err", Type: "error"}}},
+            ir.Return{},
+        }}}}
+        _, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{bad}})
+        if err == nil || !strings.Contains(err.Error(), "G: "+callee+" is not supported by the LLVM backend") { t.Fatalf("%s: %v", callee, err) }
+    }
+}
+
+// Runs the json helpers from runtime.ll in a pure-IR harness; prefers lli,
+// falling back to clang. Results match runtime/host/json.
+func TestJSONRuntime_PureIRHarness(t *testing.T) {
+    dir := t.TempDir()
+    orig := os.Getenv("AMI_GPU_BACKENDS")
+    _ = os.Setenv("AMI_GPU_BACKENDS", "cuda,opencl")
+    defer os.Setenv("AMI_GPU_BACKENDS", orig)
+    llp, err := WriteRuntimeLL(dir, DefaultTriple, false)
+    if err != nil { t.Fatalf("WriteRuntimeLL: %v", err) }
+    rtIR, err := os.ReadFile(llp)
+    if err != nil { t.Fatalf("read runtime.ll: %v", err) }
+    strs := []string{
+        "bool", "int", "float64", "string", "Duration",
+        " true ", "1", "-9223372036854775808", "1.5", `"3"`, "9223372036854775808",
+        "2.5e3", "1e400", `"a\"b\\c\n\u00e9\ud83d\ude00\ud800x"`, `{"a":[1,2,}`, `{"a":`, `{} {}`,
+        `[1,{"k":null}]`, "7", "a\"\\\t\x01 <> é\u2028",
+    }
+    var h strings.Builder
+    h.WriteString("\ndeclare i64 @write(i32, ptr, i64)\n")
+    h.WriteString(irConstBytes("nl", 0, []byte("\n")))
+    h.WriteString(irConstBytes("sp", 0, []byte(" ")))
+    for i, s := range strs { h.WriteString(irConstBytes("s", i, []byte(s))) }
+    // @put writes a handle's bytes and a newline; @err writes an error's code and
+    // message, or nothing when nil.
+    h.WriteString("define void @put(ptr %h) {\nentry:\n  %n = call i64 @ami_rt_string_len(ptr %h)\n  %p = call ptr @ami_rt_string_ptr(ptr %h)\n  %w0 = call i64 @write(i32 1, ptr %p, i64 %n)\n  %w1 = call i64 @write(i32 1, ptr @.nl.0, i64 1)\n  ret void\n}\n")
+    h.WriteString("define i1 @err(ptr %e) {\nentry:\n  %set = icmp ne ptr %e, null\n  br i1 %set, label %msg, label %ok\nmsg:\n  %c = call ptr @ami_rt_error_code_str(ptr %e)\n  %cn = call i64 @ami_rt_string_len(ptr %c)\n  %cp = call ptr @ami_rt_string_ptr(ptr %c)\n  %w0 = call i64 @write(i32 1, ptr %cp, i64 %cn)\n  %w1 = call i64 @write(i32 1, ptr @.sp.0, i64 1)\n  %m = call ptr @ami_rt_error_msg(ptr %e)\n  %mn = call i64 @ami_rt_error_len(ptr %e)\n  %w2 = call i64 @write(i32 1, ptr %m, i64 %mn)\n  %w3 = call i64 @write(i32 1, ptr @.nl.0, i64 1)\n  ret i1 true\nok:\n  ret i1 false\n}\n")
+    h.WriteString("define i32 @main() {\nentry:\n")
+    for i, s := range strs { fmt.Fprintf(&h, "  %%s%d = call ptr @ami_rt_owned_new(i8* @.s.%d, i64 %d)\n", i, i, len(s)) }
+    line := 0
+    // call invokes a helper returning { ty, ptr }; show formats its value when
+    // the error is nil.
+    call := func(ty, fn, args, show string) {
+        l := line
+        line++
+        fmt.Fprintf(&h, "  %%r%d = call { %s, ptr } @%s(%s)\n  %%v%d = extractvalue { %s, ptr } %%r%d, 0\n  %%e%d = extractvalue { %s, ptr } %%r%d, 1\n  %%f%d = call i1 @err(ptr %%e%d)\n  br i1 %%f%d, label %%n%d, label %%y%d\ny%d:\n", l, ty, fn, args, l, ty, l, l, ty, l, l, l, l, l, l, l)
+        if show == "" {
+            fmt.Fprintf(&h, "  call void @put(ptr %%v%d)\n", l)
+        } else {
+            fmt.Fprintf(&h, "  %%t%d = call ptr @%s(%s %%v%d)\n  call void @put(ptr %%t%d)\n", l, show, ty, l, l)
+        }
+        fmt.Fprintf(&h, "  br label %%n%d\nn%d:\n", l, l)
+    }
+    decode := func(abi string, doc, desc int, show string) {
+        fn := "ami_rt_json_decode_" + abi
+        ty := abi
+        switch abi {
+        case "string":
+            ty = "ptr"
+        case "unsupported_i64":
+            ty = "i64"
+        }
+        call(ty, fn, fmt.Sprintf("ptr %%s%d, ptr %%s%d", doc, desc), show)
+    }
+    decode("i1", 5, 0, "ami_rt_bool_to_string")
+    decode("i1", 6, 0, "ami_rt_bool_to_string")
+    decode("i64", 7, 1, "ami_rt_int_to_string")
+    decode("i64", 8, 1, "ami_rt_int_to_string")
+    decode("i64", 9, 1, "ami_rt_int_to_string")
+    decode("i64", 10, 1, "ami_rt_int_to_string")
+    decode("double", 11, 2, "ami_rt_float_to_string")
+    decode("double", 12, 2, "ami_rt_float_to_string")
+    decode("string", 13, 3, "")
+    decode("string", 14, 3, "")
+    decode("string", 15, 3, "")
+    decode("string", 16, 3, "")
+    decode("i64", 17, 1, "ami_rt_int_to_string")
+    decode("unsupported_i64", 18, 4, "ami_rt_int_to_string")
+    call("ptr", "ami_rt_json_encode_string", "ptr %s19", "")
+    call("ptr", "ami_rt_json_encode_i1", "i1 false", "")
+    call("ptr", "ami_rt_json_encode_i64", "i64 -5", "")
+    call("ptr", "ami_rt_json_encode_u64", "i64 -1", "")
+    for _, f := range []string{"1.000000e-07", "1.500000e-07", "1.000000e-06", "1.000000e+20", "1.000000e+21", "1.000000e-01", "0x7FF8000000000000", "0xFFF0000000000000"} {
+        call("ptr", "ami_rt_json_encode_double", "double "+f, "")
+    }
+    h.WriteString("  ret i32 0\n}\n")
+    want := strings.Join([]string{
+        "true",
+        "E_JSON_TYPE document: expected bool, got number",
+        "-9223372036854775808",
+        "E_JSON_TYPE document: expected int, got number 1.5",
+        "E_JSON_TYPE document: expected int, got string",
+        "E_JSON_TYPE document: expected int, got number 9223372036854775808",
+        "2500",
+        "E_JSON_TYPE document: expected float64, got number",
+        "a\"b\\c\né😀\uFFFDx",
+        "E_JSON_SYNTAX offset 11: invalid character",
+        "E_JSON_SYNTAX offset 5: unexpected end of input",
+        "E_JSON_SYNTAX offset 3: unexpected data after top-level value",
+        "E_JSON_TYPE document: expected int, got array",
+        "E_JSON_TYPE document: expected Duration, got unsupported target type",
+        `"a\"\\\t\u0001 <> é\u2028"`,
+        "false", "-5", "18446744073709551615",
+        "1e-7", "1.5e-7", "0.000001", "100000000000000000000", "1e+21", "0.1",
+        "E_JSON_ENCODE value: unsupported float NaN",
+        "E_JSON_ENCODE value: unsupported float -Inf",
+    }, "\n") + "\n"
+    comb := filepath.Join(dir, "combined.ll")
+    if err := os.WriteFile(comb, append(rtIR, h.String()...), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    var out []byte
+    if path, _ := exec.LookPath("lli"); path != "" {
+        out, err = exec.Command(path, comb).Output()
+    }
+    if out == nil || err != nil {
+        clang, cerr := FindClang()
+        if cerr != nil { t.Skip("lli/clang unavailable; skipping") }
+        bin := filepath.Join(dir, "harness.bin")
+        if o, err := exec.Command(clang, "-x", "ir", comb, "-o", bin, "-target", DefaultTriple).CombinedOutput(); err != nil {
+            t.Skipf("clang link failed: %v, out=%s", err, string(o))
+        }
+        if out, err = exec.Command(bin).Output(); err != nil { t.Fatalf("run: %v", err) }
+    }
+    if string(out) != want { t.Fatalf("output:\n%s\nwant:\n%s", out, want) }
+}
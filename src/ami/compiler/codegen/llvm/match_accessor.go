@@ -0,0 +1,36 @@
+package llvm
+
+import "strings"
+
+// matchTagCallees lists the runtime calls lowered matches use to read a subject's tag:
+// the enum member ordinal, the union alternative index, or 0/1 for None/Some. The
+// runtime has no variant ABI to read them from yet, so checkRuntimeCall rejects
+// these calls and the payload accessors below.
+var matchTagCallees = []string{"ami_rt_enum_tag", "ami_rt_union_tag", "ami_rt_optional_tag"}
+
+// matchAccessorParts lists the payload accessors used by lowered match arms. Each accessor
+// is named ami_rt_<part>_<abi>, takes the subject handle, and returns the bound value as
+// the LLVM type encoded by <abi>.
+var matchAccessorParts = []string{"union_val", "optional_val"}
+
+// isMatchTag reports whether callee reads a match subject's tag.
+func isMatchTag(callee string) bool {
+    for _, c := range matchTagCallees {
+        if c == callee { return true }
+    }
+    return false
+}
+
+// matchAccessorRet returns the LLVM return type of a match payload accessor, or ""
+// when the callee is not one.
+func matchAccessorRet(callee string) string {
+    for _, part := range matchAccessorParts {
+        prefix := "ami_rt_" + part + "_"
+        if !strings.HasPrefix(callee, prefix) { continue }
+        abi := strings.TrimPrefix(callee, prefix)
+        for _, a := range rangeAccessorABIs {
+            if a == abi { return abi }
+        }
+    }
+    return ""
+}
@@ -0,0 +1,38 @@
+package llvm
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestMatchAccessorRet(t *testing.T) {
+    cases := map[string]string{
+        "ami_rt_union_val_i64":    "i64",
+        "ami_rt_optional_val_ptr": "ptr",
+        "ami_rt_union_val_i128":   "",
+        "ami_rt_union_tag":        "",
+    }
+    for callee, want := range cases {
+        if got := matchAccessorRet(callee); got != want { t.Fatalf("%s: got %q want %q", callee, got, want) }
+    }
+}
+
+func TestEmitter_Match_RejectedUntilVariantABI(t *testing.T) {
+    o := ir.Value{ID: "o", Type: "Optional<int>"}
+    tag := ir.Value{ID: "tag", Type: "int64"}
+    v := ir.Value{ID: "v", Type: "int"}
+    for _, ins := range []ir.Instruction{
+        ir.Expr{Op: "call", Callee: "ami_rt_optional_tag", Args: []ir.Value{o}, Result: &tag},
+        ir.Expr{Op: "call", Callee: "ami_rt_optional_val_i64", Args: []ir.Value{o}, Result: &v},
+    } {
+        fn := ir.Function{Name: "F", Params: []ir.Value{o}, Blocks: []ir.Block{{Name: "entry", Instr: []ir.Instruction{ins, ir.Return{}}}}}
+        _, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{fn}})
+        if err == nil || !strings.Contains(err.Error(), "F: ami_rt_optional_") || !strings.Contains(err.Error(), "variant ABI") { t.Fatalf("err: %v", err) }
+    }
+    rt := RuntimeLL("", false)
+    for _, stub := range []string{"@ami_rt_enum_tag(", "@ami_rt_union_val_ptr("} {
+        if strings.Contains(rt, stub) { t.Fatalf("runtime still defines %s", stub) }
+    }
+}
@@ -39,6 +39,7 @@ func (e *ModuleEmitter) AddGlobal(def string) { if def != "" { e.globals = appen
 func (e *ModuleEmitter) AddFunction(fn ir.Function) error {
     s, err := lowerFunction(fn)
     if err != nil { return err }
+    for _, g := range stringLitGlobals(fn) { e.AddGlobal(g) }
     e.funcs = append(e.funcs, s)
     return nil
 }
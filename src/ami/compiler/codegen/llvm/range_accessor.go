@@ -0,0 +1,27 @@
+package llvm
+
+import "strings"
+
+// rangeAccessorParts lists the element accessors used by lowered range loops. Each
+// accessor is named ami_rt_<part>_<abi>, takes the collection handle and an i64
+// iteration index, and returns a scalar of the LLVM type encoded by <abi>. The runtime
+// has no container ABI to read them from yet, so checkRuntimeCall rejects these calls
+// and the slice, set and map length readers.
+var rangeAccessorParts = []string{"slice_at", "set_at", "map_key", "map_val"}
+
+// rangeAccessorABIs lists the LLVM return types an accessor may be specialized for.
+var rangeAccessorABIs = []string{"i1", "i8", "i16", "i32", "i64", "double", "ptr"}
+
+// rangeAccessorRet returns the LLVM return type of a range accessor callee, or ""
+// when the callee is not a range accessor.
+func rangeAccessorRet(callee string) string {
+    for _, part := range rangeAccessorParts {
+        prefix := "ami_rt_" + part + "_"
+        if !strings.HasPrefix(callee, prefix) { continue }
+        abi := strings.TrimPrefix(callee, prefix)
+        for _, a := range rangeAccessorABIs {
+            if a == abi { return abi }
+        }
+    }
+    return ""
+}
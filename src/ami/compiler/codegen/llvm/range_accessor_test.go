@@ -0,0 +1,41 @@
+package llvm
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestRangeAccessorRet(t *testing.T) {
+    cases := map[string]string{
+        "ami_rt_slice_at_i64":  "i64",
+        "ami_rt_set_at_ptr":    "ptr",
+        "ami_rt_map_key_i1":    "i1",
+        "ami_rt_map_val_double": "double",
+        "ami_rt_slice_at_i128": "",
+        "ami_rt_slice_len":     "",
+    }
+    for callee, want := range cases {
+        if got := rangeAccessorRet(callee); got != want { t.Fatalf("%s: got %q want %q", callee, got, want) }
+    }
+}
+
+func TestEmitter_RangeLoop_RejectedUntilContainerABI(t *testing.T) {
+    xs := ir.Value{ID: "xs", Type: "map<string,int>"}
+    n := ir.Value{ID: "n", Type: "int64"}
+    v := ir.Value{ID: "v", Type: "int"}
+    idx := ir.Value{ID: "i", Type: "int64"}
+    for _, ins := range []ir.Instruction{
+        ir.Expr{Op: "call", Callee: "ami_rt_map_len", Args: []ir.Value{xs}, Result: &n},
+        ir.Expr{Op: "call", Callee: "ami_rt_map_val_i64", Args: []ir.Value{xs, idx}, Result: &v},
+    } {
+        fn := ir.Function{Name: "F", Params: []ir.Value{xs}, Blocks: []ir.Block{{Name: "entry", Instr: []ir.Instruction{ins, ir.Return{}}}}}
+        _, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{fn}})
+        if err == nil || !strings.Contains(err.Error(), "F: ami_rt_map_") || !strings.Contains(err.Error(), "container ABI") { t.Fatalf("err: %v", err) }
+    }
+    rt := RuntimeLL("", false)
+    for _, stub := range []string{"@ami_rt_map_len(", "@ami_rt_slice_at_double("} {
+        if strings.Contains(rt, stub) { t.Fatalf("runtime still defines %s", stub) }
+    }
+}
@@ -54,21 +54,8 @@ func RuntimeLL(triple string, withMain bool) string {
         "gexit:\n  ; perform zeroize + free, then record handle\n  %p = call ptr @ami_rt_owned_ptr(ptr %h)\n  %n = call i64 @ami_rt_owned_len(ptr %h)\n  call void @ami_rt_zeroize(ptr %p, i64 %n)\n  call void @free(ptr %p)\n  call void @free(ptr %h)\n  %idx1 = load i64, ptr @ami_released_idx, align 8\n  %slot = urem i64 %idx1, %cap\n  %rt = getelementptr [256 x ptr], ptr @ami_released_tab, i64 0, i64 %slot\n  store ptr %h, ptr %rt, align 8\n  %idx2 = add i64 %idx1, 1\n  store i64 %idx2, ptr @ami_released_idx, align 8\n  ret void\n}\n\n"
     s += "declare i64 @llvm.umin.i64(i64, i64)\n"
     s += "declare i64 @llvm.ctlz.i64(i64, i1)\n\n"
-    // Error ABI: heap-allocated handle with code, message pointer and length.
-    // Layout: { i32 code; i8* msg; i64 len }
-    s += "%Error = type { i32, i8*, i64 }\n\n"
-    s += "define ptr @ami_rt_error_new(i32 %code, i8* %msg, i64 %len) {\n" +
-        "entry:\n  %mem = call ptr @malloc(i64 24)\n  ; store code\n  %cf = bitcast ptr %mem to ptr\n  store i32 %code, ptr %cf, align 4\n  ; allocate and copy message\n  %buf = call ptr @malloc(i64 %len)\n  call void @llvm.memcpy.p0.p0.i64(ptr %buf, ptr %msg, i64 %len, i1 false)\n  ; store msg ptr\n  %msgptr.i8 = getelementptr i8, ptr %mem, i64 8\n  %mp = bitcast ptr %msgptr.i8 to ptr\n  store ptr %buf, ptr %mp, align 8\n  ; store len\n  %lenptr.i8 = getelementptr i8, ptr %mem, i64 16\n  %lp = bitcast ptr %lenptr.i8 to ptr\n  store i64 %len, ptr %lp, align 8\n  ret ptr %mem\n}\n\n"
-    s += "define i32 @ami_rt_error_code(ptr %e) {\n" +
-        "entry:\n  %cf = bitcast ptr %e to ptr\n  %c = load i32, ptr %cf, align 4\n  ret i32 %c\n}\n\n"
-    s += "define ptr @ami_rt_error_msg(ptr %e) {\n" +
-        "entry:\n  %msgptr.i8 = getelementptr i8, ptr %e, i64 8\n  %mp = bitcast ptr %msgptr.i8 to ptr\n  %p = load ptr, ptr %mp, align 8\n  ret ptr %p\n}\n\n"
-    s += "define i64 @ami_rt_error_len(ptr %e) {\n" +
-        "entry:\n  %lenptr.i8 = getelementptr i8, ptr %e, i64 16\n  %lp = bitcast ptr %lenptr.i8 to ptr\n  %l = load i64, ptr %lp, align 8\n  ret i64 %l\n}\n\n"
-    s += "define void @ami_rt_error_free(ptr %e) {\n" +
-        "entry:\n  %msgptr.i8 = getelementptr i8, ptr %e, i64 8\n  %mp = bitcast ptr %msgptr.i8 to ptr\n  %p = load ptr, ptr %mp, align 8\n  call void @free(ptr %p)\n  call void @free(ptr %e)\n  ret void\n}\n\n"
-    // Convert error handle to C string (malloc'd) and set outlen
-    s += "define ptr @ami_rt_error_to_cstring(ptr %e, i32* %outlen) {\nentry:\n  %msg = call ptr @ami_rt_error_msg(ptr %e)\n  %len64 = call i64 @ami_rt_error_len(ptr %e)\n  %len = trunc i64 %len64 to i32\n  %sz = zext i32 %len to i64\n  %buf = call ptr @malloc(i64 %sz)\n  call void @llvm.memcpy.p0.p0.i64(ptr %buf, ptr %msg, i64 %sz, i1 false)\n  store i32 %len, ptr %outlen, align 4\n  ret ptr %buf\n}\n\n"
+    // Error ABI and structured error helpers (see error_runtime.go)
+    s += errorRuntimeLL()
     // GPU blocking submit: until an explicit runtime queue is added, accept an opaque
     // arg and return success (null Error). Callers should prefer direct dispatch lowerings.
     s += "define ptr @ami_rt_gpu_blocking_submit(ptr %arg) {\nentry:\n  ret ptr null\n}\n\n"
@@ -104,9 +91,10 @@ func RuntimeLL(triple string, withMain bool) string {
         s += "define ptr @ami_rt_metal_dispatch_blocking_ex(ptr %ctx, ptr %pipe, i64 %gx, i64 %gy, i64 %gz, i64 %tx, i64 %ty, i64 %tz, ptr %kinds, i64 %argc, ptr %bufs, ptr %bytes, i64* %lens) {\nentry:\n  ret ptr null\n}\n\n"
     }
 
-    // String/Slice length helpers (scaffold): return 0 until full ABI is wired.
-    s += "define i64 @ami_rt_string_len(ptr %s) {\nentry:\n  ret i64 0\n}\n\n"
-    s += "define i64 @ami_rt_slice_len(ptr %sl) {\nentry:\n  ret i64 0\n}\n\n"
+    // String helpers for interpolation and the strings stdlib (see string_runtime.go)
+    s += stringRuntimeLL()
+    // json stdlib helpers (see json_runtime.go)
+    s += jsonRuntimeLL()
 
     // No-op ingress spawner stub; real implementation will create threads/processes per ingress trigger.
     s += "define void @ami_rt_spawn_ingress(ptr %name) {\nentry:\n  ret void\n}\n\n"
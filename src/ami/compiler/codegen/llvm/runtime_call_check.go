@@ -0,0 +1,46 @@
+package llvm
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// rangeLenCallees lists the collection length readers only range loops call.
+var rangeLenCallees = map[string]bool{"ami_rt_slice_len": true, "ami_rt_set_len": true, "ami_rt_map_len": true}
+
+// unsupportedCallReason returns why the runtime cannot serve callee yet, or ""
+// when it can. Functions calling such helpers are rejected rather than linked
+// against helpers that would return zero values.
+func unsupportedCallReason(callee string) string {
+    switch {
+    case stringUnsupported[callee] != "":
+        return stringUnsupported[callee]
+    case jsonUnsupported[callee] != "":
+        return jsonUnsupported[callee]
+    case isMatchTag(callee) || matchAccessorRet(callee) != "":
+        return "match expressions need the enum, union and Optional variant ABI"
+    case rangeLenCallees[callee] || rangeAccessorRet(callee) != "":
+        return "range loops need the slice, set and map container ABI"
+    }
+    return ""
+}
+
+// checkRuntimeCall returns an error when ins calls a runtime helper the backend
+// cannot lower yet.
+func checkRuntimeCall(fn string, ins ir.Instruction) error {
+    var ex ir.Expr
+    switch v := ins.(type) {
+    case ir.Expr:
+        ex = v
+    case ir.Defer:
+        ex = v.Expr
+    default:
+        return nil
+    }
+    if ex.Op != "call" { return nil }
+    if why := unsupportedCallReason(ex.Callee); why != "" {
+        return fmt.Errorf("%s: %s is not supported by the LLVM backend yet (%s)", fn, ex.Callee, why)
+    }
+    return nil
+}
@@ -0,0 +1,273 @@
+package llvm
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// stringHelperSig is the LLVM signature of a string runtime helper.
+type stringHelperSig struct{ ret, params string }
+
+// stringHelpers lists the runtime helpers used by interpolated strings and the
+// strings stdlib, with their signatures. Strings are Owned handles; a null handle
+// is the empty string. Results are new handles owned by the caller.
+var stringHelpers = map[string]stringHelperSig{
+    "ami_rt_string_len":          {"i64", "ptr"},
+    "ami_rt_string_concat":       {"ptr", "ptr, ptr"},
+    "ami_rt_int_to_string":       {"ptr", "i64"},
+    "ami_rt_float_to_string":     {"ptr", "double"},
+    "ami_rt_bool_to_string":      {"ptr", "i1"},
+    "ami_rt_strings_contains":    {"i1", "ptr, ptr"},
+    "ami_rt_strings_has_prefix":  {"i1", "ptr, ptr"},
+    "ami_rt_strings_has_suffix":  {"i1", "ptr, ptr"},
+    "ami_rt_strings_index":       {"i64", "ptr, ptr"},
+    "ami_rt_strings_replace":     {"ptr", "ptr, ptr, ptr"},
+    "ami_rt_strings_trim":        {"ptr", "ptr, ptr"},
+    "ami_rt_strings_trim_space":  {"ptr", "ptr"},
+    "ami_rt_strings_to_upper":    {"ptr", "ptr"},
+    "ami_rt_strings_to_lower":    {"ptr", "ptr"},
+    "ami_rt_strings_parse_int":   {"{ i64, ptr }", "ptr"},
+    "ami_rt_strings_parse_float": {"{ double, ptr }", "ptr"},
+}
+
+// stringUnsupported lists the string helpers the runtime cannot define yet, with
+// the reason functions calling them are rejected.
+var stringUnsupported = map[string]string{
+    "ami_rt_any_to_string": "formatting a value of unknown type needs runtime type information",
+    "ami_rt_strings_split": "strings.Split and strings.Join need the slice container ABI",
+    "ami_rt_strings_join":  "strings.Split and strings.Join need the slice container ABI",
+}
+
+// stringParseCode is the error code of failed strings.ParseInt/ParseFloat calls.
+const stringParseCode = "E_STRINGS_PARSE"
+
+// stringHelperRet returns the type a string helper returns, or "" when callee is
+// not one.
+func stringHelperRet(callee string) string { return stringHelpers[callee].ret }
+
+// stringHelperDecl returns the extern declaration for a string helper, or "" when
+// callee is not one.
+func stringHelperDecl(callee string) string {
+    sig, ok := stringHelpers[callee]
+    if !ok { return "" }
+    return fmt.Sprintf("declare %s @%s(%s)", sig.ret, callee, sig.params)
+}
+
+// widenIntToStringArg sign- or zero-extends a narrow integer passed to
+// ami_rt_int_to_string or ami_rt_json_encode_i64, whose parameter is i64. It
+// returns the extension and the call rewritten to use it; ok is false when no
+// extension is needed.
+func widenIntToStringArg(e ir.Expr) (string, ir.Expr, bool) {
+    if e.Callee != "ami_rt_int_to_string" && e.Callee != "ami_rt_json_encode_i64" { return "", e, false }
+    if len(e.Args) != 1 || (e.Result == nil && len(e.Results) == 0) { return "", e, false }
+    a := e.Args[0]
+    ty := mapType(a.Type)
+    if ty != "i8" && ty != "i16" && ty != "i32" { return "", e, false }
+    if strings.HasPrefix(a.ID, "#") {
+        e.Args = []ir.Value{{ID: a.ID, Type: "int64"}}
+        return "", e, true
+    }
+    op := "sext"
+    if strings.HasPrefix(strings.TrimSpace(a.Type), "uint") { op = "zext" }
+    res := e.Result
+    if res == nil { res = &e.Results[0] }
+    w := ir.Value{ID: res.ID + ".arg", Type: "int64"}
+    e.Args = []ir.Value{w}
+    return fmt.Sprintf("  %%%s = %s %s %%%s to i64\n", w.ID, op, ty, a.ID), e, true
+}
+
+// stringLit returns the value of a string literal expression (op "lit:<quoted>"
+// with a string result); ok is false for other expressions.
+func stringLit(e ir.Expr) (string, bool) {
+    if !strings.HasPrefix(e.Op, "lit:") || e.Result == nil || e.Result.ID == "" { return "", false }
+    if strings.TrimSpace(e.Result.Type) != "string" { return "", false }
+    s, err := strconv.Unquote(strings.TrimPrefix(e.Op, "lit:"))
+    if err != nil { return "", false }
+    return s, true
+}
+
+// stringLitName names the constant holding the bytes of literal e in function fn.
+func stringLitName(fn string, e ir.Expr) string { return "@.str." + fn + "." + e.Result.ID }
+
+// stringLitGlobals returns the constants for the string literals in fn.
+func stringLitGlobals(fn ir.Function) []string {
+    var out []string
+    for _, blk := range fn.Blocks {
+        for _, ins := range blk.Instr {
+            ex, ok := ins.(ir.Expr)
+            if !ok { continue }
+            if s, ok := stringLit(ex); ok {
+                out = append(out, fmt.Sprintf("%s = private constant [%d x i8] c\"%s\"", stringLitName(fn.Name, ex), len(s)+1, encodeCString(s)))
+            }
+        }
+    }
+    return out
+}
+
+// lowerStringLit copies the constant of literal e into a new Owned handle.
+func lowerStringLit(fn string, e ir.Expr) string {
+    s, _ := stringLit(e)
+    return fmt.Sprintf("  %%%s = call ptr @ami_rt_owned_new(i8* %s, i64 %d)\n", e.Result.ID, stringLitName(fn, e), len(s))
+}
+
+// stringRuntimeLL returns definitions for every helper in stringHelpers. Lengths
+// and indexes count bytes; case conversion and TrimSpace are ASCII-only. Floats
+// are formatted like strconv.FormatFloat(v, 'g', -1, 64).
+func stringRuntimeLL() string {
+    space := " \t\n\v\f\r"
+    intMsg := "invalid base-10 int64"
+    floatMsg := "invalid float64"
+    s := "declare i32 @memcmp(ptr, ptr, i64)\n"
+    s += "declare { i64, i1 } @llvm.smul.with.overflow.i64(i64, i64)\n"
+    s += "declare { i64, i1 } @llvm.ssub.with.overflow.i64(i64, i64)\n\n"
+    s += "@.str.true = private constant [5 x i8] c\"true\\00\"\n"
+    s += "@.str.false = private constant [6 x i8] c\"false\\00\"\n"
+    s += "@.str.fmt.int = private constant [5 x i8] c\"%lld\\00\"\n"
+    s += "@.str.fmt.exp = private constant [5 x i8] c\"%.*e\\00\"\n"
+    s += "@.str.fmt.fixed = private constant [5 x i8] c\"%.*f\\00\"\n"
+    s += "@.str.nan = private constant [4 x i8] c\"NaN\\00\"\n"
+    s += "@.str.pinf = private constant [5 x i8] c\"+Inf\\00\"\n"
+    s += "@.str.ninf = private constant [5 x i8] c\"-Inf\\00\"\n"
+    s += "@.str.space = private constant [" + itoa(len(space)+1) + " x i8] c\"" + encodeCString(space) + "\"\n"
+    s += "@.str.parse.code = private constant [" + itoa(len(stringParseCode)+1) + " x i8] c\"" + encodeCString(stringParseCode) + "\"\n"
+    s += "@.str.parse.int = private constant [" + itoa(len(intMsg)+1) + " x i8] c\"" + encodeCString(intMsg) + "\"\n"
+    s += "@.str.parse.float = private constant [" + itoa(len(floatMsg)+1) + " x i8] c\"" + encodeCString(floatMsg) + "\"\n\n"
+    // Handle accessors treating a null handle as the empty string.
+    s += "define i64 @ami_rt_string_len(ptr %s) {\n" +
+        "entry:\n  %nil = icmp eq ptr %s, null\n  br i1 %nil, label %empty, label %some\n" +
+        "empty:\n  ret i64 0\n" +
+        "some:\n  %n = call i64 @ami_rt_owned_len(ptr %s)\n  ret i64 %n\n}\n\n"
+    s += "define ptr @ami_rt_string_ptr(ptr %s) {\n" +
+        "entry:\n  %nil = icmp eq ptr %s, null\n  br i1 %nil, label %empty, label %some\n" +
+        "empty:\n  ret ptr null\n" +
+        "some:\n  %p = call ptr @ami_rt_owned_ptr(ptr %s)\n  ret ptr %p\n}\n\n"
+    // Wraps a malloc'd buffer of n bytes in a handle without copying it.
+    s += "define ptr @ami_rt_string_adopt(ptr %buf, i64 %n) {\n" +
+        "entry:\n  %h = call ptr @malloc(i64 16)\n  store ptr %buf, ptr %h, align 8\n  %lp = getelementptr i8, ptr %h, i64 8\n  store i64 %n, ptr %lp, align 8\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_string_concat(ptr %a, ptr %b) {\n" +
+        "entry:\n  %la = call i64 @ami_rt_string_len(ptr %a)\n  %pa = call ptr @ami_rt_string_ptr(ptr %a)\n  %lb = call i64 @ami_rt_string_len(ptr %b)\n  %pb = call ptr @ami_rt_string_ptr(ptr %b)\n  %n = add i64 %la, %lb\n  %buf = call ptr @malloc(i64 %n)\n  call void @llvm.memcpy.p0.p0.i64(ptr %buf, ptr %pa, i64 %la, i1 false)\n  %dst = getelementptr i8, ptr %buf, i64 %la\n  call void @llvm.memcpy.p0.p0.i64(ptr %dst, ptr %pb, i64 %lb, i1 false)\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %n)\n  ret ptr %h\n}\n\n"
+    // Formatting
+    s += "define ptr @ami_rt_int_to_string(i64 %v) {\n" +
+        "entry:\n  %buf = call ptr @malloc(i64 24)\n  %n32 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 24, ptr @.str.fmt.int, i64 %v)\n  %n = sext i32 %n32 to i64\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %n)\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_float_to_string(double %v) {\n" +
+        "entry:\n  %nan = fcmp uno double %v, %v\n  br i1 %nan, label %isnan, label %chkinf\n" +
+        "isnan:\n  %hn = call ptr @ami_rt_owned_new(i8* @.str.nan, i64 3)\n  ret ptr %hn\n" +
+        "chkinf:\n  %pinf = fcmp oeq double %v, 0x7FF0000000000000\n  %ninf = fcmp oeq double %v, 0xFFF0000000000000\n  %inf = or i1 %pinf, %ninf\n  br i1 %inf, label %isinf, label %finite\n" +
+        "isinf:\n  %ip = select i1 %pinf, ptr @.str.pinf, ptr @.str.ninf\n  %hi = call ptr @ami_rt_owned_new(i8* %ip, i64 4)\n  ret ptr %hi\n" +
+        "finite:\n  %h = call ptr @ami_rt_float_format(double %v, i64 -4, i64 6)\n  ret ptr %h\n}\n\n"
+    // Formats a finite v with the shortest %e digits that parse back, then uses
+    // exponent form when the decimal exponent is below lo or at least hi and fixed
+    // form otherwise (strconv's 'g' layout for lo=-4, hi=6).
+    s += "define ptr @ami_rt_float_format(double %v, i64 %lo, i64 %hi) {\n" +
+        "entry:\n  %buf = call ptr @malloc(i64 40)\n  br label %try\n" +
+        "try:\n  %prec = phi i32 [ 0, %entry ], [ %prec1, %next ]\n  %ne = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 40, ptr @.str.fmt.exp, i32 %prec, double %v)\n  %back = call double @strtod(ptr %buf, ptr null)\n  %same = fcmp oeq double %back, %v\n  %last = icmp sge i32 %prec, 16\n  %stop = or i1 %same, %last\n  br i1 %stop, label %exp, label %next\n" +
+        "next:\n  %prec1 = add i32 %prec, 1\n  br label %try\n" +
+        "exp:\n  %ne64 = sext i32 %ne to i64\n  br label %scan\n" +
+        "scan:\n  %j = phi i64 [ %ne64, %exp ], [ %j1, %scan ]\n  %j1 = sub i64 %j, 1\n  %cp = getelementptr i8, ptr %buf, i64 %j1\n  %c = load i8, ptr %cp, align 1\n  %ise = icmp eq i8 %c, 101\n  br i1 %ise, label %found, label %scan\n" +
+        "found:\n  %xp = getelementptr i8, ptr %cp, i64 1\n  %x = call i64 @strtoll(ptr %xp, ptr null, i32 10)\n  %tiny = icmp slt i64 %x, %lo\n  %huge = icmp sge i64 %x, %hi\n  %useexp = or i1 %tiny, %huge\n  br i1 %useexp, label %done, label %fixed\n" +
+        "fixed:\n  %prec64 = sext i32 %prec to i64\n  %dec = sub i64 %prec64, %x\n  %neg = icmp slt i64 %dec, 0\n  %dec0 = select i1 %neg, i64 0, i64 %dec\n  %dec32 = trunc i64 %dec0 to i32\n  %nf = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buf, i64 40, ptr @.str.fmt.fixed, i32 %dec32, double %v)\n  br label %done\n" +
+        "done:\n  %n32 = phi i32 [ %ne, %found ], [ %nf, %fixed ]\n  %n = sext i32 %n32 to i64\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %n)\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_bool_to_string(i1 %b) {\n" +
+        "entry:\n  %p = select i1 %b, ptr @.str.true, ptr @.str.false\n  %n = select i1 %b, i64 4, i64 5\n  %h = call ptr @ami_rt_owned_new(i8* %p, i64 %n)\n  ret ptr %h\n}\n\n"
+    // Searching
+    s += "define i64 @ami_rt_strings_index(ptr %s, ptr %sub) {\n" +
+        "entry:\n  %ls = call i64 @ami_rt_string_len(ptr %s)\n  %ps = call ptr @ami_rt_string_ptr(ptr %s)\n  %lt = call i64 @ami_rt_string_len(ptr %sub)\n  %pt = call ptr @ami_rt_string_ptr(ptr %sub)\n  %none = icmp eq i64 %lt, 0\n  br i1 %none, label %first, label %check\n" +
+        "first:\n  ret i64 0\n" +
+        "check:\n  %fits = icmp ule i64 %lt, %ls\n  br i1 %fits, label %start, label %miss\n" +
+        "start:\n  %last = sub i64 %ls, %lt\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %start ], [ %i1, %next ]\n  %at = getelementptr i8, ptr %ps, i64 %i\n  %c = call i32 @memcmp(ptr %at, ptr %pt, i64 %lt)\n  %eq = icmp eq i32 %c, 0\n  br i1 %eq, label %hit, label %next\n" +
+        "next:\n  %i1 = add i64 %i, 1\n  %more = icmp ule i64 %i1, %last\n  br i1 %more, label %loop, label %miss\n" +
+        "hit:\n  ret i64 %i\n" +
+        "miss:\n  ret i64 -1\n}\n\n"
+    s += "define i1 @ami_rt_strings_contains(ptr %s, ptr %sub) {\n" +
+        "entry:\n  %i = call i64 @ami_rt_strings_index(ptr %s, ptr %sub)\n  %ok = icmp sge i64 %i, 0\n  ret i1 %ok\n}\n\n"
+    // Reports whether s starts (end=false) or ends (end=true) with x.
+    s += "define i1 @ami_rt_string_match_at(ptr %s, ptr %x, i1 %end) {\n" +
+        "entry:\n  %ls = call i64 @ami_rt_string_len(ptr %s)\n  %ps = call ptr @ami_rt_string_ptr(ptr %s)\n  %lx = call i64 @ami_rt_string_len(ptr %x)\n  %px = call ptr @ami_rt_string_ptr(ptr %x)\n  %none = icmp eq i64 %lx, 0\n  br i1 %none, label %yes, label %check\n" +
+        "yes:\n  ret i1 true\n" +
+        "check:\n  %fits = icmp ule i64 %lx, %ls\n  br i1 %fits, label %cmp, label %no\n" +
+        "cmp:\n  %tail = sub i64 %ls, %lx\n  %off = select i1 %end, i64 %tail, i64 0\n  %at = getelementptr i8, ptr %ps, i64 %off\n  %c = call i32 @memcmp(ptr %at, ptr %px, i64 %lx)\n  %eq = icmp eq i32 %c, 0\n  ret i1 %eq\n" +
+        "no:\n  ret i1 false\n}\n\n"
+    s += "define i1 @ami_rt_strings_has_prefix(ptr %s, ptr %p) {\n" +
+        "entry:\n  %ok = call i1 @ami_rt_string_match_at(ptr %s, ptr %p, i1 false)\n  ret i1 %ok\n}\n\n"
+    s += "define i1 @ami_rt_strings_has_suffix(ptr %s, ptr %p) {\n" +
+        "entry:\n  %ok = call i1 @ami_rt_string_match_at(ptr %s, ptr %p, i1 true)\n  ret i1 %ok\n}\n\n"
+    // Replace: a counting pass (dst null) sizes the buffer, a second pass fills it.
+    // An empty old string leaves s unchanged.
+    s += "define void @ami_rt_string_copy_if(ptr %dst, i64 %off, ptr %src, i64 %n) {\n" +
+        "entry:\n  %skip = icmp eq ptr %dst, null\n  br i1 %skip, label %done, label %copy\n" +
+        "copy:\n  %at = getelementptr i8, ptr %dst, i64 %off\n  call void @llvm.memcpy.p0.p0.i64(ptr %at, ptr %src, i64 %n, i1 false)\n  br label %done\n" +
+        "done:\n  ret void\n}\n\n"
+    s += "define i64 @ami_rt_string_replace_into(ptr %ps, i64 %ls, ptr %po, i64 %lo, ptr %pr, i64 %lr, ptr %dst) {\n" +
+        "entry:\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %i1, %keep ], [ %i2, %hit ]\n  %o = phi i64 [ 0, %entry ], [ %o1, %keep ], [ %o2, %hit ]\n  %end = icmp uge i64 %i, %ls\n  br i1 %end, label %done, label %test\n" +
+        "test:\n  %rem = sub i64 %ls, %i\n  %fits = icmp uge i64 %rem, %lo\n  %at = getelementptr i8, ptr %ps, i64 %i\n  br i1 %fits, label %cmp, label %keep\n" +
+        "cmp:\n  %c = call i32 @memcmp(ptr %at, ptr %po, i64 %lo)\n  %eq = icmp eq i32 %c, 0\n  br i1 %eq, label %hit, label %keep\n" +
+        "keep:\n  call void @ami_rt_string_copy_if(ptr %dst, i64 %o, ptr %at, i64 1)\n  %o1 = add i64 %o, 1\n  %i1 = add i64 %i, 1\n  br label %loop\n" +
+        "hit:\n  call void @ami_rt_string_copy_if(ptr %dst, i64 %o, ptr %pr, i64 %lr)\n  %o2 = add i64 %o, %lr\n  %i2 = add i64 %i, %lo\n  br label %loop\n" +
+        "done:\n  ret i64 %o\n}\n\n"
+    s += "define ptr @ami_rt_strings_replace(ptr %s, ptr %old, ptr %repl) {\n" +
+        "entry:\n  %ls = call i64 @ami_rt_string_len(ptr %s)\n  %ps = call ptr @ami_rt_string_ptr(ptr %s)\n  %lo = call i64 @ami_rt_string_len(ptr %old)\n  %po = call ptr @ami_rt_string_ptr(ptr %old)\n  %lr = call i64 @ami_rt_string_len(ptr %repl)\n  %pr = call ptr @ami_rt_string_ptr(ptr %repl)\n  %noold = icmp eq i64 %lo, 0\n  br i1 %noold, label %same, label %go\n" +
+        "same:\n  %h0 = call ptr @ami_rt_owned_new(i8* %ps, i64 %ls)\n  ret ptr %h0\n" +
+        "go:\n  %n = call i64 @ami_rt_string_replace_into(ptr %ps, i64 %ls, ptr %po, i64 %lo, ptr %pr, i64 %lr, ptr null)\n  %buf = call ptr @malloc(i64 %n)\n  %w = call i64 @ami_rt_string_replace_into(ptr %ps, i64 %ls, ptr %po, i64 %lo, ptr %pr, i64 %lr, ptr %buf)\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %n)\n  ret ptr %h\n}\n\n"
+    // Trimming: drop leading/trailing bytes found in the n-byte set.
+    s += "define i1 @ami_rt_string_byte_in(i8 %c, ptr %set, i64 %n) {\n" +
+        "entry:\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %i1, %next ]\n  %end = icmp uge i64 %i, %n\n  br i1 %end, label %no, label %body\n" +
+        "body:\n  %at = getelementptr i8, ptr %set, i64 %i\n  %b = load i8, ptr %at, align 1\n  %eq = icmp eq i8 %b, %c\n  br i1 %eq, label %yes, label %next\n" +
+        "next:\n  %i1 = add i64 %i, 1\n  br label %loop\n" +
+        "yes:\n  ret i1 true\n" +
+        "no:\n  ret i1 false\n}\n\n"
+    s += "define ptr @ami_rt_string_trim_set(ptr %s, ptr %set, i64 %n) {\n" +
+        "entry:\n  %ls = call i64 @ami_rt_string_len(ptr %s)\n  %ps = call ptr @ami_rt_string_ptr(ptr %s)\n  br label %left\n" +
+        "left:\n  %l = phi i64 [ 0, %entry ], [ %l1, %ladv ]\n  %lend = icmp uge i64 %l, %ls\n  br i1 %lend, label %right, label %lchk\n" +
+        "lchk:\n  %lp = getelementptr i8, ptr %ps, i64 %l\n  %lc = load i8, ptr %lp, align 1\n  %lin = call i1 @ami_rt_string_byte_in(i8 %lc, ptr %set, i64 %n)\n  br i1 %lin, label %ladv, label %right\n" +
+        "ladv:\n  %l1 = add i64 %l, 1\n  br label %left\n" +
+        "right:\n  %r = phi i64 [ %ls, %left ], [ %ls, %lchk ], [ %ri, %rtest ]\n  %rend = icmp ule i64 %r, %l\n  br i1 %rend, label %done, label %rtest\n" +
+        "rtest:\n  %ri = sub i64 %r, 1\n  %rp = getelementptr i8, ptr %ps, i64 %ri\n  %rc = load i8, ptr %rp, align 1\n  %rin = call i1 @ami_rt_string_byte_in(i8 %rc, ptr %set, i64 %n)\n  br i1 %rin, label %right, label %done\n" +
+        "done:\n  %len = sub i64 %r, %l\n  %start = getelementptr i8, ptr %ps, i64 %l\n  %h = call ptr @ami_rt_owned_new(i8* %start, i64 %len)\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_strings_trim(ptr %s, ptr %cutset) {\n" +
+        "entry:\n  %lc = call i64 @ami_rt_string_len(ptr %cutset)\n  %pc = call ptr @ami_rt_string_ptr(ptr %cutset)\n  %h = call ptr @ami_rt_string_trim_set(ptr %s, ptr %pc, i64 %lc)\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_strings_trim_space(ptr %s) {\n" +
+        "entry:\n  %h = call ptr @ami_rt_string_trim_set(ptr %s, ptr @.str.space, i64 " + itoa(len(space)) + ")\n  ret ptr %h\n}\n\n"
+    // Case conversion: adds delta to bytes in [lo, hi].
+    s += "define ptr @ami_rt_string_map_case(ptr %s, i8 %lo, i8 %hi, i8 %delta) {\n" +
+        "entry:\n  %n = call i64 @ami_rt_string_len(ptr %s)\n  %p = call ptr @ami_rt_string_ptr(ptr %s)\n  %buf = call ptr @malloc(i64 %n)\n  br label %loop\n" +
+        "loop:\n  %i = phi i64 [ 0, %entry ], [ %i1, %body ]\n  %end = icmp uge i64 %i, %n\n  br i1 %end, label %done, label %body\n" +
+        "body:\n  %sp = getelementptr i8, ptr %p, i64 %i\n  %c = load i8, ptr %sp, align 1\n  %ge = icmp uge i8 %c, %lo\n  %le = icmp ule i8 %c, %hi\n  %in = and i1 %ge, %le\n  %mc = add i8 %c, %delta\n  %o = select i1 %in, i8 %mc, i8 %c\n  %dp = getelementptr i8, ptr %buf, i64 %i\n  store i8 %o, ptr %dp, align 1\n  %i1 = add i64 %i, 1\n  br label %loop\n" +
+        "done:\n  %h = call ptr @ami_rt_string_adopt(ptr %buf, i64 %n)\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_strings_to_upper(ptr %s) {\n" +
+        "entry:\n  %h = call ptr @ami_rt_string_map_case(ptr %s, i8 97, i8 122, i8 -32)\n  ret ptr %h\n}\n\n"
+    s += "define ptr @ami_rt_strings_to_lower(ptr %s) {\n" +
+        "entry:\n  %h = call ptr @ami_rt_string_map_case(ptr %s, i8 65, i8 90, i8 32)\n  ret ptr %h\n}\n\n"
+    // Parsing: failures return the zero value and an E_STRINGS_PARSE error.
+    s += "define ptr @ami_rt_strings_parse_error(ptr %msg, i64 %n) {\n" +
+        "entry:\n  %c = call ptr @ami_rt_owned_new(i8* @.str.parse.code, i64 " + itoa(len(stringParseCode)) + ")\n  %m = call ptr @ami_rt_owned_new(i8* %msg, i64 %n)\n  %e = call ptr @ami_rt_error_make(ptr %c, ptr %m)\n  ; error_make copies both strings\n  %cb = call ptr @ami_rt_owned_ptr(ptr %c)\n  call void @free(ptr %cb)\n  call void @free(ptr %c)\n  %mb = call ptr @ami_rt_owned_ptr(ptr %m)\n  call void @free(ptr %mb)\n  call void @free(ptr %m)\n  ret ptr %e\n}\n\n"
+    // Optional sign and at least one digit; accumulates negatively so the minimum
+    // int64 parses, then negates unless the sign was '-'. ok is false for
+    // malformed or out-of-range input.
+    s += "define { i64, i1 } @ami_rt_parse_i64(ptr %p, i64 %n) {\n" +
+        "entry:\n  %empty = icmp eq i64 %n, 0\n  br i1 %empty, label %bad, label %sign\n" +
+        "sign:\n  %c0 = load i8, ptr %p, align 1\n  %minus = icmp eq i8 %c0, 45\n  %plus = icmp eq i8 %c0, 43\n  %signed = or i1 %minus, %plus\n  %start = zext i1 %signed to i64\n  %nodigits = icmp eq i64 %start, %n\n  br i1 %nodigits, label %bad, label %loop\n" +
+        "loop:\n  %i = phi i64 [ %start, %sign ], [ %i1, %digit ]\n  %acc = phi i64 [ 0, %sign ], [ %acc1, %digit ]\n  %end = icmp eq i64 %i, %n\n  br i1 %end, label %finish, label %body\n" +
+        "body:\n  %cp = getelementptr i8, ptr %p, i64 %i\n  %c = load i8, ptr %cp, align 1\n  %d8 = sub i8 %c, 48\n  %isdigit = icmp ult i8 %d8, 10\n  br i1 %isdigit, label %digit, label %bad\n" +
+        "digit:\n  %d = zext i8 %d8 to i64\n  %m = call { i64, i1 } @llvm.smul.with.overflow.i64(i64 %acc, i64 10)\n  %mv = extractvalue { i64, i1 } %m, 0\n  %mo = extractvalue { i64, i1 } %m, 1\n  %sb = call { i64, i1 } @llvm.ssub.with.overflow.i64(i64 %mv, i64 %d)\n  %acc1 = extractvalue { i64, i1 } %sb, 0\n  %so = extractvalue { i64, i1 } %sb, 1\n  %ovf = or i1 %mo, %so\n  %i1 = add i64 %i, 1\n  br i1 %ovf, label %bad, label %loop\n" +
+        "finish:\n  %ismin = icmp eq i64 %acc, -9223372036854775808\n  %posmin = xor i1 %minus, true\n  %toobig = and i1 %ismin, %posmin\n  br i1 %toobig, label %bad, label %ok\n" +
+        "ok:\n  %neg = sub i64 0, %acc\n  %v = select i1 %minus, i64 %acc, i64 %neg\n  %r = insertvalue { i64, i1 } { i64 0, i1 true }, i64 %v, 0\n  ret { i64, i1 } %r\n" +
+        "bad:\n  ret { i64, i1 } zeroinitializer\n}\n\n"
+    s += "define { i64, ptr } @ami_rt_strings_parse_int(ptr %s) {\n" +
+        "entry:\n  %n = call i64 @ami_rt_string_len(ptr %s)\n  %p = call ptr @ami_rt_string_ptr(ptr %s)\n  %pr = call { i64, i1 } @ami_rt_parse_i64(ptr %p, i64 %n)\n  %ok = extractvalue { i64, i1 } %pr, 1\n  br i1 %ok, label %good, label %bad\n" +
+        "good:\n  %v = extractvalue { i64, i1 } %pr, 0\n  %r = insertvalue { i64, ptr } zeroinitializer, i64 %v, 0\n  ret { i64, ptr } %r\n" +
+        "bad:\n  %e = call ptr @ami_rt_strings_parse_error(ptr @.str.parse.int, i64 " + itoa(len(intMsg)) + ")\n  %rb = insertvalue { i64, ptr } zeroinitializer, ptr %e, 1\n  ret { i64, ptr } %rb\n}\n\n"
+    // strtod must consume every byte; leading white space is rejected.
+    s += "define { double, ptr } @ami_rt_strings_parse_float(ptr %s) {\n" +
+        "entry:\n  %endp = alloca ptr, align 8\n  %n = call i64 @ami_rt_string_len(ptr %s)\n  %p = call ptr @ami_rt_string_ptr(ptr %s)\n  %empty = icmp eq i64 %n, 0\n  br i1 %empty, label %bad, label %lead\n" +
+        "lead:\n  %c0 = load i8, ptr %p, align 1\n  %ws = call i1 @ami_rt_string_byte_in(i8 %c0, ptr @.str.space, i64 " + itoa(len(space)) + ")\n  br i1 %ws, label %bad, label %parse\n" +
+        "parse:\n  %buf = call ptr @ami_rt_dup_nul(ptr %p, i64 %n)\n  %v = call double @strtod(ptr %buf, ptr %endp)\n  %stop = load ptr, ptr %endp, align 8\n  %want = getelementptr i8, ptr %buf, i64 %n\n  %all = icmp eq ptr %stop, %want\n  call void @free(ptr %buf)\n  br i1 %all, label %ok, label %bad\n" +
+        "ok:\n  %r = insertvalue { double, ptr } zeroinitializer, double %v, 0\n  ret { double, ptr } %r\n" +
+        "bad:\n  %e = call ptr @ami_rt_strings_parse_error(ptr @.str.parse.float, i64 " + itoa(len(floatMsg)) + ")\n  %rb = insertvalue { double, ptr } zeroinitializer, ptr %e, 1\n  ret { double, ptr } %rb\n}\n\n"
+    return s
+}
@@ -0,0 +1,153 @@
+package llvm
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestEmitter_StringHelpers_LiteralsAndExterns(t *testing.T) {
+    n := ir.Value{ID: "n", Type: "int32"}
+    lit := ir.Value{ID: "t1", Type: "string"}
+    ns := ir.Value{ID: "t2", Type: "string"}
+    cat := ir.Value{ID: "t3", Type: "string"}
+    fn := ir.Function{Name: "F", Params: []ir.Value{n}, Blocks: []ir.Block{{Name: "entry", Instr: []ir.Instruction{
+        ir.Expr{Op: "lit:\"n=\\\"é\\\"\"", Result: &lit},
+        ir.Expr{Op: "call", Callee: "ami_rt_int_to_string", Args: []ir.Value{n}, Result: &ns},
+        ir.Expr{Op: "call", Callee: "ami_rt_string_concat", Args: []ir.Value{lit, ns}, Result: &cat},
+        ir.Expr{Op: "call", Callee: "ami_rt_strings_parse_int", Args: []ir.Value{cat}, Results: []ir.Value{{ID: "v", Type: "int64"}, {ID: "err", Type: "error"}}},
+        ir.Return{},
+    }}}}
+    out, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{fn}})
+    if err != nil { t.Fatalf("emit: %v", err) }
+    for _, want := range []string{
+        "@.str.F.t1 = private constant [7 x i8] c\"n=\\22\\C3\\A9\\22\\00\"",
+        "%t1 = call ptr @ami_rt_owned_new(i8* @.str.F.t1, i64 6)",
+        "%t2.arg = sext i32 %n to i64",
+        "%t2 = call ptr @ami_rt_int_to_string(i64 %t2.arg)",
+        "%t3 = call ptr @ami_rt_string_concat(ptr %t1, ptr %t2)",
+        "call { i64, ptr } @ami_rt_strings_parse_int(ptr %t3)",
+        "declare ptr @ami_rt_owned_new(i8*, i64)",
+        "declare ptr @ami_rt_int_to_string(i64)",
+        "declare { i64, ptr } @ami_rt_strings_parse_int(ptr)",
+    } {
+        if !strings.Contains(out, want) { t.Fatalf("missing %q in:\n%s", want, out) }
+    }
+    rt := RuntimeLL("", false)
+    for name := range stringHelpers {
+        if !strings.Contains(rt, "@"+name+"(") { t.Fatalf("runtime does not define %s", name) }
+    }
+}
+
+// Helpers without a runtime definition are rejected rather than linked.
+func TestEmitter_StringHelpers_UnsupportedRejected(t *testing.T) {
+    s := ir.Value{ID: "s", Type: "string"}
+    x := ir.Value{ID: "x", Type: "Order"}
+    for _, ins := range []ir.Instruction{
+        ir.Expr{Op: "call", Callee: "ami_rt_any_to_string", Args: []ir.Value{x}, Result: &ir.Value{ID: "r", Type: "string"}},
+        ir.Defer{Expr: ir.Expr{Op: "call", Callee: "ami_rt_strings_split", Args: []ir.Value{s, s}}},
+    } {
+        fn := ir.Function{Name: "F", Params: []ir.Value{s, x}, Blocks: []ir.Block{{Name: "entry", Instr: []ir.Instruction{ins, ir.Return{}}}}}
+        _, err := EmitModuleLLVM(ir.Module{Package: "app", Functions: []ir.Function{fn}})
+        if err == nil || !strings.Contains(err.Error(), "F: ami_rt_") || !strings.Contains(err.Error(), "not supported by the LLVM backend") { t.Fatalf("err: %v", err) }
+    }
+}
+
+// Runs the string helpers from runtime.ll in a pure-IR harness; prefers lli,
+// falling back to clang.
+func TestStringRuntime_PureIRHarness(t *testing.T) {
+    dir := t.TempDir()
+    orig := os.Getenv("AMI_GPU_BACKENDS")
+    _ = os.Setenv("AMI_GPU_BACKENDS", "cuda,opencl")
+    defer os.Setenv("AMI_GPU_BACKENDS", orig)
+    llp, err := WriteRuntimeLL(dir, DefaultTriple, false)
+    if err != nil { t.Fatalf("WriteRuntimeLL: %v", err) }
+    rtIR, err := os.ReadFile(llp)
+    if err != nil { t.Fatalf("read runtime.ll: %v", err) }
+    strs := []string{"  Hello, World  ", "o", "He", "ld  ", "zz", "l", "L", " H", "-9223372036854775808", "9223372036854775808", "+42", "4x", "2.5e3", " 1", ""}
+    var h strings.Builder
+    h.WriteString("\ndeclare i64 @write(i32, ptr, i64)\n")
+    h.WriteString(irConstBytes("nl", 0, []byte("\n")))
+    for i, s := range strs { h.WriteString(irConstBytes("s", i, []byte(s))) }
+    // @put writes a handle's bytes and a newline; @err writes an error's code, or true when nil.
+    h.WriteString("define void @put(ptr %h) {\nentry:\n  %n = call i64 @ami_rt_string_len(ptr %h)\n  %p = call ptr @ami_rt_string_ptr(ptr %h)\n  %w0 = call i64 @write(i32 1, ptr %p, i64 %n)\n  %w1 = call i64 @write(i32 1, ptr @.nl.0, i64 1)\n  ret void\n}\n")
+    h.WriteString("define void @err(ptr %e) {\nentry:\n  %set = icmp ne ptr %e, null\n  br i1 %set, label %msg, label %ok\nmsg:\n  %c = call ptr @ami_rt_error_code_str(ptr %e)\n  call void @put(ptr %c)\n  ret void\nok:\n  %o = call ptr @ami_rt_bool_to_string(i1 true)\n  call void @put(ptr %o)\n  ret void\n}\n")
+    h.WriteString("define i32 @main() {\nentry:\n")
+    for i, s := range strs { fmt.Fprintf(&h, "  %%s%d = call ptr @ami_rt_owned_new(i8* @.s.%d, i64 %d)\n", i, i, len(s)) }
+    line := 0
+    put := func(expr string) { fmt.Fprintf(&h, "  %%r%d = %s\n  call void @put(ptr %%r%d)\n", line, expr, line); line++ }
+    putInt := func(ty, expr string) {
+        fmt.Fprintf(&h, "  %%v%d = %s\n", line, expr)
+        if ty == "i1" {
+            put(fmt.Sprintf("call ptr @ami_rt_bool_to_string(i1 %%v%d)", line))
+        } else {
+            put(fmt.Sprintf("call ptr @ami_rt_int_to_string(i64 %%v%d)", line))
+        }
+    }
+    parse := func(ty, fn string, arg int) {
+        fmt.Fprintf(&h, "  %%p%d = call { %s, ptr } @%s(ptr %%s%d)\n  %%pv%d = extractvalue { %s, ptr } %%p%d, 0\n  %%pe%d = extractvalue { %s, ptr } %%p%d, 1\n  call void @err(ptr %%pe%d)\n", arg, ty, fn, arg, arg, ty, arg, arg, ty, arg, arg)
+        if ty == "i64" {
+            put(fmt.Sprintf("call ptr @ami_rt_int_to_string(i64 %%pv%d)", arg))
+        } else {
+            put(fmt.Sprintf("call ptr @ami_rt_float_to_string(double %%pv%d)", arg))
+        }
+    }
+    put("call ptr @ami_rt_string_concat(ptr %s2, ptr %s1)")
+    put("call ptr @ami_rt_string_concat(ptr null, ptr %s14)")
+    put("call ptr @ami_rt_strings_trim_space(ptr %s0)")
+    put("call ptr @ami_rt_strings_trim(ptr %s0, ptr %s7)")
+    put("call ptr @ami_rt_strings_to_upper(ptr %s0)")
+    put("call ptr @ami_rt_strings_to_lower(ptr %s0)")
+    put("call ptr @ami_rt_strings_replace(ptr %s0, ptr %s5, ptr %s6)")
+    put("call ptr @ami_rt_strings_replace(ptr %s0, ptr %s14, ptr %s4)")
+    putInt("i64", "call i64 @ami_rt_strings_index(ptr %s0, ptr %s1)")
+    putInt("i64", "call i64 @ami_rt_strings_index(ptr %s0, ptr %s4)")
+    putInt("i1", "call i1 @ami_rt_strings_contains(ptr %s0, ptr %s3)")
+    putInt("i1", "call i1 @ami_rt_strings_has_prefix(ptr %s0, ptr %s7)")
+    putInt("i1", "call i1 @ami_rt_strings_has_suffix(ptr %s0, ptr %s3)")
+    putInt("i1", "call i1 @ami_rt_strings_has_suffix(ptr %s3, ptr %s0)")
+    putInt("i64", "call i64 @ami_rt_string_len(ptr %s0)")
+    put("call ptr @ami_rt_float_to_string(double 1.000000e-01)")
+    put("call ptr @ami_rt_float_to_string(double 1.000000e+21)")
+    put("call ptr @ami_rt_float_to_string(double 1.000000e+06)")
+    put("call ptr @ami_rt_float_to_string(double 1.234560e+05)")
+    put("call ptr @ami_rt_float_to_string(double 1.000000e-04)")
+    put("call ptr @ami_rt_float_to_string(double -3.250000e+00)")
+    put("call ptr @ami_rt_float_to_string(double 0x7FF0000000000000)")
+    put("call ptr @ami_rt_float_to_string(double 0x7FF8000000000000)")
+    parse("i64", "ami_rt_strings_parse_int", 8)
+    parse("i64", "ami_rt_strings_parse_int", 9)
+    parse("i64", "ami_rt_strings_parse_int", 10)
+    parse("i64", "ami_rt_strings_parse_int", 11)
+    parse("double", "ami_rt_strings_parse_float", 12)
+    parse("double", "ami_rt_strings_parse_float", 13)
+    parse("double", "ami_rt_strings_parse_float", 14)
+    h.WriteString("  ret i32 0\n}\n")
+    want := strings.Join([]string{
+        "Heo", "", "Hello, World", "ello, World", "  HELLO, WORLD  ", "  hello, world  ", "  HeLLo, WorLd  ", "  Hello, World  ",
+        "6", "-1", "true", "false", "true", "false", "16", "0.1", "1e+21", "1e+06", "123456", "0.0001", "-3.25", "+Inf", "NaN",
+        "true", "-9223372036854775808", "E_STRINGS_PARSE", "0", "true", "42", "E_STRINGS_PARSE", "0",
+        "true", "2500", "E_STRINGS_PARSE", "0", "E_STRINGS_PARSE", "0",
+    }, "\n") + "\n"
+    comb := filepath.Join(dir, "combined.ll")
+    if err := os.WriteFile(comb, append(rtIR, h.String()...), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    var out []byte
+    if path, _ := exec.LookPath("lli"); path != "" {
+        out, err = exec.Command(path, comb).Output()
+    }
+    if out == nil || err != nil {
+        clang, cerr := FindClang()
+        if cerr != nil { t.Skip("lli/clang unavailable; skipping") }
+        bin := filepath.Join(dir, "harness.bin")
+        if o, err := exec.Command(clang, "-x", "ir", comb, "-o", bin, "-target", DefaultTriple).CombinedOutput(); err != nil {
+            t.Skipf("clang link failed: %v, out=%s", err, string(o))
+        }
+        if out, err = exec.Command(bin).Output(); err != nil { t.Fatalf("run: %v", err) }
+    }
+    if string(out) != want { t.Fatalf("output:\n%s\nwant:\n%s", out, want) }
+}
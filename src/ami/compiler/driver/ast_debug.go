@@ -8,4 +8,5 @@ type astUnit struct {
     Imports   []astImport  `json:"imports,omitempty"`
     Funcs     []astFunc    `json:"funcs,omitempty"`
     Pipelines []astPipe    `json:"pipelines,omitempty"`
+    Types     []astType    `json:"types,omitempty"`
 }
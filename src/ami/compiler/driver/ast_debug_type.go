@@ -0,0 +1,9 @@
+package driver
+
+type astType struct {
+    Name  string  `json:"name"`
+    Alias bool    `json:"alias,omitempty"`
+    Type  string  `json:"type"`
+    Pos   *dbgPos `json:"pos,omitempty"`
+    Kind  string  `json:"kind"`
+}
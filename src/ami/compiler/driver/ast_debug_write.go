@@ -50,6 +50,8 @@ func writeASTDebug(pkg, unit string, f *ast.File) (string, error) {
                 }
             }
             u.Pipelines = append(u.Pipelines, astPipe{Name: n.Name, Steps: steps, Pos: posPtr(n.Pos), Kind: "PipelineDecl"})
+        case *ast.TypeDecl:
+            u.Types = append(u.Types, astType{Name: n.Name, Alias: n.Alias, Type: n.Type, Pos: posPtr(n.Pos), Kind: "TypeDecl"})
         }
     }
     // pragmas
@@ -68,6 +70,7 @@ func writeASTDebug(pkg, unit string, f *ast.File) (string, error) {
     })
     sort.SliceStable(u.Funcs, func(i, j int) bool { return u.Funcs[i].Name < u.Funcs[j].Name })
     sort.SliceStable(u.Pipelines, func(i, j int) bool { return u.Pipelines[i].Name < u.Pipelines[j].Name })
+    sort.SliceStable(u.Types, func(i, j int) bool { return u.Types[i].Name < u.Types[j].Name })
     dir := filepath.Join("build", "debug", "ast", pkg)
     if err := os.MkdirAll(dir, 0o755); err != nil { return "", err }
     b, err := json.MarshalIndent(u, "", "  ")
@@ -13,6 +13,7 @@ import (
     "github.com/sam-caldwell/ami/src/ami/compiler/parser"
     "github.com/sam-caldwell/ami/src/ami/compiler/sem"
     "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
     "github.com/sam-caldwell/ami/src/ami/workspace"
     "github.com/sam-caldwell/ami/src/schemas/diag"
     "encoding/json"
@@ -43,6 +44,8 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
     // Pre-collect global function signatures and param positions across all packages (for qualified calls)
     type gsig struct{ params, results []string; ppos []diag.Position }
     global := map[string]map[string]gsig{} // pkg -> func -> sig
+    // Parsed files per package, for resolving type declarations across imports
+    globalFiles := map[string][]*ast.File{}
     for _, pkg := range pkgs {
         if pkg.Files == nil { continue }
         files := append([]*source.File(nil), pkg.Files.Files...)
@@ -52,6 +55,7 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
             pr := parser.New(f)
             af, _ := pr.ParseFileCollect()
             if af == nil { continue }
+            globalFiles[af.PackageName] = append(globalFiles[af.PackageName], af)
             for _, d := range af.Decls {
                 if fn, ok := d.(*ast.FuncDecl); ok && fn.Name != "" {
                     var ps []string
@@ -171,6 +175,16 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
                 }
             }
         }
+        // Package-scoped type resolver: local declarations plus those of imported packages
+        tres := types.NewResolver(p.Name)
+        for _, u := range units { sem.DefineTypeDecls(tres, p.Name, u.ast) }
+        for alias, pkgName := range aliasToPkg {
+            tres.Import(alias, pkgName)
+            if pkgName == p.Name { continue }
+            for _, gf := range globalFiles[pkgName] { sem.DefineTypeDecls(tres, pkgName, gf) }
+        }
+        sem.SetTypeResolver(tres)
+        typeResolver = tres
         // PHASE 2: per-unit analyses, edges collection, lowering and debug
         // Package-level pipeline egress type map for edge.Pipeline resolution across units
         egressTypesPkg := map[string]string{}
@@ -257,6 +271,7 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
             attachFile(sem.AnalyzeMultiPath(af))
             attachFile(sem.AnalyzeMergeFieldTypes(af))
             attachFile(sem.AnalyzeEnums(af))
+            attachFile(sem.AnalyzeTypeDecls(af))
             attachFile(sem.AnalyzeConcurrency(af))
             attachFile(sem.AnalyzeEdgesInContext(af, egressTypesPkg))
             attachFile(sem.AnalyzeEventTypeFlowInContext(af, egressTypesPkg))
@@ -273,6 +288,11 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
             attachFile(sem.AnalyzeReturnInference(af))
             attachFile(sem.AnalyzeReturnTypesWithSigs(af, resultSigs))
             attachFile(sem.AnalyzeRAII(af))
+            attachFile(sem.AnalyzeLoops(af))
+            attachFile(sem.AnalyzeMatch(af))
+            attachFile(sem.AnalyzePropagation(af, resultSigs))
+            attachFile(sem.AnalyzeInterpolation(af, resultSigs))
+            attachFile(sem.AnalyzeJSONDecode(af))
             attachFile(sem.AnalyzeCallsWithSigs(af, paramSigs, resultSigs, paramPos, paramNames))
             attachFile(sem.AnalyzePackageAndImports(af))
             // IR/codegen-stage capability check (complements semantics layer)
@@ -384,7 +404,7 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
                 var fnames []string
                 for _, fn := range m.Functions { fnames = append(fnames, fn.Name) }
                 irUnits = append(irUnits, irIndexUnit{Unit: unit, Functions: fnames})
-                typesUnits = append(typesUnits, irTypesIndexUnit{Unit: unit, Types: collectTypes(m)})
+                typesUnits = append(typesUnits, irTypesIndexUnit{Unit: unit, Types: collectTypes(m), Decls: collectTypeDecls(p.Name, af)})
                 symbolsUnits = append(symbolsUnits, irSymbolsIndexUnit{Unit: unit, Exports: collectExports(m), Externs: collectExterns(m)})
             // IR textual emission (backend; debug only)
             be := codegen.DefaultBackend()
@@ -548,6 +568,8 @@ func Compile(ws workspace.Workspace, pkgs []Package, opts Options) (Artifacts, [
         if opts.Debug { manifestPkgs = append(manifestPkgs, bmPkgs...) }
         if opts.Log != nil { opts.Log("pkg.end", map[string]any{"pkg": p.Name}) }
     }
+    sem.SetTypeResolver(nil)
+    typeResolver = nil
     if opts.Debug && len(manifestPkgs) > 0 {
         _, _ = writeBuildManifest(BuildManifest{Schema: "manifest.v1", Packages: manifestPkgs})
     }
@@ -5,8 +5,10 @@ import (
     "github.com/sam-caldwell/ami/src/ami/compiler/ir"
 )
 
-// emitNestedCallArgs lowers nested call expressions used as arguments before the outer call.
+// emitNestedCallArgs lowers nested call expressions used as arguments before the outer call,
+// after any interpolated strings they evaluate.
 func emitNestedCallArgs(st *lowerState, e ast.Expr, out *[]ir.Instruction) {
+    hoistInterpolations(st, e, out)
     var walkArgs func(ast.Expr)
     walkArgs = func(x ast.Expr) {
         if ce, ok := x.(*ast.CallExpr); ok {
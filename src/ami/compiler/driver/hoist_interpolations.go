@@ -0,0 +1,38 @@
+package driver
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// hoistInterpolations lowers the interpolated strings that e evaluates
+// unconditionally into out ahead of e itself. Ternary branches and the right
+// operand of &&/|| are left to lowerValueSC, which lowers them in their blocks.
+func hoistInterpolations(st *lowerState, e ast.Expr, out *[]ir.Instruction) {
+    switch v := e.(type) {
+    case *ast.InterpStringLit:
+        lowerInterpString(st, v, out)
+    case *ast.BinaryExpr:
+        hoistInterpolations(st, v.X, out)
+        if v.Op != token.And && v.Op != token.Or { hoistInterpolations(st, v.Y, out) }
+    case *ast.UnaryExpr:
+        hoistInterpolations(st, v.X, out)
+    case *ast.ConditionalExpr:
+        hoistInterpolations(st, v.Cond, out)
+    case *ast.CallExpr:
+        for _, a := range v.Args { hoistInterpolations(st, a, out) }
+    case *ast.PropagateExpr:
+        hoistInterpolations(st, v.X, out)
+    case *ast.SelectorExpr:
+        hoistInterpolations(st, v.X, out)
+    case *ast.SliceLit:
+        for _, a := range v.Elems { hoistInterpolations(st, a, out) }
+    case *ast.SetLit:
+        for _, a := range v.Elems { hoistInterpolations(st, a, out) }
+    case *ast.MapLit:
+        for _, kv := range v.Elems { hoistInterpolations(st, kv.Key, out); hoistInterpolations(st, kv.Val, out) }
+    case *ast.MatchExpr:
+        hoistInterpolations(st, v.X, out)
+    }
+}
@@ -0,0 +1,70 @@
+package driver
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// irCallees returns, per block of F, the callees and literal ops of its expressions.
+func irCallees(t *testing.T, file string) map[string][]string {
+    t.Helper()
+    b, err := os.ReadFile(filepath.Join("build", "debug", "ir", "app", file+".ir.json"))
+    if err != nil { t.Fatalf("read ir: %v", err) }
+    var obj struct {
+        Functions []struct {
+            Name   string
+            Blocks []struct {
+                Name   string
+                Instrs []struct {
+                    Expr *struct{ Op, Callee string }
+                }
+            }
+        }
+    }
+    if err := json.Unmarshal(b, &obj); err != nil { t.Fatalf("json: %v", err) }
+    out := map[string][]string{}
+    for _, fn := range obj.Functions {
+        if fn.Name != "F" { continue }
+        for _, blk := range fn.Blocks {
+            for _, in := range blk.Instrs {
+                if in.Expr == nil { continue }
+                switch {
+                case in.Expr.Callee != "":
+                    out[blk.Name] = append(out[blk.Name], in.Expr.Callee)
+                case strings.HasPrefix(in.Expr.Op, "lit:"):
+                    out[blk.Name] = append(out[blk.Name], in.Expr.Op)
+                }
+            }
+        }
+    }
+    return out
+}
+
+// Interpolated strings lower to literal parts, per-type conversions and a concat chain
+// ahead of the statement that uses them; strings.* calls map to runtime helpers.
+func TestDriver_InterpStringLowering(t *testing.T) {
+    code := "package app\nimport strings\n" +
+        "func F(name string, n int, ok bool) (string, error) {\n" +
+        "  var s = \"hi ${name}, n=${n}${ok}\"\n" +
+        "  var k = strings.ParseInt(s)?\n" +
+        "  return strings.ToUpper(\"k=${k}\"), nil\n" +
+        "}\n"
+    loopIRBlocks(t, "interp_string", code)
+    got := irCallees(t, "interp_string")
+    want := []string{`lit:"hi "`, "ami_rt_string_concat", `lit:", n="`, "ami_rt_string_concat", "ami_rt_int_to_string", "ami_rt_string_concat", "ami_rt_bool_to_string", "ami_rt_string_concat", "ami_rt_strings_parse_int", "ami_rt_error_is_set"}
+    if strings.Join(got["entry"], " ") != strings.Join(want, " ") { t.Fatalf("entry:\n got %v\nwant %v", got["entry"], want) }
+    want = []string{`lit:"k="`, "ami_rt_int_to_string", "ami_rt_string_concat", "ami_rt_strings_to_upper"}
+    if strings.Join(got["pok0"], " ") != strings.Join(want, " ") { t.Fatalf("pok0:\n got %v\nwant %v", got["pok0"], want) }
+}
+
+// Interpolations in a ternary branch lower inside that branch's block.
+func TestDriver_InterpString_InBranch(t *testing.T) {
+    code := "package app\nfunc F(ok bool, n int) (string) {\n  var s = ok ? \"n=${n}\" : \"none\"\n  return s\n}\n"
+    loopIRBlocks(t, "interp_branch", code)
+    got := irCallees(t, "interp_branch")
+    if strings.Join(got["then0"], " ") != `lit:"n=" ami_rt_int_to_string ami_rt_string_concat` { t.Fatalf("then0: %v", got) }
+    if strings.Join(got["else0"], " ") != `lit:"none"` { t.Fatalf("else0: %v", got) }
+}
@@ -0,0 +1,21 @@
+package driver
+
+// irTypeDecl records a package-level type declaration in ir.types.index.json.
+// Kind is one of struct, alias, defined, or enum. Type is the declared type text
+// (struct bodies as Struct{...}); Resolved is its structural expansion when it
+// differs.
+type irTypeDecl struct {
+    Name     string        `json:"name"`
+    Kind     string        `json:"kind"`
+    Type     string        `json:"type,omitempty"`
+    Resolved string        `json:"resolved,omitempty"`
+    Exported bool          `json:"exported"`
+    Fields   []irTypeField `json:"fields,omitempty"`
+    Members  []string      `json:"members,omitempty"`
+}
+
+// irTypeField is a struct field within an irTypeDecl.
+type irTypeField struct {
+    Name string `json:"name"`
+    Type string `json:"type"`
+}
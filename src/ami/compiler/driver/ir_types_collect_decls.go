@@ -0,0 +1,35 @@
+package driver
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+// collectTypeDecls lists the type and enum declarations of a unit in source
+// order, qualified by package.
+func collectTypeDecls(pkg string, f *ast.File) []irTypeDecl {
+    if f == nil { return nil }
+    var out []irTypeDecl
+    for _, d := range f.Decls {
+        switch v := d.(type) {
+        case *ast.TypeDecl:
+            td := irTypeDecl{Name: pkg + "." + v.Name, Type: v.Type, Exported: types.IsExported(v.Name)}
+            switch {
+            case v.Alias:
+                td.Kind = "alias"
+            case len(v.Fields) > 0 || v.Type == "Struct{}":
+                td.Kind = "struct"
+            default:
+                td.Kind = "defined"
+            }
+            for _, fd := range v.Fields { td.Fields = append(td.Fields, irTypeField{Name: fd.Name, Type: fd.Type}) }
+            if rt, ok := typeResolver.Lookup(v.Name); ok && rt.String() != v.Type { td.Resolved = rt.String() }
+            out = append(out, td)
+        case *ast.EnumDecl:
+            td := irTypeDecl{Name: pkg + "." + v.Name, Kind: "enum", Exported: types.IsExported(v.Name)}
+            for _, m := range v.Members { td.Members = append(td.Members, m.Name) }
+            out = append(out, td)
+        }
+    }
+    return out
+}
@@ -1,7 +1,7 @@
 package driver
 
 type irTypesIndexUnit struct {
-    Unit  string   `json:"unit"`
-    Types []string `json:"types"`
+    Unit  string       `json:"unit"`
+    Types []string     `json:"types"`
+    Decls []irTypeDecl `json:"decls,omitempty"`
 }
-
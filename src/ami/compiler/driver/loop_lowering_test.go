@@ -0,0 +1,82 @@
+package driver
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+func loopIRBlocks(t *testing.T, file, code string) map[string][]string {
+    t.Helper()
+    fs := &source.FileSet{}
+    fs.AddFile(file+".ami", code)
+    _, _ = Compile(workspace.Workspace{}, []Package{{Name: "app", Files: fs}}, Options{Debug: true, EmitLLVMOnly: true})
+    b, err := os.ReadFile(filepath.Join("build", "debug", "ir", "app", file+".ir.json"))
+    if err != nil { t.Fatalf("read ir: %v", err) }
+    var obj map[string]any
+    if err := json.Unmarshal(b, &obj); err != nil { t.Fatalf("json: %v", err) }
+    fns, _ := obj["functions"].([]any)
+    if len(fns) == 0 { t.Fatalf("no functions in IR") }
+    out := map[string][]string{}
+    var blks []any
+    for _, f := range fns {
+        if fm, _ := f.(map[string]any); fm["name"] == "F" { blks, _ = fm["blocks"].([]any) }
+    }
+    for _, bb := range blks {
+        m := bb.(map[string]any)
+        name, _ := m["name"].(string)
+        ins, _ := m["instrs"].([]any)
+        for _, in := range ins {
+            im, _ := in.(map[string]any)
+            op, _ := im["op"].(string)
+            out[name] = append(out[name], op)
+        }
+    }
+    return out
+}
+
+func last(ops []string) string {
+    if len(ops) == 0 { return "" }
+    return ops[len(ops)-1]
+}
+
+// Range over a slice lowers to preheader, phi-driven header, body, latch and exit blocks.
+func TestDriver_LoopLowering_RangeSlice(t *testing.T) {
+    code := "package app\nfunc F(xs slice<int>) (int) {\nvar s int = 0\nfor i, x := range xs { if (x == 0) { continue }; s = s + x }\nreturn s\n}\n"
+    blks := loopIRBlocks(t, "loop_range", code)
+    for _, name := range []string{"pre0", "loop0", "body0", "latch0", "exit0"} {
+        if _, ok := blks[name]; !ok { t.Fatalf("missing block %s: %v", name, blks) }
+    }
+    h := strings.Join(blks["loop0"], ",")
+    if !strings.Contains(h, "LOOP") || !strings.Contains(h, "PHI") || last(blks["loop0"]) != "COND_BR" { t.Fatalf("header: %s", h) }
+    if last(blks["latch0"]) != "GOTO" || last(blks["body0"]) != "COND_BR" { t.Fatalf("latch/body: %v", blks) }
+    if last(blks["exit0"]) != "RETURN" { t.Fatalf("exit should hold the continuation: %v", blks["exit0"]) }
+    // every block is terminated (continue branch goes to the latch, fallthrough join as well)
+    for name, ops := range blks {
+        if name == "entry" { continue }
+        switch last(ops) {
+        case "GOTO", "COND_BR", "RETURN":
+        default:
+            t.Fatalf("block %s not terminated: %v", name, ops)
+        }
+    }
+}
+
+// Condition and infinite loops with break lower to loop/body/exit blocks and LLVM branches.
+func TestDriver_LoopLowering_CondAndBreak_LLVM(t *testing.T) {
+    code := "package app\nfunc G() (bool) { return true }\nfunc F(n int) {\nvar i int = 0\nfor i < n { i = i + 1 }\nfor { if (G()) { break } }\nreturn\n}\n"
+    blks := loopIRBlocks(t, "loop_cond", code)
+    if last(blks["loop0"]) != "COND_BR" || last(blks["loop1"]) != "GOTO" { t.Fatalf("headers: %v", blks) }
+    if last(blks["exit1"]) != "RETURN" { t.Fatalf("exit1: %v", blks["exit1"]) }
+    ll, err := os.ReadFile(filepath.Join("build", "debug", "llvm", "app", "loop_cond.ll"))
+    if err != nil { t.Fatalf("read ll: %v", err) }
+    s := string(ll)
+    for _, want := range []string{"loop0:", "body0:", "exit1:", "br label %exit1", "; loop loop1"} {
+        if !strings.Contains(s, want) { t.Fatalf("llvm missing %q:\n%s", want, s) }
+    }
+}
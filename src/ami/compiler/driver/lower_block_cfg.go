@@ -14,13 +14,45 @@ func lowerBlockCFG(st *lowerState, b *ast.BlockStmt, blockId int) ([]ir.Instruct
     var extras []ir.Block
     if b == nil { return out, extras }
     nextID := blockId
-    endsWithReturn := func(ins []ir.Instruction) bool {
-        if len(ins) == 0 { return false }
-        _, ok := ins[len(ins)-1].(ir.Return)
-        return ok
-    }
     for i := 0; i < len(b.Stmts); i++ {
         s := b.Stmts[i]
+        // Interpolated strings evaluate ahead of the statement that uses them.
+        switch v := s.(type) {
+        case *ast.VarDecl:
+            if v.Init != nil { hoistInterpolations(st, v.Init, &out) }
+        case *ast.AssignStmt:
+            hoistInterpolations(st, v.Value, &out)
+        case *ast.ReturnStmt:
+            for _, r := range v.Results { hoistInterpolations(st, r, &out) }
+        case *ast.ExprStmt:
+            hoistInterpolations(st, v.X, &out)
+        case *ast.IfStmt:
+            hoistInterpolations(st, v.Cond, &out)
+        case *ast.DeferStmt:
+            if v.Call != nil { hoistInterpolations(st, v.Call, &out) }
+        }
+        // json.Decode target type descriptors likewise evaluate ahead of the statement.
+        hoistJSONDecodeTarget(st, s, &out)
+        if m, dest, prefix, then, ok := lowerMatchStmt(st, s); ok {
+            // A match ends the current block; the remaining statements lower into its end block.
+            rest := &ast.BlockStmt{Stmts: then}
+            if len(then) == 0 { rest.Stmts = append(rest.Stmts, b.Stmts[i+1:]...) }
+            if len(rest.Stmts) == 0 { rest = nil }
+            out = append(out, prefix...)
+            mInstr, mExtra := lowerMatch(st, m, dest, rest, &nextID)
+            out = append(out, mInstr...)
+            extras = append(extras, mExtra...)
+            return out, extras
+        }
+        if p, dest, decl, ok := lowerPropagateStmt(s); ok {
+            // x? ends the current block; the remaining statements lower into the success block.
+            var rest *ast.BlockStmt
+            if i+1 < len(b.Stmts) { rest = &ast.BlockStmt{Stmts: b.Stmts[i+1:]} }
+            pInstr, pExtra := lowerPropagate(st, p, dest, decl, rest, &nextID)
+            out = append(out, pInstr...)
+            extras = append(extras, pExtra...)
+            return out, extras
+        }
         switch v := s.(type) {
         case *ast.DeferStmt:
             // Lower defer statements into IR.Defer carrying the inner Expr
@@ -39,7 +71,7 @@ func lowerBlockCFG(st *lowerState, b *ast.BlockStmt, blockId int) ([]ir.Instruct
                 out = append(out, ir.CondBr{Cond: ir.Value{ID: cid, Type: "bool"}, TrueLabel: thenName, FalseLabel: elseName})
                 tInstr, tExtra := lowerBlockCFG(st, v.Then, nextID)
                 nextID += len(tExtra) + 1
-                if !endsWithReturn(tInstr) { tInstr = append(tInstr, ir.Goto{Label: joinName}) }
+                tInstr, tExtra = closeOpen(tInstr, tExtra, joinName)
                 extras = append(extras, ir.Block{Name: thenName, Instr: tInstr})
                 extras = append(extras, tExtra...)
                 eInstr := []ir.Instruction{}
@@ -48,7 +80,7 @@ func lowerBlockCFG(st *lowerState, b *ast.BlockStmt, blockId int) ([]ir.Instruct
                     eInstr, eExtra = lowerBlockCFG(st, v.Else, nextID)
                     nextID += len(eExtra) + 1
                 }
-                if !endsWithReturn(eInstr) { eInstr = append(eInstr, ir.Goto{Label: joinName}) }
+                eInstr, eExtra = closeOpen(eInstr, eExtra, joinName)
                 extras = append(extras, ir.Block{Name: elseName, Instr: eInstr})
                 extras = append(extras, eExtra...)
                 var rest *ast.BlockStmt
@@ -63,6 +95,27 @@ func lowerBlockCFG(st *lowerState, b *ast.BlockStmt, blockId int) ([]ir.Instruct
                 extras = append(extras, joinExtra...)
                 return out, extras
             }
+        case *ast.ForStmt, *ast.RangeStmt:
+            // Loops end the current block; the remaining statements lower into the loop's exit block.
+            var rest *ast.BlockStmt
+            if i+1 < len(b.Stmts) { rest = &ast.BlockStmt{Stmts: b.Stmts[i+1:]} }
+            var lInstr []ir.Instruction
+            var lExtra []ir.Block
+            if fs, ok := v.(*ast.ForStmt); ok {
+                lInstr, lExtra = lowerStmtFor(st, fs, rest, &nextID)
+            } else {
+                lInstr, lExtra = lowerStmtRange(st, v.(*ast.RangeStmt), rest, &nextID)
+            }
+            out = append(out, lInstr...)
+            extras = append(extras, lExtra...)
+            return out, extras
+        case *ast.BreakStmt:
+            // Statements after break are unreachable and dropped.
+            if len(st.loops) > 0 { out = append(out, ir.Goto{Label: st.loops[len(st.loops)-1].exit}) }
+            return out, extras
+        case *ast.ContinueStmt:
+            if len(st.loops) > 0 { out = append(out, ir.Goto{Label: st.loops[len(st.loops)-1].cont}) }
+            return out, extras
         case *ast.VarDecl:
             // Specialized handling for Owned types with initializer
             if v.Type != "" && (v.Type == "Owned" || (len(v.Type) >= 6 && v.Type[:6] == "Owned<")) && v.Init != nil {
@@ -0,0 +1,26 @@
+package driver
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+
+// closeOpen terminates every block in a lowered region that falls through (does not end in
+// return, goto or condbr) with a goto to label. The region is the entry instruction list plus
+// the extra blocks produced for it; any open block is a fallthrough end of the region.
+func closeOpen(instr []ir.Instruction, extras []ir.Block, label string) ([]ir.Instruction, []ir.Block) {
+    if !endsWithTerminator(instr) { instr = append(instr, ir.Goto{Label: label}) }
+    for i := range extras {
+        if !endsWithTerminator(extras[i].Instr) {
+            extras[i].Instr = append(extras[i].Instr, ir.Goto{Label: label})
+        }
+    }
+    return instr, extras
+}
+
+// endsWithTerminator reports whether the instruction list ends in a control transfer.
+func endsWithTerminator(ins []ir.Instruction) bool {
+    if len(ins) == 0 { return false }
+    switch ins[len(ins)-1].(type) {
+    case ir.Return, ir.Goto, ir.CondBr:
+        return true
+    }
+    return false
+}
@@ -39,6 +39,11 @@ func lowerExpr(st *lowerState, e ast.Expr) (ir.Expr, bool) {
         if ex.Result != nil { args = append(args, *ex.Result) }
         return ir.Expr{Op: "select", Args: args, Result: res}, true
     case *ast.IdentExpr:
+        if v.Name == "nil" {
+            // nil is the zero handle (no error, no value)
+            res := &ir.Value{ID: st.newTemp(), Type: "ptr"}
+            return ir.Expr{Op: "zero", Result: res}, true
+        }
         // ident references a previously defined value; use tracked type when known
         typ := "any"
         if st != nil && st.varTypes != nil {
@@ -80,6 +85,13 @@ func lowerExpr(st *lowerState, e ast.Expr) (ir.Expr, bool) {
         id := st.newTemp()
         res := &ir.Value{ID: id, Type: "string"}
         return ir.Expr{Op: fmt.Sprintf("lit:%q", v.Value), Result: res}, true
+    case *ast.InterpStringLit:
+        // the value is lowered ahead of the statement (see hoistInterpolations)
+        if val, ok := st.interps[v]; ok {
+            res := val
+            return ir.Expr{Op: "ident", Result: &res}, true
+        }
+        return ir.Expr{}, false
     case *ast.NumberLit:
         id := st.newTemp()
         lit := v.Text
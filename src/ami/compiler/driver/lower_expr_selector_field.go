@@ -21,6 +21,11 @@ func lowerSelectorField(st *lowerState, s *ast.SelectorExpr) (ir.Expr, bool) {
     // Parse and resolve field path via types package
     root, err := types.Parse(btype)
     if err != nil { return ir.Expr{}, false }
+    // Expand declared type names (type Order struct {...}) to their structure
+    if rt := typeResolver.Resolve(root); rt.String() != root.String() {
+        root = rt
+        btype = rt.String()
+    }
     ft, ok := types.ResolveField(root, path)
     if !ok || ft == nil { return ir.Expr{}, false }
     fts := ft.String()
@@ -30,10 +35,9 @@ func lowerSelectorField(st *lowerState, s *ast.SelectorExpr) (ir.Expr, bool) {
     if fts == "Time" { rtype = "int64" }
     id := st.newTemp()
     res := &ir.Value{ID: id, Type: rtype}
-    // Provide the base as an argument for potential future codegen; keep original
-    // base type text so codegen can compute field offsets/layout.
-    bargType := st.varTypes[baseIdent]
-    arg := ir.Value{ID: baseIdent, Type: bargType}
+    // Provide the base as an argument for potential future codegen; keep the
+    // structural base type text so codegen can compute field offsets/layout.
+    arg := ir.Value{ID: baseIdent, Type: btype}
     // Encode field name into the op for debug purposes: field.<path>
     return ir.Expr{Op: "field." + path, Args: []ir.Value{arg}, Result: res}, true
 }
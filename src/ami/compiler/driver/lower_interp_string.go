@@ -0,0 +1,65 @@
+package driver
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// lowerInterpString lowers an interpolated string into out and returns its value.
+// Literal parts become string literals, embedded values are converted with
+// ami_rt_<kind>_to_string, and the pieces are joined left to right with
+// ami_rt_string_concat. A literal already lowered returns its recorded value.
+func lowerInterpString(st *lowerState, v *ast.InterpStringLit, out *[]ir.Instruction) ir.Value {
+    if val, ok := st.interps[v]; ok { return val }
+    var acc *ir.Value
+    push := func(p ir.Value) {
+        if acc == nil { acc = &p; return }
+        res := &ir.Value{ID: st.newTemp(), Type: "string"}
+        *out = append(*out, ir.Expr{Op: "call", Callee: "ami_rt_string_concat", Args: []ir.Value{*acc, p}, Result: res})
+        acc = res
+    }
+    lit := func(s string) ir.Value {
+        res := &ir.Value{ID: st.newTemp(), Type: "string"}
+        *out = append(*out, ir.Expr{Op: fmt.Sprintf("lit:%q", s), Result: res})
+        return *res
+    }
+    for i, part := range v.Parts {
+        if part != "" { push(lit(part)) }
+        if i >= len(v.Exprs) { continue }
+        emitNestedCallArgs(st, v.Exprs[i], out)
+        ex, ok := lowerExpr(st, v.Exprs[i])
+        if !ok || ex.Result == nil { continue }
+        if ex.Op != "" || ex.Callee != "" || len(ex.Args) > 0 { *out = append(*out, ex) }
+        val := *ex.Result
+        if callee := interpToStringCallee(val.Type); callee != "" {
+            res := &ir.Value{ID: st.newTemp(), Type: "string"}
+            *out = append(*out, ir.Expr{Op: "call", Callee: callee, Args: []ir.Value{val}, Result: res})
+            val = *res
+        }
+        push(val)
+    }
+    if acc == nil { v0 := lit(""); acc = &v0 }
+    if st.interps == nil { st.interps = map[*ast.InterpStringLit]ir.Value{} }
+    st.interps[v] = *acc
+    return *acc
+}
+
+// interpToStringCallee names the runtime helper formatting a value of type t,
+// or "" for strings.
+func interpToStringCallee(t string) string {
+    switch t = strings.TrimSpace(t); {
+    case t == "string":
+        return ""
+    case t == "bool":
+        return "ami_rt_bool_to_string"
+    case t == "float" || t == "float32" || t == "float64":
+        return "ami_rt_float_to_string"
+    case t == "byte" || t == "rune" || t == "Duration" || t == "Time" || strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint"):
+        return "ami_rt_int_to_string"
+    default:
+        return "ami_rt_any_to_string"
+    }
+}
@@ -0,0 +1,8 @@
+package driver
+
+// loopTarget holds the branch labels of an enclosing loop: continue jumps to
+// cont (the loop head or latch) and break jumps to exit.
+type loopTarget struct {
+    cont string
+    exit string
+}
@@ -0,0 +1,173 @@
+package driver
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+// lowerMatch lowers a match into a chain of tag tests feeding one block per arm:
+//   matchN:     subj evaluated once; tag = ami_rt_<kind>_tag(subj) (enum ordinal,
+//               sorted union alternative index, or 0/1 for None/Some); GOTO mtestN_0
+//   mtestN_k:   CONDBR tag == <pattern k tag>, caseN_j, mtestN_k+1 (`_` is GOTO caseN_j)
+//   caseN_j:    bind the variant value via ami_rt_<kind>_val_<abi>(subj); arm body, or
+//               dest = arm value when the match is used as a value
+//   mendN:      the remainder of the enclosing block (rest)
+// Unmatched subjects fall through to mendN; sem rejects non-exhaustive matches.
+func lowerMatch(st *lowerState, m *ast.MatchExpr, dest string, rest *ast.BlockStmt, nextID *int) ([]ir.Instruction, []ir.Block) {
+    n := st.matchSeq
+    st.matchSeq++
+    head := fmt.Sprintf("match%d", n)
+    end := fmt.Sprintf("mend%d", n)
+    test := func(k int) string { return fmt.Sprintf("mtest%d_%d", n, k) }
+    arm := func(j int) string { return fmt.Sprintf("case%d_%d", n, j) }
+    out := []ir.Instruction{ir.Goto{Label: head}}
+    // head: evaluate the subject once and compute its tag
+    var hInstr []ir.Instruction
+    emitNestedCallArgs(st, m.X, &hInstr)
+    subj := ir.Value{Type: "any"}
+    if ex, ok := lowerExpr(st, m.X); ok {
+        if ex.Op != "" || ex.Callee != "" || len(ex.Args) > 0 { hInstr = append(hInstr, ex) }
+        if ex.Result != nil { subj = *ex.Result }
+    }
+    var t types.Type
+    if pt, err := typeResolver.Parse(subj.Type); err == nil { t = pt }
+    kind := matchLowerKind(t)
+    tag := ir.Value{ID: st.newTemp(), Type: "int64"}
+    if kind != "" {
+        hInstr = append(hInstr, ir.Expr{Op: "call", Callee: "ami_rt_" + kind + "_tag", Args: []ir.Value{subj}, Result: &tag})
+    }
+    hInstr = append(hInstr, ir.Goto{Label: test(0)})
+    extras := []ir.Block{{Name: head, Instr: hInstr}}
+    // tests: one block per pattern, in source order
+    k := 0
+    for j, a := range m.Arms {
+        for _, p := range a.Patterns {
+            var tInstr []ir.Instruction
+            ord, ok := matchPatternTag(t, kind, p)
+            switch {
+            case p.Kind == "wildcard":
+                tInstr = append(tInstr, ir.Goto{Label: arm(j)})
+            case ok:
+                lit := ir.Value{ID: st.newTemp(), Type: "int64"}
+                cmp := ir.Value{ID: st.newTemp(), Type: "bool"}
+                tInstr = append(tInstr,
+                    ir.Expr{Op: fmt.Sprintf("lit:%d", ord), Result: &lit},
+                    ir.Expr{Op: "eq", Args: []ir.Value{tag, lit}, Result: &cmp},
+                    ir.CondBr{Cond: cmp, TrueLabel: arm(j), FalseLabel: test(k + 1)},
+                )
+            default:
+                tInstr = append(tInstr, ir.Goto{Label: test(k + 1)})
+            }
+            extras = append(extras, ir.Block{Name: test(k), Instr: tInstr})
+            k++
+        }
+    }
+    extras = append(extras, ir.Block{Name: test(k), Instr: []ir.Instruction{ir.Goto{Label: end}}})
+    // arms: bind, then lower the body or assign the value
+    for j, a := range m.Arms {
+        var aInstr []ir.Instruction
+        if len(a.Patterns) == 1 {
+            p := a.Patterns[0]
+            if p.Binding != "" && p.Binding != "_" {
+                if bt := matchBindingType(t, kind, p); bt != "" {
+                    val := ir.Value{ID: st.newTemp(), Type: bt}
+                    aInstr = append(aInstr, ir.Expr{Op: "call", Callee: "ami_rt_" + kind + "_val_" + rangeABI(bt), Args: []ir.Value{subj}, Result: &val})
+                    init := val
+                    aInstr = append(aInstr, ir.Var{Name: p.Binding, Type: bt, Init: &init, Result: ir.Value{ID: p.Binding, Type: bt}})
+                    st.varTypes[p.Binding] = bt
+                }
+            }
+        }
+        var aExtra []ir.Block
+        if a.Body != nil {
+            var bInstr []ir.Instruction
+            bInstr, aExtra = lowerBlockCFG(st, a.Body, *nextID)
+            *nextID += len(aExtra) + 1
+            aInstr = append(aInstr, bInstr...)
+        } else if a.Value != nil {
+            emitNestedCallArgs(st, a.Value, &aInstr)
+            if ex, ok := lowerExpr(st, a.Value); ok {
+                if ex.Op != "" || ex.Callee != "" || len(ex.Args) > 0 { aInstr = append(aInstr, ex) }
+                if ex.Result != nil && dest != "" { aInstr = append(aInstr, ir.Assign{DestID: dest, Src: *ex.Result}) }
+            }
+        }
+        aInstr, aExtra = closeOpen(aInstr, aExtra, end)
+        extras = append(extras, ir.Block{Name: arm(j), Instr: aInstr})
+        extras = append(extras, aExtra...)
+    }
+    eInstr := []ir.Instruction{}
+    var eExtra []ir.Block
+    if rest != nil {
+        eInstr, eExtra = lowerBlockCFG(st, rest, *nextID)
+        *nextID += len(eExtra) + 1
+    }
+    extras = append(extras, ir.Block{Name: end, Instr: eInstr})
+    extras = append(extras, eExtra...)
+    return out, extras
+}
+
+// matchLowerKind names the runtime accessor family for a match subject type:
+// "enum", "union" or "optional" ("" when the subject type is unknown).
+func matchLowerKind(t types.Type) string {
+    switch t.(type) {
+    case types.Enum:
+        return "enum"
+    case types.Union:
+        return "union"
+    case types.Optional:
+        return "optional"
+    }
+    return ""
+}
+
+// matchPatternTag returns the tag value a pattern selects: the member ordinal for enums, the
+// alternative index in sorted order for unions, and 0 (None) or 1 (Some) for optionals.
+func matchPatternTag(t types.Type, kind string, p ast.MatchPattern) (int, bool) {
+    switch kind {
+    case "enum":
+        name := p.Name
+        if i := strings.LastIndexByte(name, '.'); i >= 0 { name = name[i+1:] }
+        for i, m := range t.(types.Enum).Members {
+            if m == name { return i, true }
+        }
+    case "union":
+        pt, err := typeResolver.Parse(p.Name)
+        if err != nil { return 0, false }
+        alts := matchUnionAlts(t.(types.Union))
+        for i, a := range alts {
+            if a == pt.String() { return i, true }
+        }
+    case "optional":
+        switch p.Kind {
+        case "none":
+            return 0, true
+        case "some":
+            return 1, true
+        }
+    }
+    return 0, false
+}
+
+// matchBindingType returns the type bound by a Some(x) or Type(x) pattern.
+func matchBindingType(t types.Type, kind string, p ast.MatchPattern) string {
+    switch kind {
+    case "union":
+        if pt, err := typeResolver.Parse(p.Name); err == nil { return pt.String() }
+    case "optional":
+        if p.Kind == "some" { return t.(types.Optional).Inner.String() }
+    }
+    return ""
+}
+
+// matchUnionAlts lists union alternatives in the sorted order that defines their tags.
+func matchUnionAlts(u types.Union) []string {
+    var alts []string
+    for _, a := range u.Alts { alts = append(alts, a.String()) }
+    sort.Strings(alts)
+    return alts
+}
@@ -0,0 +1,36 @@
+package driver
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// lowerMatchStmt recognizes statements that carry a match: `match ...` on its own,
+// `var x = match ...`, `x = match ...` and `return match ...`. It returns the match, the
+// variable receiving arm values (declared by the returned prefix), and any statement that
+// must run once the match completes (the synthesized return). ok is false for other statements.
+func lowerMatchStmt(st *lowerState, s ast.Stmt) (m *ast.MatchExpr, dest string, prefix []ir.Instruction, then []ast.Stmt, ok bool) {
+    switch v := s.(type) {
+    case *ast.ExprStmt:
+        m, ok = v.X.(*ast.MatchExpr)
+    case *ast.VarDecl:
+        if m, ok = v.Init.(*ast.MatchExpr); ok {
+            prefix = append(prefix, lowerStmtVar(st, &ast.VarDecl{Pos: v.Pos, Name: v.Name, Type: v.Type}))
+            dest = v.Name
+        }
+    case *ast.AssignStmt:
+        if m, ok = v.Value.(*ast.MatchExpr); ok { dest = v.Name }
+    case *ast.ReturnStmt:
+        if len(v.Results) != 1 { return nil, "", nil, nil, false }
+        if m, ok = v.Results[0].(*ast.MatchExpr); ok {
+            rt := ""
+            if rts := st.funcResults[st.currentFn]; len(rts) > 0 { rt = rts[0] }
+            dest = fmt.Sprintf("match%d_result", st.matchSeq)
+            prefix = append(prefix, lowerStmtVar(st, &ast.VarDecl{Pos: v.Pos, Name: dest, Type: rt}))
+            then = []ast.Stmt{&ast.ReturnStmt{Pos: v.Pos, Results: []ast.Expr{&ast.IdentExpr{Pos: v.Pos, Name: dest}}}}
+        }
+    }
+    return m, dest, prefix, then, ok
+}
@@ -13,10 +13,13 @@ func lowerPipelines(f *ast.File) []ir.Pipeline {
         pd, ok := d.(*ast.PipelineDecl)
         if !ok { continue }
         pl := ir.Pipeline{Name: pd.Name}
+        // occurrence IDs match those assigned to edges in collectEdges
+        seen := 0
         for _, s := range pd.Stmts {
             st, ok := s.(*ast.StepStmt)
             if !ok || st.Name != "Collect" { continue }
-            if mp := toMergePlan(st); mp != nil { pl.Collect = append(pl.Collect, ir.CollectSpec{Step: st.Name, Merge: mp}) }
+            seen++
+            if mp := toMergePlan(st); mp != nil { pl.Collect = append(pl.Collect, ir.CollectSpec{Step: st.Name, ID: seen, Merge: mp}) }
         }
         if len(pl.Collect) > 0 { out = append(out, pl) }
     }
@@ -1,5 +1,17 @@
 package driver
 
-import "testing"
+import (
+    "testing"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
 
 func Test_lower_pipelines_Exists(t *testing.T) {}
+
+func TestLowerPipelines_CollectSpecs_CarryOccurrenceIDs(t *testing.T) {
+    code := "package app\npipeline P(){ ingress; Collect; Collect merge.Sort(\"ts\"); Collect merge.Dedup(\"id\"); egress }\n"
+    pls := lowerPipelines(mustParse(t, &source.File{Name: "u.ami", Content: code}))
+    if len(pls) != 1 || len(pls[0].Collect) != 2 { t.Fatalf("unexpected pipelines: %+v", pls) }
+    c := pls[0].Collect
+    if c[0].ID != 2 || len(c[0].Merge.Sort) != 1 { t.Fatalf("first spec: %+v", c[0]) }
+    if c[1].ID != 3 || c[1].Merge.DedupField != "id" { t.Fatalf("second spec: %+v", c[1]) }
+}
@@ -0,0 +1,90 @@
+package driver
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// lowerPropagate lowers `[var] dest = call(...)?` (or a bare `call(...)?`) into:
+//   entry: the call; set = ami_rt_error_is_set(err); CONDBR set, perrN, pokN
+//   perrN: RETURN zero values for the enclosing function's leading results and err
+//   pokN:  dest = the call's first result (declared by decl when set); then rest
+// err is the call's last result.
+func lowerPropagate(st *lowerState, p *ast.PropagateExpr, dest string, decl *ast.VarDecl, rest *ast.BlockStmt, nextID *int) ([]ir.Instruction, []ir.Block) {
+    n := st.propSeq
+    st.propSeq++
+    errL := fmt.Sprintf("perr%d", n)
+    okL := fmt.Sprintf("pok%d", n)
+    var out []ir.Instruction
+    emitNestedCallArgs(st, p.X, &out)
+    if c, ok := p.X.(*ast.CallExpr); ok { maybeEmitMethodRecv(st, c, &out) }
+    var val, errV *ir.Value
+    if ex, ok := lowerExpr(st, p.X); ok {
+        out = append(out, ex)
+        switch {
+        case len(ex.Results) > 0:
+            r := ex.Results
+            errV = &r[len(r)-1]
+            if len(r) > 1 { val = &r[0] }
+        case ex.Result != nil:
+            errV = ex.Result
+        }
+    }
+    if errV == nil { errV = &ir.Value{ID: st.newTemp(), Type: "error"}; out = append(out, ir.Expr{Op: "zero", Result: errV}) }
+    set := ir.Value{ID: st.newTemp(), Type: "bool"}
+    out = append(out,
+        ir.Expr{Op: "call", Callee: "ami_rt_error_is_set", Args: []ir.Value{*errV}, Result: &set},
+        ir.CondBr{Cond: set, TrueLabel: errL, FalseLabel: okL},
+    )
+    // error path: return early with zero values and the error
+    var eInstr []ir.Instruction
+    var rets []ir.Value
+    rts := st.funcResults[st.currentFn]
+    for i := 0; i+1 < len(rts); i++ {
+        z := ir.Value{ID: st.newTemp(), Type: rts[i]}
+        eInstr = append(eInstr, ir.Expr{Op: "zero", Result: &z})
+        rets = append(rets, z)
+    }
+    rets = append(rets, *errV)
+    eInstr = append(eInstr, ir.Return{Values: rets})
+    // success path: bind the value, then continue with the rest of the block
+    var oInstr []ir.Instruction
+    if dest != "" && val != nil {
+        if decl != nil {
+            init := *val
+            typ := decl.Type
+            if typ == "" { typ = val.Type }
+            oInstr = append(oInstr, ir.Var{Name: dest, Type: typ, Init: &init, Result: ir.Value{ID: dest, Type: typ}})
+            st.varTypes[dest] = typ
+        } else {
+            oInstr = append(oInstr, ir.Assign{DestID: dest, Src: *val})
+        }
+    }
+    var oExtra []ir.Block
+    if rest != nil {
+        var rInstr []ir.Instruction
+        rInstr, oExtra = lowerBlockCFG(st, rest, *nextID)
+        *nextID += len(oExtra) + 1
+        oInstr = append(oInstr, rInstr...)
+    }
+    extras := []ir.Block{{Name: errL, Instr: eInstr}, {Name: okL, Instr: oInstr}}
+    extras = append(extras, oExtra...)
+    return out, extras
+}
+
+// lowerPropagateStmt recognizes `var x = call()?`, `x = call()?` and a bare `call()?`,
+// returning the propagation, the variable receiving the call's value, and its
+// declaration when the statement declares it.
+func lowerPropagateStmt(s ast.Stmt) (p *ast.PropagateExpr, dest string, decl *ast.VarDecl, ok bool) {
+    switch v := s.(type) {
+    case *ast.ExprStmt:
+        p, ok = v.X.(*ast.PropagateExpr)
+    case *ast.VarDecl:
+        if p, ok = v.Init.(*ast.PropagateExpr); ok { dest, decl = v.Name, v }
+    case *ast.AssignStmt:
+        if p, ok = v.Value.(*ast.PropagateExpr); ok { dest = v.Name }
+    }
+    return p, dest, decl, ok
+}
@@ -0,0 +1,54 @@
+package driver
+
+import "strings"
+
+// rangeTypes splits a collection type into its range kind ("slice", "set" or "map") and the
+// types bound to the key and value loop variables. Slices bind an int64 index and the element,
+// sets bind the element only, and maps bind key and value. Unknown types range as slice<any>.
+func rangeTypes(coll string) (kind, keyT, valT string) {
+    coll = strings.TrimSpace(coll)
+    i := strings.IndexByte(coll, '<')
+    if i < 0 || !strings.HasSuffix(coll, ">") { return "slice", "int64", "any" }
+    base := coll[:i]
+    inner := coll[i+1 : len(coll)-1]
+    switch base {
+    case "set":
+        return "set", strings.TrimSpace(inner), ""
+    case "map":
+        depth := 0
+        for j := 0; j < len(inner); j++ {
+            switch inner[j] {
+            case '<', '{', '(':
+                depth++
+            case '>', '}', ')':
+                depth--
+            case ',':
+                if depth == 0 { return "map", strings.TrimSpace(inner[:j]), strings.TrimSpace(inner[j+1:]) }
+            }
+        }
+        return "map", strings.TrimSpace(inner), "any"
+    default:
+        return "slice", "int64", strings.TrimSpace(inner)
+    }
+}
+
+// rangeABI returns the accessor suffix for a bound element type; it mirrors the LLVM scalar
+// mapping so the runtime accessor's return type matches the lowered result.
+func rangeABI(t string) string {
+    switch strings.TrimSpace(t) {
+    case "bool":
+        return "i1"
+    case "int8", "uint8":
+        return "i8"
+    case "int16", "uint16":
+        return "i16"
+    case "int32", "uint32":
+        return "i32"
+    case "int", "int64", "uint", "uint64", "Duration", "Time", "SignalType":
+        return "i64"
+    case "real", "float64":
+        return "double"
+    default:
+        return "ptr"
+    }
+}
@@ -1,5 +1,10 @@
 package driver
 
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
 // lowerState holds per-function lowering state.
 type lowerState struct {
     temp int
@@ -10,4 +15,15 @@ type lowerState struct {
     currentFn string
     methodRecv map[string]irValue
     gpuBlocks []gpuBlock
+    // loopSeq numbers loops for unique labels; loops is the stack of enclosing loop targets.
+    loopSeq int
+    loops   []loopTarget
+    // matchSeq numbers match expressions for unique labels.
+    matchSeq int
+    // propSeq numbers error propagations (x?) for unique labels.
+    propSeq int
+    // interps records the values of interpolated strings already lowered.
+    interps map[*ast.InterpStringLit]ir.Value
+    // jsonTargets records the target types of json.Decode calls (see hoistJSONDecodeTarget).
+    jsonTargets map[*ast.CallExpr]jsonTarget
 }
@@ -17,6 +17,9 @@ var enableBufioMethodRewrite = true
 func lowerStdlibCall(st *lowerState, c *ast.CallExpr) (ir.Expr, bool) {
     if c == nil { return ir.Expr{}, false }
     name := c.Name
+    if ex, ok := lowerStdlibErrors(st, c); ok { return ex, true }
+    if ex, ok := lowerStdlibStrings(st, c); ok { return ex, true }
+    if ex, ok := lowerStdlibJSON(st, c); ok { return ex, true }
     if ex, ok := lowerStdlibMath(st, c); ok { return ex, true }
     if (name == "signal.Register") || (st != nil && st.funcParams != nil && len(st.funcParams[name]) == 2 && st.funcParams[name][0] == "SignalType") {
         if len(c.Args) >= 2 {
@@ -0,0 +1,32 @@
+package driver
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// lowerStdlibErrors maps AMI stdlib errors calls to runtime helpers:
+//   errors.New(code, msg)               → ami_rt_error_make(code, msg)
+//   errors.NewWithData(code, msg, data) → ami_rt_error_make_data(code, msg, data)
+//   errors.Code/Message/Data(e)         → ami_rt_error_code_str/_message/_data(e)
+func lowerStdlibErrors(st *lowerState, c *ast.CallExpr) (ir.Expr, bool) {
+    var callee, rtype string
+    switch c.Name {
+    case "errors.New":
+        callee, rtype = "ami_rt_error_make", "error"
+    case "errors.NewWithData":
+        callee, rtype = "ami_rt_error_make_data", "error"
+    case "errors.Code":
+        callee, rtype = "ami_rt_error_code_str", "string"
+    case "errors.Message":
+        callee, rtype = "ami_rt_error_message", "string"
+    case "errors.Data":
+        callee, rtype = "ami_rt_error_data", "map<string,any>"
+    default:
+        return ir.Expr{}, false
+    }
+    var args []ir.Value
+    for _, a := range c.Args { if ex, ok := lowerExpr(st, a); ok && ex.Result != nil { args = append(args, *ex.Result) } }
+    res := &ir.Value{ID: st.newTemp(), Type: rtype}
+    return ir.Expr{Op: "call", Callee: callee, Args: args, Result: res}, true
+}
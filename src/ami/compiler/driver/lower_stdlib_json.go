@@ -0,0 +1,112 @@
+package driver
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// jsonTarget is the declared result type of a json.Decode call and the value
+// holding its resolved type descriptor.
+type jsonTarget struct {
+    typ  string
+    desc ir.Value
+}
+
+// hoistJSONDecodeTarget records the target type of a json.Decode call that s
+// stores into a typed variable and emits the call's type descriptor (the structurally resolved target type,
+// e.g. `Struct{id:string,qty:int}`) into out ahead of the statement.
+func hoistJSONDecodeTarget(st *lowerState, s ast.Stmt, out *[]ir.Instruction) {
+    var e ast.Expr
+    typ := ""
+    switch v := s.(type) {
+    case *ast.VarDecl:
+        e, typ = v.Init, v.Type
+    case *ast.AssignStmt:
+        e, typ = v.Value, st.varTypes[v.Name]
+    }
+    if p, ok := e.(*ast.PropagateExpr); ok { e = p.X }
+    c, ok := e.(*ast.CallExpr)
+    typ = strings.TrimSpace(typ)
+    if !ok || c.Name != "json.Decode" || typ == "" { return }
+    desc := typ
+    if t, err := typeResolver.Parse(typ); err == nil { desc = t.String() }
+    res := &ir.Value{ID: st.newTemp(), Type: "string"}
+    *out = append(*out, ir.Expr{Op: fmt.Sprintf("lit:%q", desc), Result: res})
+    if st.jsonTargets == nil { st.jsonTargets = map[*ast.CallExpr]jsonTarget{} }
+    st.jsonTargets[c] = jsonTarget{typ: typ, desc: *res}
+}
+
+// jsonDecodeABI names the json.Decode helper variant for target type t: the
+// targets runtime/host/json decodes get their own helper, other scalars an
+// ami_rt_json_decode_unsupported_<abi> helper failing with E_JSON_TYPE, and
+// everything else "ptr".
+func jsonDecodeABI(t string) string {
+    switch strings.TrimSpace(t) {
+    case "bool":
+        return "i1"
+    case "int", "int64":
+        return "i64"
+    case "float64":
+        return "double"
+    case "string":
+        return "string"
+    }
+    if abi := rangeABI(t); abi != "ptr" { return "unsupported_" + abi }
+    return "ptr"
+}
+
+// jsonEncodeABI names the json.Encode helper variant for an argument of type t:
+// narrower integers widen to i64, unsigned 64-bit integers use u64 and Time,
+// like structured values, uses "ptr".
+func jsonEncodeABI(t string) string {
+    switch strings.TrimSpace(t) {
+    case "string":
+        return "string"
+    case "uint", "uint64":
+        return "u64"
+    case "Time":
+        return "ptr"
+    }
+    switch abi := rangeABI(t); abi {
+    case "i8", "i16", "i32":
+        return "i64"
+    default:
+        return abi
+    }
+}
+
+// lowerStdlibJSON maps AMI stdlib json calls to runtime helpers specialized by
+// the type of the decoded value or encoded argument:
+//   json.Encode(v)            → ami_rt_json_encode_<abi>(v) with (string, error) results
+//   var o T = json.Decode(s)? → ami_rt_json_decode_<abi>(s, desc) with (T, error) results,
+//                               desc being T's type descriptor (see hoistJSONDecodeTarget)
+// where <abi> is jsonEncodeABI(v's type) or jsonDecodeABI(T).
+func lowerStdlibJSON(st *lowerState, c *ast.CallExpr) (ir.Expr, bool) {
+    var results []string
+    switch c.Name {
+    case "json.Encode":
+        results = []string{"string", "error"}
+    case "json.Decode":
+        results = []string{"any", "error"}
+    default:
+        return ir.Expr{}, false
+    }
+    var args []ir.Value
+    for _, a := range c.Args { if ex, ok := lowerExpr(st, a); ok && ex.Result != nil { args = append(args, *ex.Result) } }
+    callee := "ami_rt_json_encode_ptr"
+    if c.Name == "json.Encode" && len(args) > 0 { callee = "ami_rt_json_encode_" + jsonEncodeABI(args[0].Type) }
+    if c.Name == "json.Decode" {
+        callee = "ami_rt_json_decode_ptr"
+        if tgt, ok := st.jsonTargets[c]; ok {
+            args = append(args, tgt.desc)
+            results = []string{tgt.typ, "error"}
+            callee = "ami_rt_json_decode_" + jsonDecodeABI(tgt.typ)
+        }
+    }
+    var vals []ir.Value
+    for _, t := range results { vals = append(vals, ir.Value{ID: st.newTemp(), Type: t}) }
+    return ir.Expr{Op: "call", Callee: callee, Args: args, Results: vals, ResultTypes: results}, true
+}
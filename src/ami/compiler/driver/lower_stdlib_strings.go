@@ -0,0 +1,48 @@
+package driver
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// stringsRuntime maps AMI stdlib strings functions to their runtime helpers and
+// result types. Parse functions return (value, error) aggregates.
+var stringsRuntime = map[string]struct {
+    callee  string
+    results []string
+}{
+    "strings.Len":         {"ami_rt_string_len", []string{"int64"}},
+    "strings.Split":       {"ami_rt_strings_split", []string{"slice<string>"}},
+    "strings.Join":        {"ami_rt_strings_join", []string{"string"}},
+    "strings.Contains":    {"ami_rt_strings_contains", []string{"bool"}},
+    "strings.HasPrefix":   {"ami_rt_strings_has_prefix", []string{"bool"}},
+    "strings.HasSuffix":   {"ami_rt_strings_has_suffix", []string{"bool"}},
+    "strings.Index":       {"ami_rt_strings_index", []string{"int64"}},
+    "strings.Replace":     {"ami_rt_strings_replace", []string{"string"}},
+    "strings.Trim":        {"ami_rt_strings_trim", []string{"string"}},
+    "strings.TrimSpace":   {"ami_rt_strings_trim_space", []string{"string"}},
+    "strings.ToUpper":     {"ami_rt_strings_to_upper", []string{"string"}},
+    "strings.ToLower":     {"ami_rt_strings_to_lower", []string{"string"}},
+    "strings.ParseInt":    {"ami_rt_strings_parse_int", []string{"int64", "error"}},
+    "strings.ParseFloat":  {"ami_rt_strings_parse_float", []string{"float64", "error"}},
+    "strings.FormatInt":   {"ami_rt_int_to_string", []string{"string"}},
+    "strings.FormatFloat": {"ami_rt_float_to_string", []string{"string"}},
+    "strings.FormatBool":  {"ami_rt_bool_to_string", []string{"string"}},
+}
+
+// lowerStdlibStrings maps AMI stdlib strings calls to runtime helpers, e.g.
+//   strings.Split(s, sep) → ami_rt_strings_split(s, sep)
+//   strings.ParseInt(s)   → ami_rt_strings_parse_int(s) with (int64, error) results
+func lowerStdlibStrings(st *lowerState, c *ast.CallExpr) (ir.Expr, bool) {
+    rt, ok := stringsRuntime[c.Name]
+    if !ok { return ir.Expr{}, false }
+    var args []ir.Value
+    for _, a := range c.Args { if ex, ok := lowerExpr(st, a); ok && ex.Result != nil { args = append(args, *ex.Result) } }
+    if len(rt.results) > 1 {
+        var results []ir.Value
+        for _, t := range rt.results { results = append(results, ir.Value{ID: st.newTemp(), Type: t}) }
+        return ir.Expr{Op: "call", Callee: rt.callee, Args: args, Results: results, ResultTypes: rt.results}, true
+    }
+    res := &ir.Value{ID: st.newTemp(), Type: rt.results[0]}
+    return ir.Expr{Op: "call", Callee: rt.callee, Args: args, Result: res}, true
+}
@@ -0,0 +1,50 @@
+package driver
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// lowerStmtFor lowers an infinite or condition loop into CFG blocks:
+//   loopN: LOOP marker; cond → CONDBR bodyN/exitN (or GOTO bodyN when infinite)
+//   bodyN: loop body; falls through back to loopN
+//   exitN: the remainder of the enclosing block (rest)
+// It returns the instructions that end the current block and the blocks it created.
+func lowerStmtFor(st *lowerState, v *ast.ForStmt, rest *ast.BlockStmt, nextID *int) ([]ir.Instruction, []ir.Block) {
+    n := st.loopSeq
+    st.loopSeq++
+    head := fmt.Sprintf("loop%d", n)
+    body := fmt.Sprintf("body%d", n)
+    exit := fmt.Sprintf("exit%d", n)
+    out := []ir.Instruction{ir.Goto{Label: head}}
+    hInstr := []ir.Instruction{ir.Loop{Name: head}}
+    if v.Cond != nil {
+        emitNestedCallArgs(st, v.Cond, &hInstr)
+        if ex, ok := lowerExpr(st, v.Cond); ok {
+            if ex.Op != "" || ex.Callee != "" || len(ex.Args) > 0 { hInstr = append(hInstr, ex) }
+            cid := ""
+            if ex.Result != nil { cid = ex.Result.ID }
+            hInstr = append(hInstr, ir.CondBr{Cond: ir.Value{ID: cid, Type: "bool"}, TrueLabel: body, FalseLabel: exit})
+        }
+    }
+    if !endsWithTerminator(hInstr) { hInstr = append(hInstr, ir.Goto{Label: body}) }
+    extras := []ir.Block{{Name: head, Instr: hInstr}}
+    st.loops = append(st.loops, loopTarget{cont: head, exit: exit})
+    bInstr, bExtra := lowerBlockCFG(st, v.Body, *nextID)
+    *nextID += len(bExtra) + 1
+    st.loops = st.loops[:len(st.loops)-1]
+    bInstr, bExtra = closeOpen(bInstr, bExtra, head)
+    extras = append(extras, ir.Block{Name: body, Instr: bInstr})
+    extras = append(extras, bExtra...)
+    eInstr := []ir.Instruction{}
+    var eExtra []ir.Block
+    if rest != nil {
+        eInstr, eExtra = lowerBlockCFG(st, rest, *nextID)
+        *nextID += len(eExtra) + 1
+    }
+    extras = append(extras, ir.Block{Name: exit, Instr: eInstr})
+    extras = append(extras, eExtra...)
+    return out, extras
+}
@@ -0,0 +1,99 @@
+package driver
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// lowerStmtRange lowers a range loop over a slice, set or map into CFG blocks driven by an
+// int64 index:
+//   preN:   coll len via ami_rt_<kind>_len; idx0 = 0
+//   loopN:  LOOP marker; idx = PHI [idx0, preN], [next, latchN]; CONDBR idx < len
+//   bodyN:  bind loop variables via ami_rt_<accessor>_<abi>(coll, idx); loop body
+//   latchN: next = idx + 1; GOTO loopN
+//   exitN:  the remainder of the enclosing block (rest)
+// continue jumps to the latch so the index always advances.
+func lowerStmtRange(st *lowerState, v *ast.RangeStmt, rest *ast.BlockStmt, nextID *int) ([]ir.Instruction, []ir.Block) {
+    n := st.loopSeq
+    st.loopSeq++
+    pre := fmt.Sprintf("pre%d", n)
+    head := fmt.Sprintf("loop%d", n)
+    body := fmt.Sprintf("body%d", n)
+    latch := fmt.Sprintf("latch%d", n)
+    exit := fmt.Sprintf("exit%d", n)
+    out := []ir.Instruction{ir.Goto{Label: pre}}
+    // preheader: evaluate the collection once and compute its length
+    var pInstr []ir.Instruction
+    emitNestedCallArgs(st, v.X, &pInstr)
+    coll := ir.Value{Type: "any"}
+    if ex, ok := lowerExpr(st, v.X); ok {
+        if ex.Op != "" || ex.Callee != "" || len(ex.Args) > 0 { pInstr = append(pInstr, ex) }
+        if ex.Result != nil { coll = *ex.Result }
+    }
+    kind, keyT, valT := rangeTypes(coll.Type)
+    length := ir.Value{ID: st.newTemp(), Type: "int64"}
+    pInstr = append(pInstr, ir.Expr{Op: "call", Callee: "ami_rt_" + kind + "_len", Args: []ir.Value{coll}, Result: &length})
+    idx0 := ir.Value{ID: st.newTemp(), Type: "int64"}
+    pInstr = append(pInstr, ir.Expr{Op: "lit:0", Result: &idx0}, ir.Goto{Label: head})
+    // header: index phi and bounds check
+    idx := ir.Value{ID: st.newTemp(), Type: "int64"}
+    next := ir.Value{ID: st.newTemp(), Type: "int64"}
+    cmp := ir.Value{ID: st.newTemp(), Type: "bool"}
+    hInstr := []ir.Instruction{
+        ir.Loop{Name: head},
+        ir.Phi{Result: idx, Incomings: []ir.PhiIncoming{{Value: idx0, Label: pre}, {Value: next, Label: latch}}},
+        ir.Expr{Op: "lt", Args: []ir.Value{idx, length}, Result: &cmp},
+        ir.CondBr{Cond: cmp, TrueLabel: body, FalseLabel: exit},
+    }
+    // body: bind loop variables, then the user body
+    var bInstr []ir.Instruction
+    fetch := func(accessor, typ string) ir.Value {
+        if typ == "" { typ = "any" }
+        r := ir.Value{ID: st.newTemp(), Type: typ}
+        bInstr = append(bInstr, ir.Expr{Op: "call", Callee: "ami_rt_" + accessor + "_" + rangeABI(typ), Args: []ir.Value{coll, idx}, Result: &r})
+        return r
+    }
+    bind := func(name string, val ir.Value) {
+        init := val
+        bInstr = append(bInstr, ir.Var{Name: name, Type: val.Type, Init: &init, Result: ir.Value{ID: name, Type: val.Type}})
+        st.varTypes[name] = val.Type
+    }
+    bound := func(name string) bool { return name != "" && name != "_" }
+    switch kind {
+    case "set":
+        if bound(v.Key) { bind(v.Key, fetch("set_at", keyT)) }
+    case "map":
+        if bound(v.Key) { bind(v.Key, fetch("map_key", keyT)) }
+        if bound(v.Value) { bind(v.Value, fetch("map_val", valT)) }
+    default:
+        if bound(v.Key) { bind(v.Key, idx) }
+        if bound(v.Value) { bind(v.Value, fetch("slice_at", valT)) }
+    }
+    st.loops = append(st.loops, loopTarget{cont: latch, exit: exit})
+    uInstr, bExtra := lowerBlockCFG(st, v.Body, *nextID)
+    *nextID += len(bExtra) + 1
+    st.loops = st.loops[:len(st.loops)-1]
+    bInstr = append(bInstr, uInstr...)
+    bInstr, bExtra = closeOpen(bInstr, bExtra, latch)
+    // latch: advance the index
+    one := ir.Value{ID: st.newTemp(), Type: "int64"}
+    lInstr := []ir.Instruction{
+        ir.Expr{Op: "lit:1", Result: &one},
+        ir.Expr{Op: "add", Args: []ir.Value{idx, one}, Result: &next},
+        ir.Goto{Label: head},
+    }
+    extras := []ir.Block{{Name: pre, Instr: pInstr}, {Name: head, Instr: hInstr}, {Name: body, Instr: bInstr}}
+    extras = append(extras, bExtra...)
+    extras = append(extras, ir.Block{Name: latch, Instr: lInstr})
+    eInstr := []ir.Instruction{}
+    var eExtra []ir.Block
+    if rest != nil {
+        eInstr, eExtra = lowerBlockCFG(st, rest, *nextID)
+        *nextID += len(eExtra) + 1
+    }
+    extras = append(extras, ir.Block{Name: exit, Instr: eInstr})
+    extras = append(extras, eExtra...)
+    return out, extras
+}
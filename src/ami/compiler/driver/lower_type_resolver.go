@@ -0,0 +1,7 @@
+package driver
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/types"
+
+// typeResolver expands user-declared type names while lowering the current
+// package; Compile sets it per package. Nil resolves nothing.
+var typeResolver *types.Resolver
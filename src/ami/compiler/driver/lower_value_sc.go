@@ -90,6 +90,8 @@ func lowerValueSC(st *lowerState, e ast.Expr, out *[]ir.Instruction, extras *[]i
         *out = append(*out, ir.Expr{Op: "call", Callee: v.Name, Args: args, Result: res})
         if res != nil { return *res, true }
         return ir.Value{}, true
+    case *ast.InterpStringLit:
+        return lowerInterpString(st, v, out), true
     default:
         ex, ok := lowerExpr(st, e)
         if !ok || ex.Result == nil { return ir.Value{}, false }
@@ -0,0 +1,27 @@
+package driver
+
+import (
+    "strings"
+    "testing"
+)
+
+// An enum match lowers to a tag test chain feeding one block per arm and an end block.
+func TestDriver_MatchLowering_Enum(t *testing.T) {
+    code := "package app\nenum Color { Red, Green, Blue }\nfunc F(c Color) (int) {\nvar n int = 0\nmatch c { Red => { n = 1 }, Green, Blue => { n = 2 } }\nreturn n\n}\n"
+    blks := loopIRBlocks(t, "match_enum", code)
+    for _, name := range []string{"match0", "mtest0_0", "mtest0_1", "mtest0_2", "mtest0_3", "case0_0", "case0_1", "mend0"} {
+        if _, ok := blks[name]; !ok { t.Fatalf("missing block %s: %v", name, blks) }
+    }
+    if !strings.Contains(strings.Join(blks["match0"], ","), "EXPR") || last(blks["mtest0_0"]) != "COND_BR" { t.Fatalf("head/test: %v", blks) }
+    if last(blks["case0_0"]) != "GOTO" || last(blks["mend0"]) != "RETURN" { t.Fatalf("arms/end: %v", blks) }
+}
+
+// A match used as a return value binds the Some payload and returns through a temporary.
+func TestDriver_MatchLowering_OptionalValue(t *testing.T) {
+    code := "package app\nfunc F(o Optional<int>) (int) {\nreturn match o { Some(v) => v, None => 0 }\n}\n"
+    blks := loopIRBlocks(t, "match_opt", code)
+    if ops := strings.Join(blks["case0_0"], ","); !strings.HasPrefix(ops, "EXPR,VAR") || !strings.Contains(ops, "ASSIGN") {
+        t.Fatalf("some arm: %s", ops)
+    }
+    if last(blks["mend0"]) != "RETURN" { t.Fatalf("end: %v", blks) }
+}
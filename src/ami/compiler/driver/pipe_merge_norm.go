@@ -19,5 +19,21 @@ type pipeMergeNorm struct {
         Lateness string `json:"lateness"`
     } `json:"watermark,omitempty"`
     Dedup       string `json:"dedup,omitempty"`
+    DedupTTL    string `json:"dedupTTL,omitempty"`
+    DedupLimit  string `json:"dedupLimit,omitempty"`
+    DedupBloom  *struct{
+        FPRate string `json:"fpRate,omitempty"`
+    } `json:"dedupBloom,omitempty"`
+    EventWindow *struct{
+        Kind  string `json:"kind"`
+        Size  string `json:"size,omitempty"`
+        Slide string `json:"slide,omitempty"`
+        Gap   string `json:"gap,omitempty"`
+    } `json:"eventWindow,omitempty"`
+    Aggregates  []struct{
+        Fn    string `json:"fn"`
+        Field string `json:"field,omitempty"`
+        As    string `json:"as,omitempty"`
+    } `json:"aggregates,omitempty"`
 }
 
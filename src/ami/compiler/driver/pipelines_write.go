@@ -166,6 +166,28 @@ func writePipelinesDebug(pkg, unit string, f *ast.File) (string, error) {
                             norm.Watermark = &wm
                         }
                         if at.Name == "merge.Dedup" { if len(margs) > 0 { norm.Dedup = margs[0] } else { norm.Dedup = "" } }
+                        if at.Name == "merge.DedupTTL" && len(margs) > 0 { norm.DedupTTL = margs[0] }
+                        if at.Name == "merge.DedupLimit" && len(margs) > 0 { norm.DedupLimit = margs[0] }
+                        if at.Name == "merge.DedupBloom" {
+                            db := struct{ FPRate string `json:"fpRate,omitempty"` }{}
+                            if len(margs) > 0 { db.FPRate = margs[0] }
+                            norm.DedupBloom = &db
+                        }
+                        if at.Name == "merge.Tumbling" || at.Name == "merge.Sliding" || at.Name == "merge.Session" {
+                            ew := struct{ Kind string `json:"kind"`; Size string `json:"size,omitempty"`; Slide string `json:"slide,omitempty"`; Gap string `json:"gap,omitempty"` }{Kind: strings.ToLower(at.Name[len("merge."):])}
+                            switch at.Name {
+                            case "merge.Tumbling": if len(margs) > 0 { ew.Size = margs[0] }
+                            case "merge.Sliding": if len(margs) > 0 { ew.Size = margs[0] }; if len(margs) > 1 { ew.Slide = margs[1] }
+                            case "merge.Session": if len(margs) > 0 { ew.Gap = margs[0] }
+                            }
+                            norm.EventWindow = &ew
+                        }
+                        if at.Name == "merge.Aggregate" && len(margs) > 0 {
+                            ag := struct{ Fn string `json:"fn"`; Field string `json:"field,omitempty"`; As string `json:"as,omitempty"` }{Fn: margs[0]}
+                            if len(margs) > 1 { ag.Field = margs[1] }
+                            if len(margs) > 2 { ag.As = margs[2] }
+                            norm.Aggregates = append(norm.Aggregates, ag)
+                        }
                     }
                     if (at.Name == "edge.MultiPath" || at.Name == "MultiPath") && st.Name == "Collect" {
                         var margs []string
@@ -0,0 +1,35 @@
+package driver
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// x? branches on the call's error: perrN returns zero values and the error, pokN binds the value.
+func TestDriver_PropagateLowering(t *testing.T) {
+    code := "package app\n" +
+        "func parse(s string) (int, error) { return 0, nil }\n" +
+        "func F(s string) (int, error) {\nvar n = parse(s)?\nn = parse(s)?\nreturn n, nil\n}\n"
+    blks := loopIRBlocks(t, "propagate", code)
+    for _, name := range []string{"perr0", "pok0", "perr1", "pok1"} {
+        if _, ok := blks[name]; !ok { t.Fatalf("missing block %s: %v", name, blks) }
+    }
+    if last(blks["entry"]) != "COND_BR" || last(blks["pok0"]) != "COND_BR" { t.Fatalf("checks: %v", blks) }
+    if ops := strings.Join(blks["perr0"], ","); ops != "EXPR,RETURN" { t.Fatalf("error path: %s", ops) }
+    if !strings.HasPrefix(strings.Join(blks["pok0"], ","), "VAR") || !strings.HasPrefix(strings.Join(blks["pok1"], ","), "ASSIGN") {
+        t.Fatalf("success paths: %v", blks)
+    }
+    if last(blks["pok1"]) != "RETURN" { t.Fatalf("continuation: %v", blks["pok1"]) }
+}
+
+// errors.New lowers to the structured error runtime constructor.
+func TestDriver_ErrorsNew_Lowering(t *testing.T) {
+    code := "package app\nimport errors\nfunc F(s string) (int, error) {\nreturn 0, errors.New(\"E_BAD_INPUT\", \"bad input\")\n}\n"
+    blks := loopIRBlocks(t, "errors_new", code)
+    if last(blks["entry"]) != "RETURN" { t.Fatalf("entry: %v", blks) }
+    b, err := os.ReadFile(filepath.Join("build", "debug", "ir", "app", "errors_new.ir.json"))
+    if err != nil { t.Fatalf("read ir: %v", err) }
+    if !strings.Contains(string(b), "ami_rt_error_make") { t.Fatalf("missing ami_rt_error_make: %s", b) }
+}
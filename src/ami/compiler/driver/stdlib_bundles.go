@@ -52,6 +52,48 @@ func builtinStdlibPackages() []Package {
     gfs.AddFile("gpu.ami", gpuSrc)
     out = append(out, Package{Name: "gpu", Files: gfs})
 
+    // errors package: structured error constructors and accessors
+    errSrc := "package errors\n" +
+        "func New(code string, message string) (error) {}\n" +
+        "func NewWithData(code string, message string, data map<string,any>) (error) {}\n" +
+        "func Code(e error) (string) {}\n" +
+        "func Message(e error) (string) {}\n" +
+        "func Data(e error) (map<string,any>) {}\n"
+    efs := &source.FileSet{}
+    efs.AddFile("errors.ami", errSrc)
+    out = append(out, Package{Name: "errors", Files: efs})
+
+    // strings package: text helpers (signatures only)
+    strSrc := "package strings\n" +
+        "func Len(s string) (int64) {}\n" +
+        "func Split(s string, sep string) (slice<string>) {}\n" +
+        "func Join(parts slice<string>, sep string) (string) {}\n" +
+        "func Contains(s string, sub string) (bool) {}\n" +
+        "func HasPrefix(s string, prefix string) (bool) {}\n" +
+        "func HasSuffix(s string, suffix string) (bool) {}\n" +
+        "func Index(s string, sub string) (int64) {}\n" +
+        "func Replace(s string, old string, repl string) (string) {}\n" +
+        "func Trim(s string, cutset string) (string) {}\n" +
+        "func TrimSpace(s string) (string) {}\n" +
+        "func ToUpper(s string) (string) {}\n" +
+        "func ToLower(s string) (string) {}\n" +
+        "func ParseInt(s string) (int64, error) {}\n" +
+        "func ParseFloat(s string) (float64, error) {}\n" +
+        "func FormatInt(v int64) (string) {}\n" +
+        "func FormatFloat(v float64) (string) {}\n" +
+        "func FormatBool(v bool) (string) {}\n"
+    strfs := &source.FileSet{}
+    strfs.AddFile("strings.ami", strSrc)
+    out = append(out, Package{Name: "strings", Files: strfs})
+
+    // json package: typed decode and deterministic encode (signatures only)
+    jsonSrc := "package json\n" +
+        "func Decode(s string) (any, error) {}\n" +
+        "func Encode(v any) (string, error) {}\n"
+    jsonfs := &source.FileSet{}
+    jsonfs.AddFile("json.ami", jsonSrc)
+    out = append(out, Package{Name: "json", Files: jsonfs})
+
     // bufio package minimal stubs (signatures only)
     // TODO: When method receivers and cross-package type references are implemented,
     // replace these function-shaped APIs with true methods on Reader/Writer/Scanner.
@@ -0,0 +1,94 @@
+package driver
+
+import (
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// json.Decode receives its target's resolved type descriptor; Encode maps to its helper.
+func TestDriver_JSONLowering(t *testing.T) {
+    code := "package app\nimport json\n" +
+        "enum Status { Open, Closed }\n" +
+        "type Order struct { id string\n qty int\n status Status }\n" +
+        "func F(body string) (string, error) {\n" +
+        "  var o Order = json.Decode(body)?\n" +
+        "  return json.Encode(o)\n" +
+        "}\n"
+    loopIRBlocks(t, "json_lowering", code)
+    got := irCallees(t, "json_lowering")
+    want := []string{`lit:"Struct{id:string,qty:int,status:app.Status}"`, "ami_rt_json_decode_ptr", "ami_rt_error_is_set"}
+    if strings.Join(got["entry"], " ") != strings.Join(want, " ") { t.Fatalf("entry:\n got %v\nwant %v", got["entry"], want) }
+    if strings.Join(got["pok0"], " ") != "ami_rt_json_encode_ptr" { t.Fatalf("pok0: %v", got["pok0"]) }
+}
+
+// Helpers are specialized by the LLVM scalar of the decoded value and the encoded argument.
+func TestDriver_JSONLowering_ScalarABI(t *testing.T) {
+    code := "package app\nimport json\n" +
+        "func F(body string) (string, error) {\n" +
+        "  var x float64 = json.Decode(body)?\n" +
+        "  return json.Encode(x)\n" +
+        "}\n"
+    loopIRBlocks(t, "json_scalar_lowering", code)
+    got := irCallees(t, "json_scalar_lowering")
+    if got["entry"][1] != "ami_rt_json_decode_double" { t.Fatalf("entry: %v", got["entry"]) }
+    if strings.Join(got["pok0"], " ") != "ami_rt_json_encode_double" { t.Fatalf("pok0: %v", got["pok0"]) }
+}
+
+// Strings, unsigned and narrow integers get their own helpers; scalar targets
+// runtime/host/json does not decode use the unsupported variant.
+func TestDriver_JSONLowering_StringAndIntegerABI(t *testing.T) {
+    code := "package app\nimport json\n" +
+        "func F(body string) (string, error) {\n" +
+        "  var s string = json.Decode(body)?\n" +
+        "  return json.Encode(s)\n" +
+        "}\n"
+    loopIRBlocks(t, "json_string_lowering", code)
+    got := irCallees(t, "json_string_lowering")
+    if got["entry"][1] != "ami_rt_json_decode_string" { t.Fatalf("entry: %v", got["entry"]) }
+    if strings.Join(got["pok0"], " ") != "ami_rt_json_encode_string" { t.Fatalf("pok0: %v", got["pok0"]) }
+    code = "package app\nimport json\n" +
+        "func F(body string, n uint64) (string, error) {\n" +
+        "  var x int32 = json.Decode(body)?\n" +
+        "  return json.Encode(n)\n" +
+        "}\n"
+    loopIRBlocks(t, "json_int_lowering", code)
+    got = irCallees(t, "json_int_lowering")
+    if got["entry"][1] != "ami_rt_json_decode_unsupported_i32" { t.Fatalf("entry: %v", got["entry"]) }
+    if strings.Join(got["pok0"], " ") != "ami_rt_json_encode_u64" { t.Fatalf("pok0: %v", got["pok0"]) }
+}
+
+// The on-disk json package compiles; decode targets must be declared and decodable.
+func TestStdlib_JSON_FS_DecodeTargets(t *testing.T) {
+    var pkgs []Package
+    for _, p := range fsStdlibPackages(filepath.Join("..", "..", "..", "..", "std", "ami", "stdlib")) {
+        if p.Name == "json" { pkgs = append(pkgs, p) }
+    }
+    if len(pkgs) != 1 { t.Fatalf("json package not found") }
+    appfs := &source.FileSet{}
+    appfs.AddFile("app.ami", "package app\nimport json\n"+
+        "type Line struct { sku string\n qty Optional<int> }\n"+
+        "func Lines(body string) (slice<Line>, error) {\n"+
+        "  var out slice<Line>\n"+
+        "  out = json.Decode(body)?\n"+
+        "  return out, nil\n"+
+        "}\n"+
+        "func Bad(body string, e error) (string, error) {\n"+
+        "  var x = json.Decode(body)?\n"+
+        "  e = json.Decode(body)?\n"+
+        "  return json.Encode(json.Decode(body)), nil\n"+
+        "}\n")
+    pkgs = append(pkgs, Package{Name: "app", Files: appfs})
+    _, diags := Compile(workspace.Workspace{}, pkgs, Options{EmitLLVMOnly: true})
+    counts := map[string]int{}
+    for _, d := range diags {
+        if string(d.Level) != "error" || d.Code == "E_TOOLCHAIN_MISSING" { continue }
+        // the LLVM backend rejects the slice<Line> decode until the container ABI exists
+        if d.Code == "E_LLVM_EMIT" && strings.Contains(d.Message, "ami_rt_json_decode_ptr") && strings.Contains(d.Message, "container ABI") { continue }
+        counts[d.Code]++
+    }
+    if counts["E_JSON_DECODE_TARGET"] != 2 || counts["E_JSON_DECODE_TYPE"] != 1 || len(counts) != 2 { t.Fatalf("diagnostics: %v", counts) }
+}
@@ -0,0 +1,52 @@
+package driver
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// The on-disk strings package compiles and resolves from an app using interpolation.
+func TestStdlib_Strings_FS_Compiles(t *testing.T) {
+    var pkgs []Package
+    for _, p := range fsStdlibPackages(filepath.Join("..", "..", "..", "..", "std", "ami", "stdlib")) {
+        if p.Name == "strings" || p.Name == "errors" { pkgs = append(pkgs, p) }
+    }
+    if len(pkgs) != 2 { t.Fatalf("stdlib packages not found: %d", len(pkgs)) }
+    appfs := &source.FileSet{}
+    appfs.AddFile("app.ami", "package app\nimport strings\n"+
+        "func F(line string) (string, error) {\n"+
+        "  var parts = strings.Split(strings.TrimSpace(line), \",\")\n"+
+        "  var n = strings.ParseInt(strings.ToLower(line))?\n"+
+        "  var ok = strings.Contains(line, \"x\") && strings.HasPrefix(line, \"a\")\n"+
+        "  return \"${strings.Join(parts, \";\")} n=${n} ok=${ok} at=${strings.Index(line, \"x\")}\", nil\n"+
+        "}\n")
+    pkgs = append(pkgs, Package{Name: "app", Files: appfs})
+    _, diags := Compile(workspace.Workspace{}, pkgs, Options{EmitLLVMOnly: true})
+    for _, d := range diags {
+        // object emission needs clang, and the LLVM backend rejects Split and Join;
+        // only front-end diagnostics matter here
+        if d.Code == "E_LLVM_EMIT" && strings.Contains(d.Message, "slice container ABI") { continue }
+        if string(d.Level) == "error" && d.Code != "E_TOOLCHAIN_MISSING" { t.Fatalf("unexpected error diagnostic: %+v", d) }
+    }
+}
+
+// Interpolation lowers to string runtime helpers the LLVM backend emits.
+func TestStdlib_Strings_InterpolationEmitsLLVM(t *testing.T) {
+    fs := &source.FileSet{}
+    fs.AddFile("strings_interp.ami", "package app\nfunc F(n int) (string) {\n  return \"n=${n}\"\n}\n")
+    _, diags := Compile(workspace.Workspace{}, []Package{{Name: "app", Files: fs}}, Options{Debug: true, EmitLLVMOnly: true})
+    for _, d := range diags {
+        if d.Code == "E_LLVM_EMIT" { t.Fatalf("unexpected E_LLVM_EMIT: %+v", d) }
+    }
+    b, err := os.ReadFile(filepath.Join("build", "debug", "llvm", "app", "strings_interp.ll"))
+    if err != nil { t.Fatalf("read llvm: %v", err) }
+    s := string(b)
+    for _, want := range []string{"@.str.F.", "c\"n=\\00\"", "call ptr @ami_rt_int_to_string(i64 ", "call ptr @ami_rt_string_concat(ptr ", "declare ptr @ami_rt_string_concat(ptr, ptr)"} {
+        if !strings.Contains(s, want) { t.Fatalf("missing %q in:\n%s", want, s) }
+    }
+}
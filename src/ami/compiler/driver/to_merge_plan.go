@@ -1,6 +1,7 @@
 package driver
 
 import (
+    "strconv"
     "strings"
     "github.com/sam-caldwell/ami/src/ami/compiler/ast"
     "github.com/sam-caldwell/ami/src/ami/compiler/ir"
@@ -33,6 +34,19 @@ func toMergePlan(st *ast.StepStmt) *ir.MergePlan {
         case "merge.Dedup":
             if len(at.Args) >= 1 { mp.DedupField = trimQuotes(at.Args[0].Text) } else { mp.DedupField = "" }
             saw = true
+        case "merge.DedupTTL":
+            if len(at.Args) >= 1 {
+                if ms, ok := parseDurationMs(at.Args[0].Text); ok && ms > 0 { mp.DedupTTLMs = ms; saw = true }
+            }
+        case "merge.DedupLimit":
+            if len(at.Args) >= 1 {
+                if n, ok := atoiSafe(at.Args[0].Text); ok && n > 0 { mp.DedupMaxKeys = n; saw = true }
+            }
+        case "merge.DedupBloom":
+            mp.DedupMode = "bloom"; saw = true
+            if len(at.Args) >= 1 {
+                if f, err := strconv.ParseFloat(trimQuotes(strings.TrimSpace(at.Args[0].Text)), 64); err == nil && f > 0 && f < 1 { mp.DedupFPRate = f }
+            }
         case "merge.Window":
             if len(at.Args) >= 1 {
                 if n, ok := atoiSafe(at.Args[0].Text); ok && n > 0 { mp.Window = n; saw = true }
@@ -48,6 +62,27 @@ func toMergePlan(st *ast.StepStmt) *ir.MergePlan {
                 if len(at.Args) >= 2 { if ms, ok := parseDurationMs(at.Args[1].Text); ok { wm.LatenessMs = ms } }
                 mp.Watermark = wm; saw = true
             }
+        case "merge.Tumbling":
+            if len(at.Args) >= 1 {
+                if ms, ok := parseDurationMs(at.Args[0].Text); ok && ms > 0 { mp.EventWindow = &ir.EventWindow{Kind: "tumbling", SizeMs: ms}; saw = true }
+            }
+        case "merge.Sliding":
+            if len(at.Args) >= 2 {
+                size, ok1 := parseDurationMs(at.Args[0].Text)
+                slide, ok2 := parseDurationMs(at.Args[1].Text)
+                if ok1 && ok2 && size > 0 && slide > 0 { mp.EventWindow = &ir.EventWindow{Kind: "sliding", SizeMs: size, SlideMs: slide}; saw = true }
+            }
+        case "merge.Session":
+            if len(at.Args) >= 1 {
+                if ms, ok := parseDurationMs(at.Args[0].Text); ok && ms > 0 { mp.EventWindow = &ir.EventWindow{Kind: "session", GapMs: ms}; saw = true }
+            }
+        case "merge.Aggregate":
+            if len(at.Args) >= 1 {
+                ag := ir.Aggregate{Fn: strings.ToLower(trimQuotes(at.Args[0].Text))}
+                if len(at.Args) >= 2 { ag.Field = trimQuotes(at.Args[1].Text) }
+                if len(at.Args) >= 3 { ag.As = trimQuotes(at.Args[2].Text) }
+                mp.Aggregates = append(mp.Aggregates, ag); saw = true
+            }
         case "merge.Buffer":
             if len(at.Args) >= 1 { if n, ok := atoiSafe(at.Args[0].Text); ok { mp.Buffer.Capacity = n } }
             if len(at.Args) >= 2 { pol := strings.ToLower(trimQuotes(at.Args[1].Text)); mp.Buffer.Policy = pol }
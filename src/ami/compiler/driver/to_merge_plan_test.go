@@ -1,5 +1,39 @@
 package driver
 
-import "testing"
+import (
+    "testing"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
 
 func Test_to_merge_plan_Exists(t *testing.T) {}
+
+func TestToMergePlan_EventWindowAndAggregates(t *testing.T) {
+    code := "package app\npipeline P(){ ingress; Collect merge.Watermark(\"ts\", \"1s\"), merge.Sliding(\"10s\", \"5s\"), merge.Aggregate(\"count\"), merge.Aggregate(\"avg\", \"v\", \"mean\"); egress }\n"
+    pls := lowerPipelines(mustParse(t, &source.File{Name: "u.ami", Content: code}))
+    if len(pls) != 1 || len(pls[0].Collect) != 1 || pls[0].Collect[0].Merge == nil { t.Fatalf("unexpected pipelines: %+v", pls) }
+    mp := pls[0].Collect[0].Merge
+    if mp.EventWindow == nil || mp.EventWindow.Kind != "sliding" || mp.EventWindow.SizeMs != 10000 || mp.EventWindow.SlideMs != 5000 {
+        t.Fatalf("event window: %+v", mp.EventWindow)
+    }
+    if len(mp.Aggregates) != 2 || mp.Aggregates[0].Fn != "count" || mp.Aggregates[1].Field != "v" || mp.Aggregates[1].As != "mean" {
+        t.Fatalf("aggregates: %+v", mp.Aggregates)
+    }
+}
+
+func TestToMergePlan_SessionAndTumbling(t *testing.T) {
+    code := "package app\npipeline P(){ ingress; Collect merge.Session(\"30s\"); Collect merge.Tumbling(250); egress }\n"
+    pls := lowerPipelines(mustParse(t, &source.File{Name: "u.ami", Content: code}))
+    c := pls[0].Collect
+    if len(c) != 2 { t.Fatalf("collect: %+v", c) }
+    if w := c[0].Merge.EventWindow; w == nil || w.Kind != "session" || w.GapMs != 30000 { t.Fatalf("session: %+v", w) }
+    if w := c[1].Merge.EventWindow; w == nil || w.Kind != "tumbling" || w.SizeMs != 250 { t.Fatalf("tumbling: %+v", w) }
+}
+
+func TestToMergePlan_DedupHorizon(t *testing.T) {
+    code := "package app\npipeline P(){ ingress; Collect merge.Dedup(\"id\"), merge.DedupTTL(\"5m\"), merge.DedupLimit(5000), merge.DedupBloom(0.001); egress }\n"
+    pls := lowerPipelines(mustParse(t, &source.File{Name: "u.ami", Content: code}))
+    mp := pls[0].Collect[0].Merge
+    if mp.DedupField != "id" || mp.DedupTTLMs != 300000 || mp.DedupMaxKeys != 5000 || mp.DedupMode != "bloom" || mp.DedupFPRate != 0.001 {
+        t.Fatalf("dedup plan: %+v", mp)
+    }
+}
@@ -0,0 +1,57 @@
+package driver
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// Declared struct types resolve across packages, carry into ir.types.index.json,
+// and lower field access against their structural layout.
+func TestCompile_TypeDecls_CrossPackage_IndexAndFieldAccess(t *testing.T) {
+    ws := workspace.DefaultWorkspace()
+    libfs := &source.FileSet{}
+    libfs.AddFile("money.ami", "package lib\ntype Money struct { amount int64; currency string }\ntype cents = int64\n")
+    appfs := &source.FileSet{}
+    appfs.AddFile("order.ami", "package app\nimport l \"lib\"\n"+
+        "type Order struct {\n  id string\n  total l.Money\n  raw l.cents\n}\n"+
+        "type Orders = slice<Order>\n"+
+        "func F(o Order) (int64) { return o.total.amount }\n"+
+        "func W(ev Event<Order>) (Event<Order>) { return ev }\n"+
+        "pipeline P() { ingress; W type(\"Event<Order>\"); Collect merge.Sort(\"total.amount\"); egress; ingress -> W; W -> Collect; Collect -> egress; }\n")
+    pkgs := []Package{{Name: "lib", Files: libfs}, {Name: "app", Files: appfs}}
+    _, diags := Compile(ws, pkgs, Options{Debug: true})
+    notExported := 0
+    for _, d := range diags {
+        switch {
+        case d.Code == "E_TYPE_NOT_EXPORTED":
+            notExported++
+        case strings.HasPrefix(d.Code, "E_TYPE_"), strings.HasPrefix(d.Code, "E_MERGE_"), d.Code == "W_MERGE_FIELD_UNVERIFIED":
+            t.Fatalf("unexpected diag: %+v", d)
+        }
+    }
+    if notExported != 1 { t.Fatalf("want one E_TYPE_NOT_EXPORTED for l.cents; diags=%+v", diags) }
+
+    b, err := os.ReadFile(filepath.Join("build", "debug", "ir", "app", "ir.types.index.json"))
+    if err != nil { t.Fatalf("types index: %v", err) }
+    var idx irTypesIndex
+    if err := json.Unmarshal(b, &idx); err != nil { t.Fatalf("json: %v", err) }
+    var decls []irTypeDecl
+    for _, u := range idx.Units { decls = append(decls, u.Decls...) }
+    if len(decls) != 2 || decls[0].Name != "app.Order" || decls[0].Kind != "struct" || !decls[0].Exported || len(decls[0].Fields) != 3 {
+        t.Fatalf("decls: %+v", decls)
+    }
+    if !strings.Contains(decls[0].Resolved, "total:Struct{amount:int64,currency:string}") { t.Fatalf("resolved: %s", decls[0].Resolved) }
+    if decls[1].Kind != "alias" || decls[1].Type != "slice<Order>" { t.Fatalf("alias decl: %+v", decls[1]) }
+
+    ir, err := os.ReadFile(filepath.Join("build", "debug", "ir", "app", "order.ir.json"))
+    if err != nil { t.Fatalf("ir: %v", err) }
+    if !strings.Contains(string(ir), "field.total.amount") || !strings.Contains(string(ir), "Struct{id:string,raw:") {
+        t.Fatalf("field access not lowered against resolved layout:\n%s", ir)
+    }
+}
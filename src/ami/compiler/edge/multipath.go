@@ -17,11 +17,18 @@ var allowedMerge = map[string]struct{}{
     "merge.Stable": {},
     "merge.Key": {},
     "merge.Dedup": {},
+    "merge.DedupTTL": {},
+    "merge.DedupLimit": {},
+    "merge.DedupBloom": {},
     "merge.Window": {},
     "merge.Watermark": {},
     "merge.Timeout": {},
     "merge.Buffer": {},
     "merge.PartitionBy": {},
+    "merge.Tumbling": {},
+    "merge.Sliding": {},
+    "merge.Session": {},
+    "merge.Aggregate": {},
 }
 
 func (m MultiPath) Validate() error {
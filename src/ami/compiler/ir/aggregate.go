@@ -0,0 +1,9 @@
+package ir
+
+// Aggregate is one per-window output of an event-time window: Fn
+// (count|sum|min|max|avg|first|last) over Field, emitted as As.
+type Aggregate struct {
+    Fn    string `json:"fn"`
+    Field string `json:"field,omitempty"`
+    As    string `json:"as,omitempty"`
+}
@@ -1,7 +1,10 @@
 package ir
 
+// CollectSpec binds a Collect step occurrence to its merge plan.
+// ID is the 1-based occurrence of Step within the pipeline and matches the
+// fromId/toId emitted in edges.json; zero means unknown.
 type CollectSpec struct {
     Step  string     `json:"step"`
+    ID    int        `json:"id,omitempty"`
     Merge *MergePlan `json:"merge,omitempty"`
 }
-
@@ -37,6 +37,7 @@ func EncodeModule(m Module) ([]byte, error) {
                 cols := make([]any, 0, len(p.Collect))
                 for _, c := range p.Collect {
                     cj := map[string]any{"step": c.Step}
+                    if c.ID > 0 { cj["id"] = c.ID }
                     if c.Merge != nil {
                         mj := map[string]any{}
                         if c.Merge.Stable { mj["stable"] = true }
@@ -53,8 +54,29 @@ func EncodeModule(m Module) ([]byte, error) {
                         if c.Merge.Window > 0 { mj["window"] = c.Merge.Window }
                         if c.Merge.TimeoutMs > 0 { mj["timeoutMs"] = c.Merge.TimeoutMs }
                         if c.Merge.DedupField != "" { mj["dedupField"] = c.Merge.DedupField }
+                        if c.Merge.DedupTTLMs > 0 { mj["dedupTTLMs"] = c.Merge.DedupTTLMs }
+                        if c.Merge.DedupMaxKeys > 0 { mj["dedupMaxKeys"] = c.Merge.DedupMaxKeys }
+                        if c.Merge.DedupMode != "" { mj["dedupMode"] = c.Merge.DedupMode }
+                        if c.Merge.DedupFPRate > 0 { mj["dedupFPRate"] = c.Merge.DedupFPRate }
                         if c.Merge.Watermark != nil { mj["watermark"] = map[string]any{"field": c.Merge.Watermark.Field, "latenessMs": c.Merge.Watermark.LatenessMs} }
                         if c.Merge.LatePolicy != "" { mj["latePolicy"] = c.Merge.LatePolicy }
+                        if w := c.Merge.EventWindow; w != nil {
+                            wj := map[string]any{"kind": w.Kind}
+                            if w.SizeMs > 0 { wj["sizeMs"] = w.SizeMs }
+                            if w.SlideMs > 0 { wj["slideMs"] = w.SlideMs }
+                            if w.GapMs > 0 { wj["gapMs"] = w.GapMs }
+                            mj["eventWindow"] = wj
+                        }
+                        if len(c.Merge.Aggregates) > 0 {
+                            aj := make([]any, 0, len(c.Merge.Aggregates))
+                            for _, a := range c.Merge.Aggregates {
+                                m := map[string]any{"fn": a.Fn}
+                                if a.Field != "" { m["field"] = a.Field }
+                                if a.As != "" { m["as"] = a.As }
+                                aj = append(aj, m)
+                            }
+                            mj["aggregates"] = aj
+                        }
                         cj["merge"] = mj
                     }
                     cols = append(cols, cj)
@@ -0,0 +1,10 @@
+package ir
+
+// EventWindow configures event-time windowing over MergePlan.Watermark.Field.
+// Kind is tumbling (SizeMs), sliding (SizeMs every SlideMs) or session (GapMs).
+type EventWindow struct {
+    Kind    string `json:"kind"`
+    SizeMs  int    `json:"sizeMs,omitempty"`
+    SlideMs int    `json:"slideMs,omitempty"`
+    GapMs   int    `json:"gapMs,omitempty"`
+}
@@ -10,7 +10,13 @@ type MergePlan struct {
     Window      int         `json:"window,omitempty"`
     TimeoutMs   int         `json:"timeoutMs,omitempty"`
     DedupField  string      `json:"dedupField,omitempty"`
+    DedupTTLMs  int         `json:"dedupTTLMs,omitempty"`
+    DedupMaxKeys int        `json:"dedupMaxKeys,omitempty"`
+    DedupMode   string      `json:"dedupMode,omitempty"`
+    DedupFPRate float64     `json:"dedupFPRate,omitempty"`
     Watermark   *Watermark  `json:"watermark,omitempty"`
     LatePolicy  string      `json:"latePolicy,omitempty"`
+    EventWindow *EventWindow `json:"eventWindow,omitempty"`
+    Aggregates  []Aggregate `json:"aggregates,omitempty"`
 }
 
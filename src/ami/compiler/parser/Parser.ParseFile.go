@@ -9,7 +9,7 @@ import (
 )
 
 // ParseFile parses a single file, recognizing only a package declaration
-// followed by imports and top-level declarations (func, pipeline, enum, error).
+// followed by imports and top-level declarations (func, pipeline, enum, type, error).
 func (p *Parser) ParseFile() (*ast.File, error) {
     if p == nil {
         return nil, fmt.Errorf("nil parser")
@@ -137,7 +137,7 @@ func (p *Parser) ParseFile() (*ast.File, error) {
         f.Decls = append(f.Decls, im)
     }
 
-    // Top-level declarations in any order: decorators+func, pipeline, enum, error.
+    // Top-level declarations in any order: decorators+func, pipeline, enum, type, error.
     for p.cur.Kind != token.EOF {
         switch p.cur.Kind {
         case token.AtSym, token.KwFunc:
@@ -181,6 +181,14 @@ func (p *Parser) ParseFile() (*ast.File, error) {
             } else {
                 f.Decls = append(f.Decls, ed)
             }
+        case token.KwType:
+            td, err := p.parseTypeDecl()
+            if err != nil {
+                p.errf("%v", err)
+                p.syncTop()
+            } else {
+                f.Decls = append(f.Decls, td)
+            }
         case token.KwError:
             eb, err := p.parseErrorBlock()
             if err != nil {
@@ -189,6 +197,11 @@ func (p *Parser) ParseFile() (*ast.File, error) {
             } else {
                 f.Decls = append(f.Decls, eb)
             }
+        case token.PoundSym:
+            // Directive lines (#pragma ...) are collected from raw content below;
+            // skip the whole line so keywords in their arguments are not parsed.
+            line := p.cur.Pos.Line
+            for p.cur.Kind != token.EOF && p.cur.Pos.Line == line { p.next() }
         default:
             // Skip unknown/irrelevant tokens at top level
             p.next()
@@ -57,6 +57,17 @@ func (p *Parser) parseExprPrec(minPrec int) (ast.Expr, bool) {
         p.next()
         left := p.parseIdentExpr(name, npos)
         return p.parseWithTernary(left, minPrec), true
+    case token.KwNil:
+        pos := p.cur.Pos
+        p.next()
+        return p.parseWithTernary(&ast.IdentExpr{Pos: pos, Name: "nil"}, minPrec), true
+    case token.KwMatch:
+        m, err := p.parseMatchExpr()
+        if err != nil {
+            p.errf("%v", err)
+            return nil, false
+        }
+        return m, true
     case token.KwFunc:
         // Tolerate function literal expressions in attribute values: func(...) [results] { ... }
         pos := p.cur.Pos
@@ -135,7 +146,7 @@ func (p *Parser) parseExprPrec(minPrec int) (ast.Expr, bool) {
             v = v[1 : len(v)-1]
         }
         p.next()
-        left := ast.Expr(&ast.StringLit{Pos: pos, Value: v})
+        left := p.parseInterpString(pos, v)
         return p.parseWithTernary(left, minPrec), true
     case token.Number:
         t := p.cur.Lexeme
@@ -0,0 +1,105 @@
+package parser
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// parseForStmt parses a loop starting at the 'for' keyword. Supported forms:
+//   for { ... }
+//   for <cond> { ... }            (parentheses around cond are optional)
+//   for k[, v] := range <expr> { ... }
+// Leading comments collected before the keyword attach to the statement.
+func (p *Parser) parseForStmt() (ast.Stmt, bool) {
+    leading := p.pending
+    p.pending = nil
+    fpos := p.cur.Pos
+    p.next()
+    var cond ast.Expr
+    switch p.cur.Kind {
+    case token.LBraceSym:
+        // infinite loop
+    case token.Ident:
+        name := p.cur.Lexeme
+        npos := p.cur.Pos
+        p.next()
+        if p.cur.Kind == token.CommaSym || p.cur.Kind == token.ColonSym {
+            return p.parseRangeClause(leading, fpos, name, npos)
+        }
+        left := p.parseIdentExpr(name, npos)
+        cond = p.parseWithTernary(left, 1)
+    default:
+        e, ok := p.parseExprPrec(1)
+        if !ok {
+            p.errf("expected loop condition or '{' after for, got %q", p.cur.Lexeme)
+            p.syncUntil(token.LBraceSym, token.SemiSym, token.RBraceSym)
+        } else {
+            cond = e
+        }
+    }
+    if p.cur.Kind != token.LBraceSym {
+        p.errf("expected '{' to start for-block, got %q", p.cur.Lexeme)
+        p.syncUntil(token.RBraceSym, token.SemiSym)
+        if p.cur.Kind == token.SemiSym { p.next() }
+        return nil, false
+    }
+    body, err := p.parseFuncBlock()
+    if err != nil {
+        p.errf("%v", err)
+        p.syncUntil(token.SemiSym, token.RBraceSym)
+        if p.cur.Kind == token.SemiSym { p.next() }
+        return nil, false
+    }
+    if p.cur.Kind == token.SemiSym { p.next() }
+    return &ast.ForStmt{Pos: fpos, Leading: leading, Cond: cond, Body: body}, true
+}
+
+// parseRangeClause parses the remainder of `for k[, v] := range <expr> { ... }`
+// after the first loop variable has been consumed.
+func (p *Parser) parseRangeClause(leading []ast.Comment, fpos source.Position, key string, keyPos source.Position) (ast.Stmt, bool) {
+    rs := &ast.RangeStmt{Pos: fpos, Leading: leading, Key: key, KeyPos: keyPos}
+    fail := func(format string, args ...any) (ast.Stmt, bool) {
+        p.errf(format, args...)
+        p.syncUntil(token.RBraceSym, token.SemiSym)
+        if p.cur.Kind == token.SemiSym { p.next() }
+        return nil, false
+    }
+    if p.cur.Kind == token.CommaSym {
+        p.next()
+        if p.cur.Kind != token.Ident {
+            return fail("expected second loop variable, got %q", p.cur.Lexeme)
+        }
+        rs.Value = p.cur.Lexeme
+        rs.ValuePos = p.cur.Pos
+        p.next()
+    }
+    // ':=' scans as ':' followed by '='
+    if p.cur.Kind != token.ColonSym {
+        return fail("expected ':=' in range clause, got %q", p.cur.Lexeme)
+    }
+    p.next()
+    if p.cur.Kind != token.Assign {
+        return fail("expected ':=' in range clause, got %q", p.cur.Lexeme)
+    }
+    p.next()
+    if p.cur.Kind != token.KwRange {
+        return fail("expected 'range' after ':=', got %q", p.cur.Lexeme)
+    }
+    p.next()
+    x, ok := p.parseExprPrec(1)
+    if !ok {
+        return fail("expected expression after range, got %q", p.cur.Lexeme)
+    }
+    rs.X = x
+    if p.cur.Kind != token.LBraceSym {
+        return fail("expected '{' to start for-block, got %q", p.cur.Lexeme)
+    }
+    body, err := p.parseFuncBlock()
+    if err != nil {
+        return fail("%v", err)
+    }
+    rs.Body = body
+    if p.cur.Kind == token.SemiSym { p.next() }
+    return rs, true
+}
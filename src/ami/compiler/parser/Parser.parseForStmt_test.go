@@ -0,0 +1,53 @@
+package parser
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func parseFuncBody(t *testing.T, body string) []ast.Stmt {
+    t.Helper()
+    src := "package app\nfunc F(xs slice<int>, m map<string,int>){\n" + body + "\n}\n"
+    f := (&source.FileSet{}).AddFile("f.ami", src)
+    file, err := New(f).ParseFile()
+    if err != nil { t.Fatalf("ParseFile: %v", err) }
+    fn, ok := file.Decls[0].(*ast.FuncDecl)
+    if !ok || fn.Body == nil { t.Fatalf("no func body: %#v", file.Decls[0]) }
+    return fn.Body.Stmts
+}
+
+func TestParseForStmt_Forms(t *testing.T) {
+    stmts := parseFuncBody(t, "for { break }\nfor i < 10 { continue }\nfor (ok) { }\nfor i, x := range xs { }\nfor k := range m { }")
+    if len(stmts) != 5 { t.Fatalf("want 5 stmts, got %d", len(stmts)) }
+    inf, ok := stmts[0].(*ast.ForStmt)
+    if !ok || inf.Cond != nil || len(inf.Body.Stmts) != 1 { t.Fatalf("infinite loop: %#v", stmts[0]) }
+    if _, ok := inf.Body.Stmts[0].(*ast.BreakStmt); !ok { t.Fatalf("want break, got %#v", inf.Body.Stmts[0]) }
+    cl, ok := stmts[1].(*ast.ForStmt)
+    if !ok { t.Fatalf("cond loop: %#v", stmts[1]) }
+    if _, ok := cl.Cond.(*ast.BinaryExpr); !ok { t.Fatalf("cond not binary: %#v", cl.Cond) }
+    if _, ok := cl.Body.Stmts[0].(*ast.ContinueStmt); !ok { t.Fatalf("want continue") }
+    if pl, ok := stmts[2].(*ast.ForStmt); !ok || pl.Cond == nil { t.Fatalf("paren cond loop: %#v", stmts[2]) }
+    r, ok := stmts[3].(*ast.RangeStmt)
+    if !ok || r.Key != "i" || r.Value != "x" { t.Fatalf("range: %#v", stmts[3]) }
+    if id, ok := r.X.(*ast.IdentExpr); !ok || id.Name != "xs" { t.Fatalf("range operand: %#v", r.X) }
+    r2, ok := stmts[4].(*ast.RangeStmt)
+    if !ok || r2.Key != "k" || r2.Value != "" { t.Fatalf("single-var range: %#v", stmts[4]) }
+}
+
+func TestParseForStmt_Errors(t *testing.T) {
+    cases := []string{
+        "for k, := range xs { }",
+        "for k = range xs { }",
+        "for k := xs { }",
+        "for i < 3 return",
+    }
+    for _, body := range cases {
+        src := "package app\nfunc F(xs slice<int>){\n" + body + "\n}\n"
+        f := (&source.FileSet{}).AddFile("f.ami", src)
+        if _, err := New(f).ParseFile(); err == nil {
+            t.Fatalf("expected error for %q", body)
+        }
+    }
+}
@@ -132,6 +132,40 @@ func (p *Parser) parseFuncBlock() (*ast.BlockStmt, error) {
             if p.cur.Kind == token.SemiSym {
                 p.next()
             }
+        case token.KwFor:
+            if st, ok := p.parseForStmt(); ok {
+                stmts = append(stmts, st)
+            }
+        case token.KwMatch:
+            leading := p.pending
+            p.pending = nil
+            m, err := p.parseMatchExpr()
+            if err != nil {
+                p.errf("%v", err)
+                p.syncUntil(token.SemiSym, token.RBraceSym)
+                if p.cur.Kind == token.SemiSym {
+                    p.next()
+                }
+                continue
+            }
+            stmts = append(stmts, &ast.ExprStmt{Pos: m.Pos, X: m, Leading: leading})
+            if p.cur.Kind == token.SemiSym {
+                p.next()
+            }
+        case token.KwBreak, token.KwContinue:
+            leading := p.pending
+            p.pending = nil
+            pos := p.cur.Pos
+            kind := p.cur.Kind
+            p.next()
+            if kind == token.KwBreak {
+                stmts = append(stmts, &ast.BreakStmt{Pos: pos, Leading: leading})
+            } else {
+                stmts = append(stmts, &ast.ContinueStmt{Pos: pos, Leading: leading})
+            }
+            if p.cur.Kind == token.SemiSym {
+                p.next()
+            }
         case token.KwReturn:
             pos := p.cur.Pos
             p.next()
@@ -2,6 +2,7 @@ package parser
 
 import (
     "fmt"
+    "strings"
 
     "github.com/sam-caldwell/ami/src/ami/compiler/ast"
     "github.com/sam-caldwell/ami/src/ami/compiler/source"
@@ -38,7 +39,7 @@ func (p *Parser) parseFuncDecl() (*ast.FuncDecl, error) {
         }
         p.next()
     }
-    if p.cur.Kind != token.Ident {
+    if p.cur.Kind != token.Ident && !isExportedKeywordName(p.cur.Lexeme) {
         return nil, fmt.Errorf("expected function name, got %q", p.cur.Lexeme)
     }
     name := p.cur.Lexeme
@@ -100,3 +101,13 @@ func (p *Parser) parseFuncDecl() (*ast.FuncDecl, error) {
     p.pendingDecos = nil
     return fn, nil
 }
+
+// isExportedKeywordName reports whether lex is a capitalized spelling of a keyword
+// (e.g. errors.New), which is accepted as an exported function name.
+func isExportedKeywordName(lex string) bool {
+    if lex == "" || lex[0] < 'A' || lex[0] > 'Z' {
+        return false
+    }
+    _, kw := token.LookupKeyword(strings.ToLower(lex))
+    return kw
+}
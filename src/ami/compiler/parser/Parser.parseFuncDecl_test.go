@@ -1,5 +1,21 @@
 package parser
 
-import "testing"
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
 
 func Test_Parser_parseFuncDecl_Exists(t *testing.T) {}
+
+func TestParser_FuncDecl_KeywordSpelledName(t *testing.T) {
+    f := &source.File{Name: "t.ami", Content: "package errors\nfunc New(m string) (error) { return nil }\n"}
+    file, err := New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    fn, ok := file.Decls[0].(*ast.FuncDecl)
+    if !ok || fn.Name != "New" { t.Fatalf("decl: %#v", file.Decls[0]) }
+    // lowercase keywords remain reserved
+    f = &source.File{Name: "u.ami", Content: "package app\nfunc new() {}\n"}
+    if _, err := New(f).ParseFile(); err == nil { t.Fatalf("expected error for reserved name") }
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+    "strings"
+
     "github.com/sam-caldwell/ami/src/ami/compiler/ast"
     "github.com/sam-caldwell/ami/src/ami/compiler/source"
     "github.com/sam-caldwell/ami/src/ami/compiler/token"
@@ -15,7 +17,8 @@ func (p *Parser) parseIdentExpr(first string, firstPos source.Position) ast.Expr
     poses := []source.Position{firstPos}
     for p.cur.Kind == token.DotSym {
         p.next()
-        if p.cur.Kind != token.Ident {
+        // keywords are plain names after '.', e.g. errors.New
+        if _, kw := token.LookupKeyword(strings.ToLower(p.cur.Lexeme)); p.cur.Kind != token.Ident && !kw {
             p.errf("expected ident after '.', got %q", p.cur.Lexeme)
             break
         }
@@ -0,0 +1,101 @@
+package parser
+
+import (
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// parseInterpString builds the literal for a string body v (quotes stripped)
+// whose opening quote is at pos. Bodies containing `${expr}` segments yield an
+// InterpStringLit; others yield a StringLit. `\$` escapes a literal dollar.
+// Embedded expressions are parsed against a blank-padded copy of the file so
+// their positions match the source.
+func (p *Parser) parseInterpString(pos source.Position, v string) ast.Expr {
+    if !strings.Contains(v, "${") {
+        return &ast.StringLit{Pos: pos, Value: strings.ReplaceAll(v, `\$`, "$")}
+    }
+    src := p.s.FileContent()
+    base := pos.Offset + 1
+    padded := func(start int) *source.File {
+        off := base + start
+        if off > len(src) { off = len(src) }
+        var b strings.Builder
+        for i := 0; i < off; i++ {
+            if src[i] == '\n' { b.WriteByte('\n') } else { b.WriteByte(' ') }
+        }
+        return &source.File{Content: b.String()}
+    }
+    lit := &ast.InterpStringLit{Pos: pos}
+    var part strings.Builder
+    for i := 0; i < len(v); i++ {
+        switch {
+        case v[i] == '\\' && i+1 < len(v) && v[i+1] == '$':
+            part.WriteByte('$')
+            i++
+        case v[i] == '\\' && i+1 < len(v):
+            part.WriteString(v[i : i+2])
+            i++
+        case v[i] == '$' && i+1 < len(v) && v[i+1] == '{':
+            start := i + 2
+            end := interpExprEnd(v, start)
+            if end < 0 {
+                pf := padded(i)
+                p.errors = append(p.errors, SyntaxError{Msg: "unterminated interpolation in string literal", Pos: pf.Pos(len(pf.Content))})
+                part.WriteString(v[i:])
+                i = len(v)
+                continue
+            }
+            text := v[start:end]
+            pf := padded(start)
+            if strings.TrimSpace(text) == "" {
+                p.errors = append(p.errors, SyntaxError{Msg: "empty interpolation in string literal", Pos: pf.Pos(len(pf.Content))})
+            } else {
+                pf.Content += text
+                sub := New(pf)
+                x, ok := sub.parseExpr()
+                p.errors = append(p.errors, sub.errors...)
+                if !ok || sub.cur.Kind != token.EOF {
+                    p.errors = append(p.errors, SyntaxError{Msg: "invalid expression in string interpolation: " + strings.TrimSpace(text), Pos: sub.cur.Pos})
+                } else {
+                    lit.Parts = append(lit.Parts, part.String())
+                    lit.Exprs = append(lit.Exprs, x)
+                    part.Reset()
+                }
+            }
+            i = end
+        default:
+            part.WriteByte(v[i])
+        }
+    }
+    lit.Parts = append(lit.Parts, part.String())
+    if len(lit.Exprs) == 0 { return &ast.StringLit{Pos: pos, Value: lit.Parts[0]} }
+    return lit
+}
+
+// interpExprEnd returns the index of the '}' closing the interpolation whose
+// expression starts at v[start], skipping nested string literals; -1 if none.
+func interpExprEnd(v string, start int) int {
+    depth := 1
+    for i := start; i < len(v); i++ {
+        switch v[i] {
+        case '{':
+            depth++
+        case '}':
+            depth--
+            if depth == 0 { return i }
+        case '"':
+            // nested string literal: skip escapes and its own interpolations
+            for i++; i < len(v) && v[i] != '"'; i++ {
+                if v[i] == '\\' { i++; continue }
+                if v[i] == '$' && i+1 < len(v) && v[i+1] == '{' {
+                    if i = interpExprEnd(v, i+2); i < 0 { return -1 }
+                }
+            }
+            if i >= len(v) { return -1 }
+        }
+    }
+    return -1
+}
@@ -0,0 +1,39 @@
+package parser
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func TestParser_InterpString_Forms(t *testing.T) {
+    st := parseFuncBody(t, `var a = "n=${n}, sum=${x + y}!"`+"\n"+`var b = "${f(n)}"`+"\n"+`var c = "cost \${n}"`)
+    a, ok := st[0].(*ast.VarDecl).Init.(*ast.InterpStringLit)
+    if !ok { t.Fatalf("a: %#v", st[0].(*ast.VarDecl).Init) }
+    if len(a.Parts) != 3 || a.Parts[0] != "n=" || a.Parts[1] != ", sum=" || a.Parts[2] != "!" { t.Fatalf("parts: %q", a.Parts) }
+    if id, ok := a.Exprs[0].(*ast.IdentExpr); !ok || id.Name != "n" { t.Fatalf("expr0: %#v", a.Exprs[0]) }
+    if _, ok := a.Exprs[1].(*ast.BinaryExpr); !ok { t.Fatalf("expr1: %#v", a.Exprs[1]) }
+    // embedded expressions keep their source positions
+    prefix := len("package app\nfunc F(xs slice<int>, m map<string,int>){\n")
+    if p := a.Exprs[0].(*ast.IdentExpr).Pos; p.Line != 3 || p.Offset != prefix+len(`var a = "n=${`) { t.Fatalf("pos: %+v", p) }
+    b := st[1].(*ast.VarDecl).Init.(*ast.InterpStringLit)
+    if len(b.Parts) != 2 || b.Parts[0] != "" || b.Parts[1] != "" { t.Fatalf("b parts: %q", b.Parts) }
+    if c, ok := st[2].(*ast.VarDecl).Init.(*ast.StringLit); !ok || c.Value != "cost ${n}" { t.Fatalf("escaped: %#v", st[2].(*ast.VarDecl).Init) }
+}
+
+func TestParser_InterpString_NestedStrings(t *testing.T) {
+    st := parseFuncBody(t, `var a = "[${join(xs, "}, ")}] ${f("n=${n}")}"`)
+    a, ok := st[0].(*ast.VarDecl).Init.(*ast.InterpStringLit)
+    if !ok || len(a.Exprs) != 2 || a.Parts[1] != "] " { t.Fatalf("a: %#v", st[0].(*ast.VarDecl).Init) }
+    if sep, ok := a.Exprs[0].(*ast.CallExpr).Args[1].(*ast.StringLit); !ok || sep.Value != "}, " { t.Fatalf("nested string: %#v", a.Exprs[0]) }
+    if inner, ok := a.Exprs[1].(*ast.CallExpr).Args[0].(*ast.InterpStringLit); !ok || inner.Parts[0] != "n=" { t.Fatalf("nested interpolation: %#v", a.Exprs[1]) }
+}
+
+func TestParser_InterpString_Errors(t *testing.T) {
+    for _, body := range []string{`var a = "x ${}"`, `var a = "x ${n"`, `var a = "x ${n n}"`} {
+        src := "package app\nfunc F(){\n" + body + "\n}\n"
+        f := (&source.FileSet{}).AddFile("f.ami", src)
+        if _, err := New(f).ParseFile(); err == nil { t.Fatalf("expected error for %s", body) }
+    }
+}
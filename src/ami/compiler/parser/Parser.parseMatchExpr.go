@@ -0,0 +1,89 @@
+package parser
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// parseMatchExpr parses a match starting at the 'match' keyword:
+//   match <expr> {
+//       Red, Green => { ... }      enum members (or Color.Red)
+//       int(n) => n + 1            union alternative binding its value
+//       Some(v) => v               Optional present
+//       None => 0                  Optional absent
+//       _ => 0                     anything else
+//   }
+// Arm bodies are a block or a single expression; arms may be separated by ',' or ';'.
+func (p *Parser) parseMatchExpr() (*ast.MatchExpr, error) {
+    m := &ast.MatchExpr{Pos: p.cur.Pos}
+    p.next()
+    x, ok := p.parseExprPrec(1)
+    if !ok { return nil, fmt.Errorf("expected expression after match, got %q", p.cur.Lexeme) }
+    m.X = x
+    if p.cur.Kind != token.LBraceSym { return nil, fmt.Errorf("expected '{' to start match arms, got %q", p.cur.Lexeme) }
+    m.LBrace = p.cur.Pos
+    p.next()
+    for p.cur.Kind != token.RBraceSym && p.cur.Kind != token.EOF {
+        if p.cur.Kind == token.CommaSym || p.cur.Kind == token.SemiSym {
+            p.next()
+            continue
+        }
+        arm := ast.MatchArm{Pos: p.cur.Pos}
+        for {
+            pat, err := p.parseMatchPattern()
+            if err != nil { return nil, err }
+            arm.Patterns = append(arm.Patterns, pat)
+            if p.cur.Kind != token.CommaSym { break }
+            p.next()
+        }
+        // '=>' scans as '=' followed by '>'
+        if p.cur.Kind != token.Assign { return nil, fmt.Errorf("expected '=>' after match pattern, got %q", p.cur.Lexeme) }
+        p.next()
+        if p.cur.Kind != token.Gt { return nil, fmt.Errorf("expected '=>' after match pattern, got %q", "="+p.cur.Lexeme) }
+        p.next()
+        if p.cur.Kind == token.LBraceSym {
+            body, err := p.parseFuncBlock()
+            if err != nil { return nil, err }
+            arm.Body = body
+        } else {
+            v, ok := p.parseExprPrec(1)
+            if !ok { return nil, fmt.Errorf("expected block or expression for match arm, got %q", p.cur.Lexeme) }
+            arm.Value = v
+        }
+        m.Arms = append(m.Arms, arm)
+    }
+    if p.cur.Kind != token.RBraceSym { return nil, fmt.Errorf("missing '}' to close match") }
+    m.RBrace = p.cur.Pos
+    p.next()
+    return m, nil
+}
+
+// parseMatchPattern parses one pattern: _, None, Some(x), Type(x), or a
+// (possibly qualified or generic) name.
+func (p *Parser) parseMatchPattern() (ast.MatchPattern, error) {
+    pat := ast.MatchPattern{Pos: p.cur.Pos, Name: p.cur.Lexeme}
+    if p.cur.Kind == token.Ident && (p.cur.Lexeme == "_" || p.cur.Lexeme == "None") {
+        pat.Kind = "wildcard"
+        if p.cur.Lexeme == "None" { pat.Kind = "none" }
+        p.next()
+        return pat, nil
+    }
+    if !p.isTypeName(p.cur.Kind) { return pat, fmt.Errorf("expected match pattern, got %q", p.cur.Lexeme) }
+    name, err := p.parseTypeRef()
+    if err != nil { return pat, err }
+    pat.Name = name
+    pat.Kind = "name"
+    if p.cur.Kind != token.LParenSym { return pat, nil }
+    p.next()
+    if p.cur.Kind != token.Ident { return pat, fmt.Errorf("expected binding name in pattern %s(...), got %q", name, p.cur.Lexeme) }
+    pat.Binding = p.cur.Lexeme
+    pat.BindingPos = p.cur.Pos
+    p.next()
+    if p.cur.Kind != token.RParenSym { return pat, fmt.Errorf("expected ')' after pattern binding, got %q", p.cur.Lexeme) }
+    p.next()
+    pat.Kind = "variant"
+    if name == "Some" { pat.Kind = "some" }
+    return pat, nil
+}
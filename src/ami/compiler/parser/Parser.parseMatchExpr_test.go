@@ -0,0 +1,40 @@
+package parser
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func TestParseMatchExpr_StatementAndExpressionForms(t *testing.T) {
+    stmts := parseFuncBody(t, "match c {\n  Red, Color.Green => { x = 1 }\n  _ => work()\n}\n"+
+        "var n = match u { int(i) => i, string(s) => 0, Some(v) => v, None => -1 }\n"+
+        "return match o { Some(v) => v; None => 0 }")
+    if len(stmts) != 3 { t.Fatalf("want 3 stmts, got %d: %#v", len(stmts), stmts) }
+    es, ok := stmts[0].(*ast.ExprStmt)
+    if !ok { t.Fatalf("stmt form: %#v", stmts[0]) }
+    m := es.X.(*ast.MatchExpr)
+    if len(m.Arms) != 2 || len(m.Arms[0].Patterns) != 2 || m.Arms[0].Body == nil || m.Arms[1].Value == nil {
+        t.Fatalf("arms: %#v", m.Arms)
+    }
+    if p := m.Arms[0].Patterns[1]; p.Kind != "name" || p.Name != "Color.Green" { t.Fatalf("qualified member: %#v", p) }
+    if p := m.Arms[1].Patterns[0]; p.Kind != "wildcard" { t.Fatalf("wildcard: %#v", p) }
+    vd := stmts[1].(*ast.VarDecl)
+    em, ok := vd.Init.(*ast.MatchExpr)
+    if !ok || len(em.Arms) != 4 { t.Fatalf("expr form: %#v", vd.Init) }
+    kinds := []string{"variant", "variant", "some", "none"}
+    for i, k := range kinds {
+        if em.Arms[i].Patterns[0].Kind != k { t.Fatalf("arm %d kind: %#v", i, em.Arms[i].Patterns[0]) }
+    }
+    if em.Arms[0].Patterns[0].Binding != "i" || em.Arms[0].Patterns[0].Name != "int" { t.Fatalf("binding: %#v", em.Arms[0].Patterns[0]) }
+    rs := stmts[2].(*ast.ReturnStmt)
+    if _, ok := rs.Results[0].(*ast.MatchExpr); !ok { t.Fatalf("return form: %#v", rs.Results) }
+}
+
+func TestParseMatchExpr_Errors(t *testing.T) {
+    for _, body := range []string{"match c { Red }", "match c { Red => }", "match c Red => 1"} {
+        f := (&source.FileSet{}).AddFile("m.ami", "package app\nfunc F(){\n"+body+"\n}\n")
+        if _, err := New(f).ParseFile(); err == nil { t.Fatalf("expected error for %q", body) }
+    }
+}
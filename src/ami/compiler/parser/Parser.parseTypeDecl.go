@@ -0,0 +1,42 @@
+package parser
+
+import (
+    "fmt"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// parseTypeDecl parses a package-level type declaration:
+//   type Name struct { field Type ... }
+//   type Name Type
+//   type Name = Type
+func (p *Parser) parseTypeDecl() (*ast.TypeDecl, error) {
+    td := &ast.TypeDecl{Pos: p.cur.Pos, Leading: p.pending}
+    p.pending = nil
+    p.next()
+    if p.cur.Kind != token.Ident {
+        return nil, fmt.Errorf("expected type name, got %q", p.cur.Lexeme)
+    }
+    td.Name = p.cur.Lexeme
+    td.NamePos = p.cur.Pos
+    p.next()
+    if p.cur.Kind == token.Assign {
+        td.Alias = true
+        p.next()
+    }
+    td.TypePos = p.cur.Pos
+    if p.cur.Kind == token.KwStruct {
+        colon := p.cur.Lexeme == "Struct"
+        p.next()
+        fields, text, err := p.parseStructFields(colon)
+        if err != nil { return nil, err }
+        td.Fields = fields
+        td.Type = text
+        return td, nil
+    }
+    ty, err := p.parseTypeRef()
+    if err != nil { return nil, fmt.Errorf("type %s: %v", td.Name, err) }
+    td.Type = ty
+    return td, nil
+}
@@ -0,0 +1,44 @@
+package parser
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func parseTypeDecls(t *testing.T, src string) []*ast.TypeDecl {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("t.ami", "package app\n"+src)
+    file, err := New(f).ParseFile()
+    if err != nil { t.Fatalf("ParseFile: %v", err) }
+    var out []*ast.TypeDecl
+    for _, d := range file.Decls {
+        if td, ok := d.(*ast.TypeDecl); ok { out = append(out, td) }
+    }
+    return out
+}
+
+func TestParseTypeDecl_Forms(t *testing.T) {
+    src := "// Order is a purchase.\ntype Order struct {\n  id string\n  total int64\n  lines slice<map<string,Optional<int>>>\n  type string\n}\n" +
+        "type ID = string\n" +
+        "type Amount models.Money\n" +
+        "type Pair Struct{a: int, b: []*Order}\n" +
+        "func F(){}\n"
+    tds := parseTypeDecls(t, src)
+    if len(tds) != 4 { t.Fatalf("want 4 type decls, got %d", len(tds)) }
+    o := tds[0]
+    if o.Name != "Order" || o.Alias || len(o.Fields) != 4 || len(o.Leading) != 1 { t.Fatalf("order: %#v", o) }
+    if o.Type != "Struct{id:string,total:int64,lines:slice<map<string,Optional<int>>>,type:string}" { t.Fatalf("order type: %s", o.Type) }
+    if o.Fields[2].Name != "lines" || o.Fields[2].TypePos.Line != 6 { t.Fatalf("field: %#v", o.Fields[2]) }
+    if !tds[1].Alias || tds[1].Type != "string" { t.Fatalf("alias: %#v", tds[1]) }
+    if tds[2].Alias || tds[2].Type != "models.Money" { t.Fatalf("defined: %#v", tds[2]) }
+    if tds[3].Type != "Struct{a:int,b:[]*Order}" { t.Fatalf("Struct form: %s", tds[3].Type) }
+}
+
+func TestParseTypeDecl_Errors(t *testing.T) {
+    for _, src := range []string{"type {}\n", "type A struct { x }\n", "type B map<string,int\n"} {
+        f := (&source.FileSet{}).AddFile("e.ami", "package app\n"+src)
+        if _, err := New(f).ParseFile(); err == nil { t.Fatalf("expected error for %q", src) }
+    }
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// parseTypeRef parses a type reference and returns its canonical text without
+// whitespace. Supported forms:
+//   Name | pkg.Name | Name<T, ...> | *T | []T | struct { f T ... } | Struct{f:T, ...}
+// A closing '>>' is split across two nested generic argument lists.
+func (p *Parser) parseTypeRef() (string, error) {
+    var half bool
+    return p.typeRefText(&half)
+}
+
+func (p *Parser) typeRefText(half *bool) (string, error) {
+    switch {
+    case p.cur.Kind == token.Star:
+        p.next()
+        inner, err := p.typeRefText(half)
+        if err != nil { return "", err }
+        return "*" + inner, nil
+    case p.cur.Kind == token.LBracketSym:
+        p.next()
+        if p.cur.Kind != token.RBracketSym { return "", fmt.Errorf("expected ']' in slice type, got %q", p.cur.Lexeme) }
+        p.next()
+        inner, err := p.typeRefText(half)
+        if err != nil { return "", err }
+        return "[]" + inner, nil
+    case p.cur.Kind == token.KwStruct:
+        // keywords scan case-insensitively: "struct { f T }" and "Struct{f: T}"
+        colon := p.cur.Lexeme == "Struct"
+        p.next()
+        if colon && p.cur.Kind != token.LBraceSym { return "Struct", nil }
+        _, text, err := p.parseStructFields(colon)
+        return text, err
+    case !p.isTypeName(p.cur.Kind):
+        return "", fmt.Errorf("expected type, got %q", p.cur.Lexeme)
+    }
+    name := p.cur.Lexeme
+    p.next()
+    name = p.captureQualifiedType(name)
+    if p.cur.Kind != token.Lt { return name, nil }
+    p.next()
+    var args []string
+    for {
+        a, err := p.typeRefText(half)
+        if err != nil { return "", err }
+        args = append(args, a)
+        if p.cur.Kind == token.CommaSym { p.next(); continue }
+        break
+    }
+    switch p.cur.Kind {
+    case token.Gt:
+        p.next()
+    case token.Shr:
+        // '>>' closes this list and the enclosing one: consume it on the second close.
+        if *half { *half = false; p.next() } else { *half = true }
+    default:
+        return "", fmt.Errorf("expected '>' to close type arguments of %s, got %q", name, p.cur.Lexeme)
+    }
+    return name + "<" + strings.Join(args, ",") + ">", nil
+}
+
+// parseStructFields parses a brace-delimited field list starting at '{'. With
+// colon set, fields are written "name: Type" (Struct{...} form); otherwise
+// "name Type". Fields may be separated by ',' or ';'. It returns the fields in
+// declaration order and the canonical "Struct{name:Type,...}" text.
+func (p *Parser) parseStructFields(colon bool) ([]ast.FieldDecl, string, error) {
+    if p.cur.Kind != token.LBraceSym { return nil, "", fmt.Errorf("expected '{' to start struct, got %q", p.cur.Lexeme) }
+    p.next()
+    var fields []ast.FieldDecl
+    for p.cur.Kind != token.RBraceSym && p.cur.Kind != token.EOF {
+        if p.cur.Kind == token.CommaSym || p.cur.Kind == token.SemiSym || p.cur.Kind == token.LineComment || p.cur.Kind == token.BlockComment {
+            p.next()
+            continue
+        }
+        _, kw := token.LookupKeyword(strings.ToLower(p.cur.Lexeme))
+        if p.cur.Kind != token.Ident && !kw {
+            return nil, "", fmt.Errorf("expected field name, got %q", p.cur.Lexeme)
+        }
+        fd := ast.FieldDecl{NamePos: p.cur.Pos, Name: p.cur.Lexeme}
+        p.next()
+        if colon {
+            if p.cur.Kind != token.ColonSym { return nil, "", fmt.Errorf("expected ':' after field %s, got %q", fd.Name, p.cur.Lexeme) }
+            p.next()
+        }
+        fd.TypePos = p.cur.Pos
+        ty, err := p.parseTypeRef()
+        if err != nil { return nil, "", fmt.Errorf("field %s: %v", fd.Name, err) }
+        fd.Type = ty
+        fields = append(fields, fd)
+    }
+    if p.cur.Kind != token.RBraceSym { return nil, "", fmt.Errorf("missing '}' to close struct") }
+    p.next()
+    parts := make([]string, len(fields))
+    for i, f := range fields { parts[i] = f.Name + ":" + f.Type }
+    return fields, "Struct{" + strings.Join(parts, ",") + "}", nil
+}
@@ -0,0 +1,23 @@
+package parser
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func TestParseTypeRef_NestedGenerics(t *testing.T) {
+    cases := map[string]string{
+        "Event<Order>":                       "Event<Order>",
+        "map<string, slice<Optional<int>>>":  "map<string,slice<Optional<int>>>",
+        "Union<int, string>":                 "Union<int,string>",
+        "struct { a int; b pkg.T }":          "Struct{a:int,b:pkg.T}",
+        "Event<map<string,int>>":             "Event<map<string,int>>",
+    }
+    for in, want := range cases {
+        p := New((&source.FileSet{}).AddFile("r.ami", in))
+        got, err := p.parseTypeRef()
+        if err != nil { t.Fatalf("%q: %v", in, err) }
+        if got != want { t.Fatalf("%q: got %q want %q", in, got, want) }
+    }
+}
@@ -7,14 +7,21 @@ import (
 
 // parseWithTernary continues parsing binary RHS with precedence and then
 // recognizes the conditional operator: cond ? then : else. The ternary has
-// the lowest precedence and is right-associative.
+// the lowest precedence and is right-associative. A trailing '?' with no
+// operand after it on the same line is error propagation (x?).
 func (p *Parser) parseWithTernary(left ast.Expr, minPrec int) ast.Expr {
     // First, consume any binary operators per precedence table.
     left = p.parseBinaryRHS(left, minPrec)
     // Then, check for conditional operator.
     if p.cur.Kind == token.QuestionSym {
         // consume '?'
+        qpos := p.cur.Pos
         p.next()
+        // A '?' not followed by an operand on the same line is the postfix
+        // error propagation operator: f(x)?
+        if !p.isExprStart(p.cur.Kind) || p.cur.Pos.Line != qpos.Line {
+            return p.parseBinaryRHS(&ast.PropagateExpr{Pos: qpos, X: left}, minPrec)
+        }
         // parse 'then' expression
         thenExpr, ok := p.parseExprPrec(1)
         if !ok {
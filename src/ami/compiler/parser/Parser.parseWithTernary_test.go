@@ -1,5 +1,20 @@
 package parser
 
-import "testing"
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+)
 
 func Test_Parser_parseWithTernary_Exists(t *testing.T) {}
+
+func TestParser_Propagate_Forms(t *testing.T) {
+    st := parseFuncBody(t, "var x = g(m)?\nx = g(m)?; h(x)?\nvar y = ok ? 1 : 2\nvar z = g(m)?\nh(z)")
+    if len(st) != 6 { t.Fatalf("stmts: %d", len(st)) }
+    if p, ok := st[0].(*ast.VarDecl).Init.(*ast.PropagateExpr); !ok || p.X.(*ast.CallExpr).Name != "g" { t.Fatalf("var: %#v", st[0]) }
+    if _, ok := st[1].(*ast.AssignStmt).Value.(*ast.PropagateExpr); !ok { t.Fatalf("assign: %#v", st[1]) }
+    if _, ok := st[2].(*ast.ExprStmt).X.(*ast.PropagateExpr); !ok { t.Fatalf("expr stmt: %#v", st[2]) }
+    if _, ok := st[3].(*ast.VarDecl).Init.(*ast.ConditionalExpr); !ok { t.Fatalf("ternary: %#v", st[3]) }
+    // an operand on the next line does not continue a conditional
+    if _, ok := st[4].(*ast.VarDecl).Init.(*ast.PropagateExpr); !ok { t.Fatalf("next line: %#v", st[4]) }
+}
@@ -11,6 +11,8 @@ func ePos(e ast.Expr) source.Position {
         return v.Pos
     case *ast.StringLit:
         return v.Pos
+    case *ast.InterpStringLit:
+        return v.Pos
     case *ast.NumberLit:
         return v.Pos
     case *ast.CallExpr:
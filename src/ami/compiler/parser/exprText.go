@@ -1,6 +1,10 @@
 package parser
 
-import "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+import (
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+)
 
 // exprText produces a simple string representation of an expression suitable
 // for debug displays (attribute args, etc.). It is not a full pretty-printer.
@@ -10,6 +14,13 @@ func exprText(e ast.Expr) string {
         return v.Name
     case *ast.StringLit:
         return v.Value
+    case *ast.InterpStringLit:
+        var b strings.Builder
+        for i, part := range v.Parts {
+            b.WriteString(part)
+            if i < len(v.Exprs) { b.WriteString("${" + exprText(v.Exprs[i]) + "}") }
+        }
+        return b.String()
     case *ast.DurationLit:
         return v.Text
     case *ast.NumberLit:
@@ -0,0 +1,17 @@
+package parser
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func TestParser_Pragmas_KeywordArgsNotParsedAsDecls(t *testing.T) {
+    src := "package app\n#pragma test:runtime pipeline=P input={\"k\":1}\npipeline P(){ ingress; egress }\n"
+    f := &source.File{Name: "p.ami", Content: src}
+    p := New(f)
+    file, err := p.ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if len(file.Pragmas) != 1 { t.Fatalf("want 1 pragma, got %d", len(file.Pragmas)) }
+    if len(file.Decls) != 1 { t.Fatalf("want 1 decl, got %d", len(file.Decls)) }
+}
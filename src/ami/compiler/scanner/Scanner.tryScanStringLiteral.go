@@ -7,28 +7,57 @@ import (
 )
 
 // tryScanStringLiteral scans a quoted string literal, with minimal escape support.
+// Interpolations (`${expr}`) may contain nested string literals.
 func (s *Scanner) tryScanStringLiteral(src string, n int, start int) (token.Token, bool) {
-	ch, size := utf8.DecodeRuneInString(src[s.offset:])
+	ch, _ := utf8.DecodeRuneInString(src[s.offset:])
 	if ch != '"' {
 		return token.Token{}, false
 	}
-	s.offset += size // skip opening quote
+	if s.skipStringLiteral(src, n) {
+		return token.Token{Kind: token.String, Lexeme: src[start:s.offset], Pos: s.file.Pos(start)}, true
+	}
+	return token.Token{Kind: token.Unknown, Lexeme: src[start:s.offset], Pos: s.file.Pos(start)}, true
+}
+
+// skipStringLiteral advances past the string literal at the current offset
+// (which must be its opening quote) and reports whether it was terminated.
+func (s *Scanner) skipStringLiteral(src string, n int) bool {
+	s.offset++ // skip opening quote
 	for s.offset < n {
 		r, sz := utf8.DecodeRuneInString(src[s.offset:])
-		if r == '"' {
+		switch {
+		case r == '"':
 			s.offset += sz
-			return token.Token{Kind: token.String, Lexeme: src[start:s.offset], Pos: s.file.Pos(start)}, true
-		}
-		if r == '\\' {
+			return true
+		case r == '\\':
 			s.offset += sz
-			if s.offset < n {
-				_, esz := utf8.DecodeRuneInString(src[s.offset:])
-				s.offset += esz
-				continue
+			if s.offset >= n {
+				return false
+			}
+			_, esz := utf8.DecodeRuneInString(src[s.offset:])
+			s.offset += esz
+		case r == '$' && s.offset+1 < n && src[s.offset+1] == '{':
+			s.offset += 2
+			for depth := 1; depth > 0; {
+				if s.offset >= n {
+					return false
+				}
+				switch src[s.offset] {
+				case '"':
+					if !s.skipStringLiteral(src, n) {
+						return false
+					}
+					continue
+				case '{':
+					depth++
+				case '}':
+					depth--
+				}
+				s.offset++
 			}
-			break
+		default:
+			s.offset += sz
 		}
-		s.offset += sz
 	}
-	return token.Token{Kind: token.Unknown, Lexeme: src[start:s.offset], Pos: s.file.Pos(start)}, true
+	return false
 }
@@ -19,3 +19,16 @@ func TestScanner_StringEscapes_And_Unterminated(t *testing.T) {
 		t.Fatalf("unterminated should be Unknown: %+v", tok)
 	}
 }
+
+func TestScanner_StringInterpolation_NestedStrings(t *testing.T) {
+	src := `"a ${join(xs, "}")} b" x`
+	s := New(&source.File{Name: "t", Content: src})
+	tok := s.Next()
+	if tok.Kind != token.String || tok.Lexeme != `"a ${join(xs, "}")} b"` {
+		t.Fatalf("interpolated string: %+v", tok)
+	}
+	s = New(&source.File{Name: "t", Content: `"a ${f("b)}"`})
+	if tok = s.Next(); tok.Kind != token.Unknown {
+		t.Fatalf("unterminated nested string should be Unknown: %+v", tok)
+	}
+}
@@ -11,6 +11,11 @@ import (
 func typesCompatible(expected, actual string) bool {
     if expected == "" || expected == "any" || actual == "" || actual == "any" { return true }
     if expected == actual { return true }
+    // Declared type names: compare aliases by target and defined types by qualified name
+    if typeResolver != nil {
+        ce, ca := canonicalTypeText(expected), canonicalTypeText(actual)
+        if ce != expected || ca != actual { return typesCompatible(ce, ca) }
+    }
     // Optional unwrap: consider Optional<X> compatible with Optional<Y> when X~Y
     if strings.HasPrefix(expected, "Optional<") && strings.HasPrefix(actual, "Optional<") {
         return typesCompatible(innerGeneric(expected), innerGeneric(actual))
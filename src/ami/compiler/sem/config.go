@@ -1,6 +1,12 @@
 package sem
 
+import "github.com/sam-caldwell/ami/src/ami/compiler/types"
+
 // SetStrictDedupUnderPartition sets the package-level toggle for elevating
 // Dedup under PartitionBy warnings to errors.
 func SetStrictDedupUnderPartition(v bool) { StrictDedupUnderPartition = v }
 
+
+// SetTypeResolver installs the package-scoped type resolver used to expand
+// user-declared type names during analysis. Pass nil to clear it.
+func SetTypeResolver(r *types.Resolver) { typeResolver = r }
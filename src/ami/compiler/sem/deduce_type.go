@@ -9,7 +9,7 @@ func deduceType(e ast.Expr) string {
     switch v := e.(type) {
     case *ast.NumberLit:
         return "int"
-    case *ast.StringLit:
+    case *ast.StringLit, *ast.InterpStringLit:
         return "string"
     case *ast.BinaryExpr:
         // simple arithmetic infer: if both sides are int and op is arithmetic, infer int
@@ -14,6 +14,8 @@ func inferExprTypeWithEnvAndResults(e ast.Expr, vars map[string]string, results
             if rs, ok := results[v.Name]; ok && len(rs) > 0 && rs[0] != "" { return rs[0] }
         }
         return "any"
+    case *ast.PropagateExpr:
+        return inferExprTypeWithEnvAndResults(v.X, vars, results)
     default:
         return inferExprTypeWithVars(e, vars)
     }
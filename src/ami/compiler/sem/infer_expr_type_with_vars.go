@@ -9,7 +9,7 @@ func inferExprTypeWithVars(e ast.Expr, vars map[string]string) string {
     case *ast.IdentExpr:
         if t, ok := vars[v.Name]; ok && t != "" { return t }
         return "any"
-    case *ast.StringLit:
+    case *ast.StringLit, *ast.InterpStringLit:
         return "string"
     case *ast.NumberLit:
         return "int"
@@ -12,7 +12,7 @@ func inferLocalExprType(env map[string]string, e ast.Expr) string {
         return "any"
     case *ast.NumberLit:
         return "int"
-    case *ast.StringLit:
+    case *ast.StringLit, *ast.InterpStringLit:
         return "string"
     case *ast.UnaryExpr:
         // logical not yields bool (i1)
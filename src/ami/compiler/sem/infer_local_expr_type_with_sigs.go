@@ -9,6 +9,9 @@ func inferLocalExprTypeWithSigs(env map[string]string, sigs map[string][]string,
     case *ast.CallExpr:
         if rs, ok := sigs[v.Name]; ok && len(rs) > 0 && rs[0] != "" { return rs[0] }
         return "any"
+    case *ast.PropagateExpr:
+        // x? yields the call's first result once the error is handled
+        return inferLocalExprTypeWithSigs(env, sigs, v.X)
     default:
         return inferLocalExprType(env, e)
     }
@@ -10,7 +10,7 @@ func inferRetTypeWithEnv(e ast.Expr, env map[string]string, results map[string][
     case *ast.CallExpr:
         if rs, ok := results[v.Name]; ok && len(rs) > 0 && rs[0] != "" { return rs[0] }
         return "any"
-    case *ast.NumberLit, *ast.StringLit, *ast.SliceLit, *ast.SetLit, *ast.MapLit:
+    case *ast.NumberLit, *ast.StringLit, *ast.InterpStringLit, *ast.SliceLit, *ast.SetLit, *ast.MapLit:
         return deduceType(e)
     default:
         return inferExprType(env, e)
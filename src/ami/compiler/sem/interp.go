@@ -0,0 +1,117 @@
+package sem
+
+import (
+    "strings"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// AnalyzeInterpolation checks the expressions embedded in interpolated string
+// literals ("n=${n}"). Each must have a scalar type with a string form: string,
+// bool, an integer or float type, or an enum. resultSigs supplies result types
+// for calls not declared in f; expressions of unknown type are accepted.
+//
+// Diagnostics:
+// - E_STRING_INTERP_TYPE: an embedded expression has an error, container, struct,
+//   union, Optional or generic type (format its parts explicitly instead).
+func AnalyzeInterpolation(f *ast.File, resultSigs map[string][]string) []diag.Record {
+    var out []diag.Record
+    if f == nil { return out }
+    now := time.Unix(0, 0).UTC()
+    tr := fileTypeResolver(f)
+    sigs := map[string][]string{}
+    for k, v := range resultSigs { sigs[k] = v }
+    for _, d := range f.Decls {
+        if fn, ok := d.(*ast.FuncDecl); ok && fn.Name != "" {
+            var rs []string
+            for _, r := range fn.Results { rs = append(rs, r.Type) }
+            sigs[fn.Name] = rs
+        }
+    }
+    for _, d := range f.Decls {
+        fn, ok := d.(*ast.FuncDecl)
+        if !ok || fn.Body == nil { continue }
+        env := buildLocalEnv(fn)
+        var walkExpr func(e ast.Expr)
+        var walkBlock func(b *ast.BlockStmt)
+        walkExpr = func(e ast.Expr) {
+            switch v := e.(type) {
+            case *ast.InterpStringLit:
+                for _, x := range v.Exprs {
+                    t := inferLocalExprTypeWithSigs(env, sigs, x)
+                    if !interpFormattable(t, tr) {
+                        p := epos(x)
+                        out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_STRING_INTERP_TYPE", Message: "cannot interpolate a value of type " + t, Pos: &diag.Position{Line: p.Line, Column: p.Column, Offset: p.Offset}, Data: map[string]any{"type": t}})
+                    }
+                    walkExpr(x)
+                }
+            case *ast.BinaryExpr:
+                walkExpr(v.X); walkExpr(v.Y)
+            case *ast.UnaryExpr:
+                walkExpr(v.X)
+            case *ast.CallExpr:
+                for _, a := range v.Args { walkExpr(a) }
+            case *ast.ConditionalExpr:
+                walkExpr(v.Cond); walkExpr(v.Then); walkExpr(v.Else)
+            case *ast.PropagateExpr:
+                walkExpr(v.X)
+            case *ast.SliceLit:
+                for _, a := range v.Elems { walkExpr(a) }
+            case *ast.SetLit:
+                for _, a := range v.Elems { walkExpr(a) }
+            case *ast.MapLit:
+                for _, kv := range v.Elems { walkExpr(kv.Key); walkExpr(kv.Val) }
+            case *ast.MatchExpr:
+                walkExpr(v.X)
+                for _, arm := range v.Arms {
+                    if arm.Value != nil { walkExpr(arm.Value) }
+                    walkBlock(arm.Body)
+                }
+            }
+        }
+        walkBlock = func(b *ast.BlockStmt) {
+            if b == nil { return }
+            for _, st := range b.Stmts {
+                switch v := st.(type) {
+                case *ast.ExprStmt:
+                    walkExpr(v.X)
+                case *ast.VarDecl:
+                    if v.Init != nil { walkExpr(v.Init) }
+                case *ast.AssignStmt:
+                    walkExpr(v.Value)
+                case *ast.ReturnStmt:
+                    for _, r := range v.Results { walkExpr(r) }
+                case *ast.DeferStmt:
+                    if v.Call != nil { walkExpr(v.Call) }
+                case *ast.IfStmt:
+                    walkExpr(v.Cond); walkBlock(v.Then); walkBlock(v.Else)
+                case *ast.ForStmt:
+                    if v.Cond != nil { walkExpr(v.Cond) }
+                    walkBlock(v.Body)
+                case *ast.RangeStmt:
+                    walkExpr(v.X); walkBlock(v.Body)
+                }
+            }
+        }
+        walkBlock(fn.Body)
+    }
+    return out
+}
+
+// interpFormattable reports whether a value of type t has a string form for
+// interpolation. Unknown types ("", "any", undeclared names) are accepted.
+func interpFormattable(t string, tr *types.Resolver) bool {
+    t = strings.TrimSpace(t)
+    if t == "" || t == "any" { return true }
+    if t == "error" { return false }
+    pt, err := tr.Parse(t)
+    if err != nil { return true }
+    switch tr.Resolve(pt).(type) {
+    case types.Struct, types.Slice, types.SliceTy, types.Set, types.Map, types.Generic, types.Union, types.Optional, types.Function, types.Pointer:
+        return false
+    }
+    return true
+}
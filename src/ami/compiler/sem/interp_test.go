@@ -0,0 +1,43 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func interpDiags(t *testing.T, code string, sigs map[string][]string) map[string]int {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("interp.ami", code)
+    af, err := parser.New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    got := map[string]int{}
+    for _, d := range AnalyzeInterpolation(af, sigs) { got[d.Code]++ }
+    return got
+}
+
+func TestAnalyzeInterpolation_Valid(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green }\n" +
+        "func count(s string) (int) { return 0 }\n" +
+        "func F(name string, n int64, r float64, ok bool, c Color, v any) (string) {\n" +
+        "  var k = count(name)\n" +
+        "  var m = \"${name}: ${n} ${r} ${ok} ${c} ${v} ${k} ${count(name) + 1} ${strings.ToUpper(name)}\"\n" +
+        "  return \"[${m}]\"\n" +
+        "}\n"
+    if got := interpDiags(t, code, map[string][]string{"strings.ToUpper": {"string"}}); len(got) != 0 { t.Fatalf("unexpected diags: %v", got) }
+}
+
+func TestAnalyzeInterpolation_Errors(t *testing.T) {
+    code := "package app\n" +
+        "type Point struct { X int, Y int }\n" +
+        "func parts() (slice<string>) { return slice<string>{} }\n" +
+        "func F(p Point, m map<string,int>, e error, o Optional<int>, ok bool) {\n" +
+        "  var a = \"${p} ${m}\"\n" +
+        "  if ok { a = \"${parts()}\" }\n" +
+        "  log(\"failed: ${e} ${o}\")\n" +
+        "}\n"
+    got := interpDiags(t, code, nil)
+    if got["E_STRING_INTERP_TYPE"] != 5 { t.Fatalf("want 5 E_STRING_INTERP_TYPE, got %v", got) }
+}
@@ -0,0 +1,9 @@
+package sem
+
+import "strings"
+
+// isOwnedTypeText reports whether a textual type denotes an Owned handle (Owned or Owned<T>).
+func isOwnedTypeText(t string) bool {
+    t = strings.TrimSpace(t)
+    return t == "Owned" || strings.HasPrefix(t, "Owned<")
+}
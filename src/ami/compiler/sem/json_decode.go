@@ -0,0 +1,167 @@
+package sem
+
+import (
+    "strings"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// AnalyzeJSONDecode checks calls to json.Decode, whose result type is taken
+// from where the call is used: a typed `var v T = json.Decode(s)?` or an
+// assignment to a variable of declared type.
+//
+// Diagnostics:
+// - E_JSON_DECODE_TARGET: the call has no declared target type (an untyped var,
+//   an undeclared variable, or any other position such as a call argument).
+// - E_JSON_DECODE_TYPE: the target type has no JSON form (error, functions,
+//   pointers, Owned and other runtime handles).
+func AnalyzeJSONDecode(f *ast.File) []diag.Record {
+    var out []diag.Record
+    if f == nil { return out }
+    now := time.Unix(0, 0).UTC()
+    tr := fileTypeResolver(f)
+    for _, d := range f.Decls {
+        fn, ok := d.(*ast.FuncDecl)
+        if !ok || fn.Body == nil { continue }
+        env := buildLocalEnv(fn)
+        // target checks a json.Decode call used with declared type t ("" if none).
+        target := func(c *ast.CallExpr, t string) {
+            p := epos(c)
+            at := &diag.Position{Line: p.Line, Column: p.Column, Offset: p.Offset}
+            t = strings.TrimSpace(t)
+            if t == "" {
+                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_JSON_DECODE_TARGET", Message: "json.Decode needs a declared target type, e.g. var v T = json.Decode(s)?", Pos: at})
+                return
+            }
+            if !jsonDecodable(t, tr) {
+                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_JSON_DECODE_TYPE", Message: "cannot decode JSON into a value of type " + t, Pos: at, Data: map[string]any{"type": t}})
+            }
+        }
+        var walkExpr func(e ast.Expr)
+        var walkBlock func(b *ast.BlockStmt)
+        // top handles e as the whole value stored into a target of type t.
+        top := func(e ast.Expr, t string) {
+            if p, ok := e.(*ast.PropagateExpr); ok { e = p.X }
+            if c, ok := e.(*ast.CallExpr); ok && c.Name == "json.Decode" {
+                target(c, t)
+                for _, a := range c.Args { walkExpr(a) }
+                return
+            }
+            walkExpr(e)
+        }
+        walkExpr = func(e ast.Expr) {
+            switch v := e.(type) {
+            case *ast.CallExpr:
+                if v.Name == "json.Decode" { target(v, "") }
+                for _, a := range v.Args { walkExpr(a) }
+            case *ast.PropagateExpr:
+                walkExpr(v.X)
+            case *ast.BinaryExpr:
+                walkExpr(v.X); walkExpr(v.Y)
+            case *ast.UnaryExpr:
+                walkExpr(v.X)
+            case *ast.ConditionalExpr:
+                walkExpr(v.Cond); walkExpr(v.Then); walkExpr(v.Else)
+            case *ast.InterpStringLit:
+                for _, x := range v.Exprs { walkExpr(x) }
+            case *ast.SliceLit:
+                for _, a := range v.Elems { walkExpr(a) }
+            case *ast.SetLit:
+                for _, a := range v.Elems { walkExpr(a) }
+            case *ast.MapLit:
+                for _, kv := range v.Elems { walkExpr(kv.Key); walkExpr(kv.Val) }
+            case *ast.MatchExpr:
+                walkExpr(v.X)
+                for _, arm := range v.Arms {
+                    if arm.Value != nil { walkExpr(arm.Value) }
+                    walkBlock(arm.Body)
+                }
+            }
+        }
+        walkBlock = func(b *ast.BlockStmt) {
+            if b == nil { return }
+            for _, st := range b.Stmts {
+                switch v := st.(type) {
+                case *ast.VarDecl:
+                    if v.Type != "" { env[v.Name] = v.Type }
+                    if v.Init != nil { top(v.Init, v.Type) }
+                case *ast.AssignStmt:
+                    top(v.Value, env[v.Name])
+                case *ast.ReturnStmt:
+                    for _, r := range v.Results { walkExpr(r) }
+                case *ast.ExprStmt:
+                    walkExpr(v.X)
+                case *ast.DeferStmt:
+                    if v.Call != nil { walkExpr(v.Call) }
+                case *ast.IfStmt:
+                    walkExpr(v.Cond); walkBlock(v.Then); walkBlock(v.Else)
+                case *ast.ForStmt:
+                    if v.Cond != nil { walkExpr(v.Cond) }
+                    walkBlock(v.Body)
+                case *ast.RangeStmt:
+                    walkExpr(v.X); walkBlock(v.Body)
+                }
+            }
+        }
+        walkBlock(fn.Body)
+    }
+    return out
+}
+
+// jsonDecodable reports whether JSON can be decoded into a value of type t:
+// scalars, enums, structs, Optional, Union, containers and Event<T> of such
+// types, and any. Undeclared names are left to the type checks.
+func jsonDecodable(t string, tr *types.Resolver) bool {
+    t = strings.TrimSpace(t)
+    if t == "error" { return false }
+    pt, err := tr.Parse(t)
+    if err != nil { return true }
+    var ok func(x types.Type, depth int) bool
+    ok = func(x types.Type, depth int) bool {
+        if depth > 32 { return true }
+        switch v := x.(type) {
+        case types.Primitive, types.Enum:
+            return true
+        case types.Named:
+            // self references left by Resolve are checked at their declaration
+            return v.Name != "error"
+        case types.Struct:
+            for _, ft := range v.Fields { if !ok(ft, depth+1) { return false } }
+            return true
+        case types.Optional:
+            return ok(v.Inner, depth+1)
+        case types.Union:
+            for _, a := range v.Alts { if !ok(a, depth+1) { return false } }
+            return true
+        case types.Slice:
+            return ok(v.Elem, depth+1)
+        case types.SliceTy:
+            return ok(v.Elem, depth+1)
+        case types.Set:
+            return ok(v.Elem, depth+1)
+        case types.Map:
+            return ok(v.Val, depth+1)
+        case types.Generic:
+            switch {
+            case v.Name == "any" && len(v.Args) == 0:
+                return true
+            case (v.Name == "slice" || v.Name == "set" || v.Name == "Event") && len(v.Args) == 1:
+                return ok(v.Args[0], depth+1)
+            case v.Name == "map" && len(v.Args) == 2:
+                return ok(v.Args[1], depth+1)
+            case len(v.Args) == 0 && strings.HasPrefix(v.Name, "[]"):
+                inner, err := types.Parse(v.Name[2:])
+                return err != nil || ok(tr.Resolve(inner), depth+1)
+            case len(v.Args) == 0:
+                // runtime handles have no JSON form; undeclared names are left to the type checks
+                return !builtinTypeName(v.Name)
+            }
+            return false
+        }
+        return false
+    }
+    return ok(pt, 0)
+}
@@ -0,0 +1,48 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func jsonDecodeDiags(t *testing.T, code string) map[string]int {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("jd.ami", code)
+    af, err := parser.New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    got := map[string]int{}
+    for _, d := range AnalyzeJSONDecode(af) { got[d.Code]++ }
+    return got
+}
+
+func TestAnalyzeJSONDecode_Targets(t *testing.T) {
+    code := "package app\n" +
+        "enum Status { Open, Closed }\n" +
+        "type Line struct { sku string\n qty Optional<int> }\n" +
+        "type Order struct { id string\n status Status\n lines slice<Line>\n tags map<string,string>\n extra any }\n" +
+        "func F(body string) (Order, error) {\n" +
+        "  var o Order = json.Decode(body)?\n" +
+        "  var e Event<Order> = json.Decode(body)?\n" +
+        "  var u Union<int,string> = json.Decode(body)?\n" +
+        "  o = json.Decode(body)?\n" +
+        "  return o, nil\n" +
+        "}\n"
+    if got := jsonDecodeDiags(t, code); len(got) != 0 { t.Fatalf("unexpected diags: %v", got) }
+}
+
+func TestAnalyzeJSONDecode_Errors(t *testing.T) {
+    code := "package app\n" +
+        "type Job struct { run Owned }\n" +
+        "func F(body string, err error) (string, error) {\n" +
+        "  var a = json.Decode(body)?\n" +
+        "  b = json.Decode(body)?\n" +
+        "  var j Job = json.Decode(body)?\n" +
+        "  err = json.Decode(body)?\n" +
+        "  var s = json.Encode(json.Decode(body))?\n" +
+        "  return s, nil\n" +
+        "}\n"
+    got := jsonDecodeDiags(t, code)
+    if got["E_JSON_DECODE_TARGET"] != 3 || got["E_JSON_DECODE_TYPE"] != 2 { t.Fatalf("diags: %v", got) }
+}
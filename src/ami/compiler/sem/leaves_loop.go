@@ -0,0 +1,15 @@
+package sem
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+
+// leavesLoop reports whether a statement sequence unconditionally exits the
+// enclosing loop via break or return.
+func leavesLoop(stmts []ast.Stmt) bool {
+    for _, st := range stmts {
+        switch st.(type) {
+        case *ast.BreakStmt, *ast.ReturnStmt:
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,25 @@
+package sem
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/token"
+)
+
+// loopCondType returns the best-effort type of a loop condition. Comparisons,
+// logical operators and negation are bool; other forms defer to local inference.
+func loopCondType(e ast.Expr, env map[string]string) string {
+    switch v := e.(type) {
+    case *ast.BinaryExpr:
+        switch v.Op {
+        case token.Eq, token.Ne, token.Lt, token.Le, token.Gt, token.Ge, token.And, token.Or:
+            return "bool"
+        }
+        return deduceType(e)
+    case *ast.UnaryExpr:
+        if v.Op == token.Bang { return "bool" }
+        return "any"
+    case *ast.IdentExpr:
+        if v.Name == "true" || v.Name == "false" { return "bool" }
+    }
+    return inferExprTypeWithVars(e, env)
+}
@@ -0,0 +1,149 @@
+package sem
+
+import (
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// loopScope tracks the state of one enclosing loop during AnalyzeLoops.
+type loopScope struct {
+    // declared holds variables declared inside this loop's body (per-iteration lifetime).
+    declared map[string]bool
+    // coll names the ranged-over collection for range loops ("" otherwise).
+    coll string
+}
+
+// AnalyzeLoops validates for/range loops and the statements that only make sense inside them.
+//
+// Diagnostics:
+// - E_BREAK_OUTSIDE_LOOP / E_CONTINUE_OUTSIDE_LOOP: branch statement without an enclosing loop.
+// - E_LOOP_COND_NOT_BOOL: a condition loop whose condition has a known non-bool type.
+// - E_RANGE_NOT_ITERABLE: range over a value whose known type is not slice, map or set.
+// - E_RANGE_SET_TWO_VARS: range over a set binds a single element variable.
+// - E_RANGE_MUTATES_COLLECTION: assignment to the ranged-over collection inside its loop body.
+// - E_RAII_CONSUME_IN_LOOP: an Owned variable declared outside the loop is released or
+//   transferred inside it without a following break/return, so it would be consumed on
+//   every iteration.
+// - E_RAII_LOOP_LEAK: an Owned variable declared in a loop body is not released or
+//   transferred before the iteration ends.
+// - E_DEFER_IN_LOOP: defer inside a loop body accumulates until function exit.
+func AnalyzeLoops(f *ast.File) []diag.Record {
+    var out []diag.Record
+    if f == nil { return out }
+    now := time.Unix(0, 0).UTC()
+    params := collectFunctionParams(f)
+    emit := func(code, msg string, p source.Position) {
+        out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: code, Message: msg, Pos: &diag.Position{Line: p.Line, Column: p.Column, Offset: p.Offset}})
+    }
+    for _, d := range f.Decls {
+        fn, ok := d.(*ast.FuncDecl)
+        if !ok || fn.Body == nil { continue }
+        env := buildLocalEnv(fn)
+        owned := map[string]bool{}
+        for _, p := range fn.Params { if isOwnedTypeText(p.Type) { owned[p.Name] = true } }
+        var loops []*loopScope
+        // walk visits b; after holds the statements that run once b completes,
+        // up to the end of the innermost loop body.
+        var walk func(b *ast.BlockStmt, after []ast.Stmt)
+        // consumeAt reports an outer Owned variable consumed inside the innermost loop,
+        // unless the remainder of the loop body (rest) leaves the loop.
+        consumeAt := func(name string, pos source.Position, rest []ast.Stmt) {
+            if len(loops) == 0 || !owned[name] || loops[len(loops)-1].declared[name] { return }
+            if leavesLoop(rest) { return }
+            emit("E_RAII_CONSUME_IN_LOOP", "Owned variable consumed on every loop iteration: "+name, pos)
+        }
+        checkCall := func(c *ast.CallExpr, rest []ast.Stmt) {
+            if c == nil { return }
+            if name, pos := releaseTargetFromCall(c); name != "" {
+                consumeAt(name, pos, rest)
+                return
+            }
+            sig := params[c.Name]
+            for i, a := range c.Args {
+                if id, ok := a.(*ast.IdentExpr); ok && i < len(sig) && isOwnedTypeText(sig[i]) {
+                    consumeAt(id.Name, id.Pos, rest)
+                }
+            }
+        }
+        loopBody := func(scope *loopScope, body *ast.BlockStmt) {
+            loops = append(loops, scope)
+            walk(body, nil)
+            loops = loops[:len(loops)-1]
+            if body == nil { return }
+            for _, st := range body.Stmts {
+                if vd, ok := st.(*ast.VarDecl); ok && isOwnedTypeText(vd.Type) && !ownedConsumedIn(body, vd.Name, params) {
+                    emit("E_RAII_LOOP_LEAK", "Owned variable not released or transferred within loop iteration: "+vd.Name, vd.Pos)
+                }
+            }
+        }
+        walk = func(b *ast.BlockStmt, after []ast.Stmt) {
+            if b == nil { return }
+            for i, st := range b.Stmts {
+                rest := append(append([]ast.Stmt{}, b.Stmts[i+1:]...), after...)
+                switch v := st.(type) {
+                case *ast.BreakStmt:
+                    if len(loops) == 0 { emit("E_BREAK_OUTSIDE_LOOP", "break outside of a loop", v.Pos) }
+                case *ast.ContinueStmt:
+                    if len(loops) == 0 { emit("E_CONTINUE_OUTSIDE_LOOP", "continue outside of a loop", v.Pos) }
+                case *ast.VarDecl:
+                    if isOwnedTypeText(v.Type) { owned[v.Name] = true }
+                    if len(loops) > 0 { loops[len(loops)-1].declared[v.Name] = true }
+                case *ast.AssignStmt:
+                    for _, l := range loops {
+                        if l.coll != "" && l.coll == v.Name {
+                            emit("E_RANGE_MUTATES_COLLECTION", "collection modified while ranging over it: "+v.Name, v.NamePos)
+                            break
+                        }
+                    }
+                    if c, ok := v.Value.(*ast.CallExpr); ok { checkCall(c, rest) }
+                case *ast.ExprStmt:
+                    switch x := v.X.(type) {
+                    case *ast.CallExpr:
+                        checkCall(x, rest)
+                    case *ast.MatchExpr:
+                        for _, arm := range x.Arms {
+                            if arm.Body != nil {
+                                walk(arm.Body, rest)
+                            } else if c, ok := arm.Value.(*ast.CallExpr); ok {
+                                checkCall(c, rest)
+                            }
+                        }
+                    }
+                case *ast.DeferStmt:
+                    if len(loops) > 0 { emit("E_DEFER_IN_LOOP", "defer inside loop body runs only at function exit", v.Pos) }
+                case *ast.IfStmt:
+                    walk(v.Then, rest)
+                    walk(v.Else, rest)
+                case *ast.ForStmt:
+                    if v.Cond != nil {
+                        if t := loopCondType(v.Cond, env); t != "any" && t != "" && t != "bool" {
+                            emit("E_LOOP_COND_NOT_BOOL", "loop condition must be bool, got "+t, epos(v.Cond))
+                        }
+                    }
+                    loopBody(&loopScope{declared: map[string]bool{}}, v.Body)
+                case *ast.RangeStmt:
+                    t := inferExprTypeWithVars(v.X, env)
+                    base := t
+                    if b, _, ok := baseAndArgs(t); ok { base = b }
+                    switch base {
+                    case "slice", "map", "set", "any", "":
+                    default:
+                        emit("E_RANGE_NOT_ITERABLE", "cannot range over value of type "+t, epos(v.X))
+                    }
+                    if base == "set" && v.Value != "" {
+                        emit("E_RANGE_SET_TWO_VARS", "range over set binds a single element variable", v.ValuePos)
+                    }
+                    scope := &loopScope{declared: map[string]bool{v.Key: true}}
+                    if v.Value != "" { scope.declared[v.Value] = true }
+                    if id, ok := v.X.(*ast.IdentExpr); ok { scope.coll = id.Name }
+                    loopBody(scope, v.Body)
+                }
+            }
+        }
+        walk(fn.Body, nil)
+    }
+    return out
+}
@@ -0,0 +1,82 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+func loopCodes(t *testing.T, code string) map[string]int {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("loops.ami", code)
+    af, _ := parser.New(f).ParseFileCollect()
+    got := map[string]int{}
+    for _, d := range AnalyzeLoops(af) { got[d.Code]++ }
+    return got
+}
+
+func TestAnalyzeLoops_BranchOutsideLoop(t *testing.T) {
+    got := loopCodes(t, "package app\nfunc F(){ break; continue; for { break; continue } }\n")
+    if got["E_BREAK_OUTSIDE_LOOP"] != 1 || got["E_CONTINUE_OUTSIDE_LOOP"] != 1 { t.Fatalf("codes: %v", got) }
+}
+
+func TestAnalyzeLoops_CondAndRangeTypes(t *testing.T) {
+    code := "package app\nfunc F(xs slice<int>, s set<string>, n int){\n" +
+        "for n { }\nfor n < 3 { }\nfor i, x := range xs { }\nfor a, b := range s { }\nfor c := range n { }\n}\n"
+    got := loopCodes(t, code)
+    if got["E_LOOP_COND_NOT_BOOL"] != 1 { t.Fatalf("cond: %v", got) }
+    if got["E_RANGE_SET_TWO_VARS"] != 1 { t.Fatalf("set vars: %v", got) }
+    if got["E_RANGE_NOT_ITERABLE"] != 1 { t.Fatalf("iterable: %v", got) }
+}
+
+func TestAnalyzeLoops_RAIIAcrossIterations(t *testing.T) {
+    code := "package app\nfunc H(a Owned){}\nfunc F(o Owned, xs slice<int>){\n" +
+        "for i := range xs { release(o) }\n" +
+        "for i := range xs { H(o) }\n" +
+        "for i := range xs { release(o); break }\n" +
+        "for i := range xs { var t Owned; release(t) }\n" +
+        "for i := range xs { var u Owned }\n" +
+        "for { defer release(o) }\n}\n"
+    got := loopCodes(t, code)
+    if got["E_RAII_CONSUME_IN_LOOP"] != 2 { t.Fatalf("consume: %v", got) }
+    if got["E_RAII_LOOP_LEAK"] != 1 { t.Fatalf("leak: %v", got) }
+    if got["E_DEFER_IN_LOOP"] != 1 { t.Fatalf("defer: %v", got) }
+}
+
+// A break after an enclosing if (or match) still leaves the loop.
+func TestAnalyzeLoops_RAIIConsumeInNestedBlocks(t *testing.T) {
+    head := "package app\nfunc F(o Owned, xs slice<int>, c bool, n Optional<int>){\n"
+    leaves := head + "for { if c { release(o) }; break }\n" +
+        "for { match n { Some(v) => { release(o) }, None => { } }; break }\n}\n"
+    if got := loopCodes(t, leaves); len(got) != 0 { t.Fatalf("unexpected: %v", got) }
+    stays := head + "for i := range xs { if c { release(o) } }\n" +
+        "for i := range xs { match n { Some(v) => { release(o) }, None => { } } }\n}\n"
+    if got := loopCodes(t, stays); got["E_RAII_CONSUME_IN_LOOP"] != 2 { t.Fatalf("consume: %v", got) }
+}
+
+func TestAnalyzeLoops_MatchArms(t *testing.T) {
+    code := "package app\nfunc F(n Optional<int>){\n" +
+        "match n { Some(v) => { break }, None => { continue } }\n" +
+        "for { match n { Some(v) => { defer F(n) }, None => { break } } }\n}\n"
+    got := loopCodes(t, code)
+    if got["E_BREAK_OUTSIDE_LOOP"] != 1 || got["E_CONTINUE_OUTSIDE_LOOP"] != 1 || got["E_DEFER_IN_LOOP"] != 1 || len(got) != 3 { t.Fatalf("codes: %v", got) }
+}
+
+func TestAnalyzeLoops_MutatingRangedCollection(t *testing.T) {
+    code := "package app\nfunc F(xs slice<int>, ys slice<int>){\nfor i, x := range xs { *xs = ys; *ys = xs }\n}\n"
+    f := (&source.FileSet{}).AddFile("mut.ami", code)
+    af, _ := parser.New(f).ParseFileCollect()
+    var hits []diag.Record
+    for _, d := range AnalyzeLoops(af) { if d.Code == "E_RANGE_MUTATES_COLLECTION" { hits = append(hits, d) } }
+    if len(hits) != 1 || hits[0].Pos == nil || hits[0].Pos.Line != 3 { t.Fatalf("hits: %+v", hits) }
+}
+
+func TestAnalyzeNameResolution_LoopVariablesInScope(t *testing.T) {
+    code := "package app\nfunc G(a int){}\nfunc F(xs slice<int>){\nfor i, x := range xs { G(x); G(i); G(y) }\n}\n"
+    f := (&source.FileSet{}).AddFile("res.ami", code)
+    af, _ := parser.New(f).ParseFileCollect()
+    ds := AnalyzeNameResolution(af)
+    if len(ds) != 1 || ds[0].Message != "unresolved identifier: y" { t.Fatalf("diags: %+v", ds) }
+}
@@ -0,0 +1,229 @@
+package sem
+
+import (
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// AnalyzeMatch checks match statements and expressions against the type of
+// their subject (enum, union or Optional).
+//
+// Diagnostics:
+// - E_MATCH_NOT_EXHAUSTIVE: enum members, union alternatives, or the Some/None
+//   arms of an Optional are left unhandled and there is no `_` arm.
+// - E_MATCH_UNKNOWN_PATTERN: a pattern that does not name a member/alternative
+//   of the subject, or whose form does not fit it (e.g., Some on an enum).
+// - E_MATCH_SUBJECT_TYPE: the subject has a known type that is not matchable.
+// - E_MATCH_ARM_NOT_EXPR: an arm of a match used as a value has a block body.
+// - W_MATCH_UNREACHABLE_ARM: a pattern already covered by an earlier arm.
+func AnalyzeMatch(f *ast.File) []diag.Record {
+    var out []diag.Record
+    if f == nil { return out }
+    now := time.Unix(0, 0).UTC()
+    tr := fileTypeResolver(f)
+    emit := func(level diag.Level, code, msg string, p source.Position, data map[string]any) {
+        out = append(out, diag.Record{Timestamp: now, Level: level, Code: code, Message: msg, Pos: &diag.Position{Line: p.Line, Column: p.Column, Offset: p.Offset}, Data: data})
+    }
+    var checkMatch func(m *ast.MatchExpr, env map[string]string, asValue bool)
+    var walkExpr func(e ast.Expr, env map[string]string, asValue bool)
+    var walkBlock func(b *ast.BlockStmt, env map[string]string)
+    walkExpr = func(e ast.Expr, env map[string]string, asValue bool) {
+        switch v := e.(type) {
+        case *ast.MatchExpr:
+            checkMatch(v, env, asValue)
+        case *ast.CallExpr:
+            for _, a := range v.Args { walkExpr(a, env, true) }
+        case *ast.BinaryExpr:
+            walkExpr(v.X, env, true); walkExpr(v.Y, env, true)
+        case *ast.UnaryExpr:
+            walkExpr(v.X, env, true)
+        case *ast.ConditionalExpr:
+            walkExpr(v.Cond, env, true); walkExpr(v.Then, env, true); walkExpr(v.Else, env, true)
+        case *ast.PropagateExpr:
+            walkExpr(v.X, env, true)
+        case *ast.SliceLit:
+            for _, x := range v.Elems { walkExpr(x, env, true) }
+        case *ast.SetLit:
+            for _, x := range v.Elems { walkExpr(x, env, true) }
+        case *ast.MapLit:
+            for _, el := range v.Elems { walkExpr(el.Key, env, true); walkExpr(el.Val, env, true) }
+        }
+    }
+    walkBlock = func(b *ast.BlockStmt, env map[string]string) {
+        if b == nil { return }
+        for _, st := range b.Stmts {
+            switch v := st.(type) {
+            case *ast.ExprStmt:
+                walkExpr(v.X, env, false)
+            case *ast.VarDecl:
+                if v.Init != nil { walkExpr(v.Init, env, true) }
+            case *ast.AssignStmt:
+                walkExpr(v.Value, env, true)
+            case *ast.ReturnStmt:
+                for _, r := range v.Results { walkExpr(r, env, true) }
+            case *ast.IfStmt:
+                walkBlock(v.Then, env); walkBlock(v.Else, env)
+            case *ast.ForStmt:
+                walkBlock(v.Body, env)
+            case *ast.RangeStmt:
+                walkBlock(v.Body, env)
+            }
+        }
+    }
+    checkMatch = func(m *ast.MatchExpr, env map[string]string, asValue bool) {
+        subj := matchSubjectType(m.X, env, tr)
+        kind := matchKind(subj)
+        if kind == "" && subj != nil {
+            emit(diag.Error, "E_MATCH_SUBJECT_TYPE", "match subject must be an enum, union or Optional, got "+subj.String(), m.Pos, map[string]any{"type": subj.String()})
+        }
+        covered := map[string]bool{}
+        wild := false
+        for _, arm := range m.Arms {
+            if asValue && arm.Body != nil {
+                emit(diag.Error, "E_MATCH_ARM_NOT_EXPR", "match used as a value requires expression arms", arm.Pos, nil)
+            }
+            armEnv := env
+            for _, pat := range arm.Patterns {
+                if wild {
+                    emit(diag.Warn, "W_MATCH_UNREACHABLE_ARM", "unreachable match arm after _", pat.Pos, nil)
+                    continue
+                }
+                if pat.Kind == "wildcard" { wild = true; continue }
+                key, btype, ok := matchPatternKey(subj, kind, pat, tr)
+                if !ok {
+                    if kind != "" { emit(diag.Error, "E_MATCH_UNKNOWN_PATTERN", "pattern "+pat.Name+" does not match "+subj.String(), pat.Pos, map[string]any{"pattern": pat.Name, "type": subj.String()}) }
+                    continue
+                }
+                if covered[key] {
+                    emit(diag.Warn, "W_MATCH_UNREACHABLE_ARM", "pattern "+pat.Name+" is already handled", pat.Pos, map[string]any{"pattern": pat.Name})
+                }
+                covered[key] = true
+                if pat.Binding != "" && pat.Binding != "_" && btype != "" {
+                    if len(arm.Patterns) > 1 { btype = "any" }
+                    armEnv = copyEnv(armEnv)
+                    armEnv[pat.Binding] = btype
+                }
+            }
+            if arm.Body != nil { walkBlock(arm.Body, armEnv) }
+            if arm.Value != nil { walkExpr(arm.Value, armEnv, true) }
+        }
+        if wild { return }
+        if subj == nil {
+            // exhaustiveness cannot be checked without the subject's type
+            emit(diag.Error, "E_MATCH_SUBJECT_UNKNOWN", "match subject type is unknown; add a _ arm", m.Pos, nil)
+            return
+        }
+        if kind == "" { return }
+        var missing []string
+        for _, k := range matchCases(subj, kind) {
+            if !covered[k] { missing = append(missing, k) }
+        }
+        if len(missing) == 0 { return }
+        msg := "match is not exhaustive: missing " + strings.Join(missing, ", ")
+        if kind == "optional" && len(missing) == 1 { msg = "match is not exhaustive: missing " + missing[0] + " arm" }
+        emit(diag.Error, "E_MATCH_NOT_EXHAUSTIVE", msg, m.Pos, map[string]any{"missing": missing, "type": subj.String()})
+    }
+    for _, d := range f.Decls {
+        fn, ok := d.(*ast.FuncDecl)
+        if !ok || fn.Body == nil { continue }
+        walkBlock(fn.Body, buildLocalEnv(fn))
+    }
+    return out
+}
+
+// matchSubjectType returns the resolved type of a match subject, or nil when
+// unknown. Selecting payload on an Event<T> yields T.
+func matchSubjectType(e ast.Expr, env map[string]string, tr *types.Resolver) types.Type {
+    switch v := e.(type) {
+    case *ast.IdentExpr:
+        if t := env[v.Name]; t != "" && t != "any" {
+            if pt, err := tr.Parse(t); err == nil { return pt }
+        }
+    case *ast.SelectorExpr:
+        if base := matchSubjectType(v.X, env, tr); base != nil {
+            if g, ok := base.(types.Generic); ok && g.Name == "Event" && len(g.Args) == 1 {
+                if v.Sel == "payload" { return tr.Resolve(g.Args[0]) }
+                return nil
+            }
+            if ft, ok := tr.ResolveField(base, v.Sel); ok { return tr.Resolve(ft) }
+        }
+    }
+    return nil
+}
+
+// matchKind classifies a subject type as "enum", "union" or "optional" ("" otherwise).
+func matchKind(t types.Type) string {
+    switch t.(type) {
+    case types.Enum:
+        return "enum"
+    case types.Union:
+        return "union"
+    case types.Optional:
+        return "optional"
+    }
+    return ""
+}
+
+// matchCases lists the cases a match over t must cover, in declaration order
+// for enums and sorted order for unions.
+func matchCases(t types.Type, kind string) []string {
+    switch kind {
+    case "enum":
+        return append([]string(nil), t.(types.Enum).Members...)
+    case "union":
+        var alts []string
+        for _, a := range t.(types.Union).Alts { alts = append(alts, a.String()) }
+        sort.Strings(alts)
+        return alts
+    case "optional":
+        return []string{"Some", "None"}
+    }
+    return nil
+}
+
+// matchPatternKey maps a pattern onto the case it covers and the type of its
+// binding, if any. ok is false when the pattern does not fit the subject.
+func matchPatternKey(t types.Type, kind string, pat ast.MatchPattern, tr *types.Resolver) (key, binding string, ok bool) {
+    switch kind {
+    case "enum":
+        if pat.Kind != "name" { return "", "", false }
+        e := t.(types.Enum)
+        name := pat.Name
+        if i := strings.LastIndexByte(name, '.'); i > 0 {
+            q := name[:i]
+            if q != e.Name && !strings.HasSuffix(e.Name, "."+q) { return "", "", false }
+            name = name[i+1:]
+        }
+        for _, m := range e.Members {
+            if m == name { return m, "", true }
+        }
+    case "union":
+        if pat.Kind != "name" && pat.Kind != "variant" { return "", "", false }
+        pt, err := tr.Parse(pat.Name)
+        if err != nil { return "", "", false }
+        for _, a := range t.(types.Union).Alts {
+            if types.Equal(a, pt) { return a.String(), a.String(), true }
+        }
+    case "optional":
+        switch pat.Kind {
+        case "some":
+            return "Some", t.(types.Optional).Inner.String(), true
+        case "none":
+            return "None", "", true
+        }
+    }
+    return "", "", false
+}
+
+// copyEnv returns a shallow copy of a local type environment.
+func copyEnv(env map[string]string) map[string]string {
+    out := make(map[string]string, len(env)+1)
+    for k, v := range env { out[k] = v }
+    return out
+}
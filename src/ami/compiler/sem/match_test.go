@@ -0,0 +1,109 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func matchCodes(t *testing.T, code string) map[string]int {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("match.ami", code)
+    af, err := parser.New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    got := map[string]int{}
+    for _, d := range AnalyzeMatch(af) { got[d.Code]++ }
+    return got
+}
+
+func TestAnalyzeMatch_Exhaustive(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green, Blue }\n" +
+        "type Shape = Union<int,string>\n" +
+        "func F(c Color, s Shape, o Optional<int>) (int) {\n" +
+        "  var n = match c { Color.Red => 1, Green, Blue => 2 }\n" +
+        "  match s { int(i) => { n = i }, string(x) => { n = 0 } }\n" +
+        "  match o { Some(v) => { n = v }, None => { n = 0 } }\n" +
+        "  return n\n" +
+        "}\n"
+    if got := matchCodes(t, code); len(got) != 0 { t.Fatalf("unexpected diags: %v", got) }
+}
+
+func TestAnalyzeMatch_Missing(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green, Blue }\n" +
+        "func F(c Color, u Union<int,string>, o Optional<int>) {\n" +
+        "  match c { Red => { } }\n" +
+        "  match u { int(i) => { } }\n" +
+        "  match o { Some(v) => { } }\n" +
+        "  match c { _ => { } }\n" +
+        "}\n"
+    got := matchCodes(t, code)
+    if got["E_MATCH_NOT_EXHAUSTIVE"] != 3 || len(got) != 1 { t.Fatalf("codes: %v", got) }
+}
+
+func TestAnalyzeMatch_PatternErrors(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green }\n" +
+        "func F(c Color, n int) {\n" +
+        "  match c { Purple => { }, Some(x) => { }, Red => { }, Red => { }, _ => { }, Green => { } }\n" +
+        "  match n { _ => { } }\n" +
+        "  var x = match c { Red => { }, _ => 0 }\n" +
+        "}\n"
+    got := matchCodes(t, code)
+    if got["E_MATCH_UNKNOWN_PATTERN"] != 2 || got["W_MATCH_UNREACHABLE_ARM"] != 2 || got["E_MATCH_SUBJECT_TYPE"] != 1 || got["E_MATCH_ARM_NOT_EXPR"] != 1 {
+        t.Fatalf("codes: %v", got)
+    }
+}
+
+func TestAnalyzeNameResolution_MatchBindings(t *testing.T) {
+    code := "package app\n" +
+        "func F(o Optional<int>) (int) {\n" +
+        "  var n = match o { Some(v) => v, None => missing }\n" +
+        "  return n\n" +
+        "}\n"
+    f := (&source.FileSet{}).AddFile("match.ami", code)
+    af, err := parser.New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    ds := AnalyzeNameResolution(af)
+    if len(ds) != 1 || ds[0].Message != "unresolved identifier: missing" { t.Fatalf("diags: %+v", ds) }
+}
+
+func TestAnalyzeMatch_EventPayloadSubjects(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green, Blue }\n" +
+        "type Payload struct { color Color; note Optional<string> }\n" +
+        "func F(ev Event<Payload>) (string) {\n" +
+        "  match ev.payload.color { Red => { }, Green => { } }\n" +
+        "  match ev.payload.note { Some(v) => { } }\n" +
+        "  match ev.payload.color { Purple => { }, _ => { } }\n" +
+        "  return match ev.payload.note { Some(v) => v, None => \"\" }\n" +
+        "}\n"
+    got := matchCodes(t, code)
+    if got["E_MATCH_NOT_EXHAUSTIVE"] != 2 || got["E_MATCH_UNKNOWN_PATTERN"] != 1 || len(got) != 2 { t.Fatalf("codes: %v", got) }
+}
+
+func TestAnalyzeMatch_NestedInExpressions(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green, Blue }\n" +
+        "func G(n int) (int) { return n }\n" +
+        "func F(c Color) (int) {\n" +
+        "  G(match c { Red => 1 })\n" +
+        "  var n = 1 + match c { Red => 1, Green => 2 }\n" +
+        "  return match c { Red => match c { Blue => 1, _ => 2 }, _ => match c { Red => 3 } }\n" +
+        "}\n"
+    got := matchCodes(t, code)
+    if got["E_MATCH_NOT_EXHAUSTIVE"] != 3 || len(got) != 1 { t.Fatalf("codes: %v", got) }
+}
+
+func TestAnalyzeMatch_UnknownSubjectNeedsWildcard(t *testing.T) {
+    code := "package app\n" +
+        "func G() (any) { return 0 }\n" +
+        "func F(x any) {\n" +
+        "  match G() { Some(v) => { } }\n" +
+        "  match x { None => { }, _ => { } }\n" +
+        "}\n"
+    got := matchCodes(t, code)
+    if got["E_MATCH_SUBJECT_UNKNOWN"] != 1 || len(got) != 1 { t.Fatalf("codes: %v", got) }
+}
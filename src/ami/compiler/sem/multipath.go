@@ -29,6 +29,13 @@ func AnalyzeMultiPath(f *ast.File) []diag.Record {
         "merge.Timeout":    {1, 1},
         "merge.Buffer":     {1, 2},
         "merge.PartitionBy":{1, 1},
+        "merge.Tumbling":   {1, 1},
+        "merge.Sliding":    {2, 2},
+        "merge.Session":    {1, 1},
+        "merge.Aggregate":  {1, 3},
+        "merge.DedupTTL":   {1, 1},
+        "merge.DedupLimit": {1, 1},
+        "merge.DedupBloom": {0, 1},
     }
     for _, d := range f.Decls {
         pd, ok := d.(*ast.PipelineDecl)
@@ -53,6 +60,10 @@ func AnalyzeMultiPath(f *ast.File) []diag.Record {
             hasStable := false
             hasWindow := false
             hasWatermark := false
+            eventWindow := ""
+            hasAggregate := false
+            hasDedup := false
+            dedupOption := ""
             dedupNoField := false
             var sortFields []string
             watermarkField := ""
@@ -135,6 +146,39 @@ func AnalyzeMultiPath(f *ast.File) []diag.Record {
                                 }
                             }
                             hasWindow = true
+                        case "merge.Tumbling", "merge.Sliding", "merge.Session":
+                            for _, a := range at.Args {
+                                if !validWindowDuration(a.Text) {
+                                    out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_ATTR_TYPE", Message: at.Name + ": must be a positive duration (e.g., 500ms,1s,5m)", Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"value": strings.TrimSpace(a.Text)}})
+                                }
+                            }
+                            if eventWindow != "" && eventWindow != at.Name {
+                                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_ATTR_CONFLICT", Message: at.Name + " conflicts with " + eventWindow, Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"prev": eventWindow, "value": at.Name}})
+                            }
+                            eventWindow = at.Name
+                        case "merge.Aggregate":
+                            if !validAggregateFn(at.Args[0].Text) {
+                                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_ATTR_ARGS", Message: "merge.Aggregate: function must be one of count,sum,min,max,avg,first,last", Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"fn": strings.TrimSpace(at.Args[0].Text)}})
+                            }
+                            if argc >= 2 && !validFieldName(at.Args[1].Text) {
+                                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_FIELD_NAME_INVALID", Message: "merge.Aggregate: invalid field name", Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"field": at.Args[1].Text}})
+                            }
+                            hasAggregate = true
+                        case "merge.DedupTTL":
+                            if !validWindowDuration(at.Args[0].Text) {
+                                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_ATTR_TYPE", Message: "merge.DedupTTL: must be a positive duration (e.g., 500ms,1s,5m)", Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"value": strings.TrimSpace(at.Args[0].Text)}})
+                            }
+                            dedupOption = at.Name
+                        case "merge.DedupLimit":
+                            if !validPositiveInt(at.Args[0].Text) {
+                                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_ATTR_TYPE", Message: "merge.DedupLimit: must be a positive integer", Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"value": strings.TrimSpace(at.Args[0].Text)}})
+                            }
+                            dedupOption = at.Name
+                        case "merge.DedupBloom":
+                            if argc >= 1 && !validFPRate(at.Args[0].Text) {
+                                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_ATTR_TYPE", Message: "merge.DedupBloom: false-positive rate must be a number in (0,1)", Pos: &diag.Position{Line: at.Pos.Line, Column: at.Pos.Column, Offset: at.Pos.Offset}, Data: map[string]any{"value": strings.TrimSpace(at.Args[0].Text)}})
+                            }
+                            dedupOption = at.Name
                         case "merge.Timeout":
                             if argc >= 1 {
                                 ms := strings.TrimSpace(at.Args[0].Text)
@@ -186,7 +230,10 @@ func AnalyzeMultiPath(f *ast.File) []diag.Record {
                         if at.Name == "merge.Key" && argc >= 1 { keyField = at.Args[0].Text }
                         if at.Name == "merge.PartitionBy" && argc >= 1 { partitionField = at.Args[0].Text }
                         if at.Name == "merge.Dedup" && argc == 0 { dedupNoField = true }
+                        if at.Name == "merge.Dedup" { hasDedup = true }
                         // conflict detection on repeated attributes with differing normalized value
+                        // merge.Aggregate is additive: each occurrence adds an output column.
+                        if at.Name == "merge.Aggregate" { continue }
                         key := at.Name
                         val := canonicalAttrValue(at.Name, at.Args)
                         if prev, ok := seen[key]; ok {
@@ -235,6 +282,21 @@ func AnalyzeMultiPath(f *ast.File) []diag.Record {
                 p := stepPos(st)
                 out = append(out, diag.Record{Timestamp: now, Level: diag.Warn, Code: "W_MERGE_WINDOW_WITHOUT_WATERMARK", Message: "merge.Window without Watermark may be based on processing time", Pos: &p, Data: map[string]any{"window": true}})
             }
+            // event-time windows close on the watermark, so one is required
+            if eventWindow != "" && !hasWatermark {
+                p := stepPos(st)
+                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_MERGE_EVENT_WINDOW_WITHOUT_WATERMARK", Message: eventWindow + " requires merge.Watermark(field, lateness)", Pos: &p, Data: map[string]any{"window": eventWindow}})
+            }
+            // dedup horizon/mode settings only apply when dedup is enabled; the
+            // runtime dedups on merge.Key when merge.Dedup is absent
+            if dedupOption != "" && !hasDedup && keyField == "" {
+                p := stepPos(st)
+                out = append(out, diag.Record{Timestamp: now, Level: diag.Warn, Code: "W_MERGE_DEDUP_OPTION_WITHOUT_DEDUP", Message: dedupOption + " has no effect without merge.Dedup or merge.Key", Pos: &p, Data: map[string]any{"option": dedupOption}})
+            }
+            if hasAggregate && eventWindow == "" {
+                p := stepPos(st)
+                out = append(out, diag.Record{Timestamp: now, Level: diag.Warn, Code: "W_MERGE_AGGREGATE_WITHOUT_EVENT_WINDOW", Message: "merge.Aggregate has no effect without merge.Tumbling, merge.Sliding or merge.Session", Pos: &p, Data: map[string]any{"aggregate": true}})
+            }
             // watermark field not used as primary sort
             if hasWatermark && hasSort {
                 primary := ""
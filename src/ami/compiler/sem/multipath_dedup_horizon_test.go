@@ -0,0 +1,23 @@
+package sem
+
+import "testing"
+
+func TestMerge_DedupHorizon_Valid(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Dedup(\"id\"), merge.DedupTTL(\"10m\"), merge.DedupLimit(100000), merge.DedupBloom(0.001)")
+    if len(codes) != 0 { t.Fatalf("unexpected diags: %v", codes) }
+}
+
+func TestMerge_DedupHorizon_InvalidValues(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Dedup(\"id\"), merge.DedupTTL(\"0s\"), merge.DedupLimit(-1), merge.DedupBloom(1.5)")
+    if codes["E_MERGE_ATTR_TYPE"] != 3 { t.Fatalf("want 3 type errors: %v", codes) }
+}
+
+func TestMerge_DedupHorizon_WithoutDedupWarns(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.DedupTTL(\"1h\")")
+    if codes["W_MERGE_DEDUP_OPTION_WITHOUT_DEDUP"] != 1 { t.Fatalf("codes: %v", codes) }
+}
+
+func TestMerge_DedupHorizon_KeyEnablesDedup(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Key(\"id\"), merge.DedupTTL(\"1h\"), merge.DedupLimit(1000)")
+    if codes["W_MERGE_DEDUP_OPTION_WITHOUT_DEDUP"] != 0 { t.Fatalf("codes: %v", codes) }
+}
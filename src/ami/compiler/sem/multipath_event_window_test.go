@@ -0,0 +1,40 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func analyzeMergeCodes(t *testing.T, step string) map[string]int {
+    t.Helper()
+    code := "package app\npipeline P(){ ingress; " + step + "; egress }\n"
+    f := (&source.FileSet{}).AddFile("mp_ew.ami", code)
+    af, _ := parser.New(f).ParseFile()
+    codes := map[string]int{}
+    for _, d := range AnalyzeMultiPath(af) { codes[d.Code]++ }
+    return codes
+}
+
+func TestMerge_EventWindow_Valid(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Watermark(\"ts\", \"1s\"), merge.Tumbling(\"1m\"), merge.Aggregate(\"count\"), merge.Aggregate(\"sum\", \"v\")")
+    if len(codes) != 0 { t.Fatalf("unexpected diags: %v", codes) }
+}
+
+func TestMerge_EventWindow_RequiresWatermark(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Session(\"30s\")")
+    if codes["E_MERGE_EVENT_WINDOW_WITHOUT_WATERMARK"] != 1 { t.Fatalf("codes: %v", codes) }
+}
+
+func TestMerge_EventWindow_InvalidDurationAndConflict(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Watermark(\"ts\", \"1s\"), merge.Sliding(\"10s\", \"0s\"), merge.Tumbling(\"x\")")
+    if codes["E_MERGE_ATTR_TYPE"] != 2 { t.Fatalf("want 2 type errors: %v", codes) }
+    if codes["E_MERGE_ATTR_CONFLICT"] != 1 { t.Fatalf("want conflict: %v", codes) }
+}
+
+func TestMerge_Aggregate_Validation(t *testing.T) {
+    codes := analyzeMergeCodes(t, "Collect merge.Aggregate(\"median\", \"v\")")
+    if codes["E_MERGE_ATTR_ARGS"] != 1 { t.Fatalf("want bad fn: %v", codes) }
+    if codes["W_MERGE_AGGREGATE_WITHOUT_EVENT_WINDOW"] != 1 { t.Fatalf("want no-window warning: %v", codes) }
+}
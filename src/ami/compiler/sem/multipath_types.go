@@ -41,9 +41,12 @@ func AnalyzeMergeFieldTypes(f *ast.File) []diag.Record {
         }
     }
 
+    // declared payload types (type Name struct {...}) resolve to their structure
+    tr := fileTypeResolver(f)
+
     // helper to detect if Type is Event<primitive>
     isEventOfPrimitive := func(ts string) bool {
-        ty, err := types.Parse(ts)
+        ty, err := tr.Parse(ts)
         if err != nil { return false }
         if g, ok := ty.(types.Generic); ok && g.Name == "Event" && len(g.Args) == 1 {
             switch a := g.Args[0].(type) {
@@ -61,7 +64,7 @@ func AnalyzeMergeFieldTypes(f *ast.File) []diag.Record {
     fieldType := func(ts, field string) (types.Type, bool) {
         ty, err := types.Parse(ts)
         if err != nil { return nil, false }
-        return types.ResolveField(ty, field)
+        return tr.ResolveField(ty, field)
     }
 
     // For each Collect, find upstreams and validate fields
@@ -0,0 +1,39 @@
+package sem
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+
+// ownedConsumedIn reports whether variable `name` is released, transferred to an
+// Owned parameter, or returned anywhere within block b (including nested blocks).
+func ownedConsumedIn(b *ast.BlockStmt, name string, params map[string][]string) bool {
+    if b == nil { return false }
+    call := func(c *ast.CallExpr) bool {
+        if c == nil { return false }
+        if n, _ := releaseTargetFromCall(c); n == name { return true }
+        sig := params[c.Name]
+        for i, a := range c.Args {
+            if id, ok := a.(*ast.IdentExpr); ok && id.Name == name && i < len(sig) && isOwnedTypeText(sig[i]) { return true }
+        }
+        return false
+    }
+    for _, st := range b.Stmts {
+        switch v := st.(type) {
+        case *ast.ExprStmt:
+            if c, ok := v.X.(*ast.CallExpr); ok && call(c) { return true }
+        case *ast.AssignStmt:
+            if c, ok := v.Value.(*ast.CallExpr); ok && call(c) { return true }
+        case *ast.DeferStmt:
+            if call(v.Call) { return true }
+        case *ast.ReturnStmt:
+            for _, e := range v.Results {
+                if id, ok := e.(*ast.IdentExpr); ok && id.Name == name { return true }
+            }
+        case *ast.IfStmt:
+            if ownedConsumedIn(v.Then, name, params) || ownedConsumedIn(v.Else, name, params) { return true }
+        case *ast.ForStmt:
+            if ownedConsumedIn(v.Body, name, params) { return true }
+        case *ast.RangeStmt:
+            if ownedConsumedIn(v.Body, name, params) { return true }
+        }
+    }
+    return false
+}
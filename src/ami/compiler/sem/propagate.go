@@ -0,0 +1,119 @@
+package sem
+
+import (
+    "strings"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// AnalyzePropagation validates uses of the error propagation operator (x?).
+// resultSigs supplies result types for calls not declared in f (e.g., imported
+// functions); calls with unknown signatures are accepted.
+//
+// Diagnostics:
+// - E_PROPAGATE_NO_ERROR_RESULT: the enclosing function's last result is not error.
+// - E_PROPAGATE_OPERAND: the operand is not a call, or the callee's last result is not error.
+// - E_PROPAGATE_POSITION: x? appears other than as `var v = x?`, `v = x?` or a statement `x?`.
+func AnalyzePropagation(f *ast.File, resultSigs map[string][]string) []diag.Record {
+    var out []diag.Record
+    if f == nil { return out }
+    now := time.Unix(0, 0).UTC()
+    emit := func(code, msg string, p source.Position, data map[string]any) {
+        out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: code, Message: msg, Pos: &diag.Position{Line: p.Line, Column: p.Column, Offset: p.Offset}, Data: data})
+    }
+    results := map[string][]string{}
+    for k, v := range resultSigs { results[k] = v }
+    for _, d := range f.Decls {
+        if fn, ok := d.(*ast.FuncDecl); ok && fn.Name != "" {
+            var rs []string
+            for _, r := range fn.Results { rs = append(rs, r.Type) }
+            results[fn.Name] = rs
+        }
+    }
+    for _, d := range f.Decls {
+        fn, ok := d.(*ast.FuncDecl)
+        if !ok || fn.Body == nil { continue }
+        errResult := len(fn.Results) > 0 && strings.TrimSpace(fn.Results[len(fn.Results)-1].Type) == "error"
+        check := func(p *ast.PropagateExpr) {
+            if !errResult {
+                emit("E_PROPAGATE_NO_ERROR_RESULT", "? requires the enclosing function to return error as its last result", p.Pos, map[string]any{"function": fn.Name})
+            }
+            c, ok := p.X.(*ast.CallExpr)
+            if !ok {
+                emit("E_PROPAGATE_OPERAND", "? applies to a call returning error", p.Pos, nil)
+                return
+            }
+            if rs, ok := results[c.Name]; ok && (len(rs) == 0 || strings.TrimSpace(rs[len(rs)-1]) != "error") {
+                emit("E_PROPAGATE_OPERAND", "? applies to a call returning error: "+c.Name, p.Pos, map[string]any{"callee": c.Name})
+            }
+        }
+        // misplaced reports propagation nested anywhere inside e.
+        var misplaced func(e ast.Expr)
+        var walk func(b *ast.BlockStmt)
+        misplaced = func(e ast.Expr) {
+            switch v := e.(type) {
+            case *ast.PropagateExpr:
+                emit("E_PROPAGATE_POSITION", "? must be the whole right-hand side of a var, assignment or expression statement", v.Pos, nil)
+                misplaced(v.X)
+            case *ast.CallExpr:
+                for _, a := range v.Args { misplaced(a) }
+            case *ast.BinaryExpr:
+                misplaced(v.X); misplaced(v.Y)
+            case *ast.UnaryExpr:
+                misplaced(v.X)
+            case *ast.ConditionalExpr:
+                misplaced(v.Cond); misplaced(v.Then); misplaced(v.Else)
+            case *ast.SliceLit:
+                for _, a := range v.Elems { misplaced(a) }
+            case *ast.SetLit:
+                for _, a := range v.Elems { misplaced(a) }
+            case *ast.MapLit:
+                for _, kv := range v.Elems { misplaced(kv.Key); misplaced(kv.Val) }
+            case *ast.MatchExpr:
+                misplaced(v.X)
+                for _, arm := range v.Arms {
+                    walk(arm.Body)
+                    if arm.Value != nil { misplaced(arm.Value) }
+                }
+            }
+        }
+        // top accepts x? as the whole statement value and checks the rest for misplacement.
+        top := func(e ast.Expr) {
+            if p, ok := e.(*ast.PropagateExpr); ok {
+                check(p)
+                if c, ok := p.X.(*ast.CallExpr); ok {
+                    for _, a := range c.Args { misplaced(a) }
+                }
+                return
+            }
+            misplaced(e)
+        }
+        walk = func(b *ast.BlockStmt) {
+            if b == nil { return }
+            for _, st := range b.Stmts {
+                switch v := st.(type) {
+                case *ast.VarDecl:
+                    if v.Init != nil { top(v.Init) }
+                case *ast.AssignStmt:
+                    top(v.Value)
+                case *ast.ExprStmt:
+                    top(v.X)
+                case *ast.ReturnStmt:
+                    for _, r := range v.Results { misplaced(r) }
+                case *ast.IfStmt:
+                    misplaced(v.Cond); walk(v.Then); walk(v.Else)
+                case *ast.ForStmt:
+                    if v.Cond != nil { misplaced(v.Cond) }
+                    walk(v.Body)
+                case *ast.RangeStmt:
+                    misplaced(v.X); walk(v.Body)
+                }
+            }
+        }
+        walk(fn.Body)
+    }
+    return out
+}
@@ -0,0 +1,50 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+)
+
+func propagateCodes(t *testing.T, code string, sigs map[string][]string) map[string]int {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("prop.ami", code)
+    af, err := parser.New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    got := map[string]int{}
+    for _, d := range AnalyzePropagation(af, sigs) { got[d.Code]++ }
+    return got
+}
+
+func TestAnalyzePropagation_Valid(t *testing.T) {
+    code := "package app\n" +
+        "func parse(s string) (int, error) { return 0, nil }\n" +
+        "func W(ev Event<string>) (Event<int>, error) {\n" +
+        "  var n = parse(ev.payload)?\n" +
+        "  n = parse(ev.payload)?\n" +
+        "  errors.New(\"E_X\", \"x\")?\n" +
+        "  return ev, nil\n" +
+        "}\n"
+    if got := propagateCodes(t, code, map[string][]string{"errors.New": {"error"}}); len(got) != 0 { t.Fatalf("unexpected diags: %v", got) }
+}
+
+func TestAnalyzePropagation_Errors(t *testing.T) {
+    code := "package app\n" +
+        "func parse(s string) (int, error) { return 0, nil }\n" +
+        "func size(s string) (int) { return 0 }\n" +
+        "func F(s string) (int) {\n" +
+        "  var n = parse(s)?\n" +
+        "  return n\n" +
+        "}\n" +
+        "func G(s string) (int, error) {\n" +
+        "  var a = size(s)?\n" +
+        "  var b = parse(s)? + 1\n" +
+        "  size(parse(s)?)\n" +
+        "  return a, nil\n" +
+        "}\n"
+    got := propagateCodes(t, code, nil)
+    if got["E_PROPAGATE_NO_ERROR_RESULT"] != 1 || got["E_PROPAGATE_OPERAND"] != 1 || got["E_PROPAGATE_POSITION"] != 2 {
+        t.Fatalf("codes: %v", got)
+    }
+}
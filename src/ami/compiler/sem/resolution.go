@@ -41,16 +41,48 @@ func AnalyzeNameResolution(f *ast.File) []diag.Record {
         for _, p := range fn.Params { if p.Name != "" { env[p.Name] = true } }
         // allow referencing any top-level func names in this file
         for n := range topFuncs { env[n] = true }
-        // Gather var decls
-        for _, st := range fn.Body.Stmts {
-            if vd, ok := st.(*ast.VarDecl); ok && vd.Name != "" { env[vd.Name] = true }
+        // Gather var decls, loop variables and match bindings, including those in nested blocks
+        var gather func(b *ast.BlockStmt)
+        gatherMatch := func(e ast.Expr) {
+            m, ok := e.(*ast.MatchExpr)
+            if !ok { return }
+            for _, arm := range m.Arms {
+                for _, p := range arm.Patterns { if p.Binding != "" { env[p.Binding] = true } }
+                gather(arm.Body)
+            }
         }
+        gather = func(b *ast.BlockStmt) {
+            if b == nil { return }
+            for _, st := range b.Stmts {
+                switch v := st.(type) {
+                case *ast.VarDecl:
+                    if v.Name != "" { env[v.Name] = true }
+                    gatherMatch(v.Init)
+                case *ast.ExprStmt:
+                    gatherMatch(v.X)
+                case *ast.AssignStmt:
+                    gatherMatch(v.Value)
+                case *ast.ReturnStmt:
+                    for _, e := range v.Results { gatherMatch(e) }
+                case *ast.IfStmt:
+                    gather(v.Then); gather(v.Else)
+                case *ast.ForStmt:
+                    gather(v.Body)
+                case *ast.RangeStmt:
+                    if v.Key != "" { env[v.Key] = true }
+                    if v.Value != "" { env[v.Value] = true }
+                    gather(v.Body)
+                }
+            }
+        }
+        gather(fn.Body)
         // Walk expressions to find unresolved idents
         var walkExpr func(e ast.Expr)
+        var walkBlock func(b *ast.BlockStmt)
         walkExpr = func(e ast.Expr) {
             switch v := e.(type) {
             case *ast.IdentExpr:
-                if v.Name != "" && !env[v.Name] {
+                if v.Name != "" && v.Name != "nil" && !env[v.Name] {
                     if _, ok := imports[v.Name]; ok { return }
                     out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_UNRESOLVED_IDENT", Message: "unresolved identifier: " + v.Name, Pos: &diag.Position{Line: v.Pos.Line, Column: v.Pos.Column, Offset: v.Pos.Offset}})
                 }
@@ -64,21 +96,42 @@ func AnalyzeNameResolution(f *ast.File) []diag.Record {
                 for _, a := range v.Elems { walkExpr(a) }
             case *ast.MapLit:
                 for _, kv := range v.Elems { walkExpr(kv.Key); walkExpr(kv.Val) }
+            case *ast.PropagateExpr:
+                walkExpr(v.X)
+            case *ast.InterpStringLit:
+                for _, x := range v.Exprs { walkExpr(x) }
+            case *ast.MatchExpr:
+                walkExpr(v.X)
+                for _, arm := range v.Arms {
+                    walkBlock(arm.Body)
+                    if arm.Value != nil { walkExpr(arm.Value) }
+                }
             }
         }
-        // Apply over statements
-        for _, st := range fn.Body.Stmts {
-            switch v := st.(type) {
-            case *ast.AssignStmt:
-                walkExpr(v.Value)
-            case *ast.ReturnStmt:
-                for _, e := range v.Results { walkExpr(e) }
-            case *ast.ExprStmt:
-                walkExpr(v.X)
-            case *ast.VarDecl:
-                if v.Init != nil { walkExpr(v.Init) }
+        // Apply over statements, descending into if, loop and match arm bodies
+        walkBlock = func(b *ast.BlockStmt) {
+            if b == nil { return }
+            for _, st := range b.Stmts {
+                switch v := st.(type) {
+                case *ast.AssignStmt:
+                    walkExpr(v.Value)
+                case *ast.ReturnStmt:
+                    for _, e := range v.Results { walkExpr(e) }
+                case *ast.ExprStmt:
+                    walkExpr(v.X)
+                case *ast.VarDecl:
+                    if v.Init != nil { walkExpr(v.Init) }
+                case *ast.IfStmt:
+                    walkExpr(v.Cond); walkBlock(v.Then); walkBlock(v.Else)
+                case *ast.ForStmt:
+                    if v.Cond != nil { walkExpr(v.Cond) }
+                    walkBlock(v.Body)
+                case *ast.RangeStmt:
+                    walkExpr(v.X); walkBlock(v.Body)
+                }
             }
         }
+        walkBlock(fn.Body)
     }
     return out
 }
@@ -0,0 +1,187 @@
+package sem
+
+import (
+    "strings"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// AnalyzeTypeDecls validates package-level type declarations:
+// - E_TYPE_REDECLARED: a type or enum name declared more than once in the file
+// - E_STRUCT_FIELD_DUPLICATE: a struct declares the same field twice
+// - E_TYPE_UNKNOWN: a bare type name that is neither builtin nor declared, in a
+//   declaration, a function signature or a pipeline type("...") attribute
+// - E_TYPE_NOT_EXPORTED: a reference to an unexported type of another package
+// - E_TYPE_CYCLE: a declaration contains itself without indirection through
+//   Optional, a container, or a pointer (alias cycles included)
+func AnalyzeTypeDecls(f *ast.File) []diag.Record {
+    var out []diag.Record
+    if f == nil { return out }
+    now := time.Unix(0, 0).UTC()
+    tr := fileTypeResolver(f)
+    at := func(p source.Position) *diag.Position { return &diag.Position{Line: p.Line, Column: p.Column, Offset: p.Offset} }
+    seen := map[string]bool{}
+    var decls []*ast.TypeDecl
+    for _, d := range f.Decls {
+        name := ""
+        var pos source.Position
+        switch v := d.(type) {
+        case *ast.TypeDecl:
+            name, pos = v.Name, v.NamePos
+            decls = append(decls, v)
+        case *ast.EnumDecl:
+            name, pos = v.Name, v.NamePos
+        default:
+            continue
+        }
+        if seen[name] {
+            out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_TYPE_REDECLARED", Message: "type redeclared: " + name, Pos: at(pos), Data: map[string]any{"type": name}})
+        }
+        seen[name] = true
+    }
+    // fields and references
+    // local names (type parameters) are skipped
+    checkRefs := func(text string, pos source.Position, local map[string]bool) {
+        t, err := types.Parse(text)
+        if err != nil { return }
+        for _, ref := range typeRefNames(t) {
+            if local[ref] { continue }
+            if strings.Contains(ref, ".") {
+                if tr.Declared(ref) {
+                    if _, ok := tr.Lookup(ref); !ok {
+                        out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_TYPE_NOT_EXPORTED", Message: "type not exported by its package: " + ref, Pos: at(pos), Data: map[string]any{"type": ref}})
+                    }
+                }
+                continue
+            }
+            if builtinTypeName(ref) || tr.Declared(ref) { continue }
+            out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_TYPE_UNKNOWN", Message: "unknown type: " + ref, Pos: at(pos), Data: map[string]any{"type": ref}})
+        }
+    }
+    byName := map[string]*ast.TypeDecl{}
+    for _, td := range decls {
+        if _, ok := byName[td.Name]; !ok { byName[td.Name] = td }
+        if len(td.Fields) == 0 {
+            checkRefs(td.Type, td.TypePos, nil)
+            continue
+        }
+        fields := map[string]bool{}
+        for _, fd := range td.Fields {
+            if fields[fd.Name] {
+                out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_STRUCT_FIELD_DUPLICATE", Message: "duplicate field " + fd.Name + " in " + td.Name, Pos: at(fd.NamePos), Data: map[string]any{"type": td.Name, "field": fd.Name}})
+            }
+            fields[fd.Name] = true
+            checkRefs(fd.Type, fd.TypePos, nil)
+        }
+    }
+    // function signatures and pipeline step types
+    for _, d := range f.Decls {
+        switch v := d.(type) {
+        case *ast.FuncDecl:
+            tps := map[string]bool{}
+            for _, tp := range v.TypeParams { tps[tp.Name] = true }
+            if v.RecvType != "" { checkRefs(v.RecvType, v.RecvTypePos, tps) }
+            for _, p := range v.Params { checkRefs(p.Type, p.TypePos, tps) }
+            for _, r := range v.Results { checkRefs(r.Type, r.TypePos, tps) }
+        case *ast.PipelineDecl:
+            stmts := v.Stmts
+            if v.Error != nil && v.Error.Body != nil { stmts = append(append([]ast.Stmt{}, stmts...), v.Error.Body.Stmts...) }
+            for _, s := range stmts {
+                st, ok := s.(*ast.StepStmt)
+                if !ok { continue }
+                for _, a := range st.Attrs {
+                    if (a.Name != "type" && a.Name != "Type") || len(a.Args) == 0 { continue }
+                    checkRefs(strings.Trim(a.Args[0].Text, "\"'"), a.Args[0].Pos, nil)
+                }
+            }
+        }
+    }
+    // cycles: follow direct (non-indirected) references between local declarations
+    for _, td := range decls {
+        if byName[td.Name] != td { continue }
+        if typeDeclReaches(byName, f.PackageName, td.Name, td.Name, map[string]bool{}) {
+            out = append(out, diag.Record{Timestamp: now, Level: diag.Error, Code: "E_TYPE_CYCLE", Message: "type " + td.Name + " contains itself; use Optional<" + td.Name + "> or a container to break the cycle", Pos: at(td.NamePos), Data: map[string]any{"type": td.Name}})
+        }
+    }
+    return out
+}
+
+// typeDeclReaches reports whether the declaration named from directly embeds target.
+func typeDeclReaches(byName map[string]*ast.TypeDecl, pkg, from, target string, visited map[string]bool) bool {
+    td := byName[from]
+    if td == nil || visited[from] { return false }
+    visited[from] = true
+    t, err := types.Parse(td.Type)
+    if err != nil { return false }
+    for _, ref := range directTypeRefs(t) {
+        ref = strings.TrimPrefix(ref, pkg+".")
+        if ref == target { return true }
+        if typeDeclReaches(byName, pkg, ref, target, visited) { return true }
+    }
+    return false
+}
+
+// directTypeRefs lists names a type embeds by value: struct fields and union
+// alternatives are followed; Optional, pointers, and generic containers are not.
+func directTypeRefs(t types.Type) []string {
+    switch v := t.(type) {
+    case types.Named:
+        return []string{v.Name}
+    case types.Generic:
+        if len(v.Args) == 0 { return []string{v.Name} }
+    case types.Struct:
+        var out []string
+        for _, ft := range v.Fields { out = append(out, directTypeRefs(ft)...) }
+        return out
+    case types.Union:
+        var out []string
+        for _, a := range v.Alts { out = append(out, directTypeRefs(a)...) }
+        return out
+    }
+    return nil
+}
+
+// typeRefNames lists every bare or qualified type name referenced by t.
+func typeRefNames(t types.Type) []string {
+    switch v := t.(type) {
+    case types.Named:
+        return []string{v.Name}
+    case types.Generic:
+        if len(v.Args) == 0 {
+            // bracketed slice/pointer forms are kept as text by types.Parse
+            n := strings.TrimLeft(v.Name, "[]*")
+            if n != v.Name {
+                if inner, err := types.Parse(n); err == nil { return typeRefNames(inner) }
+            }
+            return []string{n}
+        }
+        var out []string
+        for _, a := range v.Args { out = append(out, typeRefNames(a)...) }
+        return out
+    case types.Struct:
+        var out []string
+        for _, ft := range v.Fields { out = append(out, typeRefNames(ft)...) }
+        return out
+    case types.Optional:
+        return typeRefNames(v.Inner)
+    case types.Union:
+        var out []string
+        for _, a := range v.Alts { out = append(out, typeRefNames(a)...) }
+        return out
+    }
+    return nil
+}
+
+// builtinTypeName reports whether a bare type name is provided by the language.
+func builtinTypeName(n string) bool {
+    if prim(n) { return true }
+    switch n {
+    case "any", "error", "Struct", "Event", "Error", "Owned", "Duration", "Time", "Ticker", "Signal":
+        return true
+    }
+    return false
+}
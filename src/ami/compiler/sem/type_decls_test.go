@@ -0,0 +1,89 @@
+package sem
+
+import (
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/parser"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+func typeDeclCodes(t *testing.T, code string) map[string]int {
+    t.Helper()
+    f := (&source.FileSet{}).AddFile("types.ami", code)
+    af, err := parser.New(f).ParseFile()
+    if err != nil { t.Fatalf("parse: %v", err) }
+    got := map[string]int{}
+    for _, d := range AnalyzeTypeDecls(af) { got[d.Code]++ }
+    return got
+}
+
+func TestAnalyzeTypeDecls_Valid(t *testing.T) {
+    code := "package app\n" +
+        "enum Color { Red, Green }\n" +
+        "type ID = string\n" +
+        "type Line struct { sku ID; qty int }\n" +
+        "type Order struct { id ID; color Color; lines slice<Line>; next Optional<Order>; tz time.Zone }\n"
+    if got := typeDeclCodes(t, code); len(got) != 0 { t.Fatalf("unexpected diags: %v", got) }
+}
+
+func TestAnalyzeTypeDecls_Errors(t *testing.T) {
+    code := "package app\n" +
+        "type A struct { x int; x string; m Missing }\n" +
+        "type A = int\n" +
+        "type Node struct { v int; self Node }\n" +
+        "type P = Q\n" +
+        "type Q = P\n"
+    got := typeDeclCodes(t, code)
+    if got["E_TYPE_REDECLARED"] != 1 || got["E_STRUCT_FIELD_DUPLICATE"] != 1 || got["E_TYPE_UNKNOWN"] != 1 {
+        t.Fatalf("codes: %v", got)
+    }
+    if got["E_TYPE_CYCLE"] != 3 { t.Fatalf("cycles: %v", got) }
+}
+
+func TestAnalyzeTypeDecls_UnknownInSignaturesAndPipelines(t *testing.T) {
+    code := "package app\n" +
+        "type Order struct { id string }\n" +
+        "func G(x Unknown) (Event<Order>, error) {}\n" +
+        "func H(e Event<Order>) (Missing) {}\n" +
+        "func Map<T any>(x T, xs slice<T>) (T) {}\n" +
+        "pipeline P(){ A type(\"Event<Unknwon>\"); B type(\"Event<Order>\"); A -> B; egress }\n"
+    got := typeDeclCodes(t, code)
+    if got["E_TYPE_UNKNOWN"] != 3 || len(got) != 1 { t.Fatalf("codes: %v", got) }
+}
+
+func TestAnalyzeTypeDecls_NotExported(t *testing.T) {
+    r := types.NewResolver("app")
+    r.Import("m", "models")
+    r.Define("models", "money", types.MustParse("int64"), false)
+    r.Define("models", "Money", types.MustParse("int64"), false)
+    SetTypeResolver(r)
+    defer SetTypeResolver(nil)
+    got := typeDeclCodes(t, "package app\nimport m\ntype T struct { a m.money; b m.Money }\n")
+    if got["E_TYPE_NOT_EXPORTED"] != 1 || len(got) != 1 { t.Fatalf("codes: %v", got) }
+}
+
+func TestTypesCompatible_DeclaredTypes(t *testing.T) {
+    r := types.NewResolver("app")
+    r.Define("app", "Order", types.MustParse("Struct{id:string}"), false)
+    r.Define("app", "Other", types.MustParse("Struct{id:string}"), false)
+    r.Define("app", "ID", types.MustParse("string"), true)
+    SetTypeResolver(r)
+    defer SetTypeResolver(nil)
+    if !typesCompatible("Event<Order>", "Event<app.Order>") { t.Fatalf("qualified and bare names should match") }
+    if !typesCompatible("Event<ID>", "Event<string>") { t.Fatalf("alias should match its target") }
+    if typesCompatible("Event<Order>", "Event<Other>") { t.Fatalf("distinct defined types must not match") }
+}
+
+func TestAnalyzeMergeFieldTypes_DeclaredPayload(t *testing.T) {
+    code := "package app\n" +
+        "type Meta struct { region string }\n" +
+        "type Order struct { id string; total int64; meta Meta; tags slice<string> }\n" +
+        "pipeline P(){ A type(\"Event<Order>\"); Collect merge.Sort(\"meta.region\"); A -> Collect; egress }\n" +
+        "pipeline Q(){ A type(\"Event<Order>\"); Collect merge.Sort(\"tags\"); A -> Collect; egress }\n"
+    f := (&source.FileSet{}).AddFile("m.ami", code)
+    af, _ := parser.New(f).ParseFile()
+    got := map[string]int{}
+    for _, d := range AnalyzeMergeFieldTypes(af) { got[d.Code]++ }
+    if len(got) != 1 || got["E_MERGE_SORT_FIELD_UNORDERABLE"] != 1 { t.Fatalf("codes: %v", got) }
+}
@@ -0,0 +1,49 @@
+package sem
+
+import (
+    "github.com/sam-caldwell/ami/src/ami/compiler/ast"
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+// typeResolver resolves package-level type declarations (including those of
+// imported packages) for the package currently being analyzed. The driver sets
+// it per package; when nil, analyzers fall back to the declarations of the file
+// under analysis.
+var typeResolver *types.Resolver
+
+// DefineTypeDecls records the type and enum declarations of f in r under pkg.
+func DefineTypeDecls(r *types.Resolver, pkg string, f *ast.File) {
+    if r == nil || f == nil { return }
+    for _, d := range f.Decls {
+        switch v := d.(type) {
+        case *ast.TypeDecl:
+            if t, err := types.Parse(v.Type); err == nil { r.Define(pkg, v.Name, t, v.Alias) }
+        case *ast.EnumDecl:
+            var members []string
+            for _, m := range v.Members { members = append(members, m.Name) }
+            r.DefineEnum(pkg, v.Name, members)
+        }
+    }
+}
+
+// fileTypeResolver returns the configured resolver, or one built from the
+// declarations of f alone.
+func fileTypeResolver(f *ast.File) *types.Resolver {
+    if typeResolver != nil { return typeResolver }
+    if f == nil { return nil }
+    r := types.NewResolver(f.PackageName)
+    DefineTypeDecls(r, f.PackageName, f)
+    return r
+}
+
+// canonicalTypeText rewrites declared type names in s to their canonical form
+// (aliases expanded, defined types package-qualified). Text without declared
+// names is returned unchanged.
+func canonicalTypeText(s string) string {
+    if typeResolver == nil || s == "" { return s }
+    t, err := types.Parse(s)
+    if err != nil { return s }
+    c := typeResolver.Canonical(t)
+    if c.String() == t.String() { return s }
+    return c.String()
+}
@@ -0,0 +1,12 @@
+package sem
+
+import "strings"
+
+// validAggregateFn reports whether s names a supported merge.Aggregate function.
+func validAggregateFn(s string) bool {
+    switch strings.ToLower(strings.Trim(strings.TrimSpace(s), "\"")) {
+    case "count", "sum", "min", "max", "avg", "first", "last":
+        return true
+    }
+    return false
+}
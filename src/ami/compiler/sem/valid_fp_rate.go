@@ -0,0 +1,12 @@
+package sem
+
+import (
+    "strconv"
+    "strings"
+)
+
+// validFPRate reports whether s is a false-positive rate strictly between 0 and 1.
+func validFPRate(s string) bool {
+    f, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(s), "\""), 64)
+    return err == nil && f > 0 && f < 1
+}
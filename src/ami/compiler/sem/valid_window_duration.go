@@ -0,0 +1,12 @@
+package sem
+
+import "strings"
+
+// validWindowDuration reports whether s is a positive integer (ms) or a
+// duration literal with a positive magnitude (e.g., 500ms, 1s, 5m).
+func validWindowDuration(s string) bool {
+    s = strings.TrimSpace(s)
+    if isInteger(s) { return validPositiveInt(s) }
+    if !isDurationLike(s) { return false }
+    return validPositiveInt(numericPrefix(s))
+}
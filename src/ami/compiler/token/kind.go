@@ -153,6 +153,9 @@ const (
     // Comments
     LineComment  // //...
     BlockComment // /*...*/
+
+    // Keywords added after the initial set; appended to keep existing values stable.
+    KwMatch
 )
 
 // Operators maps operator lexemes to their token kinds.
@@ -245,6 +248,7 @@ var Keywords = map[string]Kind{
     "len":     KwLen,
     "make":    KwMake,
     "map":     KwMap,
+    "match":   KwMatch,
     "set":     KwSet,
     "slice":   KwSlice,
     "new":     KwNew,
@@ -175,6 +175,8 @@ func (k Kind) String() string {
         return "KwMake"
     case KwMap:
         return "KwMap"
+    case KwMatch:
+        return "KwMatch"
     case KwSet:
         return "KwSet"
     case KwSlice:
@@ -0,0 +1,11 @@
+package types
+
+// Enum represents a user-declared enumeration. Name is the qualified
+// declaration name (e.g., "app.Color"); Members lists the declared members in
+// source order. Enums compare by name and are orderable by member index.
+type Enum struct {
+    Name    string
+    Members []string
+}
+
+func (e Enum) String() string { return e.Name }
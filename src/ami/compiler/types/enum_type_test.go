@@ -0,0 +1,11 @@
+package types
+
+import "testing"
+
+func Test_enum_type_Exists(t *testing.T) {}
+
+func TestEnum_String_IsName(t *testing.T) {
+    e := Enum{Name: "app.Color", Members: []string{"Red", "Green"}}
+    if e.String() != "app.Color" { t.Fatalf("got %q", e.String()) }
+    if !IsOrderable(e) { t.Fatalf("enum should be orderable") }
+}
@@ -0,0 +1,10 @@
+package types
+
+import "unicode"
+
+// IsExported reports whether a declared type name is visible to importing
+// packages. Like Go, names beginning with an upper-case letter are exported.
+func IsExported(name string) bool {
+    for _, r := range name { return unicode.IsUpper(r) }
+    return false
+}
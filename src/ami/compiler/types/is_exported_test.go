@@ -0,0 +1,10 @@
+package types
+
+import "testing"
+
+func TestIsExported(t *testing.T) {
+    cases := map[string]bool{"Order": true, "order": false, "": false, "_X": false, "Ünit": true}
+    for in, want := range cases {
+        if got := IsExported(in); got != want { t.Fatalf("%q: got %v want %v", in, got, want) }
+    }
+}
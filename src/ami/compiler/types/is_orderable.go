@@ -2,7 +2,7 @@ package types
 
 func IsOrderable(t Type) bool {
     switch v := t.(type) {
-    case Primitive:
+    case Primitive, Enum:
         return true
     case Optional:
         return IsOrderable(v.Inner)
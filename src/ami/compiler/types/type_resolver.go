@@ -0,0 +1,179 @@
+package types
+
+import "strings"
+
+// Resolver maps package-level type declarations to their definitions so that
+// named references (e.g., "Order" or "models.Order") can be expanded into
+// structural types. A Resolver is scoped to one package (its home); imported
+// packages are reachable through their import aliases, and only exported names
+// resolve across package boundaries.
+//
+// Aliases (type A = T) are always transparent. Defined types (type A T and
+// struct declarations) are nominal: Canonical keeps them as qualified names,
+// while Resolve expands them to their underlying structure for field access
+// and layout. Both expansions are cycle-safe; a reference that recurs into
+// itself is left as its qualified Named form.
+//
+// The zero value is not usable; construct with NewResolver. A nil *Resolver
+// is valid and resolves nothing.
+type Resolver struct {
+    pkg     string
+    imports map[string]string
+    defs    map[string]typeDef
+}
+
+type typeDef struct {
+    pkg   string
+    name  string
+    t     Type
+    alias bool
+}
+
+// NewResolver returns an empty resolver for the given home package.
+func NewResolver(pkg string) *Resolver {
+    return &Resolver{pkg: pkg, imports: map[string]string{}, defs: map[string]typeDef{}}
+}
+
+// Package returns the home package name.
+func (r *Resolver) Package() string {
+    if r == nil { return "" }
+    return r.pkg
+}
+
+// Import makes the declarations of pkg reachable through alias.
+func (r *Resolver) Import(alias, pkg string) {
+    if r == nil || alias == "" || pkg == "" { return }
+    r.imports[alias] = pkg
+}
+
+// Define records a declaration of name in pkg. When alias is true the name is
+// a transparent alias of t; otherwise it is a distinct defined type.
+func (r *Resolver) Define(pkg, name string, t Type, alias bool) {
+    if r == nil || name == "" || t == nil { return }
+    r.defs[pkg+"."+name] = typeDef{pkg: pkg, name: name, t: t, alias: alias}
+}
+
+// DefineEnum records an enum declaration of name in pkg.
+func (r *Resolver) DefineEnum(pkg, name string, members []string) {
+    if r == nil || name == "" { return }
+    m := append([]string(nil), members...)
+    r.Define(pkg, name, Enum{Name: pkg + "." + name, Members: m}, false)
+}
+
+// Declared reports whether name refers to a declaration, regardless of
+// whether it is exported.
+func (r *Resolver) Declared(name string) bool {
+    _, ok := r.find(r.Package(), name)
+    return ok
+}
+
+// Lookup resolves a declared name from the home package and returns its
+// structural expansion. Unexported names of other packages do not resolve.
+func (r *Resolver) Lookup(name string) (Type, bool) {
+    d, ok := r.lookup(r.Package(), name)
+    if !ok { return nil, false }
+    return r.expand(d, map[string]bool{}, true), true
+}
+
+// Qualified returns the qualified "pkg.Name" form for a declared name.
+func (r *Resolver) Qualified(name string) (string, bool) {
+    d, ok := r.find(r.Package(), name)
+    if !ok { return "", false }
+    return d.pkg + "." + d.name, true
+}
+
+// Resolve expands declared names within t into their underlying structure.
+func (r *Resolver) Resolve(t Type) Type {
+    if r == nil || t == nil { return t }
+    return r.walk(r.pkg, t, map[string]bool{}, true)
+}
+
+// Canonical expands aliases within t and renders defined types by their
+// qualified names, so equal types compare equal by String() regardless of
+// how they were spelled.
+func (r *Resolver) Canonical(t Type) Type {
+    if r == nil || t == nil { return t }
+    return r.walk(r.pkg, t, map[string]bool{}, false)
+}
+
+// Parse parses s and resolves declared names within it.
+func (r *Resolver) Parse(s string) (Type, error) {
+    t, err := Parse(s)
+    if err != nil { return nil, err }
+    return r.Resolve(t), nil
+}
+
+// ResolveField resolves a dotted field path like ResolveField after expanding
+// declared names along the way.
+func (r *Resolver) ResolveField(root Type, path string) (Type, bool) {
+    return ResolveField(r.Resolve(root), path)
+}
+
+// find locates a declaration as seen from the home package, ignoring export rules.
+func (r *Resolver) find(home, name string) (typeDef, bool) {
+    if r == nil || name == "" { return typeDef{}, false }
+    if i := strings.LastIndexByte(name, '.'); i > 0 {
+        q := name[:i]
+        if p, ok := r.imports[q]; ok && home == r.pkg { q = p }
+        d, ok := r.defs[q+"."+name[i+1:]]
+        return d, ok
+    }
+    d, ok := r.defs[home+"."+name]
+    return d, ok
+}
+
+// lookup is find with export rules applied to foreign declarations.
+func (r *Resolver) lookup(home, name string) (typeDef, bool) {
+    d, ok := r.find(home, name)
+    if !ok { return typeDef{}, false }
+    if d.pkg != home && !IsExported(d.name) { return typeDef{}, false }
+    return d, true
+}
+
+func (r *Resolver) expand(d typeDef, visiting map[string]bool, deep bool) Type {
+    key := d.pkg + "." + d.name
+    if !d.alias && !deep { return Named{Name: key} }
+    if visiting[key] { return Named{Name: key} }
+    visiting[key] = true
+    out := r.walk(d.pkg, d.t, visiting, deep)
+    delete(visiting, key)
+    return out
+}
+
+func (r *Resolver) walk(home string, t Type, visiting map[string]bool, deep bool) Type {
+    switch v := t.(type) {
+    case Named:
+        if d, ok := r.lookup(home, v.Name); ok { return r.expand(d, visiting, deep) }
+        return v
+    case Generic:
+        if len(v.Args) == 0 {
+            if d, ok := r.lookup(home, v.Name); ok { return r.expand(d, visiting, deep) }
+            return v
+        }
+        args := make([]Type, len(v.Args))
+        for i, a := range v.Args { args[i] = r.walk(home, a, visiting, deep) }
+        return Generic{Name: v.Name, Args: args}
+    case Struct:
+        fields := make(map[string]Type, len(v.Fields))
+        for k, f := range v.Fields { fields[k] = r.walk(home, f, visiting, deep) }
+        return Struct{Fields: fields}
+    case Optional:
+        return Optional{Inner: r.walk(home, v.Inner, visiting, deep)}
+    case Union:
+        alts := make([]Type, len(v.Alts))
+        for i, a := range v.Alts { alts[i] = r.walk(home, a, visiting, deep) }
+        return Union{Alts: alts}
+    case Map:
+        return Map{Key: r.walk(home, v.Key, visiting, deep), Val: r.walk(home, v.Val, visiting, deep)}
+    case Set:
+        return Set{Elem: r.walk(home, v.Elem, visiting, deep)}
+    case SliceTy:
+        return SliceTy{Elem: r.walk(home, v.Elem, visiting, deep)}
+    case Slice:
+        return Slice{Elem: r.walk(home, v.Elem, visiting, deep)}
+    case Pointer:
+        return Pointer{Elem: r.walk(home, v.Elem, visiting, deep)}
+    default:
+        return t
+    }
+}
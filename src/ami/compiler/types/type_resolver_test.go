@@ -0,0 +1,53 @@
+package types
+
+import "testing"
+
+func Test_type_resolver_Exists(t *testing.T) {}
+
+func testResolver(t *testing.T) *Resolver {
+    t.Helper()
+    r := NewResolver("app")
+    r.Import("m", "models")
+    r.Define("models", "Money", MustParse("Struct{amount:int64,currency:string}"), false)
+    r.Define("models", "secret", MustParse("string"), false)
+    r.Define("app", "Order", MustParse("Struct{id:string,total:m.Money,tags:slice<string>}"), false)
+    r.Define("app", "ID", MustParse("string"), true)
+    r.Define("app", "Node", MustParse("Struct{v:int,next:Optional<Node>}"), false)
+    r.DefineEnum("app", "Color", []string{"Red", "Green"})
+    return r
+}
+
+func TestResolver_Resolve_ExpandsAcrossPackages(t *testing.T) {
+    r := testResolver(t)
+    got, err := r.Parse("Event<Order>")
+    if err != nil { t.Fatalf("parse: %v", err) }
+    want := "Event<Struct{id:string,tags:slice<string>,total:Struct{amount:int64,currency:string}}>"
+    if got.String() != want { t.Fatalf("got %s\nwant %s", got, want) }
+    ft, ok := r.ResolveField(MustParse("Event<Order>"), "total.amount")
+    if !ok || ft.String() != "int64" { t.Fatalf("field: %v %v", ft, ok) }
+}
+
+func TestResolver_Canonical_AliasesTransparent_DefinedNominal(t *testing.T) {
+    r := testResolver(t)
+    if s := r.Canonical(MustParse("ID")).String(); s != "string" { t.Fatalf("alias: %s", s) }
+    if s := r.Canonical(MustParse("Event<Order>")).String(); s != "Event<app.Order>" { t.Fatalf("defined: %s", s) }
+    if s := r.Canonical(MustParse("Event<app.Order>")).String(); s != "Event<app.Order>" { t.Fatalf("qualified: %s", s) }
+    if s := r.Canonical(MustParse("m.Money")).String(); s != "models.Money" { t.Fatalf("imported: %s", s) }
+}
+
+func TestResolver_Unexported_And_Cycles(t *testing.T) {
+    r := testResolver(t)
+    if _, ok := r.Lookup("m.secret"); ok { t.Fatalf("unexported foreign name should not resolve") }
+    if !r.Declared("m.secret") { t.Fatalf("declared should ignore export rules") }
+    n, ok := r.Lookup("Node")
+    if !ok { t.Fatalf("lookup Node") }
+    if n.String() != "Struct{next:Optional<app.Node>,v:int}" { t.Fatalf("cycle: %s", n) }
+    c, ok := r.Lookup("Color")
+    if e, isEnum := c.(Enum); !ok || !isEnum || len(e.Members) != 2 { t.Fatalf("enum: %#v", c) }
+}
+
+func TestResolver_Nil_IsNoop(t *testing.T) {
+    var r *Resolver
+    in := MustParse("Event<Order>")
+    if r.Resolve(in).String() != in.String() || r.Declared("Order") { t.Fatalf("nil resolver should be a no-op") }
+}
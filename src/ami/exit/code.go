@@ -24,4 +24,8 @@ const (
 	// Per SPEC, this maps to the same numeric value as integrity in the current phase.
 	// Use this alias to signal network-originated failures.
 	Network Code = Integrity
+
+	// Signature indicates a dependency that is unsigned, signed by an untrusted
+	// key or whose signature does not verify (see ami.workspace trust).
+	Signature Code = 5
 )
@@ -14,6 +14,7 @@ func TestCode_Int(t *testing.T) {
         {"IO", IO, 3},
         {"Integrity", Integrity, 4},
         {"NetworkAlias", Network, int(Integrity)},
+        {"Signature", Signature, 5},
     }
     for _, tc := range cases {
         if got := tc.code.Int(); got != tc.want {
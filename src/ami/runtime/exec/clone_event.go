@@ -0,0 +1,29 @@
+package exec
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// cloneEvent deep-copies map/slice payloads and trace so that fan-out branches
+// can mutate their copy without racing each other.
+func cloneEvent(e ev.Event) ev.Event {
+    c := e
+    if e.Trace != nil {
+        if t, ok := cloneValue(e.Trace).(map[string]any); ok { c.Trace = t }
+    }
+    c.Payload = cloneValue(e.Payload)
+    return c
+}
+
+func cloneValue(v any) any {
+    switch x := v.(type) {
+    case map[string]any:
+        m := make(map[string]any, len(x))
+        for k, vv := range x { m[k] = cloneValue(vv) }
+        return m
+    case []any:
+        s := make([]any, len(x))
+        for i, vv := range x { s[i] = cloneValue(vv) }
+        return s
+    default:
+        return v
+    }
+}
@@ -0,0 +1,17 @@
+package exec
+
+import (
+    "testing"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestCloneEvent_DeepCopiesMapsAndSlices(t *testing.T) {
+    e := ev.Event{ID: "x", Trace: map[string]any{"k": 1}, Payload: map[string]any{"a": []any{map[string]any{"b": 1}}}}
+    c := cloneEvent(e)
+    c.Trace["k"] = 2
+    c.Payload.(map[string]any)["a"].([]any)[0].(map[string]any)["b"] = 2
+    if e.Trace["k"] != 1 { t.Fatalf("trace aliased") }
+    if e.Payload.(map[string]any)["a"].([]any)[0].(map[string]any)["b"] != 1 { t.Fatalf("payload aliased") }
+    if c.ID != "x" { t.Fatalf("id not copied") }
+}
@@ -0,0 +1,25 @@
+package exec
+
+import ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+
+// collectSpecFor binds a Collect node to its ir.CollectSpec by step identity.
+// Specs carrying an occurrence ID match the node's ID exactly; specs without
+// IDs (older IR) bind by order of appearance among specs for the same step.
+// It returns the spec's index within the pipeline's Collect list and its
+// merge plan, or (-1, nil) when no spec applies.
+func collectSpecFor(m ir.Module, pipeline string, n graphNode) (int, *ir.MergePlan) {
+    for _, p := range m.Pipelines {
+        if p.Name != pipeline { continue }
+        occ := 0
+        for i, c := range p.Collect {
+            if c.Step != n.Name { continue }
+            if c.ID > 0 {
+                if c.ID == n.ID { return i, c.Merge }
+                continue
+            }
+            occ++
+            if occ == n.ID { return i, c.Merge }
+        }
+    }
+    return -1, nil
+}
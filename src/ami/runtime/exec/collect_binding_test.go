@@ -0,0 +1,18 @@
+package exec
+
+import (
+    "testing"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestCollectSpecFor_ByIDAndByOrder(t *testing.T) {
+    a, b := &ir.MergePlan{Key: "a"}, &ir.MergePlan{Key: "b"}
+    withIDs := ir.Module{Pipelines: []ir.Pipeline{{Name: "P", Collect: []ir.CollectSpec{{Step: "Collect", ID: 3, Merge: b}, {Step: "Collect", ID: 1, Merge: a}}}}}
+    if i, p := collectSpecFor(withIDs, "P", graphNode{Name: "Collect", ID: 3}); i != 0 || p != b { t.Fatalf("id 3 -> %d %+v", i, p) }
+    if i, p := collectSpecFor(withIDs, "P", graphNode{Name: "Collect", ID: 1}); i != 1 || p != a { t.Fatalf("id 1 -> %d %+v", i, p) }
+    if i, p := collectSpecFor(withIDs, "P", graphNode{Name: "Collect", ID: 2}); i != -1 || p != nil { t.Fatalf("id 2 should be unbound: %d %+v", i, p) }
+    legacy := ir.Module{Pipelines: []ir.Pipeline{{Name: "P", Collect: []ir.CollectSpec{{Step: "Collect", Merge: a}, {Step: "Collect", Merge: b}}}}}
+    if _, p := collectSpecFor(legacy, "P", graphNode{Name: "Collect", ID: 2}); p != b { t.Fatalf("legacy order binding: %+v", p) }
+    if _, p := collectSpecFor(legacy, "Q", graphNode{Name: "Collect", ID: 1}); p != nil { t.Fatalf("other pipeline should not bind") }
+}
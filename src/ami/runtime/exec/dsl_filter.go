@@ -1,21 +1,27 @@
 package exec
 
-import ev "github.com/sam-caldwell/ami/src/schemas/events"
+import (
+    "github.com/sam-caldwell/ami/src/ami/runtime/expr"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
 
-// DSL stubs
-func applyFilter(expr string, e ev.Event) bool {
-    switch expr {
+// compileFilter compiles a --filter expression (see package expr). Empty and
+// "none" accept everything; the legacy "drop_even" stub remains as shorthand
+// for dropping events whose payload["i"] is even.
+func compileFilter(src string) (func(ev.Event) (bool, error), error) {
+    switch src {
     case "", "none":
-        return true
+        return func(ev.Event) (bool, error) { return true, nil }, nil
     case "drop_even":
-        // drops events where payload["i"] is even
-        if m, ok := e.Payload.(map[string]any); ok {
-            if v, ok := m["i"].(int); ok { return v%2 != 0 }
-            if f, ok := m["i"].(float64); ok { return int(f)%2 != 0 }
-        }
-        return true
-    default:
-        return true
+        return func(e ev.Event) (bool, error) {
+            if m, ok := e.Payload.(map[string]any); ok {
+                if v, ok := m["i"].(int); ok { return v%2 != 0, nil }
+                if f, ok := m["i"].(float64); ok { return int(f)%2 != 0, nil }
+            }
+            return true, nil
+        }, nil
     }
+    f, err := expr.ParseFilter(src)
+    if err != nil { return nil, err }
+    return f.Match, nil
 }
-
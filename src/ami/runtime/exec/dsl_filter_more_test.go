@@ -5,15 +5,13 @@ import (
     ev "github.com/sam-caldwell/ami/src/schemas/events"
 )
 
-func Test_applyFilter_DropEven_IntAndFloat(t *testing.T) {
-    e1 := ev.Event{Payload: map[string]any{"i": 2}}
-    if keep := applyFilter("drop_even", e1); keep { t.Fatalf("expected drop for even int") }
-    e2 := ev.Event{Payload: map[string]any{"i": 3.0}}
-    if keep := applyFilter("drop_even", e2); !keep { t.Fatalf("expected keep for odd float") }
+func Test_compileFilter_DropEven_IntAndFloat(t *testing.T) {
+    f, err := compileFilter("drop_even")
+    if err != nil { t.Fatalf("compile: %v", err) }
+    if keep, err := f(ev.Event{Payload: map[string]any{"i": 2}}); err != nil || keep { t.Fatalf("expected drop for even int") }
+    if keep, err := f(ev.Event{Payload: map[string]any{"i": 3.0}}); err != nil || !keep { t.Fatalf("expected keep for odd float") }
 }
 
-func Test_applyFilter_Unknown_Passes(t *testing.T) {
-    e := ev.Event{Payload: map[string]any{"i": 2}}
-    if !applyFilter("unknown", e) { t.Fatalf("unknown filter should pass") }
+func Test_compileFilter_Invalid_Fails(t *testing.T) {
+    if _, err := compileFilter("i =="); err == nil { t.Fatalf("expected compile error") }
 }
-
@@ -1,18 +1,27 @@
 package exec
 
-import ev "github.com/sam-caldwell/ami/src/schemas/events"
+import (
+    "strings"
 
-func applyTransform(expr string, e ev.Event) ev.Event {
-    switch expr {
-    case "", "none":
-        return e
-    default:
-        // add_field:name sets payload[name]=true
-        if len(expr) > 10 && expr[:10] == "add_field:" {
-            key := expr[10:]
+    "github.com/sam-caldwell/ami/src/ami/runtime/expr"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// compileTransform compiles a --transform expression (see package expr). Empty
+// and "none" are identity; the legacy "add_field:name" stub remains as
+// shorthand for setting payload[name]=true on object payloads.
+func compileTransform(src string) (func(ev.Event) (ev.Event, error), error) {
+    switch {
+    case src == "" || src == "none":
+        return func(e ev.Event) (ev.Event, error) { return e, nil }, nil
+    case strings.HasPrefix(src, "add_field:"):
+        key := strings.TrimPrefix(src, "add_field:")
+        return func(e ev.Event) (ev.Event, error) {
             if m, ok := e.Payload.(map[string]any); ok && key != "" { m[key] = true; e.Payload = m }
-        }
-        return e
+            return e, nil
+        }, nil
     }
+    t, err := expr.ParseTransform(src)
+    if err != nil { return nil, err }
+    return t.Apply, nil
 }
-
@@ -5,10 +5,11 @@ import (
     ev "github.com/sam-caldwell/ami/src/schemas/events"
 )
 
-func Test_applyTransform_AddField(t *testing.T) {
-    e := ev.Event{Payload: map[string]any{"x": 1}}
-    out := applyTransform("add_field:newflag", e)
+func Test_compileTransform_AddField(t *testing.T) {
+    tr, err := compileTransform("add_field:newflag")
+    if err != nil { t.Fatalf("compile: %v", err) }
+    out, err := tr(ev.Event{Payload: map[string]any{"x": 1}})
+    if err != nil { t.Fatalf("apply: %v", err) }
     m := out.Payload.(map[string]any)
     if m["newflag"] != true { t.Fatalf("expected newflag=true, got: %v", m["newflag"]) }
 }
-
@@ -5,6 +5,9 @@ type edgeEntry struct {
     Pipeline string `json:"pipeline"`
     From     string `json:"from"`
     To       string `json:"to"`
+    // Occurrence IDs disambiguate repeated step names (e.g., two Transform steps).
+    FromID   int    `json:"fromId,omitempty"`
+    ToID     int    `json:"toId,omitempty"`
     // Optional fields for enriched edges
     Bounded  bool   `json:"bounded,omitempty"`
     Delivery string `json:"delivery,omitempty"`
@@ -0,0 +1,9 @@
+package exec
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// edgeOut is a stage's handle on one outgoing edge channel and its policy.
+type edgeOut struct {
+    ch  chan ev.Event
+    pol edgePolicy
+}
@@ -0,0 +1,24 @@
+package exec
+
+// edgePolicy is the resolved capacity and backpressure for one edge.
+type edgePolicy struct{ cap int; bp string; from string; to string }
+
+// defaultEdgePolicy is used when edges.json carries no explicit settings.
+func defaultEdgePolicy(from, to string) edgePolicy {
+    return edgePolicy{cap: 1024, bp: "block", from: from, to: to}
+}
+
+// policyFromEdge derives capacity/backpressure from an edges.json entry,
+// preferring explicit backpressure and falling back to the delivery mode.
+func policyFromEdge(ed edgeEntry) edgePolicy {
+    pol := defaultEdgePolicy(ed.From, ed.To)
+    if ed.MaxCapacity > 0 { pol.cap = ed.MaxCapacity }
+    if ed.Backpressure != "" { pol.bp = ed.Backpressure; return pol }
+    switch ed.Delivery {
+    case "bestEffort": pol.bp = "dropNewest"
+    case "atLeastOnce": pol.bp = "block"
+    case "shuntNewest": pol.bp = "dropNewest"
+    case "shuntOldest": pol.bp = "dropOldest"
+    }
+    return pol
+}
@@ -22,28 +22,54 @@ func (e *Engine) Close() { if e.pool != nil { e.pool.Stop() } }
 // RunMerge starts a merge task based on a MergePlan and returns an output channel.
 // The caller should cancel ctx to stop the task; the returned channel is closed on shutdown.
 func (e *Engine) RunMerge(ctx context.Context, plan ir.MergePlan, in <-chan ev.Event) (<-chan ev.Event, error) {
-    if e == nil || e.pool == nil { return nil, fmt.Errorf("engine not initialized") }
-    out := make(chan ev.Event, 1024)
-    rp := toRuntimePlan(plan)
-    if err := e.pool.Submit(rmerge.MergeTask("collect", rp, in, out)); err != nil { return nil, err }
-    // Close 'out' when ctx is done (best-effort)
-    go func(){ <-ctx.Done(); // allow loop to observe cancellation
-        // Grace period to drain
-        time.Sleep(5 * time.Millisecond)
-        close(out)
-    }()
-    return out, nil
+    out, _, err := e.runMerge(ctx, plan, in, nil)
+    return out, err
 }
 
 // RunMergeWithStats is like RunMerge but returns a Stats pointer populated at shutdown.
+// The output channel closes once the merge task has exited (input closed or ctx
+// cancelled), so Stats are final when a reader observes the close.
 func (e *Engine) RunMergeWithStats(ctx context.Context, plan ir.MergePlan, in <-chan ev.Event) (<-chan ev.Event, *rmerge.Stats, error) {
+    return e.runMerge(ctx, plan, in, nil)
+}
+
+// RunMergeWithCheckpoint is RunMergeWithStats with operator state resumed from
+// and periodically saved to cp.Path (see merge.RunPlanWithCheckpoint).
+func (e *Engine) RunMergeWithCheckpoint(ctx context.Context, plan ir.MergePlan, in <-chan ev.Event, cp rmerge.Checkpoint) (<-chan ev.Event, *rmerge.Stats, error) {
+    return e.runMerge(ctx, plan, in, &cp)
+}
+
+func (e *Engine) runMerge(ctx context.Context, plan ir.MergePlan, in <-chan ev.Event, cp *rmerge.Checkpoint) (<-chan ev.Event, *rmerge.Stats, error) {
     if e == nil || e.pool == nil { return nil, nil, fmt.Errorf("engine not initialized") }
     out := make(chan ev.Event, 1024)
     rp := toRuntimePlan(plan)
     var st rmerge.Stats
-    // Inline runner to attach stats
-    task := scheduler.Task{Source: "collect", Do: func(c context.Context){ rmerge.RunPlanWithStats(c, rp, in, out, &st) }}
+    started := make(chan struct{})
+    done := make(chan struct{})
+    // Inline runner to attach stats; stops on either the pool or the run context.
+    task := scheduler.Task{Source: "collect", Do: func(c context.Context){
+        close(started)
+        defer close(done)
+        if ctx.Err() != nil { return }
+        rc, stop := context.WithCancel(ctx)
+        defer stop()
+        go func(){ select { case <-c.Done(): stop(); case <-rc.Done(): } }()
+        if cp == nil { rmerge.RunPlanWithStats(rc, rp, in, out, &st); return }
+        if err := rmerge.RunPlanWithCheckpoint(rc, rp, in, out, &st, *cp); err != nil && cp.OnError != nil { cp.OnError(err) }
+    }}
     if err := e.pool.Submit(task); err != nil { return nil, nil, err }
-    go func(){ <-ctx.Done(); time.Sleep(5 * time.Millisecond); close(out) }()
+    go func(){
+        select {
+        case <-done:
+        case <-ctx.Done():
+            // Grace period for a task still waiting on a pool worker; once
+            // started, wait for it to flush so no send races the close.
+            select {
+            case <-started: <-done
+            case <-time.After(5 * time.Millisecond):
+            }
+        }
+        close(out)
+    }()
     return out, &st, nil
 }
@@ -0,0 +1,8 @@
+package exec
+
+// ErrPipelineCycle is returned when a pipeline's edges do not form a DAG.
+type ErrPipelineCycle struct{ Pipeline, Node string }
+
+func (e ErrPipelineCycle) Error() string {
+    return "pipeline " + e.Pipeline + " contains a cycle at " + e.Node
+}
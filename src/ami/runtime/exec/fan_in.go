@@ -0,0 +1,29 @@
+package exec
+
+import (
+    "sync"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// fanIn merges upstream edge channels into a single stream for a stage.
+// A single input is returned as-is; no inputs yields a closed channel.
+// The merged channel is unbuffered so edge capacities remain authoritative.
+func fanIn(ins []<-chan ev.Event) <-chan ev.Event {
+    switch len(ins) {
+    case 0:
+        ch := make(chan ev.Event)
+        close(ch)
+        return ch
+    case 1:
+        return ins[0]
+    }
+    out := make(chan ev.Event)
+    var wg sync.WaitGroup
+    wg.Add(len(ins))
+    for _, in := range ins {
+        go func(in <-chan ev.Event){ defer wg.Done(); for e := range in { out <- e } }(in)
+    }
+    go func(){ wg.Wait(); close(out) }()
+    return out
+}
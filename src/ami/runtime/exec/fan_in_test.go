@@ -0,0 +1,21 @@
+package exec
+
+import (
+    "testing"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestFanIn_MergesAndClosesAfterAllInputs(t *testing.T) {
+    a := make(chan ev.Event, 2)
+    b := make(chan ev.Event, 2)
+    a <- ev.Event{ID: "a1"}; a <- ev.Event{ID: "a2"}; close(a)
+    b <- ev.Event{ID: "b1"}; close(b)
+    n := 0
+    for range fanIn([]<-chan ev.Event{a, b}) { n++ }
+    if n != 3 { t.Fatalf("expected 3 events, got %d", n) }
+}
+
+func TestFanIn_NoInputs_Closed(t *testing.T) {
+    if _, ok := <-fanIn(nil); ok { t.Fatalf("expected closed channel") }
+}
@@ -0,0 +1,35 @@
+package exec
+
+import "strconv"
+
+// pipelineGraph is the directed graph of step occurrences for one pipeline.
+// Nodes are stored in a deterministic topological order (ties broken by first
+// appearance in edges.json) so stages can be wired upstream-first.
+type pipelineGraph struct {
+    Pipeline string
+    Nodes    []graphNode
+    Edges    []edgeEntry
+    index    map[string]int
+}
+
+// nodeKey returns the unique key for a step occurrence. Occurrence IDs of 0
+// (edges written without IDs) and 1 share the bare step name.
+func nodeKey(name string, id int) string {
+    if id <= 1 { return name }
+    return name + "#" + strconv.Itoa(id)
+}
+
+// node returns the node for key and whether it exists.
+func (g *pipelineGraph) node(key string) (graphNode, bool) {
+    if g == nil { return graphNode{}, false }
+    i, ok := g.index[key]
+    if !ok { return graphNode{}, false }
+    return g.Nodes[i], true
+}
+
+// has reports whether any node carries the given step name.
+func (g *pipelineGraph) has(name string) bool {
+    if g == nil { return false }
+    for _, n := range g.Nodes { if n.Name == name { return true } }
+    return false
+}
@@ -0,0 +1,22 @@
+package exec
+
+// deliveryFor returns the delivery mode of n's inbound edges: atLeastOnce if
+// any inbound edge explicitly requests it, bestEffort if all of them do,
+// otherwise "". The compiler labels every edge atLeastOnce by default, so an
+// atLeastOnce edge counts as explicit only when it also carries the "block"
+// backpressure that requests it (merge.Buffer(n, block), edge.FIFO(backpressure=block)).
+func deliveryFor(g *pipelineGraph, n graphNode) string {
+    if len(n.In) == 0 { return "" }
+    best := true
+    for _, i := range n.In {
+        switch g.Edges[i].Delivery {
+        case "atLeastOnce":
+            if g.Edges[i].Backpressure == "block" { return "atLeastOnce" }
+            best = false
+        case "bestEffort":
+        default: best = false
+        }
+    }
+    if best { return "bestEffort" }
+    return ""
+}
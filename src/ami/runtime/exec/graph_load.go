@@ -0,0 +1,73 @@
+package exec
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+)
+
+// loadPipelineGraph loads build/debug/asm/<pkg>/edges.json under rootDir and
+// builds the full DAG for the named pipeline, preserving fan-out and fan-in.
+// Self-loops are ignored; any other cycle yields ErrPipelineCycle.
+func loadPipelineGraph(rootDir, pkg, pipeline string) (*pipelineGraph, error) {
+    path := filepath.Join(rootDir, "build", "debug", "asm", pkg, "edges.json")
+    b, err := os.ReadFile(path)
+    if err != nil { return nil, err }
+    var idx edgesIndex
+    if err := json.Unmarshal(b, &idx); err != nil { return nil, err }
+    return buildPipelineGraph(pipeline, idx.Edges)
+}
+
+// buildPipelineGraph constructs a pipelineGraph from edge entries.
+func buildPipelineGraph(pipeline string, edges []edgeEntry) (*pipelineGraph, error) {
+    type pending struct{ key, name string; id int }
+    var order []pending
+    seen := map[string]bool{}
+    add := func(name string, id int) string {
+        k := nodeKey(name, id)
+        if !seen[k] {
+            seen[k] = true
+            if id < 1 { id = 1 }
+            order = append(order, pending{key: k, name: name, id: id})
+        }
+        return k
+    }
+    g := &pipelineGraph{Pipeline: pipeline, index: map[string]int{}}
+    type link struct{ from, to string }
+    var links []link
+    dup := map[link]bool{}
+    for _, e := range edges {
+        if e.Pipeline != pipeline { continue }
+        fk := add(e.From, e.FromID)
+        tk := add(e.To, e.ToID)
+        l := link{fk, tk}
+        if fk == tk || dup[l] { continue }
+        dup[l] = true
+        g.Edges = append(g.Edges, e)
+        links = append(links, l)
+    }
+    // Kahn's algorithm over first-appearance order for determinism.
+    indeg := map[string]int{}
+    for _, l := range links { indeg[l.to]++ }
+    done := map[string]bool{}
+    for len(g.Nodes) < len(order) {
+        progressed := false
+        for _, p := range order {
+            if done[p.key] || indeg[p.key] > 0 { continue }
+            done[p.key] = true
+            progressed = true
+            g.index[p.key] = len(g.Nodes)
+            g.Nodes = append(g.Nodes, graphNode{Key: p.key, Name: p.name, ID: p.id})
+            for _, l := range links { if l.from == p.key { indeg[l.to]-- } }
+        }
+        if !progressed {
+            for _, p := range order { if !done[p.key] { return nil, ErrPipelineCycle{Pipeline: pipeline, Node: p.key} } }
+        }
+    }
+    for i, l := range links {
+        fi, ti := g.index[l.from], g.index[l.to]
+        g.Nodes[fi].Out = append(g.Nodes[fi].Out, i)
+        g.Nodes[ti].In = append(g.Nodes[ti].In, i)
+    }
+    return g, nil
+}
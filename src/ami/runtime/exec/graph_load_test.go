@@ -0,0 +1,52 @@
+package exec
+
+import "testing"
+
+func TestBuildPipelineGraph_FanOutFanIn_TopoOrder(t *testing.T) {
+    g, err := buildPipelineGraph("P", []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "A"},
+        {Pipeline: "P", From: "ingress", To: "B"},
+        {Pipeline: "P", From: "A", To: "Collect"},
+        {Pipeline: "P", From: "B", To: "Collect"},
+        {Pipeline: "P", From: "Collect", To: "egress"},
+        {Pipeline: "Q", From: "ingress", To: "Z"},
+    })
+    if err != nil { t.Fatalf("build: %v", err) }
+    var keys []string
+    for _, n := range g.Nodes { keys = append(keys, n.Key) }
+    want := []string{"ingress", "A", "B", "Collect", "egress"}
+    if len(keys) != len(want) { t.Fatalf("nodes: %v", keys) }
+    for i := range want { if keys[i] != want[i] { t.Fatalf("order: %v", keys) } }
+    in, _ := g.node("ingress")
+    if len(in.Out) != 2 { t.Fatalf("ingress fan-out: %+v", in) }
+    c, _ := g.node("Collect")
+    if len(c.In) != 2 || len(c.Out) != 1 { t.Fatalf("collect fan-in: %+v", c) }
+}
+
+func TestBuildPipelineGraph_OccurrenceIDs_DistinctNodes(t *testing.T) {
+    g, err := buildPipelineGraph("P", []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "Transform", ToID: 1},
+        {Pipeline: "P", From: "Transform", FromID: 1, To: "Transform", ToID: 2},
+        {Pipeline: "P", From: "Transform", FromID: 2, To: "egress"},
+    })
+    if err != nil { t.Fatalf("build: %v", err) }
+    if len(g.Nodes) != 4 { t.Fatalf("expected 4 nodes, got %+v", g.Nodes) }
+    n, ok := g.node("Transform#2")
+    if !ok || n.ID != 2 || n.Name != "Transform" { t.Fatalf("second occurrence: %+v ok=%v", n, ok) }
+}
+
+func TestBuildPipelineGraph_SelfLoopIgnored_CycleRejected(t *testing.T) {
+    if _, err := buildPipelineGraph("P", []edgeEntry{{Pipeline: "P", From: "ingress", To: "A"}, {Pipeline: "P", From: "A", To: "A"}}); err != nil {
+        t.Fatalf("self-loop should be ignored: %v", err)
+    }
+    _, err := buildPipelineGraph("P", []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "A"},
+        {Pipeline: "P", From: "A", To: "B"},
+        {Pipeline: "P", From: "B", To: "A"},
+    })
+    if _, ok := err.(ErrPipelineCycle); !ok { t.Fatalf("expected ErrPipelineCycle, got %v", err) }
+}
+
+func TestLoadPipelineGraph_MissingFileReturnsError(t *testing.T) {
+    if _, err := loadPipelineGraph(t.TempDir(), "app", "P"); err == nil { t.Fatalf("expected error") }
+}
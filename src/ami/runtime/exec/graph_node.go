@@ -0,0 +1,10 @@
+package exec
+
+// graphNode is a single step occurrence in a pipeline graph built from edges.json.
+type graphNode struct {
+    Key  string // unique key: name, or name#id for repeated occurrences
+    Name string // step name as written in source (e.g., Transform, Collect)
+    ID   int    // 1-based occurrence of Name within the pipeline
+    In   []int  // indices into pipelineGraph.Edges targeting this node
+    Out  []int  // indices into pipelineGraph.Edges originating at this node
+}
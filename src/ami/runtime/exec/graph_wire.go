@@ -0,0 +1,51 @@
+package exec
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// stageFunc starts the stage for node n. It must consume in until closed,
+// forward results to outs, and close every out channel when done.
+type stageFunc func(n graphNode, in <-chan ev.Event, outs []edgeOut) error
+
+// wireGraph allocates one channel per edge (sized from the edge policy) and
+// starts every node upstream-first. Ingress reads from src. Terminal nodes other
+// than egress are routed into egress so their output is not lost; egress is
+// always started last, synthesized when the graph has no egress node.
+func wireGraph(g *pipelineGraph, src <-chan ev.Event, run stageFunc) error {
+    chans := make([]chan ev.Event, len(g.Edges))
+    for i, ed := range g.Edges { chans[i] = make(chan ev.Event, policyFromEdge(ed).cap) }
+    extra := map[int]edgeOut{}
+    var sinks []<-chan ev.Event
+    for i, n := range g.Nodes {
+        if n.Name == "egress" || len(n.Out) > 0 { continue }
+        eo := edgeOut{ch: make(chan ev.Event, 1024), pol: defaultEdgePolicy(n.Name, "egress")}
+        extra[i] = eo
+        sinks = append(sinks, eo.ch)
+    }
+    var egress *graphNode
+    for i, n := range g.Nodes {
+        if n.Name == "egress" { egress = &g.Nodes[i]; continue }
+        var ins []<-chan ev.Event
+        for _, ei := range n.In { ins = append(ins, chans[ei]) }
+        if n.Name == "ingress" && src != nil { ins = append(ins, src) }
+        var outs []edgeOut
+        for _, ei := range n.Out { outs = append(outs, edgeOut{ch: chans[ei], pol: policyFromEdge(g.Edges[ei])}) }
+        if eo, ok := extra[i]; ok { outs = append(outs, eo) }
+        if err := run(n, fanIn(ins), outs); err != nil { return err }
+    }
+    en := graphNode{Key: "egress", Name: "egress", ID: 1}
+    var ins []<-chan ev.Event
+    var outs []edgeOut
+    if egress != nil {
+        en = *egress
+        for _, ei := range en.In { ins = append(ins, chans[ei]) }
+        for _, ei := range en.Out { outs = append(outs, edgeOut{ch: chans[ei], pol: policyFromEdge(g.Edges[ei])}) }
+    }
+    return run(en, fanIn(append(ins, sinks...)), outs)
+}
+
+// egressPolicy returns the policy for the final output channel: the policy of
+// the sole edge into egress, or the default when egress joins several edges.
+func egressPolicy(g *pipelineGraph) edgePolicy {
+    if n, ok := g.node("egress"); ok && len(n.In) == 1 { return policyFromEdge(g.Edges[n.In[0]]) }
+    return defaultEdgePolicy("", "egress")
+}
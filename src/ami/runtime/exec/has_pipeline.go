@@ -0,0 +1,10 @@
+package exec
+
+// HasPipeline reports whether the compiled debug artifacts under rootDir
+// describe a runnable graph for pipeline in package pkg (at least an edge
+// between two nodes). Pipelines without one would run as a pass-through.
+func HasPipeline(rootDir, pkg, pipeline string) bool {
+    g, err := loadPipelineGraph(rootDir, pkg, pipeline)
+    if err != nil { _, cyc := err.(ErrPipelineCycle); return cyc }
+    return len(g.Nodes) > 1
+}
@@ -25,6 +25,10 @@ type ExecOptions struct {
     // over the in-process Workers registry. If a worker name cannot be resolved by
     // the invoker, the registry will be consulted as a fallback.
     Invoker       WorkerInvoker
+    // RequireWorkers fails every event reaching a Transform whose worker
+    // resolves through neither Invoker nor Workers (reported as E_WORKER_UNAVAILABLE)
+    // instead of passing events through unchanged.
+    RequireWorkers bool
     // ErrorChan: when provided, worker errors are sent here as errors.v1
     // payloads instead of being injected into the main event stream.
     // Callers should drain this channel to avoid goroutine leaks.
@@ -33,4 +37,22 @@ type ExecOptions struct {
     // When set, shunted events are copied, enriched with trace context under key "shunt",
     // and forwarded here non-blockingly.
     ShuntChan     chan events.Event
+    // FanOut selects how a stage with several outgoing edges distributes events:
+    // "broadcast" (default) copies each event to every edge; "roundRobin" routes
+    // each event to exactly one edge in turn.
+    FanOut        string
+    // Retry configures worker retries. Workers fed by atLeastOnce edges use
+    // MaxAttempts=3 with 10ms doubling backoff when unset; bestEffort edges
+    // never retry.
+    Retry         RetryPolicy
+    // Checkpoint enables durable Collect state: when Dir is set each Collect
+    // stage resumes from and periodically snapshots to
+    // Dir/<pipeline>/collect-<index>.json (merge.snapshot.v1).
+    Checkpoint    CheckpointOptions
+}
+
+// CheckpointOptions configures merge operator checkpointing.
+type CheckpointOptions struct {
+    Dir      string
+    Interval time.Duration // 0 = snapshot only at exit
 }
@@ -0,0 +1,48 @@
+package exec
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestRunPipelineWithStats_Checkpoint_ResumesCollectState(t *testing.T) {
+    m := MakeCollectOnlyModule(t, "app", "P", "Collect", NewMergePlan().Dedup("id").Build())
+    opts := ExecOptions{Checkpoint: CheckpointOptions{Dir: t.TempDir()}}
+    run := func(ids ...string) int {
+        eng, err := NewEngineFromModule(ir.Module{})
+        if err != nil { t.Fatalf("engine: %v", err) }
+        defer eng.Close()
+        in := make(chan ev.Event, len(ids))
+        for _, id := range ids { in <- ev.Event{Payload: map[string]any{"id": id}} }
+        close(in)
+        out, stats, err := eng.RunPipelineWithStats(context.Background(), m, "P", in, nil, "", "", opts)
+        if err != nil { t.Fatalf("run: %v", err) }
+        n := 0
+        for range out { n++ }
+        for range stats {}
+        return n
+    }
+    if n := run("a", "b"); n != 2 { t.Fatalf("first run: %d", n) }
+    if _, err := os.Stat(checkpointPath(opts.Checkpoint.Dir, "P", 0)); err != nil { t.Fatalf("checkpoint not written: %v", err) }
+    if n := run("a", "c"); n != 1 { t.Fatalf("resumed run should dedup a: got %d", n) }
+}
+
+func TestRunPipelineWithStats_Checkpoint_CorruptSnapshotFailsRun(t *testing.T) {
+    m := MakeCollectOnlyModule(t, "app", "P", "Collect", NewMergePlan().Dedup("id").Build())
+    opts := ExecOptions{Checkpoint: CheckpointOptions{Dir: t.TempDir()}}
+    path := checkpointPath(opts.Checkpoint.Dir, "P", 0)
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { t.Fatal(err) }
+    if err := os.WriteFile(path, []byte(`{"schema":"other"}`), 0o644); err != nil { t.Fatal(err) }
+    eng, err := NewEngineFromModule(ir.Module{})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    if _, _, err := eng.RunPipelineWithStats(context.Background(), m, "P", make(chan ev.Event), nil, "", "", opts); err == nil || !strings.Contains(err.Error(), "unsupported snapshot schema") {
+        t.Fatalf("expected checkpoint error, got %v", err)
+    }
+}
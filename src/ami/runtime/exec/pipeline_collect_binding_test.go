@@ -0,0 +1,50 @@
+package exec
+
+import (
+    "context"
+    "sync"
+    "testing"
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rmerge "github.com/sam-caldwell/ami/src/ami/runtime/merge"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// Two Collect steps with different plans: each runs its own plan and reports
+// stats under its own CollectSpec index.
+func TestRunPipelineWithStats_EachCollectUsesItsOwnPlan(t *testing.T) {
+    m := MakeModuleWithEdges(t, "app", "P", []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "Collect", ToID: 1},
+        {Pipeline: "P", From: "Collect", FromID: 1, To: "Collect", ToID: 2},
+        {Pipeline: "P", From: "Collect", FromID: 2, To: "egress"},
+    })
+    plain := NewMergePlan().Buffer(16, "block").Build()
+    dedup := NewMergePlan().Buffer(16, "block").Build()
+    dedup.DedupField = "id"
+    m.Pipelines[0].Collect = []ir.CollectSpec{{Step: "Collect", ID: 1, Merge: &plain}, {Step: "Collect", ID: 2, Merge: &dedup}}
+    eng, err := NewEngineFromModule(ir.Module{Concurrency: 2})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    var mu sync.Mutex
+    got := map[int]rmerge.Stats{}
+    emit := func(info StageInfo, st rmerge.Stats) {
+        if info.Kind != "collect" { return }
+        mu.Lock(); got[info.Index] = st; mu.Unlock()
+    }
+    in := make(chan ev.Event, 4)
+    for _, id := range []string{"a", "a", "b", "b"} { in <- ev.Event{Payload: map[string]any{"id": id}} }
+    close(in)
+    ctx, cancel := context.WithCancel(context.Background())
+    out, stats, err := eng.RunPipelineWithStats(ctx, m, "P", in, emit, "", "", ExecOptions{})
+    if err != nil { t.Fatalf("run: %v", err) }
+    time.Sleep(80 * time.Millisecond)
+    cancel()
+    n := 0
+    for range out { n++ }
+    for range stats {}
+    if n != 2 { t.Fatalf("expected dedup by second Collect to yield 2 events, got %d", n) }
+    mu.Lock(); defer mu.Unlock()
+    if got[0].Enqueued != 4 { t.Fatalf("first Collect stats: %+v", got[0]) }
+    if got[1].Enqueued != 2 { t.Fatalf("second Collect stats: %+v", got[1]) }
+}
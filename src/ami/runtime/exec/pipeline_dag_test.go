@@ -0,0 +1,104 @@
+package exec
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func diamondEdges() []edgeEntry {
+    return []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "A"},
+        {Pipeline: "P", From: "ingress", To: "B"},
+        {Pipeline: "P", From: "A", To: "egress"},
+        {Pipeline: "P", From: "B", To: "egress"},
+    }
+}
+
+func runDiamond(t *testing.T, opts ExecOptions, transform string) []ev.Event {
+    t.Helper()
+    m := MakeModuleWithEdges(t, "app", "P", diamondEdges())
+    eng, err := NewEngineFromModule(ir.Module{})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    in := make(chan ev.Event, 4)
+    for i := 0; i < 4; i++ { in <- ev.Event{Payload: map[string]any{"i": i}} }
+    close(in)
+    out, stats, err := eng.RunPipelineWithStats(ctx, m, "P", in, nil, "", transform, opts)
+    if err != nil { t.Fatalf("run: %v", err) }
+    var got []ev.Event
+    for e := range out { got = append(got, e) }
+    names := map[string]bool{}
+    for s := range stats { names[s.Stage.Name] = true }
+    if !names["A"] || !names["B"] { t.Fatalf("expected stats for both branches: %v", names) }
+    return got
+}
+
+func TestRunPipelineWithStats_FanOut_BroadcastsToEveryBranch(t *testing.T) {
+    got := runDiamond(t, ExecOptions{}, "add_field:seen")
+    if len(got) != 8 { t.Fatalf("expected 8 events (4 per branch), got %d", len(got)) }
+}
+
+func TestRunPipelineWithStats_FanOut_RoundRobinRoutesOnce(t *testing.T) {
+    got := runDiamond(t, ExecOptions{FanOut: "roundRobin"}, "")
+    if len(got) != 4 { t.Fatalf("expected 4 events, got %d", len(got)) }
+}
+
+func TestRunPipelineWithStats_FanIn_CollectMergesBranches(t *testing.T) {
+    edges := []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "A"},
+        {Pipeline: "P", From: "ingress", To: "B"},
+        {Pipeline: "P", From: "A", To: "Collect"},
+        {Pipeline: "P", From: "B", To: "Collect"},
+        {Pipeline: "P", From: "Collect", To: "egress"},
+    }
+    m := AttachCollect(MakeModuleWithEdges(t, "app", "P", edges), "P", "Collect", NewMergePlan().Buffer(16, "block").Build())
+    eng, err := NewEngineFromModule(ir.Module{})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    ctx, cancel := context.WithCancel(context.Background())
+    in := make(chan ev.Event, 3)
+    for i := 0; i < 3; i++ { in <- ev.Event{Payload: map[string]any{"i": i}} }
+    close(in)
+    out, stats, err := eng.RunPipelineWithStats(ctx, m, "P", in, nil, "", "", ExecOptions{})
+    if err != nil { t.Fatalf("run: %v", err) }
+    time.Sleep(50 * time.Millisecond)
+    cancel()
+    n := 0
+    for range out { n++ }
+    for range stats {}
+    if n != 6 { t.Fatalf("expected 6 merged events, got %d", n) }
+}
+
+func TestRunPipeline_FanOut_Broadcast(t *testing.T) {
+    m := MakeModuleWithEdges(t, "app", "P", diamondEdges())
+    eng, err := NewEngineFromModule(ir.Module{})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    in := make(chan ev.Event, 2)
+    in <- ev.Event{Payload: 1}; in <- ev.Event{Payload: 2}
+    close(in)
+    out, err := eng.RunPipeline(context.Background(), m, "P", in)
+    if err != nil { t.Fatalf("run: %v", err) }
+    n := 0
+    for range out { n++ }
+    if n != 4 { t.Fatalf("expected 4 events, got %d", n) }
+}
+
+func TestRunPipeline_Cycle_ReturnsError(t *testing.T) {
+    m := MakeModuleWithEdges(t, "app", "P", []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "A"},
+        {Pipeline: "P", From: "A", To: "B"},
+        {Pipeline: "P", From: "B", To: "A"},
+    })
+    eng, _ := NewEngineFromModule(ir.Module{})
+    defer eng.Close()
+    if _, err := eng.RunPipeline(context.Background(), m, "P", make(chan ev.Event)); err == nil {
+        t.Fatalf("expected cycle error")
+    }
+}
@@ -0,0 +1,68 @@
+package exec
+
+import (
+    "context"
+    "testing"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func runLinearExpr(t *testing.T, filter, transform string, opts ExecOptions, payloads ...any) []ev.Event {
+    t.Helper()
+    m := MakeModuleWithEdges(t, "app", "P", []edgeEntry{
+        {Pipeline: "P", From: "ingress", To: "A"},
+        {Pipeline: "P", From: "A", To: "egress"},
+    })
+    eng, err := NewEngineFromModule(ir.Module{})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    in := make(chan ev.Event, len(payloads))
+    for _, p := range payloads { in <- ev.Event{Payload: p} }
+    close(in)
+    out, stats, err := eng.RunPipelineWithStats(context.Background(), m, "P", in, nil, filter, transform, opts)
+    if err != nil { t.Fatalf("run: %v", err) }
+    var got []ev.Event
+    for e := range out { got = append(got, e) }
+    for range stats {}
+    return got
+}
+
+func TestRunPipelineWithStats_FilterAndTransformExpressions(t *testing.T) {
+    got := runLinearExpr(t, "i % 2 == 1 && has(user)", "set user.name = upper(user.name); del tmp",
+        ExecOptions{},
+        map[string]any{"i": 1, "user": map[string]any{"name": "ada"}, "tmp": 1},
+        map[string]any{"i": 2, "user": map[string]any{"name": "bob"}},
+        map[string]any{"i": 3},
+    )
+    if len(got) != 1 { t.Fatalf("expected 1 event, got %d", len(got)) }
+    m := got[0].Payload.(map[string]any)
+    if m["user"].(map[string]any)["name"] != "ADA" { t.Fatalf("transform not applied: %v", m) }
+    if _, ok := m["tmp"]; ok { t.Fatalf("tmp not deleted: %v", m) }
+}
+
+func TestRunPipelineWithStats_ExprRuntimeError_DropsAndReports(t *testing.T) {
+    ec := make(chan errs.Error, 4)
+    got := runLinearExpr(t, "n > 1", "", ExecOptions{ErrorChan: ec},
+        map[string]any{"n": 2}, map[string]any{"n": "x"})
+    if len(got) != 1 { t.Fatalf("expected 1 event, got %d", len(got)) }
+    select {
+    case e := <-ec:
+        if e.Code != "E_FILTER" { t.Fatalf("expected E_FILTER, got %s", e.Code) }
+    default:
+        t.Fatalf("expected error report")
+    }
+}
+
+func TestRunPipelineWithStats_ExprParseError_Fails(t *testing.T) {
+    m := MakeModuleWithEdges(t, "app", "P", []edgeEntry{{Pipeline: "P", From: "ingress", To: "egress"}})
+    eng, _ := NewEngineFromModule(ir.Module{})
+    defer eng.Close()
+    if _, _, err := eng.RunPipelineWithStats(context.Background(), m, "P", make(chan ev.Event), nil, "i ==", "", ExecOptions{}); err == nil {
+        t.Fatalf("expected parse error")
+    }
+    if _, _, err := eng.RunPipelineWithStats(context.Background(), m, "P", make(chan ev.Event), nil, "", "frob x", ExecOptions{}); err == nil {
+        t.Fatalf("expected transform parse error")
+    }
+}
@@ -0,0 +1,35 @@
+package exec
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+func TestRunPipelineWithStats_TimerSource_VirtualClock(t *testing.T) {
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    clk := amitime.NewVirtualClock(start)
+    prev := amitime.SetClock(clk)
+    defer amitime.SetClock(prev)
+    eng, err := NewEngineFromModule(ir.Module{Concurrency: 1, Schedule: "fifo"})
+    if err != nil { t.Fatalf("engine: %v", err) }
+    defer eng.Close()
+    m := MakeModuleWithEdges(t, "vclock", "P", []edgeEntry{{Pipeline: "P", From: "ingress", To: "Timer"}, {Pipeline: "P", From: "Timer", To: "egress"}})
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    out, statsCh, err := eng.RunPipelineWithStats(ctx, m, "P", nil, nil, "", "", ExecOptions{Sandbox: SandboxPolicy{AllowDevice: true}, TimerInterval: time.Hour, TimerCount: 3})
+    if err != nil { t.Fatalf("run: %v", err) }
+    clk.BlockUntil(1) // timer source armed
+    for i := 1; i <= 3; i++ {
+        clk.Advance(time.Hour)
+        e, ok := <-out
+        if !ok { t.Fatalf("output closed after %d ticks", i-1) }
+        m := e.Payload.(map[string]any)
+        if ts, _ := m["ts"].(time.Time); !ts.Equal(start.Add(time.Duration(i) * time.Hour)) { t.Fatalf("tick %d at %v", i, m["ts"]) }
+    }
+    for range out {}
+    for range statsCh {}
+}
@@ -0,0 +1,61 @@
+package exec
+
+import (
+    "context"
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+// RetryPolicy controls how a worker stage retries failed events. Each retry
+// increments the event's Attempt; once MaxAttempts tries have failed the event
+// is reported on ErrorChan as errors.v1 (code E_RETRY_EXHAUSTED, or the code of a
+// structured WorkerError).
+type RetryPolicy struct {
+    MaxAttempts int           // total tries per event at one stage (<=1 disables retry)
+    Backoff     time.Duration // delay before the first retry; doubles per retry
+    MaxBackoff  time.Duration // cap for the doubled delay (0 = uncapped)
+}
+
+// defaultRetryPolicy applies to workers fed by explicit atLeastOnce edges
+// (see deliveryFor) when ExecOptions.Retry is unset.
+var defaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond, MaxBackoff: time.Second}
+
+// delay returns the backoff before retry n (1-based).
+func (p RetryPolicy) delay(n int) time.Duration {
+    d := p.Backoff
+    for i := 1; i < n && d > 0; i++ {
+        d *= 2
+        if p.MaxBackoff > 0 && d >= p.MaxBackoff { return p.MaxBackoff }
+    }
+    if p.MaxBackoff > 0 && d > p.MaxBackoff { return p.MaxBackoff }
+    return d
+}
+
+// retryPolicyFor resolves the policy for a worker whose inbound delivery mode
+// is delivery: bestEffort never retries; atLeastOnce falls back to the default
+// policy when ExecOptions.Retry is unset.
+func (r *stageRunner) retryPolicyFor(delivery string) RetryPolicy {
+    p := r.opts.Retry
+    switch delivery {
+    case "bestEffort":
+        p.MaxAttempts = 1
+    case "atLeastOnce":
+        if p.MaxAttempts == 0 { p = defaultRetryPolicy }
+    }
+    if p.MaxAttempts < 1 { p.MaxAttempts = 1 }
+    return p
+}
+
+// sleepCtx waits for d on the runtime clock (amitime.GetClock, so virtual
+// time drives retry backoff too) or until ctx is done, reporting whether d
+// elapsed.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+    if d <= 0 { return ctx.Err() == nil }
+    t := amitime.GetClock().NewTimer(d)
+    defer t.Stop()
+    select {
+    case <-t.C(): return true
+    case <-ctx.Done(): return false
+    }
+}
@@ -0,0 +1,129 @@
+package exec
+
+import (
+    "context"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rmerge "github.com/sam-caldwell/ami/src/ami/runtime/merge"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+)
+
+// stageRunner holds the per-run state shared by every stage goroutine started
+// by RunPipelineWithStats: options, compiled filter/transform expressions,
+// worker resolution and the stats sink.
+type stageRunner struct {
+    eng           *Engine
+    ctx           context.Context
+    module        ir.Module
+    pipeline      string
+    opts          ExecOptions
+    filter        func(ev.Event) (bool, error)
+    xform         func(ev.Event) (ev.Event, error)
+    invoker       WorkerInvoker
+    workers       []string
+    emit          func(StageInfo, rmerge.Stats)
+    statsOut      chan StageStats
+}
+
+// forward reports final stage stats to the callback and the stats channel.
+// Sends after the stats channel is closed are ignored.
+func (r *stageRunner) forward(info StageInfo, st rmerge.Stats) {
+    if r.emit != nil { r.emit(info, st) }
+    defer func(){ _ = recover() }()
+    r.statsOut <- StageStats{Stage: info, Stats: st}
+}
+
+// send delivers e on next honoring the edge backpressure policy.
+func (r *stageRunner) send(next chan ev.Event, e ev.Event, st *rmerge.Stats, pol edgePolicy) {
+    switch pol.bp {
+    case "dropNewest":
+        select {
+        case next <- e:
+        default:
+            if st != nil { st.Dropped++ }
+        }
+    case "dropOldest":
+        select {
+        case next <- e:
+        default:
+            // buffer full: evict one oldest then try once more
+            select { case <-next: default: }
+            select { case next <- e: default: if st != nil { st.Dropped++ } }
+        }
+    case "shuntNewest":
+        // attempt send; if full, emit shunt advisory and drop from main stream
+        select {
+        case next <- e:
+        default:
+            if st != nil { st.Dropped++ }
+            r.shunt(e, pol, "W_SHUNTED_NEWEST", "shunted (newest)")
+        }
+    case "shuntOldest":
+        // attempt send; if full, evict one oldest and attempt once, else shunt
+        select {
+        case next <- e:
+        default:
+            select { case <-next: default: }
+            select {
+            case next <- e:
+            default:
+                if st != nil { st.Dropped++ }
+                r.shunt(e, pol, "W_SHUNTED_OLDEST", "shunted (oldest)")
+            }
+        }
+    default: // block
+        next <- e
+    }
+}
+
+// shunt emits a non-blocking advisory and a trace-enriched copy of e to the
+// configured error and shunt channels.
+func (r *stageRunner) shunt(e ev.Event, pol edgePolicy, code, msg string) {
+    edge := map[string]any{"from": pol.from, "to": pol.to, "id": pol.from+"->"+pol.to}
+    if r.opts.ErrorChan != nil {
+        data := map[string]any{"pipeline": r.pipeline, "edge": edge}
+        select { case r.opts.ErrorChan <- errs.Error{Level: "warn", Code: code, Message: msg, Data: data}: default: }
+    }
+    if r.opts.ShuntChan != nil {
+        se := e
+        if se.Trace == nil { se.Trace = map[string]any{} }
+        se.Trace["shunt"] = map[string]any{"pipeline": r.pipeline, "edge": edge, "policy": pol.bp}
+        select { case r.opts.ShuntChan <- se: default: }
+    }
+}
+
+// reportExprError forwards a filter/transform evaluation failure as errors.v1
+// to the error channel without blocking.
+func (r *stageRunner) reportExprError(code, stage string, err error) {
+    if r.opts.ErrorChan == nil { return }
+    data := map[string]any{"pipeline": r.pipeline, "stage": stage}
+    select { case r.opts.ErrorChan <- errs.Error{Level: "error", Code: code, Message: err.Error(), Data: data}: default: }
+}
+
+// fanOut delivers e to the stage's outgoing edges. By default every edge
+// receives its own copy (broadcast); with FanOut "roundRobin" each event is
+// routed to exactly one edge in turn, tracked by seq.
+func (r *stageRunner) fanOut(outs []edgeOut, e ev.Event, st *rmerge.Stats, seq *int) {
+    switch len(outs) {
+    case 0:
+        return
+    case 1:
+        r.send(outs[0].ch, e, st, outs[0].pol)
+        return
+    }
+    if r.opts.FanOut == "roundRobin" {
+        o := outs[*seq%len(outs)]
+        *seq++
+        r.send(o.ch, e, st, o.pol)
+        return
+    }
+    // Copy before the first send so no branch can mutate e while others clone it.
+    copies := make([]ev.Event, len(outs))
+    copies[0] = e
+    for i := 1; i < len(outs); i++ { copies[i] = cloneEvent(e) }
+    for i, o := range outs { r.send(o.ch, copies[i], st, o.pol) }
+}
+
+// closeOuts closes every outgoing edge channel of a finished stage.
+func closeOuts(outs []edgeOut) { for _, o := range outs { close(o.ch) } }
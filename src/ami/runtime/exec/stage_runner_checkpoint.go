@@ -0,0 +1,48 @@
+package exec
+
+import (
+    "fmt"
+    "path/filepath"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rmerge "github.com/sam-caldwell/ami/src/ami/runtime/merge"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// runCollectMerge starts the merge for Collect spec idx, checkpointing its
+// operator state when ExecOptions.Checkpoint.Dir is set.
+func (r *stageRunner) runCollectMerge(idx int, plan ir.MergePlan, in <-chan ev.Event) (<-chan ev.Event, *rmerge.Stats, error) {
+    if r.opts.Checkpoint.Dir == "" { return r.eng.runMergeStageWithStats(r.ctx, plan, in) }
+    cp := rmerge.Checkpoint{
+        Path:     checkpointPath(r.opts.Checkpoint.Dir, r.pipeline, idx),
+        Interval: r.opts.Checkpoint.Interval,
+        OnError: func(err error) {
+            if r.opts.ErrorChan == nil { return }
+            data := map[string]any{"pipeline": r.pipeline, "collect": idx}
+            select { case r.opts.ErrorChan <- errs.Error{Level: "error", Code: "E_CHECKPOINT", Message: err.Error(), Data: data}: default: }
+        },
+    }
+    return r.eng.RunMergeWithCheckpoint(r.ctx, plan, in, cp)
+}
+
+// checkCheckpoints verifies that every existing Collect snapshot of pipeline
+// restores under its plan, so a corrupt checkpoint fails the run before any
+// stage starts instead of stalling the Collect stage.
+func checkCheckpoints(m ir.Module, pipeline string, opts ExecOptions) error {
+    if opts.Checkpoint.Dir == "" { return nil }
+    for _, p := range m.Pipelines {
+        if p.Name != pipeline { continue }
+        for idx, c := range p.Collect {
+            if c.Merge == nil { continue }
+            path := checkpointPath(opts.Checkpoint.Dir, pipeline, idx)
+            if err := rmerge.CheckSnapshot(toRuntimePlan(*c.Merge), path); err != nil { return fmt.Errorf("checkpoint %s: %w", path, err) }
+        }
+    }
+    return nil
+}
+
+// checkpointPath is the snapshot file for Collect spec idx of pipeline.
+func checkpointPath(dir, pipeline string, idx int) string {
+    return filepath.Join(dir, pipeline, fmt.Sprintf("collect-%d.json", idx))
+}
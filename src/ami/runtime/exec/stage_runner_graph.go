@@ -0,0 +1,44 @@
+package exec
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// runGraph starts one stage per node of g and returns the egress output.
+// Transform occurrences bind to worker names and Collect occurrences bind to
+// their CollectSpec by occurrence ID; transform indices follow topological order.
+func (r *stageRunner) runGraph(g *pipelineGraph, in <-chan ev.Event) (<-chan ev.Event, error) {
+    var out <-chan ev.Event
+    hasTimer := g.has("Timer")
+    tIdx := 0
+    err := wireGraph(g, in, func(n graphNode, nin <-chan ev.Event, outs []edgeOut) error {
+        switch n.Name {
+        case "ingress":
+            if hasTimer { return r.timer(outs) }
+            r.ingress(nin, outs)
+        case "egress":
+            out = r.egress(nin, egressPolicy(g), outs)
+        case "Collect":
+            idx, plan := collectSpecFor(r.module, r.pipeline, n)
+            return r.collect(idx, n.Name, plan, nin, outs)
+        case "GpuDispatch":
+            err := r.gpuDispatch(tIdx, nin, outs)
+            tIdx++
+            return err
+        default:
+            if w := r.workerFor(n); w != "" {
+                r.worker(tIdx, w, deliveryFor(g, n), nin, outs)
+            } else {
+                r.transform(tIdx, n.Name, nin, outs)
+            }
+            tIdx++
+        }
+        return nil
+    })
+    if err != nil { return nil, err }
+    return out, nil
+}
+
+// workerFor returns the worker bound to a Transform occurrence, if any.
+func (r *stageRunner) workerFor(n graphNode) string {
+    if n.Name != "Transform" || n.ID < 1 || n.ID > len(r.workers) { return "" }
+    return r.workers[n.ID-1]
+}
@@ -0,0 +1,221 @@
+package exec
+
+import (
+    "errors"
+    "fmt"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    amitrigger "github.com/sam-caldwell/ami/src/ami/runtime/host/trigger"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+    amigpu "github.com/sam-caldwell/ami/src/ami/runtime/host/gpu"
+    rmerge "github.com/sam-caldwell/ami/src/ami/runtime/merge"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+)
+
+// ingress forwards source events to the first hop(s) with counters. Like
+// every stage it reports its stats before closing its outputs, so egress
+// cannot close the stats channel while upstream stats are still pending.
+func (r *stageRunner) ingress(in <-chan ev.Event, outs []edgeOut) {
+    go func(){
+        var st rmerge.Stats
+        seq := 0
+        for e := range in { st.Enqueued++; r.fanOut(outs, e, &st, &seq); st.Emitted++ }
+        r.forward(StageInfo{Name: "ingress", Kind: "ingress", Index: 0}, st)
+        closeOuts(outs)
+    }()
+}
+
+// timer replaces ingress when the pipeline contains a Timer node, emitting
+// {i, ts} payloads at opts.TimerInterval until TimerCount or cancellation.
+func (r *stageRunner) timer(outs []edgeOut) error {
+    if err := sandboxCheck(r.opts.Sandbox, "device"); err != nil { return err }
+    go func(){
+        var st rmerge.Stats
+        seq := 0
+        // Use AMI trigger.Timer to emit time events and adapt to schemas.Event
+        tCh, stop := amitrigger.Timer(amitime.Duration(r.opts.TimerInterval))
+        defer stop()
+    loop:
+        for i := 0; r.opts.TimerCount <= 0 || i < r.opts.TimerCount; {
+            select {
+            case <-r.ctx.Done():
+                break loop
+            case tm := <-tCh:
+                st.Enqueued++
+                e := ev.Event{Payload: map[string]any{"i": i, "ts": toStdTime(tm.Value)}}
+                r.fanOut(outs, e, &st, &seq)
+                st.Emitted++
+                i++
+            }
+        }
+        r.forward(StageInfo{Name: "Timer", Kind: "ingress", Index: 0}, st)
+        closeOuts(outs)
+    }()
+    return nil
+}
+
+// transform applies the compiled filter and transform and forwards survivors.
+// Events whose expressions fail to evaluate are dropped and reported as
+// E_FILTER/E_TRANSFORM on the error channel.
+func (r *stageRunner) transform(idx int, name string, in <-chan ev.Event, outs []edgeOut) {
+    go func(){
+        var st rmerge.Stats
+        seq := 0
+        for e := range in {
+            st.Enqueued++
+            keep, err := r.filter(e)
+            if err != nil { r.reportExprError("E_FILTER", name, err) }
+            if err != nil || !keep { st.Dropped++; continue }
+            e, err = r.xform(e)
+            if err != nil { r.reportExprError("E_TRANSFORM", name, err); st.Dropped++; continue }
+            r.fanOut(outs, e, &st, &seq); st.Emitted++
+        }
+        r.forward(StageInfo{Name: name, Kind: "transform", Index: idx}, st)
+        closeOuts(outs)
+    }()
+}
+
+// gpuDispatch is a minimal GPU transform stage placeholder that integrates with
+// scheduling and honors sandbox policy. It performs backend preflight checks and
+// then passes events through unchanged to keep determinism in tests/environments
+// without a real GPU. Deterministic stats are emitted upon completion.
+func (r *stageRunner) gpuDispatch(idx int, in <-chan ev.Event, outs []edgeOut) error {
+    if err := sandboxCheck(r.opts.Sandbox, "device"); err != nil { return err }
+    // Preflight probes: call availability helpers (results are advisory here)
+    _ = amigpu.MetalAvailable()
+    _ = amigpu.CudaAvailable()
+    _ = amigpu.OpenCLAvailable()
+    go func(){
+        var st rmerge.Stats
+        seq := 0
+        for e := range in {
+            st.Enqueued++
+            // In this bring-up stage, dispatch is a no-op to preserve determinism
+            // across hosts; the payload is forwarded unmodified.
+            r.fanOut(outs, e, &st, &seq)
+            st.Emitted++
+        }
+        r.forward(StageInfo{Name: "GpuDispatch", Kind: "transform", Index: idx}, st)
+        closeOuts(outs)
+    }()
+    return nil
+}
+
+// worker runs the named worker over each event. The dynamic invoker takes
+// precedence over the in-process registry; unresolved workers act as identity.
+// Failed calls are retried per the stage's RetryPolicy (incrementing Attempt, with backoff);
+// an event is acknowledged once the worker returns without error. Final
+// failures go to ErrorChan as errors.v1 (blocking for atLeastOnce inputs so
+// they are never lost) or, without ErrorChan, into the stream as payload.
+func (r *stageRunner) worker(idx int, wname, delivery string, in <-chan ev.Event, outs []edgeOut) {
+    wf := func(e ev.Event) (any, error) { return e, nil }
+    resolved := false
+    if r.invoker != nil {
+        if f, ok := r.invoker.Resolve(wname); ok && f != nil { wf = f; resolved = true }
+    }
+    if !resolved && r.opts.Workers != nil {
+        if f, ok := r.opts.Workers[wname]; ok && f != nil { wf = f; resolved = true }
+    }
+    pol := r.retryPolicyFor(delivery)
+    if !resolved && r.opts.RequireWorkers {
+        wf = func(ev.Event) (any, error) {
+            return nil, &WorkerError{Code: "E_WORKER_UNAVAILABLE", Message: fmt.Sprintf("worker %q is not available: no compiled workers library or registered worker provides it", wname)}
+        }
+        pol = RetryPolicy{MaxAttempts: 1}
+    }
+    go func(){
+        var st rmerge.Stats
+        seq := 0
+        for e := range in {
+            st.Enqueued++
+            if e.Attempt < 1 { e.Attempt = 1 }
+            out, err := wf(e)
+            for try := 1; err != nil && try < pol.MaxAttempts; try++ {
+                if !sleepCtx(r.ctx, pol.delay(try)) { break }
+                e.Attempt++
+                out, err = wf(e)
+            }
+            if err != nil {
+                r.workerFailed(wname, delivery, pol, e, err, outs, &st, &seq)
+                continue
+            }
+            switch v := out.(type) {
+            case ev.Event:
+                r.fanOut(outs, v, &st, &seq)
+            default:
+                ne := e; ne.Payload = v; r.fanOut(outs, ne, &st, &seq)
+            }
+            st.Emitted++
+        }
+        r.forward(StageInfo{Name: wname, Kind: "transform", Index: idx}, st)
+        closeOuts(outs)
+    }()
+}
+
+// workerFailed reports an event whose worker call failed on its final attempt.
+// A structured WorkerError keeps its code, message and data; the runtime only adds
+// its context keys (worker, attempts, event) where the worker's data lacks them.
+func (r *stageRunner) workerFailed(wname, delivery string, pol RetryPolicy, e ev.Event, err error, outs []edgeOut, st *rmerge.Stats, seq *int) {
+    code := "E_WORKER"
+    msg := err.Error()
+    data := map[string]any{}
+    var we *WorkerError
+    if errors.As(err, &we) && we.Code != "" {
+        code, msg = we.Code, we.Message
+        for k, v := range we.Data { data[k] = v }
+    }
+    setDefault := func(k string, v any) { if _, ok := data[k]; !ok { data[k] = v } }
+    setDefault("worker", wname)
+    if pol.MaxAttempts > 1 {
+        if we == nil || we.Code == "" { code = "E_RETRY_EXHAUSTED" }
+        setDefault("attempts", e.Attempt)
+        setDefault("event", map[string]any{"id": e.ID, "payload": e.Payload})
+    }
+    ee := errs.Error{Level: "error", Code: code, Message: msg, Data: data}
+    if r.opts.ErrorChan == nil { ne := e; ne.Payload = ee; r.fanOut(outs, ne, st, seq); st.Emitted++; return }
+    if delivery == "atLeastOnce" {
+        select { case r.opts.ErrorChan <- ee: case <-r.ctx.Done(): st.Dropped++ }
+        return
+    }
+    select { case r.opts.ErrorChan <- ee: default: }
+}
+
+// collect runs a merge operator for plan and forwards its output. Without a
+// plan the stage is a transparent relay. Merge stats are reported once the
+// merge output closes, under the step name and its CollectSpec index.
+func (r *stageRunner) collect(idx int, name string, plan *ir.MergePlan, in <-chan ev.Event, outs []edgeOut) error {
+    src := in
+    var sp *rmerge.Stats
+    if plan != nil {
+        oc, s, err := r.runCollectMerge(idx, *plan, in)
+        if err != nil { return err }
+        src, sp = oc, s
+    }
+    go func(){
+        seq := 0
+        for e := range src { r.fanOut(outs, e, nil, &seq) }
+        // merge output closes only after the operator exits, so stats are final;
+        // report before closing outs so egress cannot close the stats channel first.
+        if sp != nil { r.forward(StageInfo{Name: name, Kind: "collect", Index: idx}, *sp) }
+        closeOuts(outs)
+    }()
+    return nil
+}
+
+// egress forwards to the returned output channel, sized and policed by pol,
+// then reports egress stats and closes the stats channel.
+func (r *stageRunner) egress(in <-chan ev.Event, pol edgePolicy, outs []edgeOut) <-chan ev.Event {
+    buf := pol.cap
+    if buf <= 0 { buf = 1024 }
+    next := make(chan ev.Event, buf)
+    closeOuts(outs)
+    go func(){
+        var st rmerge.Stats
+        for e := range in { st.Enqueued++; r.send(next, e, &st, pol); st.Emitted++ }
+        close(next)
+        r.forward(StageInfo{Name: "egress", Kind: "egress", Index: 0}, st)
+        close(r.statsOut)
+    }()
+    return next
+}
@@ -18,6 +18,19 @@ func WriteEdges(t *testing.T, pkg, pipeline string, edges []edgeEntry) {
     if err := os.WriteFile(filepath.Join(dir, "edges.json"), b, 0o644); err != nil { t.Fatalf("write edges: %v", err) }
 }
 
+// WriteTransformWorker writes build/debug/ir/<pkg>/u.pipelines.json declaring
+// ingress -> step(worker) -> egress, so the step resolves to the named worker.
+func WriteTransformWorker(t *testing.T, pkg, pipeline, step, worker string) {
+    t.Helper()
+    dir := filepath.Join("build", "debug", "ir", pkg)
+    if err := os.MkdirAll(dir, 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    pl := map[string]any{"pipelines": []any{map[string]any{"name": pipeline, "steps": []any{
+        map[string]any{"name": "ingress"}, map[string]any{"name": step, "args": []string{worker}}, map[string]any{"name": "egress"},
+    }}}}
+    b, _ := json.Marshal(pl)
+    if err := os.WriteFile(filepath.Join(dir, "u.pipelines.json"), b, 0o644); err != nil { t.Fatalf("write pipelines: %v", err) }
+}
+
 // MakeModuleWithEdges constructs a minimal Module and writes edges for tests.
 func MakeModuleWithEdges(t *testing.T, pkg, pipeline string, edges []edgeEntry) ir.Module {
     t.Helper()
@@ -18,7 +18,13 @@ func toRuntimePlan(p ir.MergePlan) rmerge.Plan {
     rp.TimeoutMs = p.TimeoutMs
     rp.LatePolicy = p.LatePolicy
     if p.DedupField != "" { rp.Dedup.Field = p.DedupField }
+    rp.Dedup.TTLMs = p.DedupTTLMs
+    rp.Dedup.MaxKeys = p.DedupMaxKeys
+    rp.Dedup.Mode = p.DedupMode
+    rp.Dedup.FPRate = p.DedupFPRate
     if p.Watermark != nil { rp.Watermark = &rmerge.Watermark{Field: p.Watermark.Field, LatenessMs: p.Watermark.LatenessMs} }
+    if p.EventWindow != nil { rp.EventWindow = &rmerge.EventWindow{Kind: p.EventWindow.Kind, SizeMs: p.EventWindow.SizeMs, SlideMs: p.EventWindow.SlideMs, GapMs: p.EventWindow.GapMs} }
+    for _, a := range p.Aggregates { rp.Aggregates = append(rp.Aggregates, rmerge.Aggregate{Fn: a.Fn, Field: a.Field, As: a.As}) }
     return rp
 }
 
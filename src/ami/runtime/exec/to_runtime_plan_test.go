@@ -1,5 +1,29 @@
 package exec
 
-import "testing"
+import (
+    "testing"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
 
 func Test_to_runtime_plan_Exists(t *testing.T) {}
+
+func TestToRuntimePlan_EventWindowAndAggregates(t *testing.T) {
+    p := ir.MergePlan{
+        Watermark:   &ir.Watermark{Field: "ts", LatenessMs: 100},
+        EventWindow: &ir.EventWindow{Kind: "sliding", SizeMs: 1000, SlideMs: 500},
+        Aggregates:  []ir.Aggregate{{Fn: "count"}, {Fn: "avg", Field: "v", As: "mean"}},
+    }
+    rp := toRuntimePlan(p)
+    if rp.EventWindow == nil || rp.EventWindow.Kind != "sliding" || rp.EventWindow.SizeMs != 1000 || rp.EventWindow.SlideMs != 500 {
+        t.Fatalf("event window: %+v", rp.EventWindow)
+    }
+    if len(rp.Aggregates) != 2 || rp.Aggregates[1].Field != "v" || rp.Aggregates[1].As != "mean" { t.Fatalf("aggregates: %+v", rp.Aggregates) }
+}
+
+func TestToRuntimePlan_DedupHorizon(t *testing.T) {
+    rp := toRuntimePlan(ir.MergePlan{DedupField: "id", DedupTTLMs: 1000, DedupMaxKeys: 10, DedupMode: "bloom", DedupFPRate: 0.05})
+    if rp.Dedup.Field != "id" || rp.Dedup.TTLMs != 1000 || rp.Dedup.MaxKeys != 10 || rp.Dedup.Mode != "bloom" || rp.Dedup.FPRate != 0.05 {
+        t.Fatalf("dedup: %+v", rp.Dedup)
+    }
+}
@@ -0,0 +1,39 @@
+package exec
+
+import (
+    "encoding/json"
+    "strings"
+)
+
+// WorkerError is a structured worker failure. When a worker returns one (directly
+// or wrapped), its Code, Message and Data are copied verbatim into the errors.v1
+// record sent to the pipeline's error pipeline instead of the generic E_WORKER.
+type WorkerError struct {
+    Code    string
+    Message string
+    Data    map[string]any
+}
+
+// Error renders "code: message" (or just the message when Code is empty).
+func (e *WorkerError) Error() string {
+    if e.Code == "" { return e.Message }
+    if e.Message == "" { return e.Code }
+    return e.Code + ": " + e.Message
+}
+
+// ParseWorkerError decodes the error string reported by a compiled worker. A JSON
+// object with a non-empty "code" (errors.v1 or {code,message,data}) yields a
+// WorkerError; any other string is not structured.
+func ParseWorkerError(s string) (*WorkerError, bool) {
+    s = strings.TrimSpace(s)
+    if !strings.HasPrefix(s, "{") { return nil, false }
+    var obj struct {
+        Schema  string         `json:"schema"`
+        Code    string         `json:"code"`
+        Message string         `json:"message"`
+        Data    map[string]any `json:"data"`
+    }
+    if err := json.Unmarshal([]byte(s), &obj); err != nil || obj.Code == "" { return nil, false }
+    if obj.Schema != "" && obj.Schema != "errors.v1" { return nil, false }
+    return &WorkerError{Code: obj.Code, Message: obj.Message, Data: obj.Data}, true
+}
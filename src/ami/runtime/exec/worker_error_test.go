@@ -0,0 +1,48 @@
+package exec
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+)
+
+func TestParseWorkerError(t *testing.T) {
+    we, ok := ParseWorkerError(`{"schema":"errors.v1","code":"E_BAD_SKU","message":"unknown sku","data":{"sku":"x1"}}`)
+    if !ok || we.Code != "E_BAD_SKU" || we.Message != "unknown sku" || we.Data["sku"] != "x1" { t.Fatalf("parse: %+v %v", we, ok) }
+    if we.Error() != "E_BAD_SKU: unknown sku" { t.Fatalf("Error(): %q", we.Error()) }
+    for _, s := range []string{"boom", `{"message":"no code"}`, `{"schema":"diag.v1","code":"E_X"}`, `{bad json`} {
+        if _, ok := ParseWorkerError(s); ok { t.Fatalf("%q: unexpected structured error", s) }
+    }
+}
+
+// A structured WorkerError keeps its code, message and data in the errors.v1 record.
+func TestWorker_StructuredError_MappedVerbatim(t *testing.T) {
+    m := MakeTransformOnlyModule(t, "app", "P", []string{"Transform"})
+    WriteTransformWorker(t, "app", "P", "Transform", "W")
+    eng := &Engine{}
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    in := make(chan ev.Event, 1)
+    in <- ev.Event{Payload: map[string]any{"sku": "x1"}}
+    close(in)
+    errCh := make(chan errs.Error, 1)
+    opts := ExecOptions{Workers: map[string]func(ev.Event) (any, error){
+        "W": func(e ev.Event) (any, error) {
+            we := &WorkerError{Code: "E_BAD_SKU", Message: "unknown sku", Data: map[string]any{"sku": "x1", "worker": "lookup"}}
+            return nil, fmt.Errorf("validate: %w", we)
+        },
+    }, ErrorChan: errCh}
+    out, statsCh, err := eng.RunPipelineWithStats(ctx, m, "P", in, nil, "", "", opts)
+    if err != nil { t.Fatalf("run: %v", err) }
+    for range out { t.Fatalf("no main outputs expected") }
+    for range statsCh {}
+    select {
+    case e := <-errCh:
+        if e.Code != "E_BAD_SKU" || e.Message != "unknown sku" { t.Fatalf("code/message: %+v", e) }
+        if e.Data["sku"] != "x1" || e.Data["worker"] != "lookup" { t.Fatalf("data: %+v", e.Data) }
+    default:
+        t.Fatalf("expected error on error channel")
+    }
+}
@@ -0,0 +1,89 @@
+package exec
+
+import (
+    "errors"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "runtime"
+    "strings"
+    "testing"
+
+    llvme "github.com/sam-caldwell/ami/src/ami/compiler/codegen/llvm"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// workerErrorLL holds workers shaped like the compiled core wrappers' error
+// path: they build an error handle and return ami_rt_error_to_cstring's
+// result in *err with its length in *out_len.
+const workerErrorLL = `
+declare ptr @ami_rt_owned_new(ptr, i64)
+declare ptr @ami_rt_error_make(ptr, ptr)
+declare ptr @ami_rt_error_to_cstring(ptr, ptr)
+
+@.code = private constant [5 x i8] c"E_BAD"
+@.msg = private constant [9 x i8] c"bad input"
+
+define ptr @ami_worker_Coded(ptr %in, i32 %inlen, ptr %outlen, ptr %err) {
+entry:
+  %c = call ptr @ami_rt_owned_new(ptr @.code, i64 5)
+  %m = call ptr @ami_rt_owned_new(ptr @.msg, i64 9)
+  %e = call ptr @ami_rt_error_make(ptr %c, ptr %m)
+  %s = call ptr @ami_rt_error_to_cstring(ptr %e, ptr %outlen)
+  store ptr %s, ptr %err, align 8
+  ret ptr null
+}
+
+define ptr @ami_worker_Plain(ptr %in, i32 %inlen, ptr %outlen, ptr %err) {
+entry:
+  %m = call ptr @ami_rt_owned_new(ptr @.msg, i64 9)
+  %e = call ptr @ami_rt_error_make(ptr null, ptr %m)
+  %s = call ptr @ami_rt_error_to_cstring(ptr %e, ptr %outlen)
+  store ptr %s, ptr %err, align 8
+  ret ptr null
+}
+`
+
+// A worker error rendered by the LLVM runtime reaches the caller through
+// DLSOInvoker as a *WorkerError (errors.v1) or a plain error of exactly the
+// rendered length.
+func TestDLSOInvoker_CompiledWorkerError(t *testing.T) {
+    clang, err := llvme.FindClang()
+    if err != nil { t.Skip("clang not found; skipping") }
+    dir := t.TempDir()
+    // keep the Metal externs, which only a darwin build links, out of the runtime
+    t.Setenv("AMI_GPU_BACKENDS", "cuda,opencl")
+    rtLL, err := llvme.WriteRuntimeLL(dir, llvme.DefaultTriple, false)
+    if err != nil { t.Fatalf("WriteRuntimeLL: %v", err) }
+    rtObj := filepath.Join(dir, "runtime.o")
+    if err := llvme.CompileLLToObject(clang, rtLL, rtObj, llvme.DefaultTriple); err != nil { t.Skipf("compile runtime.ll failed: %v", err) }
+    wLL := filepath.Join(dir, "workers.ll")
+    if err := os.WriteFile(wLL, []byte(workerErrorLL), 0o644); err != nil { t.Fatal(err) }
+    wObj := filepath.Join(dir, "workers.o")
+    if err := llvme.CompileLLToObject(clang, wLL, wObj, llvme.DefaultTriple); err != nil { t.Skipf("compile workers.ll failed: %v", err) }
+    var lib string
+    var cmd *exec.Cmd
+    switch runtime.GOOS {
+    case "linux":
+        lib = filepath.Join(dir, "libw.so")
+        cmd = exec.Command(clang, "-shared", "-fPIC", rtObj, wObj, "-o", lib, "-target", llvme.DefaultTriple)
+    case "darwin":
+        lib = filepath.Join(dir, "libw.dylib")
+        cmd = exec.Command(clang, "-dynamiclib", rtObj, wObj, "-o", lib, "-target", llvme.DefaultTriple)
+    default:
+        t.Skip("OS not supported for shared linking")
+    }
+    if out, err := cmd.CombinedOutput(); err != nil { t.Skipf("link shared lib failed: %v, out=%s", err, out) }
+
+    inv := NewDLSOInvoker(lib, "ami_worker_")
+    if inv == nil { t.Skip("invoker unavailable (no cgo)") }
+    call, ok := inv.Resolve("Coded")
+    if !ok { t.Fatalf("resolve Coded failed") }
+    _, err = call(ev.Event{Payload: map[string]any{"x": 1}})
+    var we *WorkerError
+    if !errors.As(err, &we) || we.Code != "E_BAD" || we.Message != "bad input" { t.Fatalf("coded error: %#v", err) }
+    call, ok = inv.Resolve("Plain")
+    if !ok { t.Fatalf("resolve Plain failed") }
+    _, err = call(ev.Event{})
+    if err == nil || err.Error() != "bad input" || strings.ContainsRune(err.Error(), 0) { t.Fatalf("plain error: %q", err) }
+}
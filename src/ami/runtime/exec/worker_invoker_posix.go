@@ -43,7 +43,8 @@ import (
 // The symbol must implement the minimal ABI:
 //   const char* fn(const char* in_json, int in_len, int* out_len, const char** err);
 // On success, returns malloc'd JSON (Event or payload) and sets *err to NULL. On error,
-// returns NULL and sets *err to malloc'd error string. Caller frees returned pointers.
+// returns NULL and sets *err to a malloc'd error string, with its length in *out_len
+// when non-zero (else it is read up to its NUL). Caller frees returned pointers.
 type DLSOInvoker struct {
     libPath string
     prefix  string
@@ -77,7 +78,13 @@ func (d *DLSOInvoker) Resolve(workerName string) (func(ev.Event) (any, error), b
         cin := (*C.char)(unsafe.Pointer(&inb[0]))
         cout := C.ami_call_worker(fn, cin, C.int(len(inb)), &outLen, (**C.char)(unsafe.Pointer(&errStr)))
         if cout == nil {
-            if errStr != nil { defer C.free(unsafe.Pointer(errStr)); return nil, errors.New(C.GoString(errStr)) }
+            if errStr != nil {
+                defer C.free(unsafe.Pointer(errStr))
+                var msg string
+                if outLen > 0 { msg = C.GoStringN(errStr, outLen) } else { msg = C.GoString(errStr) }
+                if we, ok := ParseWorkerError(msg); ok { return nil, we }
+                return nil, errors.New(msg)
+            }
             return nil, errors.New("worker returned null output")
         }
         defer C.free(unsafe.Pointer(cout))
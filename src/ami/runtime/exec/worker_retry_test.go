@@ -0,0 +1,122 @@
+package exec
+
+import (
+    "context"
+    "errors"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// runRetryWorker runs ingress -> Transform(W) -> egress with the given inbound
+// delivery; atLeastOnce edges carry the explicit "block" backpressure.
+func runRetryWorker(t *testing.T, delivery string, w func(ev.Event) (any, error), opts ExecOptions) ([]ev.Event, []errs.Error) {
+    t.Helper()
+    bp := ""
+    if delivery == "atLeastOnce" { bp = "block" }
+    return runRetryWorkerEdge(t, edgeEntry{Pipeline: "P", From: "ingress", To: "Transform", Delivery: delivery, Backpressure: bp}, w, opts)
+}
+
+// runRetryWorkerEdge is runRetryWorker with the inbound edge given verbatim.
+func runRetryWorkerEdge(t *testing.T, inbound edgeEntry, w func(ev.Event) (any, error), opts ExecOptions) ([]ev.Event, []errs.Error) {
+    t.Helper()
+    WriteEdges(t, "app", "P", []edgeEntry{
+        inbound,
+        {Pipeline: "P", From: "Transform", To: "egress"},
+    })
+    WriteTransformWorker(t, "app", "P", "Transform", "W")
+    m := ir.Module{Package: "app", Pipelines: []ir.Pipeline{{Name: "P"}}}
+    eng := &Engine{}
+    in := make(chan ev.Event, 1)
+    in <- ev.Event{ID: "e1", Payload: map[string]any{"i": 1}}
+    close(in)
+    errCh := make(chan errs.Error, 4)
+    opts.Workers = map[string]func(ev.Event) (any, error){"W": w}
+    opts.ErrorChan = errCh
+    out, stats, err := eng.RunPipelineWithStats(context.Background(), m, "P", in, nil, "", "", opts)
+    if err != nil { t.Fatalf("run: %v", err) }
+    var got []ev.Event
+    for e := range out { got = append(got, e) }
+    for range stats {}
+    close(errCh)
+    var gotErrs []errs.Error
+    for e := range errCh { gotErrs = append(gotErrs, e) }
+    return got, gotErrs
+}
+
+func TestWorker_AtLeastOnce_RetriesUntilSuccess(t *testing.T) {
+    var calls int32
+    var attempts []int
+    w := func(e ev.Event) (any, error) {
+        attempts = append(attempts, e.Attempt)
+        if atomic.AddInt32(&calls, 1) < 3 { return nil, errors.New("transient") }
+        return e.Payload, nil
+    }
+    got, gotErrs := runRetryWorker(t, "atLeastOnce", w, ExecOptions{Retry: RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}})
+    if len(got) != 1 || len(gotErrs) != 0 { t.Fatalf("outputs=%d errors=%v", len(got), gotErrs) }
+    if got[0].Attempt != 3 { t.Fatalf("expected Attempt=3, got %d", got[0].Attempt) }
+    if len(attempts) != 3 || attempts[0] != 1 || attempts[2] != 3 { t.Fatalf("attempts seen: %v", attempts) }
+}
+
+func TestWorker_AtLeastOnce_ExhaustedGoesToErrorChan(t *testing.T) {
+    var calls int32
+    w := func(ev.Event) (any, error) { atomic.AddInt32(&calls, 1); return nil, errors.New("down") }
+    got, gotErrs := runRetryWorker(t, "atLeastOnce", w, ExecOptions{Retry: RetryPolicy{Backoff: time.Millisecond}})
+    if len(got) != 0 { t.Fatalf("no outputs expected") }
+    if calls != 3 { t.Fatalf("expected default 3 attempts, got %d", calls) }
+    if len(gotErrs) != 1 || gotErrs[0].Code != "E_RETRY_EXHAUSTED" { t.Fatalf("errors: %+v", gotErrs) }
+    if gotErrs[0].Data["attempts"] != 3 { t.Fatalf("attempts: %v", gotErrs[0].Data) }
+}
+
+func TestWorker_BestEffort_NoRetry(t *testing.T) {
+    var calls int32
+    w := func(ev.Event) (any, error) { atomic.AddInt32(&calls, 1); return nil, errors.New("down") }
+    _, gotErrs := runRetryWorker(t, "bestEffort", w, ExecOptions{Retry: RetryPolicy{MaxAttempts: 5}})
+    if calls != 1 { t.Fatalf("bestEffort must not retry, got %d calls", calls) }
+    if len(gotErrs) != 1 || gotErrs[0].Code != "E_WORKER" { t.Fatalf("errors: %+v", gotErrs) }
+}
+
+func TestRetryPolicy_DelayDoublesAndCaps(t *testing.T) {
+    p := RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}
+    if p.delay(1) != 10*time.Millisecond || p.delay(2) != 20*time.Millisecond || p.delay(3) != 25*time.Millisecond {
+        t.Fatalf("delays: %v %v %v", p.delay(1), p.delay(2), p.delay(3))
+    }
+}
+
+func TestWorker_RequireWorkers_FailsUnresolvedWithoutRetry(t *testing.T) {
+    got, gotErrs := runRetryWorker(t, "atLeastOnce", nil, ExecOptions{RequireWorkers: true})
+    if len(got) != 0 || len(gotErrs) != 1 { t.Fatalf("outputs=%d errors=%v", len(got), gotErrs) }
+    if gotErrs[0].Code != "E_WORKER_UNAVAILABLE" || gotErrs[0].Data["worker"] != "W" { t.Fatalf("unexpected error: %+v", gotErrs[0]) }
+}
+
+// The compiler's default atLeastOnce label (no explicit backpressure) does
+// not request retries: the failure is reported once as E_WORKER.
+func TestWorker_DefaultDelivery_NoRetry(t *testing.T) {
+    var calls int32
+    w := func(ev.Event) (any, error) { atomic.AddInt32(&calls, 1); return nil, errors.New("down") }
+    _, gotErrs := runRetryWorkerEdge(t, edgeEntry{Pipeline: "P", From: "ingress", To: "Transform", Delivery: "atLeastOnce"}, w, ExecOptions{})
+    if calls != 1 { t.Fatalf("default delivery must not retry, got %d calls", calls) }
+    if len(gotErrs) != 1 || gotErrs[0].Code != "E_WORKER" { t.Fatalf("errors: %+v", gotErrs) }
+}
+
+// Retry backoff waits on the runtime clock, so virtual time drives it.
+func TestSleepCtx_UsesRuntimeClock(t *testing.T) {
+    vc := amitime.NewVirtualClock(time.Unix(0, 0))
+    prev := amitime.SetClock(vc)
+    defer amitime.SetClock(prev)
+    done := make(chan bool, 1)
+    go func(){ done <- sleepCtx(context.Background(), time.Hour) }()
+    vc.BlockUntil(1)
+    vc.Advance(time.Hour)
+    select {
+    case ok := <-done:
+        if !ok { t.Fatalf("sleep reported cancellation") }
+    case <-time.After(time.Second):
+        t.Fatalf("backoff did not follow the virtual clock")
+    }
+}
@@ -0,0 +1,112 @@
+package expr
+
+import (
+    "strconv"
+    "strings"
+)
+
+// builtin describes a function callable from expressions. raw builtins receive
+// unevaluated arguments (e.g., has(path)); fn builtins receive values.
+type builtin struct {
+    min, max int // arity bounds; max < 0 means variadic
+    fn       func(en *env, c *callNode, args []any) (any, error)
+    raw      func(en *env, c *callNode) (any, error)
+}
+
+var builtins map[string]builtin
+
+func init() {
+    builtins = map[string]builtin{
+        "has": {min: 1, max: 1, raw: func(en *env, c *callNode) (any, error) {
+            p, ok := c.args[0].(*pathNode)
+            if !ok { return nil, errAt(en.src, c.args[0].pos(), "has() needs a field path") }
+            _, found := en.lookup(p.path)
+            return found, nil
+        }},
+        "len": {min: 1, max: 1, fn: func(en *env, c *callNode, a []any) (any, error) {
+            switch x := a[0].(type) {
+            case string: return int64(len([]rune(x))), nil
+            case []any: return int64(len(x)), nil
+            case map[string]any: return int64(len(x)), nil
+            }
+            return nil, argErr(en, c, 0, "string, array or object", a[0])
+        }},
+        "lower": strFunc(strings.ToLower),
+        "upper": strFunc(strings.ToUpper),
+        "trim":  strFunc(strings.TrimSpace),
+        "contains":   strPred(strings.Contains),
+        "startsWith": strPred(strings.HasPrefix),
+        "endsWith":   strPred(strings.HasSuffix),
+        "concat": {min: 1, max: -1, fn: func(en *env, c *callNode, a []any) (any, error) {
+            var sb strings.Builder
+            for _, v := range a { sb.WriteString(toString(v)) }
+            return sb.String(), nil
+        }},
+        "substr": {min: 2, max: 3, fn: func(en *env, c *callNode, a []any) (any, error) {
+            s, ok := a[0].(string)
+            if !ok { return nil, argErr(en, c, 0, "string", a[0]) }
+            rs := []rune(s)
+            start, ok := a[1].(int64)
+            if !ok { return nil, argErr(en, c, 1, "integer", a[1]) }
+            if start < 0 { start = 0 }
+            if start > int64(len(rs)) { start = int64(len(rs)) }
+            end := int64(len(rs))
+            if len(a) == 3 {
+                n, ok := a[2].(int64)
+                if !ok { return nil, argErr(en, c, 2, "integer", a[2]) }
+                if n >= 0 && start+n < end { end = start + n }
+            }
+            return string(rs[start:end]), nil
+        }},
+        "str": {min: 1, max: 1, fn: func(en *env, c *callNode, a []any) (any, error) { return toString(a[0]), nil }},
+        "num": {min: 1, max: 1, fn: func(en *env, c *callNode, a []any) (any, error) {
+            switch x := a[0].(type) {
+            case int64, float64: return x, nil
+            case string:
+                if n, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64); err == nil { return n, nil }
+                if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil { return f, nil }
+                return nil, errAt(en.src, c.args[0].pos(), "num(): cannot parse %q as number", x)
+            }
+            return nil, argErr(en, c, 0, "number or string", a[0])
+        }},
+    }
+}
+
+func strFunc(f func(string) string) builtin {
+    return builtin{min: 1, max: 1, fn: func(en *env, c *callNode, a []any) (any, error) {
+        s, ok := a[0].(string)
+        if !ok { return nil, argErr(en, c, 0, "string", a[0]) }
+        return f(s), nil
+    }}
+}
+
+func strPred(f func(string, string) bool) builtin {
+    return builtin{min: 2, max: 2, fn: func(en *env, c *callNode, a []any) (any, error) {
+        s, ok := a[0].(string)
+        if !ok { return nil, argErr(en, c, 0, "string", a[0]) }
+        t, ok := a[1].(string)
+        if !ok { return nil, argErr(en, c, 1, "string", a[1]) }
+        return f(s, t), nil
+    }}
+}
+
+func argErr(en *env, c *callNode, i int, want string, got any) *Error {
+    return errAt(en.src, c.args[i].pos(), "%s() argument %d must be %s, got %s", c.name, i+1, want, typeName(got))
+}
+
+// checkArity validates a call's argument count at parse time.
+func checkArity(src string, c *callNode) error {
+    b := builtins[c.name]
+    n := len(c.args)
+    if n < b.min || (b.max >= 0 && n > b.max) {
+        switch {
+        case b.max < 0:
+            return errAt(src, c.at, "%s() takes at least %d argument(s), got %d", c.name, b.min, n)
+        case b.min == b.max:
+            return errAt(src, c.at, "%s() takes %d argument(s), got %d", c.name, b.min, n)
+        default:
+            return errAt(src, c.at, "%s() takes %d to %d arguments, got %d", c.name, b.min, b.max, n)
+        }
+    }
+    return nil
+}
@@ -0,0 +1,20 @@
+// Package expr implements the small, sandboxed expression language used by
+// `ami run --filter` and `--transform` to prototype pipeline stages.
+//
+// Expressions read event payload fields by dotted path (the same addressing
+// as merge field paths, e.g. user.address.city), plus $id and $attempt for
+// event metadata. They support literals (numbers, 'strings', true, false,
+// null), comparisons, && || !, arithmetic (+ - * / %), string concatenation
+// with +, and builtins: has, len, lower, upper, trim, contains, startsWith,
+// endsWith, concat, substr, str, num.
+//
+// A filter is a single boolean expression. A transform is a ';'-separated list
+// of statements applied in order to a copy of the payload:
+//
+//     set path = expr      assign (creates intermediate objects)
+//     del path             delete a field
+//     rename path -> path  move a field
+//     keep path, path      project: keep only the listed fields
+//
+// Evaluation never performs I/O and never mutates its input event.
+package expr
@@ -0,0 +1,23 @@
+package expr
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// env is the evaluation context: the event being processed and the source
+// text used for diagnostics.
+type env struct {
+    src   string
+    event ev.Event
+}
+
+// lookup resolves a field path. "$id" and "$attempt" address event metadata;
+// every other path is read from the payload. Missing fields yield null.
+func (en *env) lookup(path string) (any, bool) {
+    switch path {
+    case "$id":
+        return en.event.ID, true
+    case "$attempt":
+        return int64(en.event.Attempt), true
+    }
+    v, ok := getPath(en.event.Payload, path)
+    return normalize(v), ok
+}
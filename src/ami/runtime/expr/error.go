@@ -0,0 +1,16 @@
+package expr
+
+import "fmt"
+
+// Error reports a parse or evaluation failure at a column of the source expression.
+type Error struct {
+    Src string // original expression text
+    Pos int    // 0-based byte offset into Src
+    Msg string
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("expr: col %d: %s", e.Pos+1, e.Msg) }
+
+func errAt(src string, pos int, format string, args ...any) *Error {
+    return &Error{Src: src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
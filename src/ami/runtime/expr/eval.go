@@ -0,0 +1,115 @@
+package expr
+
+import "math"
+
+func (n *litNode) eval(en *env) (any, error) { return n.val, nil }
+
+func (n *pathNode) eval(en *env) (any, error) {
+    v, _ := en.lookup(n.path)
+    return v, nil
+}
+
+func (n *unaryNode) eval(en *env) (any, error) {
+    x, err := n.x.eval(en)
+    if err != nil { return nil, err }
+    switch n.op {
+    case "!":
+        b, ok := x.(bool)
+        if !ok { return nil, errAt(en.src, n.at, "operator ! needs bool, got %s", typeName(x)) }
+        return !b, nil
+    default: // "-"
+        switch v := x.(type) {
+        case int64: return -v, nil
+        case float64: return -v, nil
+        }
+        return nil, errAt(en.src, n.at, "operator - needs number, got %s", typeName(x))
+    }
+}
+
+func (n *binaryNode) eval(en *env) (any, error) {
+    // && and || short-circuit and require bool operands.
+    if n.op == "&&" || n.op == "||" {
+        l, err := n.evalBool(en, n.l)
+        if err != nil { return nil, err }
+        if (n.op == "&&" && !l) || (n.op == "||" && l) { return l, nil }
+        return n.evalBool(en, n.r)
+    }
+    l, err := n.l.eval(en)
+    if err != nil { return nil, err }
+    r, err := n.r.eval(en)
+    if err != nil { return nil, err }
+    switch n.op {
+    case "==": return equal(l, r), nil
+    case "!=": return !equal(l, r), nil
+    case "<", "<=", ">", ">=":
+        c, ok := compare(l, r)
+        if !ok { return nil, errAt(en.src, n.at, "cannot compare %s %s %s", typeName(l), n.op, typeName(r)) }
+        switch n.op {
+        case "<": return c < 0, nil
+        case "<=": return c <= 0, nil
+        case ">": return c > 0, nil
+        default: return c >= 0, nil
+        }
+    case "+":
+        if ls, ok := l.(string); ok {
+            if rs, ok := r.(string); ok { return ls + rs, nil }
+        }
+    }
+    return n.arith(en, l, r)
+}
+
+func (n *binaryNode) evalBool(en *env, x node) (bool, error) {
+    v, err := x.eval(en)
+    if err != nil { return false, err }
+    b, ok := v.(bool)
+    if !ok { return false, errAt(en.src, x.pos(), "operator %s needs bool, got %s", n.op, typeName(v)) }
+    return b, nil
+}
+
+// arith applies + - * / % to numbers. Integer operands stay integral except
+// for division, which yields a float unless it is exact.
+func (n *binaryNode) arith(en *env, l, r any) (any, error) {
+    if !isNumber(l) || !isNumber(r) {
+        return nil, errAt(en.src, n.at, "operator %s needs numbers, got %s and %s", n.op, typeName(l), typeName(r))
+    }
+    li, lInt := l.(int64)
+    ri, rInt := r.(int64)
+    if lInt && rInt {
+        switch n.op {
+        case "+": return li + ri, nil
+        case "-": return li - ri, nil
+        case "*": return li * ri, nil
+        case "%":
+            if ri == 0 { return nil, errAt(en.src, n.at, "modulo by zero") }
+            return li % ri, nil
+        case "/":
+            if ri == 0 { return nil, errAt(en.src, n.at, "division by zero") }
+            if li%ri == 0 { return li / ri, nil }
+        }
+    }
+    lf, _ := toFloat(l)
+    rf, _ := toFloat(r)
+    switch n.op {
+    case "+": return lf + rf, nil
+    case "-": return lf - rf, nil
+    case "*": return lf * rf, nil
+    case "/":
+        if rf == 0 { return nil, errAt(en.src, n.at, "division by zero") }
+        return lf / rf, nil
+    default: // "%"
+        if rf == 0 { return nil, errAt(en.src, n.at, "modulo by zero") }
+        return math.Mod(lf, rf), nil
+    }
+}
+
+func (n *callNode) eval(en *env) (any, error) {
+    b := builtins[n.name]
+    if b.raw != nil { return b.raw(en, n) }
+    args := make([]any, len(n.args))
+    for i, a := range n.args {
+        v, err := a.eval(en)
+        if err != nil { return nil, err }
+        args[i] = v
+    }
+    return b.fn(en, n, args)
+}
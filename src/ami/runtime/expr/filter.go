@@ -0,0 +1,32 @@
+package expr
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// Filter is a compiled boolean predicate over events.
+type Filter struct {
+    src  string
+    root node
+}
+
+// ParseFilter compiles a filter expression.
+func ParseFilter(src string) (*Filter, error) {
+    p, err := newParser(src)
+    if err != nil { return nil, err }
+    if p.peek().kind == tkEOF { return nil, errAt(src, 0, "empty filter expression") }
+    root, err := p.parseExpr()
+    if err != nil { return nil, err }
+    if err := p.done(); err != nil { return nil, err }
+    return &Filter{src: src, root: root}, nil
+}
+
+// Match evaluates the filter against e. Non-boolean results are errors.
+func (f *Filter) Match(e ev.Event) (bool, error) {
+    v, err := f.root.eval(&env{src: f.src, event: e})
+    if err != nil { return false, err }
+    b, ok := v.(bool)
+    if !ok { return false, errAt(f.src, f.root.pos(), "filter must evaluate to bool, got %s", typeName(v)) }
+    return b, nil
+}
+
+// String returns the source text of the filter.
+func (f *Filter) String() string { return f.src }
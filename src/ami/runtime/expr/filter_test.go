@@ -0,0 +1,89 @@
+package expr
+
+import (
+    "strings"
+    "testing"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func match(t *testing.T, src string, payload any) bool {
+    t.Helper()
+    f, err := ParseFilter(src)
+    if err != nil { t.Fatalf("parse %q: %v", src, err) }
+    ok, err := f.Match(ev.Event{ID: "e1", Attempt: 2, Payload: payload})
+    if err != nil { t.Fatalf("match %q: %v", src, err) }
+    return ok
+}
+
+func TestFilter_ComparisonsBooleanArithmetic(t *testing.T) {
+    p := map[string]any{"i": 3, "f": 2.5, "user": map[string]any{"name": "Ada", "age": int64(36)}}
+    cases := map[string]bool{
+        "i % 2 != 0":                     true,
+        "i * 2 + 1 == 7":                 true,
+        "f > i":                          false,
+        "7 / 2 == 3.5 && 6 / 2 == 3":     true,
+        "user.age >= 18 && !(i == 4)":    true,
+        "user.name == 'Ada' || missing":  true,
+        "-i < 0":                         true,
+        "$id == 'e1' && $attempt == 2":   true,
+        "missing == null":                true,
+    }
+    for src, want := range cases {
+        if got := match(t, src, p); got != want { t.Fatalf("%q: got %v want %v", src, got, want) }
+    }
+}
+
+func TestFilter_StringFunctions(t *testing.T) {
+    p := map[string]any{"s": "  Hello World ", "tags": []any{"a", "b"}}
+    cases := map[string]bool{
+        "trim(s) == 'Hello World'":              true,
+        "contains(lower(s), 'world')":           true,
+        "startsWith(trim(s), 'He')":             true,
+        "endsWith(upper(trim(s)), 'WORLD')":     true,
+        "len(tags) == 2 && len('héllo') == 5":   true,
+        "substr(trim(s), 6) == 'World'":         true,
+        "substr('abcdef', 1, 2) == 'bc'":        true,
+        "concat('a', 1, true) == 'a1true'":     true,
+        "'x' + str(1.5) == 'x1.5'":             true,
+        "num('42') + 1 == 43":                   true,
+        "has(s) && !has(nope.deep)":             true,
+    }
+    for src, want := range cases {
+        if got := match(t, src, p); got != want { t.Fatalf("%q: got %v want %v", src, got, want) }
+    }
+}
+
+func TestFilter_ParseErrors_HaveColumns(t *testing.T) {
+    cases := map[string]string{
+        "":             "empty filter",
+        "i ==":         "col 5: expected value",
+        "(i == 1":      "expected ')'",
+        "i == 1 j":     "col 8: unexpected trailing input",
+        "nope(1)":      `unknown function "nope"`,
+        "lower()":      "lower() takes 1 argument(s), got 0",
+        "a.":           "expected path segment",
+    }
+    for src, want := range cases {
+        _, err := ParseFilter(src)
+        if err == nil || !strings.Contains(err.Error(), want) { t.Fatalf("%q: got %v, want %q", src, err, want) }
+    }
+}
+
+func TestFilter_EvalErrors(t *testing.T) {
+    p := map[string]any{"s": "x", "i": 1}
+    cases := map[string]string{
+        "i":          "filter must evaluate to bool, got number",
+        "s > 1":      "cannot compare string > number",
+        "s && true":  "operator && needs bool, got string",
+        "i / 0 == 1": "division by zero",
+        "lower(i) == 'x'": "lower() argument 1 must be string, got number",
+        "has(1)":     "has() needs a field path",
+    }
+    for src, want := range cases {
+        f, err := ParseFilter(src)
+        if err != nil { t.Fatalf("parse %q: %v", src, err) }
+        _, err = f.Match(ev.Event{Payload: p})
+        if err == nil || !strings.Contains(err.Error(), want) { t.Fatalf("%q: got %v, want %q", src, err, want) }
+    }
+}
@@ -0,0 +1,69 @@
+package expr
+
+import "strings"
+
+// twoCharOps lists multi-character operators recognized by the lexer.
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||", "->"}
+
+// lex splits src into tokens. Identifiers may start with '$' (event metadata).
+func lex(src string) ([]token, error) {
+    var toks []token
+    i := 0
+    for i < len(src) {
+        c := src[i]
+        switch {
+        case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+            i++
+        case isIdentStart(c):
+            j := i + 1
+            for j < len(src) && isIdentPart(src[j]) { j++ }
+            toks = append(toks, token{kind: tkIdent, text: src[i:j], pos: i})
+            i = j
+        case c >= '0' && c <= '9':
+            j := i
+            for j < len(src) && src[j] >= '0' && src[j] <= '9' { j++ }
+            if j+1 < len(src) && src[j] == '.' && src[j+1] >= '0' && src[j+1] <= '9' {
+                j++
+                for j < len(src) && src[j] >= '0' && src[j] <= '9' { j++ }
+            }
+            toks = append(toks, token{kind: tkNumber, text: src[i:j], pos: i})
+            i = j
+        case c == '"' || c == '\'':
+            var sb strings.Builder
+            j := i + 1
+            closed := false
+            for j < len(src) {
+                if src[j] == '\\' && j+1 < len(src) {
+                    switch src[j+1] {
+                    case 'n': sb.WriteByte('\n')
+                    case 't': sb.WriteByte('\t')
+                    default: sb.WriteByte(src[j+1])
+                    }
+                    j += 2
+                    continue
+                }
+                if src[j] == c { closed = true; j++; break }
+                sb.WriteByte(src[j])
+                j++
+            }
+            if !closed { return nil, errAt(src, i, "unterminated string literal") }
+            toks = append(toks, token{kind: tkString, text: sb.String(), pos: i})
+            i = j
+        default:
+            op := ""
+            for _, o := range twoCharOps { if strings.HasPrefix(src[i:], o) { op = o; break } }
+            if op == "" {
+                if !strings.ContainsRune("+-*/%<>!=(),.;", rune(c)) { return nil, errAt(src, i, "unexpected character %q", c) }
+                op = string(c)
+            }
+            toks = append(toks, token{kind: tkOp, text: op, pos: i})
+            i += len(op)
+        }
+    }
+    toks = append(toks, token{kind: tkEOF, pos: len(src)})
+    return toks, nil
+}
+
+func isIdentStart(c byte) bool { return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+func isIdentPart(c byte) bool { return isIdentStart(c) || (c >= '0' && c <= '9') }
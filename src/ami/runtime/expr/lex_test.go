@@ -0,0 +1,19 @@
+package expr
+
+import "testing"
+
+func TestLex_TokensAndPositions(t *testing.T) {
+    toks, err := lex(`a.b >= 1.5 && $id != 'x\'y' -> ;`)
+    if err != nil { t.Fatalf("lex: %v", err) }
+    want := []string{"a", ".", "b", ">=", "1.5", "&&", "$id", "!=", "x'y", "->", ";", ""}
+    if len(toks) != len(want) { t.Fatalf("got %d tokens: %+v", len(toks), toks) }
+    for i, w := range want { if toks[i].text != w { t.Fatalf("tok %d: got %q want %q", i, toks[i].text, w) } }
+    if toks[3].pos != 4 { t.Fatalf("pos of >=: %d", toks[3].pos) }
+}
+
+func TestLex_Errors(t *testing.T) {
+    if _, err := lex(`"open`); err == nil { t.Fatalf("expected unterminated string error") }
+    _, err := lex(`a # b`)
+    e, ok := err.(*Error)
+    if !ok || e.Pos != 2 { t.Fatalf("expected error at col 3, got %v", err) }
+}
@@ -0,0 +1,23 @@
+package expr
+
+// node is an expression AST node.
+type node interface {
+    pos() int
+    eval(en *env) (any, error)
+}
+
+type litNode struct{ at int; val any }
+
+type pathNode struct{ at int; path string }
+
+type unaryNode struct{ at int; op string; x node }
+
+type binaryNode struct{ at int; op string; l, r node }
+
+type callNode struct{ at int; name string; args []node }
+
+func (n *litNode) pos() int    { return n.at }
+func (n *pathNode) pos() int   { return n.at }
+func (n *unaryNode) pos() int  { return n.at }
+func (n *binaryNode) pos() int { return n.at }
+func (n *callNode) pos() int   { return n.at }
@@ -0,0 +1,204 @@
+package expr
+
+import (
+    "strconv"
+    "strings"
+)
+
+// parser is a recursive-descent parser over lexed tokens.
+//
+// Precedence (low to high): ||, &&, comparisons, + -, * / %, unary ! -.
+type parser struct {
+    src  string
+    toks []token
+    i    int
+}
+
+func newParser(src string) (*parser, error) {
+    toks, err := lex(src)
+    if err != nil { return nil, err }
+    return &parser{src: src, toks: toks}, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token { t := p.toks[p.i]; if t.kind != tkEOF { p.i++ }; return t }
+
+func (p *parser) isOp(op string) bool { t := p.peek(); return t.kind == tkOp && t.text == op }
+
+func (p *parser) isKeyword(kw string) bool { t := p.peek(); return t.kind == tkIdent && t.text == kw }
+
+func (p *parser) expectOp(op string) error {
+    if !p.isOp(op) { return p.unexpected("expected '" + op + "'") }
+    p.next()
+    return nil
+}
+
+func (p *parser) unexpected(want string) *Error {
+    t := p.peek()
+    switch t.kind {
+    case tkEOF:
+        return errAt(p.src, t.pos, "%s, found end of expression", want)
+    case tkString:
+        return errAt(p.src, t.pos, "%s, found string %q", want, t.text)
+    default:
+        return errAt(p.src, t.pos, "%s, found %q", want, t.text)
+    }
+}
+
+func (p *parser) parseExpr() (node, error) { return p.parseOr() }
+
+func (p *parser) parseOr() (node, error) {
+    l, err := p.parseAnd()
+    if err != nil { return nil, err }
+    for p.isOp("||") {
+        t := p.next()
+        r, err := p.parseAnd()
+        if err != nil { return nil, err }
+        l = &binaryNode{at: t.pos, op: t.text, l: l, r: r}
+    }
+    return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+    l, err := p.parseCmp()
+    if err != nil { return nil, err }
+    for p.isOp("&&") {
+        t := p.next()
+        r, err := p.parseCmp()
+        if err != nil { return nil, err }
+        l = &binaryNode{at: t.pos, op: t.text, l: l, r: r}
+    }
+    return l, nil
+}
+
+func (p *parser) parseCmp() (node, error) {
+    l, err := p.parseAdd()
+    if err != nil { return nil, err }
+    t := p.peek()
+    if t.kind == tkOp {
+        switch t.text {
+        case "==", "!=", "<", "<=", ">", ">=":
+            p.next()
+            r, err := p.parseAdd()
+            if err != nil { return nil, err }
+            return &binaryNode{at: t.pos, op: t.text, l: l, r: r}, nil
+        }
+    }
+    return l, nil
+}
+
+func (p *parser) parseAdd() (node, error) {
+    l, err := p.parseMul()
+    if err != nil { return nil, err }
+    for p.isOp("+") || p.isOp("-") {
+        t := p.next()
+        r, err := p.parseMul()
+        if err != nil { return nil, err }
+        l = &binaryNode{at: t.pos, op: t.text, l: l, r: r}
+    }
+    return l, nil
+}
+
+func (p *parser) parseMul() (node, error) {
+    l, err := p.parseUnary()
+    if err != nil { return nil, err }
+    for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+        t := p.next()
+        r, err := p.parseUnary()
+        if err != nil { return nil, err }
+        l = &binaryNode{at: t.pos, op: t.text, l: l, r: r}
+    }
+    return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+    if p.isOp("!") || p.isOp("-") {
+        t := p.next()
+        x, err := p.parseUnary()
+        if err != nil { return nil, err }
+        return &unaryNode{at: t.pos, op: t.text, x: x}, nil
+    }
+    return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+    t := p.peek()
+    switch t.kind {
+    case tkNumber:
+        p.next()
+        if strings.Contains(t.text, ".") {
+            f, err := strconv.ParseFloat(t.text, 64)
+            if err != nil { return nil, errAt(p.src, t.pos, "invalid number %q", t.text) }
+            return &litNode{at: t.pos, val: f}, nil
+        }
+        n, err := strconv.ParseInt(t.text, 10, 64)
+        if err != nil { return nil, errAt(p.src, t.pos, "invalid number %q", t.text) }
+        return &litNode{at: t.pos, val: n}, nil
+    case tkString:
+        p.next()
+        return &litNode{at: t.pos, val: t.text}, nil
+    case tkIdent:
+        switch t.text {
+        case "true":
+            p.next(); return &litNode{at: t.pos, val: true}, nil
+        case "false":
+            p.next(); return &litNode{at: t.pos, val: false}, nil
+        case "null":
+            p.next(); return &litNode{at: t.pos, val: nil}, nil
+        }
+        if p.toks[p.i+1].kind == tkOp && p.toks[p.i+1].text == "(" { return p.parseCall() }
+        return p.parsePath()
+    case tkOp:
+        if t.text == "(" {
+            p.next()
+            x, err := p.parseExpr()
+            if err != nil { return nil, err }
+            if err := p.expectOp(")"); err != nil { return nil, err }
+            return x, nil
+        }
+    }
+    return nil, p.unexpected("expected value, field path or '('")
+}
+
+func (p *parser) parseCall() (node, error) {
+    t := p.next()
+    if _, ok := builtins[t.text]; !ok { return nil, errAt(p.src, t.pos, "unknown function %q", t.text) }
+    p.next() // (
+    c := &callNode{at: t.pos, name: t.text}
+    if !p.isOp(")") {
+        for {
+            a, err := p.parseExpr()
+            if err != nil { return nil, err }
+            c.args = append(c.args, a)
+            if !p.isOp(",") { break }
+            p.next()
+        }
+    }
+    if err := p.expectOp(")"); err != nil { return nil, err }
+    if err := checkArity(p.src, c); err != nil { return nil, err }
+    return c, nil
+}
+
+// parsePath parses a dotted field path (segments are identifiers or integers),
+// using the same addressing as merge field paths.
+func (p *parser) parsePath() (*pathNode, error) {
+    t := p.peek()
+    if t.kind != tkIdent { return nil, p.unexpected("expected field path") }
+    p.next()
+    segs := []string{t.text}
+    for p.isOp(".") {
+        p.next()
+        s := p.peek()
+        if s.kind != tkIdent && s.kind != tkNumber { return nil, p.unexpected("expected path segment after '.'") }
+        p.next()
+        segs = append(segs, s.text)
+    }
+    return &pathNode{at: t.pos, path: strings.Join(segs, ".")}, nil
+}
+
+// done reports an error unless all tokens were consumed.
+func (p *parser) done() error {
+    if p.peek().kind != tkEOF { return p.unexpected("unexpected trailing input") }
+    return nil
+}
@@ -0,0 +1,65 @@
+package expr
+
+import "strings"
+
+// getPath reads a dotted path from a map payload (same addressing as merge).
+func getPath(root any, path string) (any, bool) {
+    cur := root
+    for _, seg := range strings.Split(path, ".") {
+        m, ok := cur.(map[string]any)
+        if !ok { return nil, false }
+        v, ok := m[seg]
+        if !ok { return nil, false }
+        cur = v
+    }
+    return cur, true
+}
+
+// setPath writes v at path, creating intermediate objects. It fails when an
+// intermediate segment exists but is not an object.
+func setPath(root map[string]any, path string, v any) bool {
+    segs := strings.Split(path, ".")
+    cur := root
+    for _, seg := range segs[:len(segs)-1] {
+        nx, ok := cur[seg]
+        if !ok {
+            m := map[string]any{}
+            cur[seg] = m
+            cur = m
+            continue
+        }
+        m, ok := nx.(map[string]any)
+        if !ok { return false }
+        cur = m
+    }
+    cur[segs[len(segs)-1]] = v
+    return true
+}
+
+// delPath removes the value at path; missing paths are a no-op.
+func delPath(root map[string]any, path string) {
+    segs := strings.Split(path, ".")
+    cur := root
+    for _, seg := range segs[:len(segs)-1] {
+        m, ok := cur[seg].(map[string]any)
+        if !ok { return }
+        cur = m
+    }
+    delete(cur, segs[len(segs)-1])
+}
+
+// clone deep-copies maps and slices so transforms never mutate their input.
+func clone(v any) any {
+    switch x := v.(type) {
+    case map[string]any:
+        m := make(map[string]any, len(x))
+        for k, vv := range x { m[k] = clone(vv) }
+        return m
+    case []any:
+        s := make([]any, len(x))
+        for i, vv := range x { s[i] = clone(vv) }
+        return s
+    default:
+        return v
+    }
+}
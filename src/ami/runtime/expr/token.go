@@ -0,0 +1,19 @@
+package expr
+
+// tokKind classifies lexical tokens.
+type tokKind int
+
+const (
+    tkEOF tokKind = iota
+    tkIdent
+    tkNumber
+    tkString
+    tkOp
+)
+
+// token is a lexical token with its byte offset in the source.
+type token struct {
+    kind tokKind
+    text string // identifier, operator, number literal, or unquoted string
+    pos  int
+}
@@ -0,0 +1,122 @@
+package expr
+
+import ev "github.com/sam-caldwell/ami/src/schemas/events"
+
+// Transform is a compiled sequence of payload edit statements.
+type Transform struct {
+    src   string
+    stmts []stmt
+}
+
+// stmt is one transform statement: set, del, rename or keep.
+type stmt struct {
+    at    int
+    kind  string
+    paths []string // target(s): set/del use [0]; rename uses [0] -> [1]; keep uses all
+    x     node     // value for set
+}
+
+// ParseTransform compiles a ';'-separated list of transform statements.
+func ParseTransform(src string) (*Transform, error) {
+    p, err := newParser(src)
+    if err != nil { return nil, err }
+    t := &Transform{src: src}
+    for p.peek().kind != tkEOF {
+        if p.isOp(";") { p.next(); continue }
+        s, err := p.parseStmt()
+        if err != nil { return nil, err }
+        t.stmts = append(t.stmts, s)
+        if p.peek().kind != tkEOF && !p.isOp(";") { return nil, p.unexpected("expected ';' between statements") }
+    }
+    if len(t.stmts) == 0 { return nil, errAt(src, 0, "empty transform expression") }
+    return t, nil
+}
+
+func (p *parser) parseStmt() (stmt, error) {
+    kw := p.peek()
+    if kw.kind != tkIdent { return stmt{}, p.unexpected("expected set, del, rename or keep") }
+    s := stmt{at: kw.pos, kind: kw.text}
+    switch kw.text {
+    case "set":
+        p.next()
+        path, err := p.parsePath()
+        if err != nil { return stmt{}, err }
+        if err := p.expectOp("="); err != nil { return stmt{}, err }
+        x, err := p.parseExpr()
+        if err != nil { return stmt{}, err }
+        s.paths, s.x = []string{path.path}, x
+    case "del":
+        p.next()
+        path, err := p.parsePath()
+        if err != nil { return stmt{}, err }
+        s.paths = []string{path.path}
+    case "rename":
+        p.next()
+        from, err := p.parsePath()
+        if err != nil { return stmt{}, err }
+        if err := p.expectOp("->"); err != nil { return stmt{}, err }
+        to, err := p.parsePath()
+        if err != nil { return stmt{}, err }
+        s.paths = []string{from.path, to.path}
+    case "keep":
+        p.next()
+        for {
+            path, err := p.parsePath()
+            if err != nil { return stmt{}, err }
+            s.paths = append(s.paths, path.path)
+            if !p.isOp(",") { break }
+            p.next()
+        }
+    default:
+        return stmt{}, p.unexpected("expected set, del, rename or keep")
+    }
+    for _, path := range s.paths {
+        if path[0] == '$' { return stmt{}, errAt(p.src, s.at, "%s: event metadata %s is read-only", s.kind, path) }
+    }
+    return s, nil
+}
+
+// Apply runs the statements against a deep copy of e's payload and returns
+// the updated event. A nil payload is treated as an empty object; any other
+// non-object payload is an error.
+func (t *Transform) Apply(e ev.Event) (ev.Event, error) {
+    var payload map[string]any
+    switch x := e.Payload.(type) {
+    case nil:
+        payload = map[string]any{}
+    case map[string]any:
+        payload = clone(x).(map[string]any)
+    default:
+        return e, errAt(t.src, 0, "transform needs an object payload, got %s", typeName(normalize(x)))
+    }
+    out := e
+    out.Payload = payload
+    en := &env{src: t.src, event: out}
+    for _, s := range t.stmts {
+        switch s.kind {
+        case "set":
+            v, err := s.x.eval(en)
+            if err != nil { return e, err }
+            // copy objects and lists so later edits to either path stay independent
+            if !setPath(payload, s.paths[0], clone(v)) { return e, errAt(t.src, s.at, "set %s: parent is not an object", s.paths[0]) }
+        case "del":
+            delPath(payload, s.paths[0])
+        case "rename":
+            v, ok := getPath(payload, s.paths[0])
+            if !ok { continue }
+            delPath(payload, s.paths[0])
+            if !setPath(payload, s.paths[1], v) { return e, errAt(t.src, s.at, "rename %s: parent of %s is not an object", s.paths[0], s.paths[1]) }
+        case "keep":
+            kept := map[string]any{}
+            for _, path := range s.paths {
+                if v, ok := getPath(payload, path); ok { setPath(kept, path, clone(v)) }
+            }
+            for k := range payload { delete(payload, k) }
+            for k, v := range kept { payload[k] = v }
+        }
+    }
+    return out, nil
+}
+
+// String returns the source text of the transform.
+func (t *Transform) String() string { return t.src }
@@ -0,0 +1,61 @@
+package expr
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestTransform_SetDelRenameKeep(t *testing.T) {
+    tr, err := ParseTransform("set total = price * qty; set meta.tag = upper(kind); del kind; rename user.name -> who; keep total, meta, who")
+    if err != nil { t.Fatalf("parse: %v", err) }
+    in := map[string]any{"price": 2.5, "qty": 4, "kind": "x", "user": map[string]any{"name": "ada"}}
+    out, err := tr.Apply(ev.Event{Payload: in})
+    if err != nil { t.Fatalf("apply: %v", err) }
+    want := map[string]any{"total": 10.0, "meta": map[string]any{"tag": "X"}, "who": "ada"}
+    if !reflect.DeepEqual(out.Payload, want) { t.Fatalf("got %+v want %+v", out.Payload, want) }
+    if _, ok := in["total"]; ok || in["kind"] != "x" { t.Fatalf("input payload mutated: %+v", in) }
+}
+
+func TestTransform_NilPayloadBecomesObject(t *testing.T) {
+    tr, _ := ParseTransform("set flag = true")
+    out, err := tr.Apply(ev.Event{})
+    if err != nil { t.Fatalf("apply: %v", err) }
+    if out.Payload.(map[string]any)["flag"] != true { t.Fatalf("got %+v", out.Payload) }
+}
+
+func TestTransform_Errors(t *testing.T) {
+    parse := map[string]string{
+        "":              "empty transform",
+        "set a 1":       "expected '='",
+        "frob a":        "expected set, del, rename or keep",
+        "del a del b":   "expected ';' between statements",
+        "set $id = 1":   "read-only",
+        "rename a b":    "expected '->'",
+    }
+    for src, want := range parse {
+        _, err := ParseTransform(src)
+        if err == nil || !strings.Contains(err.Error(), want) { t.Fatalf("%q: got %v, want %q", src, err, want) }
+    }
+    tr, _ := ParseTransform("set a.b = 1")
+    if _, err := tr.Apply(ev.Event{Payload: map[string]any{"a": 5}}); err == nil || !strings.Contains(err.Error(), "parent is not an object") {
+        t.Fatalf("expected parent error, got %v", err)
+    }
+    if _, err := tr.Apply(ev.Event{Payload: 3}); err == nil || !strings.Contains(err.Error(), "object payload") {
+        t.Fatalf("expected payload type error, got %v", err)
+    }
+}
+
+// set copies object values: editing the source afterwards leaves the copy intact.
+func TestTransform_SetCopiesValues(t *testing.T) {
+    tr, err := ParseTransform("set copy = user; del user.name; set tags2 = tags; set tags = len(tags)")
+    if err != nil { t.Fatalf("parse: %v", err) }
+    out, err := tr.Apply(ev.Event{Payload: map[string]any{"user": map[string]any{"name": "ada", "id": 1}, "tags": []any{"a"}}})
+    if err != nil { t.Fatalf("apply: %v", err) }
+    p := out.Payload.(map[string]any)
+    if p["copy"].(map[string]any)["name"] != "ada" { t.Fatalf("copy aliased user: %+v", p) }
+    if _, ok := p["user"].(map[string]any)["name"]; ok { t.Fatalf("user.name not deleted: %+v", p) }
+    if !reflect.DeepEqual(p["tags2"], []any{"a"}) { t.Fatalf("tags2: %+v", p) }
+}
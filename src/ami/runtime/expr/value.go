@@ -0,0 +1,90 @@
+package expr
+
+import (
+    "fmt"
+    "math"
+    "strconv"
+)
+
+// normalize maps Go numeric kinds onto int64/float64 so evaluation deals
+// with a small closed set: nil, bool, string, int64, float64, map, slice.
+func normalize(v any) any {
+    switch x := v.(type) {
+    case int: return int64(x)
+    case int8: return int64(x)
+    case int16: return int64(x)
+    case int32: return int64(x)
+    case uint: return int64(x)
+    case uint8: return int64(x)
+    case uint16: return int64(x)
+    case uint32: return int64(x)
+    case uint64: return int64(x)
+    case float32: return float64(x)
+    default: return v
+    }
+}
+
+func toFloat(v any) (float64, bool) {
+    switch x := v.(type) {
+    case int64: return float64(x), true
+    case float64: return x, true
+    }
+    return 0, false
+}
+
+func isNumber(v any) bool { _, ok := toFloat(v); return ok }
+
+// typeName names a normalized value for diagnostics.
+func typeName(v any) string {
+    switch v.(type) {
+    case nil: return "null"
+    case bool: return "bool"
+    case string: return "string"
+    case int64, float64: return "number"
+    case map[string]any: return "object"
+    case []any: return "array"
+    default: return fmt.Sprintf("%T", v)
+    }
+}
+
+// toString renders a value for string functions and concatenation.
+func toString(v any) string {
+    switch x := v.(type) {
+    case nil: return "null"
+    case string: return x
+    case int64: return strconv.FormatInt(x, 10)
+    case float64:
+        if x == math.Trunc(x) && math.Abs(x) < 1e15 { return strconv.FormatInt(int64(x), 10) }
+        return strconv.FormatFloat(x, 'g', -1, 64)
+    default: return fmt.Sprint(x)
+    }
+}
+
+// equal compares normalized values; numbers compare by value across int/float.
+func equal(a, b any) bool {
+    if fa, ok := toFloat(a); ok {
+        fb, ok := toFloat(b)
+        return ok && fa == fb
+    }
+    switch x := a.(type) {
+    case nil: return b == nil
+    case bool: y, ok := b.(bool); return ok && x == y
+    case string: y, ok := b.(string); return ok && x == y
+    }
+    return false
+}
+
+// compare orders two numbers or two strings; ok is false for other pairs.
+func compare(a, b any) (int, bool) {
+    if fa, ok := toFloat(a); ok {
+        fb, ok := toFloat(b)
+        if !ok { return 0, false }
+        switch { case fa < fb: return -1, true; case fa > fb: return 1, true }
+        return 0, true
+    }
+    sa, ok1 := a.(string)
+    sb, ok2 := b.(string)
+    if !ok1 || !ok2 { return 0, false }
+    switch { case sa < sb: return -1, true; case sa > sb: return 1, true }
+    return 0, true
+}
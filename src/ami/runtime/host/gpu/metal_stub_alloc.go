@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package gpu
+
+// MetalAlloc is unavailable on non-darwin platforms.
+func MetalAlloc(n int) (Buffer, error) { return Buffer{}, ErrUnavailable }
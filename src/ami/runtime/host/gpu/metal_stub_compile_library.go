@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package gpu
+
+// MetalCompileLibrary is unavailable on non-darwin platforms.
+func MetalCompileLibrary(src string) (Library, error) { return Library{}, ErrUnavailable }
@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package gpu
+
+// MetalCopyFromDevice is unavailable on non-darwin platforms.
+func MetalCopyFromDevice(dst []byte, src Buffer) error { return ErrUnavailable }
@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package gpu
+
+// MetalCopyToDevice is unavailable on non-darwin platforms.
+func MetalCopyToDevice(dst Buffer, src []byte) error { return ErrUnavailable }
@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package gpu
+
+// MetalCreateContext is unavailable on non-darwin platforms.
+func MetalCreateContext(dev Device) (Context, error) { return Context{}, ErrUnavailable }
@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package gpu
+
+// MetalCreatePipeline is unavailable on non-darwin platforms.
+func MetalCreatePipeline(lib Library, name string) (Pipeline, error) { return Pipeline{}, ErrUnavailable }
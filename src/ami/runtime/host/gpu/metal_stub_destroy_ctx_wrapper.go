@@ -0,0 +1,9 @@
+//go:build !darwin
+
+package gpu
+
+// MetalDestroyContext invalidates the context; non-darwin builds never hold valid handles.
+func MetalDestroyContext(ctx Context) error {
+    if ctx.backend != "metal" || !ctx.valid || ctx.ctxId <= 0 { return ErrInvalidHandle }
+    return nil
+}
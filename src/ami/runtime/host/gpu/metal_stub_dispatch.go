@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package gpu
+
+// MetalDispatch is unavailable on non-darwin platforms.
+func MetalDispatch(ctx Context, p Pipeline, grid, threadsPerGroup [3]uint32, args ...any) error {
+    return ErrUnavailable
+}
@@ -0,0 +1,9 @@
+//go:build !darwin
+
+package gpu
+
+// MetalFree releases a buffer; non-darwin builds never hold valid handles.
+func MetalFree(buf Buffer) error {
+    if buf.backend != "metal" || !buf.valid || buf.bufId <= 0 { return ErrInvalidHandle }
+    return nil
+}
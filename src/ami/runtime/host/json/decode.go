@@ -0,0 +1,182 @@
+package json
+
+import (
+    "bytes"
+    stdjson "encoding/json"
+    "errors"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+// Decode parses data as a single JSON value of type t and returns it in the
+// runtime value model: structs and maps become map[string]any, slices and sets
+// []any, Optional<T> nil or a T, enums their member name, Event<T> its T
+// payload, int an int and int64 an int64. Object members a struct does not
+// declare are ignored. Struct fields are checked in name order, so the
+// *DecodeError returned for a mismatching document is deterministic.
+func Decode(data []byte, t types.Type) (any, error) {
+    dec := stdjson.NewDecoder(bytes.NewReader(data))
+    dec.UseNumber()
+    var raw any
+    if err := dec.Decode(&raw); err != nil { return nil, syntaxError(err, dec.InputOffset()) }
+    if _, err := dec.Token(); err != io.EOF {
+        return nil, &DecodeError{Code: "E_JSON_SYNTAX", Offset: dec.InputOffset(), Detail: "unexpected data after top-level value"}
+    }
+    return decodeValue(raw, t, "")
+}
+
+func syntaxError(err error, off int64) *DecodeError {
+    var se *stdjson.SyntaxError
+    if errors.As(err, &se) { off = se.Offset }
+    detail := err.Error()
+    if err == io.EOF || err == io.ErrUnexpectedEOF { detail = "unexpected end of input" }
+    return &DecodeError{Code: "E_JSON_SYNTAX", Offset: off, Detail: detail}
+}
+
+func decodeValue(raw any, t types.Type, path string) (any, error) {
+    mismatch := func() error { return &DecodeError{Code: "E_JSON_TYPE", Path: path, Expected: t.String(), Got: jsonKind(raw)} }
+    switch tt := t.(type) {
+    case types.Primitive:
+        switch tt.K {
+        case types.Bool:
+            if b, ok := raw.(bool); ok { return b, nil }
+        case types.String:
+            if s, ok := raw.(string); ok { return s, nil }
+        case types.Int, types.Int64:
+            n, ok := raw.(stdjson.Number)
+            if !ok { return nil, mismatch() }
+            i, err := strconv.ParseInt(n.String(), 10, 64)
+            if err != nil { return nil, &DecodeError{Code: "E_JSON_TYPE", Path: path, Expected: t.String(), Got: "number " + n.String()} }
+            if tt.K == types.Int { return int(i), nil }
+            return i, nil
+        case types.Float64:
+            if n, ok := raw.(stdjson.Number); ok {
+                f, err := n.Float64()
+                if err == nil { return f, nil }
+            }
+        }
+        return nil, mismatch()
+    case types.Optional:
+        if raw == nil { return nil, nil }
+        return decodeValue(raw, tt.Inner, path)
+    case types.Struct:
+        obj, ok := raw.(map[string]any)
+        if !ok { return nil, mismatch() }
+        names := make([]string, 0, len(tt.Fields))
+        for k := range tt.Fields { names = append(names, k) }
+        sort.Strings(names)
+        out := make(map[string]any, len(names))
+        for _, k := range names {
+            ft := tt.Fields[k]
+            fv, present := obj[k]
+            if !present {
+                if _, opt := ft.(types.Optional); opt { out[k] = nil; continue }
+                return nil, &DecodeError{Code: "E_JSON_MISSING_FIELD", Path: joinField(path, k), Expected: ft.String()}
+            }
+            v, err := decodeValue(fv, ft, joinField(path, k))
+            if err != nil { return nil, err }
+            out[k] = v
+        }
+        return out, nil
+    case types.Enum:
+        if s, ok := raw.(string); ok {
+            for _, m := range tt.Members { if m == s { return s, nil } }
+            return nil, &DecodeError{Code: "E_JSON_TYPE", Path: path, Expected: "one of " + strings.Join(tt.Members, "|"), Got: strconv.Quote(s)}
+        }
+        return nil, mismatch()
+    case types.Union:
+        for _, alt := range tt.Alts {
+            if v, err := decodeValue(raw, alt, path); err == nil { return v, nil }
+        }
+        return nil, mismatch()
+    case types.Slice:
+        return decodeList(raw, tt.Elem, path, mismatch)
+    case types.SliceTy:
+        return decodeList(raw, tt.Elem, path, mismatch)
+    case types.Set:
+        return decodeList(raw, tt.Elem, path, mismatch)
+    case types.Map:
+        return decodeMap(raw, tt.Val, path, mismatch)
+    case types.Generic:
+        switch {
+        case tt.Name == "any" && len(tt.Args) == 0:
+            return plainValue(raw), nil
+        case tt.Name == "Event" && len(tt.Args) == 1:
+            return decodeValue(raw, tt.Args[0], path)
+        case (tt.Name == "slice" || tt.Name == "set") && len(tt.Args) == 1:
+            return decodeList(raw, tt.Args[0], path, mismatch)
+        case tt.Name == "map" && len(tt.Args) == 2:
+            return decodeMap(raw, tt.Args[1], path, mismatch)
+        case strings.HasPrefix(tt.Name, "[]") && len(tt.Args) == 0:
+            if et, err := types.Parse(tt.Name[2:]); err == nil { return decodeList(raw, et, path, mismatch) }
+        }
+    }
+    return nil, &DecodeError{Code: "E_JSON_TYPE", Path: path, Expected: t.String(), Got: "unsupported target type"}
+}
+
+func decodeList(raw any, elem types.Type, path string, mismatch func() error) (any, error) {
+    arr, ok := raw.([]any)
+    if !ok { return nil, mismatch() }
+    out := make([]any, len(arr))
+    for i, x := range arr {
+        v, err := decodeValue(x, elem, path+"["+strconv.Itoa(i)+"]")
+        if err != nil { return nil, err }
+        out[i] = v
+    }
+    return out, nil
+}
+
+func decodeMap(raw any, val types.Type, path string, mismatch func() error) (any, error) {
+    obj, ok := raw.(map[string]any)
+    if !ok { return nil, mismatch() }
+    keys := make([]string, 0, len(obj))
+    for k := range obj { keys = append(keys, k) }
+    sort.Strings(keys)
+    out := make(map[string]any, len(obj))
+    for _, k := range keys {
+        v, err := decodeValue(obj[k], val, path+"["+strconv.Quote(k)+"]")
+        if err != nil { return nil, err }
+        out[k] = v
+    }
+    return out, nil
+}
+
+// plainValue converts an untyped document into the value model; integral
+// numbers become int and others float64.
+func plainValue(raw any) any {
+    switch v := raw.(type) {
+    case stdjson.Number:
+        if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil { return int(i) }
+        f, _ := v.Float64()
+        return f
+    case []any:
+        for i := range v { v[i] = plainValue(v[i]) }
+        return v
+    case map[string]any:
+        for k := range v { v[k] = plainValue(v[k]) }
+        return v
+    }
+    return raw
+}
+
+func joinField(path, name string) string {
+    if path == "" { return name }
+    return path + "." + name
+}
+
+// jsonKind names the JSON kind of a decoded value for error reports.
+func jsonKind(raw any) string {
+    switch raw.(type) {
+    case nil: return "null"
+    case bool: return "bool"
+    case stdjson.Number: return "number"
+    case string: return "string"
+    case []any: return "array"
+    case map[string]any: return "object"
+    }
+    return "unknown"
+}
@@ -0,0 +1,39 @@
+package json
+
+import "fmt"
+
+// DecodeError reports why a JSON document does not match the requested type.
+// Code is E_JSON_SYNTAX for malformed documents, E_JSON_TYPE for a value of the
+// wrong kind and E_JSON_MISSING_FIELD for an absent required struct field.
+// Path names the offending value ("" for the document root, e.g.
+// `items[2].sku` or `tags["env"]`); Expected and Got describe the mismatch and
+// Detail carries the parser message for syntax errors.
+type DecodeError struct {
+    Code     string
+    Path     string
+    Expected string
+    Got      string
+    Offset   int64
+    Detail   string
+}
+
+func (e *DecodeError) Error() string {
+    where := e.Path
+    if where == "" { where = "document" }
+    switch e.Code {
+    case "E_JSON_SYNTAX":
+        return fmt.Sprintf("%s: offset %d: %s", e.Code, e.Offset, e.Detail)
+    case "E_JSON_MISSING_FIELD":
+        return fmt.Sprintf("%s: %s: missing required field of type %s", e.Code, where, e.Expected)
+    default:
+        return fmt.Sprintf("%s: %s: expected %s, got %s", e.Code, where, e.Expected, e.Got)
+    }
+}
+
+// Data returns the error's fields as an errors.v1 data map.
+func (e *DecodeError) Data() map[string]any {
+    if e.Code == "E_JSON_SYNTAX" { return map[string]any{"offset": e.Offset} }
+    m := map[string]any{"path": e.Path, "expected": e.Expected}
+    if e.Got != "" { m["got"] = e.Got }
+    return m
+}
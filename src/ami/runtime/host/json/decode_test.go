@@ -0,0 +1,72 @@
+package json
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+func orderType() types.Type {
+    return types.Struct{Fields: map[string]types.Type{
+        "id":     types.Primitive{K: types.String},
+        "qty":    types.Primitive{K: types.Int},
+        "price":  types.Primitive{K: types.Float64},
+        "status": types.Enum{Name: "app.Status", Members: []string{"Open", "Closed"}},
+        "note":   types.Optional{Inner: types.Primitive{K: types.String}},
+        "tags":   types.Generic{Name: "map", Args: []types.Type{types.Primitive{K: types.String}, types.Primitive{K: types.String}}},
+        "lines":  types.Generic{Name: "slice", Args: []types.Type{types.Struct{Fields: map[string]types.Type{"sku": types.Primitive{K: types.String}}}}},
+    }}
+}
+
+func TestDecode_TypedStruct(t *testing.T) {
+    v, err := Decode([]byte(`{"id":"o1","qty":3,"price":9.5,"status":"Open","tags":{"env":"prod"},"lines":[{"sku":"a"}],"extra":true}`), orderType())
+    if err != nil { t.Fatalf("decode: %v", err) }
+    want := map[string]any{
+        "id": "o1", "qty": 3, "price": 9.5, "status": "Open", "note": nil,
+        "tags": map[string]any{"env": "prod"},
+        "lines": []any{map[string]any{"sku": "a"}},
+    }
+    if !reflect.DeepEqual(v, want) { t.Fatalf("got %#v", v) }
+}
+
+func TestDecode_FieldErrors(t *testing.T) {
+    cases := []struct{ doc, code, path string }{
+        {`{"id":"o1","qty":"3","price":1,"status":"Open","tags":{},"lines":[]}`, "E_JSON_TYPE", "qty"},
+        {`{"id":"o1","qty":1.5,"price":1,"status":"Open","tags":{},"lines":[]}`, "E_JSON_TYPE", "qty"},
+        {`{"id":"o1","qty":1,"price":1,"status":"Gone","tags":{},"lines":[]}`, "E_JSON_TYPE", "status"},
+        {`{"id":"o1","qty":1,"price":1,"status":"Open","tags":{"a":1},"lines":[]}`, "E_JSON_TYPE", `tags["a"]`},
+        {`{"id":"o1","qty":1,"price":1,"status":"Open","tags":{},"lines":[{"sku":"a"},{}]}`, "E_JSON_MISSING_FIELD", "lines[1].sku"},
+        {`{"id":"o1","qty":1,"price":1,"status":"Open","tags":{}}`, "E_JSON_MISSING_FIELD", "lines"},
+        {`{"id":null,"qty":1,"price":1,"status":"Open","tags":{},"lines":[]}`, "E_JSON_TYPE", "id"},
+        {`[1]`, "E_JSON_TYPE", ""},
+        {`{"id":`, "E_JSON_SYNTAX", ""},
+        {`{} {}`, "E_JSON_SYNTAX", ""},
+    }
+    for _, c := range cases {
+        _, err := Decode([]byte(c.doc), orderType())
+        var de *DecodeError
+        if !errors.As(err, &de) { t.Fatalf("%s: want DecodeError, got %v", c.doc, err) }
+        if de.Code != c.code || de.Path != c.path { t.Fatalf("%s: got %s at %q (%v)", c.doc, de.Code, de.Path, de) }
+    }
+}
+
+func TestDecode_UnionEventAndAny(t *testing.T) {
+    u := types.Union{Alts: []types.Type{types.Primitive{K: types.Int64}, types.Primitive{K: types.String}}}
+    if v, err := Decode([]byte(`"x"`), u); err != nil || v != "x" { t.Fatalf("union string: %v %v", v, err) }
+    if v, err := Decode([]byte(`7`), u); err != nil || v != int64(7) { t.Fatalf("union int64: %v %v", v, err) }
+    ev := types.Generic{Name: "Event", Args: []types.Type{types.Primitive{K: types.Bool}}}
+    if v, err := Decode([]byte(`true`), ev); err != nil || v != true { t.Fatalf("event payload: %v %v", v, err) }
+    v, err := Decode([]byte(`{"a":[1,2.5,null]}`), types.Generic{Name: "any"})
+    if err != nil { t.Fatal(err) }
+    if !reflect.DeepEqual(v, map[string]any{"a": []any{1, 2.5, nil}}) { t.Fatalf("any: %#v", v) }
+}
+
+func TestDecodeError_MessageAndData(t *testing.T) {
+    e := &DecodeError{Code: "E_JSON_TYPE", Path: "qty", Expected: "int", Got: "string"}
+    if e.Error() != "E_JSON_TYPE: qty: expected int, got string" { t.Fatalf("message: %s", e.Error()) }
+    if d := e.Data(); d["path"] != "qty" || d["expected"] != "int" || d["got"] != "string" { t.Fatalf("data: %v", d) }
+    s := &DecodeError{Code: "E_JSON_SYNTAX", Offset: 4, Detail: "unexpected end of input"}
+    if s.Error() != "E_JSON_SYNTAX: offset 4: unexpected end of input" || s.Data()["offset"] != int64(4) { t.Fatalf("syntax: %s %v", s.Error(), s.Data()) }
+}
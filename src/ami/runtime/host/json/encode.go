@@ -0,0 +1,91 @@
+package json
+
+import (
+    "bytes"
+    stdjson "encoding/json"
+    "fmt"
+    stdmath "math"
+    "reflect"
+    "sort"
+    "strconv"
+)
+
+// Encode renders a runtime value as compact JSON. Output is deterministic:
+// object keys are written in sorted order (as events.v1 payloads are) and
+// strings are not HTML-escaped. Values JSON cannot represent (NaN, infinities,
+// non-string map keys, functions, channels) fail with an E_JSON_ENCODE error
+// naming their path.
+func Encode(v any) ([]byte, error) {
+    var buf bytes.Buffer
+    if err := encodeValue(&buf, reflect.ValueOf(v), ""); err != nil { return nil, err }
+    return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, rv reflect.Value, path string) error {
+    fail := func(why string) error {
+        where := path
+        if where == "" { where = "value" }
+        return fmt.Errorf("E_JSON_ENCODE: %s: %s", where, why)
+    }
+    if !rv.IsValid() { buf.WriteString("null"); return nil }
+    if rv.Type() == reflect.TypeOf(stdjson.Number("")) {
+        buf.WriteString(rv.String())
+        return nil
+    }
+    switch rv.Kind() {
+    case reflect.Interface, reflect.Pointer:
+        if rv.IsNil() { buf.WriteString("null"); return nil }
+        return encodeValue(buf, rv.Elem(), path)
+    case reflect.Bool:
+        buf.WriteString(strconv.FormatBool(rv.Bool()))
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        buf.WriteString(strconv.FormatInt(rv.Int(), 10))
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        buf.WriteString(strconv.FormatUint(rv.Uint(), 10))
+    case reflect.Float32, reflect.Float64:
+        f := rv.Float()
+        if stdmath.IsNaN(f) || stdmath.IsInf(f, 0) { return fail("unsupported float " + strconv.FormatFloat(f, 'g', -1, 64)) }
+        b, _ := stdjson.Marshal(f)
+        buf.Write(b)
+    case reflect.String:
+        writeString(buf, rv.String())
+    case reflect.Slice, reflect.Array:
+        if rv.Kind() == reflect.Slice && rv.IsNil() { buf.WriteString("null"); return nil }
+        buf.WriteByte('[')
+        for i := 0; i < rv.Len(); i++ {
+            if i > 0 { buf.WriteByte(',') }
+            if err := encodeValue(buf, rv.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil { return err }
+        }
+        buf.WriteByte(']')
+    case reflect.Map:
+        if rv.Type().Key().Kind() != reflect.String { return fail("map key type " + rv.Type().Key().String() + " is not string") }
+        if rv.IsNil() { buf.WriteString("null"); return nil }
+        keys := make([]string, 0, rv.Len())
+        for _, k := range rv.MapKeys() { keys = append(keys, k.String()) }
+        sort.Strings(keys)
+        buf.WriteByte('{')
+        for i, k := range keys {
+            if i > 0 { buf.WriteByte(',') }
+            writeString(buf, k)
+            buf.WriteByte(':')
+            kv := reflect.ValueOf(k).Convert(rv.Type().Key())
+            if err := encodeValue(buf, rv.MapIndex(kv), path+"["+strconv.Quote(k)+"]"); err != nil { return err }
+        }
+        buf.WriteByte('}')
+    default:
+        // other host values (e.g. time.Time) use their standard JSON form
+        b, err := stdjson.Marshal(rv.Interface())
+        if err != nil { return fail(err.Error()) }
+        buf.Write(b)
+    }
+    return nil
+}
+
+// writeString writes s as a JSON string literal without HTML escaping.
+func writeString(buf *bytes.Buffer, s string) {
+    var tmp bytes.Buffer
+    enc := stdjson.NewEncoder(&tmp)
+    enc.SetEscapeHTML(false)
+    _ = enc.Encode(s)
+    buf.Write(bytes.TrimSuffix(tmp.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,34 @@
+package json
+
+import (
+    stdmath "math"
+    "strings"
+    "testing"
+)
+
+func TestEncode_SortedKeysAndValueModel(t *testing.T) {
+    v := map[string]any{
+        "z": 1, "a": []any{true, nil, 2.5, "x<y"},
+        "m": map[string]any{"b": int64(2), "a": map[string]int{"y": 1, "x": 0}},
+    }
+    for i := 0; i < 5; i++ {
+        b, err := Encode(v)
+        if err != nil { t.Fatal(err) }
+        want := `{"a":[true,null,2.5,"x<y"],"m":{"a":{"x":0,"y":1},"b":2},"z":1}`
+        if string(b) != want { t.Fatalf("got %s", b) }
+    }
+}
+
+func TestEncode_RoundTripsDecode(t *testing.T) {
+    doc := `{"id":"o1","lines":[{"sku":"a"}],"note":null,"price":9.5,"qty":3,"status":"Open","tags":{"env":"prod"}}`
+    v, err := Decode([]byte(doc), orderType())
+    if err != nil { t.Fatal(err) }
+    b, err := Encode(v)
+    if err != nil || string(b) != doc { t.Fatalf("round trip: %s %v", b, err) }
+}
+
+func TestEncode_Unsupported(t *testing.T) {
+    if _, err := Encode(map[string]any{"f": stdmath.NaN()}); err == nil || !strings.HasPrefix(err.Error(), `E_JSON_ENCODE: ["f"]`) { t.Fatalf("nan: %v", err) }
+    if _, err := Encode(map[int]string{1: "a"}); err == nil || !strings.Contains(err.Error(), "not string") { t.Fatalf("int keys: %v", err) }
+    if _, err := Encode([]any{func() {}}); err == nil { t.Fatal("func: expected error") }
+}
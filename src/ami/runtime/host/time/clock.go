@@ -0,0 +1,13 @@
+package amitime
+
+import stdtime "time"
+
+// Clock is the time source used by the runtime (timers, merge timeouts and
+// watermarks, kvstore TTLs). SystemClock reads the wall clock; VirtualClock only
+// moves when advanced explicitly, which makes simulations and tests deterministic.
+type Clock interface {
+    Now() stdtime.Time
+    NewTimer(d Duration) ClockTimer
+    NewTicker(d Duration) ClockTicker
+    Sleep(d Duration)
+}
@@ -0,0 +1,26 @@
+package amitime
+
+import "sync"
+
+var (
+    clockMu sync.RWMutex
+    // current clock (package-scoped); nil means SystemClock
+    currentClock Clock
+)
+
+// SetClock installs c as the process-wide runtime clock (nil restores the
+// system clock) and returns the previously installed clock.
+func SetClock(c Clock) Clock {
+    clockMu.Lock(); defer clockMu.Unlock()
+    prev := currentClock
+    currentClock = c
+    if prev == nil { return SystemClock{} }
+    return prev
+}
+
+// GetClock returns the process-wide runtime clock.
+func GetClock() Clock {
+    clockMu.RLock(); defer clockMu.RUnlock()
+    if currentClock == nil { return SystemClock{} }
+    return currentClock
+}
@@ -0,0 +1,10 @@
+package amitime
+
+import stdtime "time"
+
+// ClockTicker delivers time values on C at a fixed period of its Clock. Like
+// time.Ticker, ticks are dropped when the receiver falls behind.
+type ClockTicker interface {
+    C() <-chan stdtime.Time
+    Stop()
+}
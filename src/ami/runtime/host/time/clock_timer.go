@@ -0,0 +1,10 @@
+package amitime
+
+import stdtime "time"
+
+// ClockTimer delivers a single time value on C once its Clock reaches the deadline.
+type ClockTimer interface {
+    C() <-chan stdtime.Time
+    // Stop prevents the timer from firing; it reports whether the timer was pending.
+    Stop() bool
+}
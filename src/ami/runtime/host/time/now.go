@@ -1,7 +1,5 @@
 package amitime
 
-import stdtime "time"
-
-// Now returns the current wall-clock time.
-func Now() Time { return Time{t: stdtime.Now()} }
-
+// Now returns the current time of the runtime clock (wall clock by default;
+// see SetClock).
+func Now() Time { return Time{t: GetClock().Now()} }
@@ -1,7 +1,4 @@
 package amitime
 
-import stdtime "time"
-
-// Sleep pauses the current goroutine for at least duration d.
-func Sleep(d Duration) { stdtime.Sleep(d) }
-
+// Sleep pauses the current goroutine for at least duration d of runtime-clock time.
+func Sleep(d Duration) { GetClock().Sleep(d) }
@@ -0,0 +1,28 @@
+package amitime
+
+import stdtime "time"
+
+// SystemClock is the wall-clock Clock backed by the standard library.
+type SystemClock struct{}
+
+// Now returns the current wall-clock time.
+func (SystemClock) Now() stdtime.Time { return stdtime.Now() }
+
+// NewTimer returns a timer firing after d of wall-clock time.
+func (SystemClock) NewTimer(d Duration) ClockTimer { return sysTimer{t: stdtime.NewTimer(d)} }
+
+// NewTicker returns a ticker with wall-clock period d (d must be positive).
+func (SystemClock) NewTicker(d Duration) ClockTicker { return sysTicker{t: stdtime.NewTicker(d)} }
+
+// Sleep pauses the current goroutine for at least d.
+func (SystemClock) Sleep(d Duration) { stdtime.Sleep(d) }
+
+type sysTimer struct{ t *stdtime.Timer }
+
+func (s sysTimer) C() <-chan stdtime.Time { return s.t.C }
+func (s sysTimer) Stop() bool             { return s.t.Stop() }
+
+type sysTicker struct{ t *stdtime.Ticker }
+
+func (s sysTicker) C() <-chan stdtime.Time { return s.t.C }
+func (s sysTicker) Stop()                  { s.t.Stop() }
@@ -1,11 +1,9 @@
 package amitime
 
-import (
-    stdtime "time"
-    "sync"
-)
+import "sync"
 
-// Ticker periodically invokes registered handlers at a fixed interval.
+// Ticker periodically invokes registered handlers at a fixed interval of the
+// runtime clock (captured when Start is called).
 type Ticker struct {
     d        Duration
     mu       sync.Mutex
@@ -32,12 +30,12 @@ func (t *Ticker) Start() {
     t.stopCh = make(chan struct{})
     d := t.d
     t.mu.Unlock()
+    tick := GetClock().NewTicker(d)
     go func(){
-        tick := stdtime.NewTicker(d)
         defer tick.Stop()
         for {
             select {
-            case <-tick.C:
+            case <-tick.C():
                 t.mu.Lock()
                 fns := append([]func(){}, t.handlers...)
                 t.mu.Unlock()
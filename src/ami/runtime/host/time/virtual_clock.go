@@ -0,0 +1,164 @@
+package amitime
+
+import (
+    "sync"
+    stdtime "time"
+)
+
+// VirtualClock is a deterministic Clock whose time only moves through Advance,
+// AdvanceTo or AdvanceNext. Timers and tickers fire in deadline order while the
+// clock is advanced, each observing Now equal to its own deadline; Sleep blocks
+// until another goroutine advances past the wake-up time.
+type VirtualClock struct {
+    mu      sync.Mutex
+    cond    *sync.Cond
+    now     stdtime.Time
+    seq     int
+    waiters []*vwaiter
+}
+
+// vwaiter is a pending virtual timer (period 0) or ticker.
+type vwaiter struct {
+    clk    *VirtualClock
+    at     stdtime.Time
+    period Duration
+    seq    int
+    ch     chan stdtime.Time
+}
+
+// NewVirtualClock returns a VirtualClock reading start.
+func NewVirtualClock(start stdtime.Time) *VirtualClock {
+    c := &VirtualClock{now: start}
+    c.cond = sync.NewCond(&c.mu)
+    return c
+}
+
+// Now returns the current virtual time.
+func (c *VirtualClock) Now() stdtime.Time {
+    c.mu.Lock(); defer c.mu.Unlock()
+    return c.now
+}
+
+// NewTimer returns a timer firing once the clock reaches Now()+d; a
+// non-positive d fires immediately.
+func (c *VirtualClock) NewTimer(d Duration) ClockTimer {
+    c.mu.Lock(); defer c.mu.Unlock()
+    w := &vwaiter{clk: c, ch: make(chan stdtime.Time, 1)}
+    if d <= 0 { w.ch <- c.now; return w }
+    w.at = c.now.Add(d)
+    c.add(w)
+    return w
+}
+
+// NewTicker returns a ticker firing every d of virtual time. It panics when d
+// is not positive, matching time.NewTicker.
+func (c *VirtualClock) NewTicker(d Duration) ClockTicker {
+    if d <= 0 { panic("amitime: non-positive interval for VirtualClock.NewTicker") }
+    c.mu.Lock(); defer c.mu.Unlock()
+    w := &vwaiter{clk: c, at: c.now.Add(d), period: d, ch: make(chan stdtime.Time, 1)}
+    c.add(w)
+    return vticker{w}
+}
+
+// Sleep blocks until the clock has been advanced by at least d.
+func (c *VirtualClock) Sleep(d Duration) { <-c.NewTimer(d).C() }
+
+// Advance moves the clock forward by d, firing every timer and ticker due on
+// the way. Negative durations are ignored.
+func (c *VirtualClock) Advance(d Duration) {
+    if d < 0 { return }
+    c.mu.Lock(); defer c.mu.Unlock()
+    c.advanceTo(c.now.Add(d))
+}
+
+// AdvanceTo moves the clock forward to t; it never moves the clock backwards.
+func (c *VirtualClock) AdvanceTo(t stdtime.Time) {
+    c.mu.Lock(); defer c.mu.Unlock()
+    if t.Before(c.now) { return }
+    c.advanceTo(t)
+}
+
+// AdvanceNext jumps to the earliest pending deadline and fires it. It reports
+// false, leaving the clock untouched, when nothing is pending.
+func (c *VirtualClock) AdvanceNext() bool {
+    c.mu.Lock(); defer c.mu.Unlock()
+    w := c.next()
+    if w == nil { return false }
+    c.advanceTo(w.at)
+    return true
+}
+
+// Waiters returns the number of pending timers and tickers (including
+// goroutines blocked in Sleep).
+func (c *VirtualClock) Waiters() int {
+    c.mu.Lock(); defer c.mu.Unlock()
+    return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers or tickers are pending, so a test
+// can advance only after the code under test has armed its timers.
+func (c *VirtualClock) BlockUntil(n int) {
+    c.mu.Lock(); defer c.mu.Unlock()
+    for len(c.waiters) < n { c.cond.Wait() }
+}
+
+// add registers w; callers hold c.mu.
+func (c *VirtualClock) add(w *vwaiter) {
+    c.seq++
+    w.seq = c.seq
+    c.waiters = append(c.waiters, w)
+    c.cond.Broadcast()
+}
+
+// remove unregisters w and reports whether it was pending; callers hold c.mu.
+func (c *VirtualClock) remove(w *vwaiter) bool {
+    for i, x := range c.waiters {
+        if x == w {
+            c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+            return true
+        }
+    }
+    return false
+}
+
+// next returns the earliest pending waiter, ties broken by creation order.
+func (c *VirtualClock) next() *vwaiter {
+    var best *vwaiter
+    for _, w := range c.waiters {
+        if best == nil || w.at.Before(best.at) || (w.at.Equal(best.at) && w.seq < best.seq) { best = w }
+    }
+    return best
+}
+
+// advanceTo fires due waiters in deadline order and then sets the clock to t;
+// callers hold c.mu. Sends never block: like the standard library, a tick is
+// dropped while the previous one is still unread.
+func (c *VirtualClock) advanceTo(t stdtime.Time) {
+    for {
+        w := c.next()
+        if w == nil || w.at.After(t) { break }
+        c.now = w.at
+        select { case w.ch <- w.at: default: }
+        if w.period <= 0 { c.remove(w); continue }
+        if len(w.ch) == cap(w.ch) {
+            // every further tick up to t would be dropped; skip them in one step
+            w.at = w.at.Add(Duration(t.Sub(w.at)/w.period) * w.period)
+        }
+        w.at = w.at.Add(w.period)
+    }
+    c.now = t
+}
+
+func (w *vwaiter) C() <-chan stdtime.Time { return w.ch }
+
+// Stop cancels the timer or ticker, reporting whether it was still pending.
+func (w *vwaiter) Stop() bool {
+    w.clk.mu.Lock(); defer w.clk.mu.Unlock()
+    return w.clk.remove(w)
+}
+
+// vticker adapts a periodic vwaiter to ClockTicker.
+type vticker struct{ w *vwaiter }
+
+func (t vticker) C() <-chan stdtime.Time { return t.w.ch }
+func (t vticker) Stop()                  { t.w.Stop() }
@@ -0,0 +1,91 @@
+package amitime
+
+import (
+    "sync"
+    "testing"
+    stdtime "time"
+)
+
+var vstart = stdtime.Date(2024, 1, 1, 0, 0, 0, 0, stdtime.UTC)
+
+func TestVirtualClock_TimerFiresOnlyWhenAdvanced(t *testing.T) {
+    c := NewVirtualClock(vstart)
+    tm := c.NewTimer(5 * stdtime.Second)
+    c.Advance(4 * stdtime.Second)
+    select {
+    case <-tm.C():
+        t.Fatalf("timer fired early")
+    default:
+    }
+    c.Advance(stdtime.Second)
+    select {
+    case got := <-tm.C():
+        if !got.Equal(vstart.Add(5 * stdtime.Second)) { t.Fatalf("fired at %v", got) }
+    default:
+        t.Fatalf("timer did not fire")
+    }
+    if tm.Stop() { t.Fatalf("stop after fire should report false") }
+    if c.Waiters() != 0 { t.Fatalf("waiters=%d", c.Waiters()) }
+}
+
+func TestVirtualClock_TickerDropsUnreadTicks(t *testing.T) {
+    c := NewVirtualClock(vstart)
+    tk := c.NewTicker(stdtime.Second)
+    defer tk.Stop()
+    c.Advance(10 * stdtime.Second)
+    if got := <-tk.C(); !got.Equal(vstart.Add(stdtime.Second)) { t.Fatalf("first tick at %v", got) }
+    select {
+    case <-tk.C():
+        t.Fatalf("unread ticks should be dropped")
+    default:
+    }
+    c.Advance(stdtime.Second)
+    if got := <-tk.C(); !got.Equal(vstart.Add(11 * stdtime.Second)) { t.Fatalf("next tick at %v", got) }
+}
+
+func TestVirtualClock_DeadlineOrderAndAdvanceNext(t *testing.T) {
+    c := NewVirtualClock(vstart)
+    a := c.NewTimer(3 * stdtime.Second)
+    b := c.NewTimer(stdtime.Second)
+    if !c.AdvanceNext() { t.Fatalf("expected a pending deadline") }
+    if !c.Now().Equal(vstart.Add(stdtime.Second)) { t.Fatalf("now=%v", c.Now()) }
+    <-b.C()
+    if !c.AdvanceNext() { t.Fatalf("expected a pending deadline") }
+    <-a.C()
+    if c.AdvanceNext() { t.Fatalf("no deadline should remain") }
+    c.AdvanceTo(vstart) // never moves backwards
+    if !c.Now().Equal(vstart.Add(3 * stdtime.Second)) { t.Fatalf("now=%v", c.Now()) }
+}
+
+func TestVirtualClock_SleepBlocksUntilAdvance(t *testing.T) {
+    c := NewVirtualClock(vstart)
+    var wg sync.WaitGroup
+    wg.Add(1)
+    var woke stdtime.Time
+    go func(){ defer wg.Done(); c.Sleep(stdtime.Minute); woke = c.Now() }()
+    c.BlockUntil(1)
+    c.Advance(stdtime.Minute)
+    wg.Wait()
+    if !woke.Equal(vstart.Add(stdtime.Minute)) { t.Fatalf("woke at %v", woke) }
+}
+
+func TestSetClock_DrivesNowSleepAndTicker(t *testing.T) {
+    c := NewVirtualClock(vstart)
+    prev := SetClock(c)
+    defer SetClock(prev)
+    if got := Now().UnixNano(); got != vstart.UnixNano() { t.Fatalf("Now=%d", got) }
+    tk := NewTicker(stdtime.Second)
+    fired := make(chan struct{}, 4)
+    tk.Register(func(){ fired <- struct{}{} })
+    tk.Start()
+    defer tk.Stop()
+    c.Advance(stdtime.Second)
+    select {
+    case <-fired:
+    case <-stdtime.After(2 * stdtime.Second):
+        t.Fatalf("ticker handler not invoked after virtual advance")
+    }
+    if _, ok := SetClock(nil).(*VirtualClock); !ok { t.Fatalf("SetClock should return the previous clock") }
+    if _, ok := GetClock().(SystemClock); !ok { t.Fatalf("nil should restore the system clock") }
+    SetClock(c)
+}
@@ -1,25 +1,23 @@
 package trigger
 
-import (
-    stdtime "time"
-    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
-)
+import amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
 
-// Schedule emits a single Event[amitime.Time] at the specified time, then stops.
+// Schedule emits a single Event[amitime.Time] when the runtime clock
+// (amitime.GetClock) reaches at, then stops.
 func Schedule(at amitime.Time) (<-chan Event[amitime.Time], func()) {
     out := make(chan Event[amitime.Time], 1)
-    now := stdtime.Now()
-    d := at.UnixNano() - now.UnixNano()
+    clk := amitime.GetClock()
+    d := at.UnixNano() - clk.Now().UnixNano()
     if d < 0 { d = 0 }
-    timer := stdtime.NewTimer(stdtime.Duration(d))
+    timer := clk.NewTimer(amitime.Duration(d))
     done := make(chan struct{})
     go func() {
         defer close(out)
         select {
-        case tm := <-timer.C:
-            out <- Event[amitime.Time]{Value: toAMI(tm), Timestamp: amitime.Now()}
+        case tm := <-timer.C():
+            out <- Event[amitime.Time]{Value: toAMI(tm), Timestamp: toAMI(tm)}
         case <-done:
-            if !timer.Stop() { <-timer.C }
+            timer.Stop()
         }
     }()
     stop := func() { close(done) }
@@ -0,0 +1,30 @@
+package trigger
+
+import (
+    "testing"
+    stdtime "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+func TestTimerAndSchedule_VirtualClock(t *testing.T) {
+    start := stdtime.Date(2024, 1, 1, 0, 0, 0, 0, stdtime.UTC)
+    c := amitime.NewVirtualClock(start)
+    prev := amitime.SetClock(c)
+    defer amitime.SetClock(prev)
+
+    ticks, stopT := Timer(amitime.Duration(stdtime.Second))
+    defer stopT()
+    once, stopS := Schedule(amitime.FromUnix(start.Unix()+5, 0))
+    defer stopS()
+
+    for i := 1; i <= 5; i++ {
+        c.Advance(stdtime.Second)
+        e := <-ticks
+        if want := start.Add(stdtime.Duration(i) * stdtime.Second).UnixNano(); e.Value.UnixNano() != want || e.Timestamp.UnixNano() != want {
+            t.Fatalf("tick %d: value=%d ts=%d want %d", i, e.Value.UnixNano(), e.Timestamp.UnixNano(), want)
+        }
+    }
+    e, ok := <-once
+    if !ok || e.Value.Unix() != start.Unix()+5 { t.Fatalf("schedule: ok=%v value=%d", ok, e.Value.Unix()) }
+}
@@ -0,0 +1,18 @@
+package kvstore
+
+// Backend persists a Store's entries. The Store calls it under its own lock,
+// after every mutation, in access order, so replaying Load restores both the
+// entries and their LRU order (least-recently used first). Reads that change
+// no metadata are not written; their LRU effect is persisted by Compact.
+type Backend interface {
+    // Load returns the persisted records, least-recently used first.
+    Load() ([]Record, error)
+    // Put upserts a record and marks it most-recently used.
+    Put(r Record) error
+    // Delete removes a key.
+    Delete(key string) error
+    // Compact replaces the persisted state with live (LRU order).
+    Compact(live []Record) error
+    // Close flushes and releases resources.
+    Close() error
+}
@@ -0,0 +1,5 @@
+package kvstore
+
+// BackendFactory opens the Backend for a namespace. Returning (nil, nil)
+// keeps that namespace in memory.
+type BackendFactory func(ns string) (Backend, error)
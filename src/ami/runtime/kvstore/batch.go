@@ -0,0 +1,34 @@
+package kvstore
+
+import "sort"
+
+// GetMany reads several keys under one lock, with Get semantics per key
+// (read budgets, sliding TTL, hit/miss metrics). Missing keys are omitted.
+func (s *Store) GetMany(keys ...string) map[string]any {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    out := make(map[string]any, len(keys))
+    for _, k := range keys {
+        if v, ok := s.getLocked(k); ok { out[k] = v }
+    }
+    return out
+}
+
+// PutMany stores every pair under one lock with the same options, so readers
+// observe either none or all of them. Keys are written in sorted order, which
+// fixes both their LRU order and the order of Watch events.
+func (s *Store) PutMany(kv map[string]any, opts ...PutOption) {
+    o := applyOptions(opts)
+    keys := make([]string, 0, len(kv))
+    for k := range kv { keys = append(keys, k) }
+    sort.Strings(keys)
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for _, k := range keys { s.putLocked(k, kv[k], o) }
+}
+
+// GetMany runs Store.GetMany on the default store.
+func GetMany(keys ...string) map[string]any { return defaultStore.GetMany(keys...) }
+
+// PutMany runs Store.PutMany on the default store.
+func PutMany(kv map[string]any, opts ...PutOption) { defaultStore.PutMany(kv, opts...) }
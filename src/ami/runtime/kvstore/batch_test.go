@@ -0,0 +1,33 @@
+package kvstore
+
+import "testing"
+
+func TestStore_PutMany_GetMany(t *testing.T) {
+    s := New()
+    s.PutMany(map[string]any{"a": 1, "b": 2}, WithMaxReads(1))
+    got := s.GetMany("a", "b", "c")
+    if len(got) != 2 || got["a"] != 1 || got["b"] != 2 { t.Fatalf("got %v", got) }
+    if len(s.GetMany("a", "b")) != 0 { t.Fatalf("read budget should apply per key") }
+    if m := s.Metrics(); m.Hits != 2 || m.Misses != 3 { t.Fatalf("metrics: %+v", m) }
+}
+
+func TestNamespace_BatchOps(t *testing.T) {
+    ResetRegistry()
+    ns := Namespace("p/n")
+    ns.PutMany(map[string]any{"x": 1})
+    if got := Namespace("p/n").GetMany("x"); got["x"] != 1 { t.Fatalf("got %v", got) }
+}
+
+// PutMany writes keys in sorted order: Watch events and LRU eviction are
+// deterministic.
+func TestStore_PutMany_SortedOrder(t *testing.T) {
+    s := New()
+    ch, cancel := s.Watch("", 8)
+    defer cancel()
+    s.PutMany(map[string]any{"c": 3, "a": 1, "d": 4, "b": 2})
+    for _, want := range []string{"a", "b", "c", "d"} {
+        if ev := <-ch; ev.Key != want { t.Fatalf("event %q, want %q", ev.Key, want) }
+    }
+    s.SetCapacity(2)
+    if s.Has("a") || s.Has("b") || !s.Has("c") || !s.Has("d") { t.Fatalf("expected a and b evicted first") }
+}
@@ -0,0 +1,22 @@
+package kvstore
+
+import "time"
+
+// ChangeKind identifies the mutation reported to watchers.
+type ChangeKind string
+
+const (
+    ChangePut    ChangeKind = "put"    // value created or replaced
+    ChangeDelete ChangeKind = "delete" // Del, GetAndDelete, or read budget exhausted
+    ChangeExpire ChangeKind = "expire" // TTL elapsed (observed on access or Sweep)
+    ChangeEvict  ChangeKind = "evict"  // removed by LRU capacity
+)
+
+// Change is one mutation delivered by Watch. Value is the new value for
+// puts and the removed value otherwise.
+type Change struct {
+    Kind  ChangeKind
+    Key   string
+    Value any
+    Time  time.Time
+}
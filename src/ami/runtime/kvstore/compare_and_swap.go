@@ -0,0 +1,21 @@
+package kvstore
+
+// CompareAndSwap replaces key's value with new when the live value deep-equals
+// old, with numbers compared by value (see valuesEqual), keeping its TTL and
+// read budget. Missing or expired keys never match; use PutIfAbsent to create.
+// Failed swaps count as Conflicts.
+func (s *Store) CompareAndSwap(key string, old, new any) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    e, ok := s.liveLocked(key)
+    if !ok || !valuesEqual(e.val, old) { s.metrics.Conflicts++; return false }
+    s.metrics.Hits++
+    e.val = new
+    s.touchLocked(key)
+    s.persistPutLocked(key, e)
+    s.notifyLocked(ChangePut, key, new)
+    return true
+}
+
+// CompareAndSwap runs Store.CompareAndSwap on the default store.
+func CompareAndSwap(key string, old, new any) bool { return defaultStore.CompareAndSwap(key, old, new) }
@@ -0,0 +1,53 @@
+package kvstore
+
+import (
+    "sync"
+    "testing"
+)
+
+func TestStore_CompareAndSwap(t *testing.T) {
+    s := New()
+    if s.CompareAndSwap("k", nil, 1) { t.Fatalf("missing key must not swap") }
+    s.Put("k", map[string]any{"v": 1})
+    if s.CompareAndSwap("k", map[string]any{"v": 2}, 3) { t.Fatalf("mismatch must not swap") }
+    if !s.CompareAndSwap("k", map[string]any{"v": 1}, 3) { t.Fatalf("expected swap") }
+    if v, _ := s.Get("k"); v != 3 { t.Fatalf("got %v", v) }
+    if m := s.Metrics(); m.Conflicts != 2 { t.Fatalf("conflicts: %d", m.Conflicts) }
+}
+
+func TestStore_CompareAndSwap_ConcurrentCounter(t *testing.T) {
+    s := New()
+    s.Put("n", 0)
+    var wg sync.WaitGroup
+    for i := 0; i < 4; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for j := 0; j < 50; j++ {
+                for {
+                    v, _ := s.Get("n")
+                    if s.CompareAndSwap("n", v, v.(int)+1) { break }
+                }
+            }
+        }()
+    }
+    wg.Wait()
+    if v, _ := s.Get("n"); v != 200 { t.Fatalf("expected 200, got %v", v) }
+}
+
+// Values restored by the file backend decode as JSON numbers; CompareAndSwap
+// still matches them against the ints originally stored.
+func TestStore_CompareAndSwap_AfterReopen(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.Put("n", 5)
+    s.Put("m", map[string]any{"v": 1, "xs": []int{1, 2}})
+    s.Put("f", 1.5)
+    _ = s.Close()
+    s = reopen(t, dir)
+    defer func() { _ = s.Close() }()
+    if !s.CompareAndSwap("n", 5, 6) { t.Fatalf("int must match restored number") }
+    if !s.CompareAndSwap("m", map[string]any{"v": 1, "xs": []int{1, 2}}, 0) { t.Fatalf("nested numbers must match") }
+    if !s.CompareAndSwap("f", 1.5, 2.5) { t.Fatalf("float must match") }
+    if s.CompareAndSwap("n", 5, 7) || s.CompareAndSwap("n", "6", 7) { t.Fatalf("mismatches must not swap") }
+}
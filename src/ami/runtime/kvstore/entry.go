@@ -14,7 +14,3 @@ type entry struct {
 func (e *entry) isExpiredAt(t time.Time) bool {
     return !e.expireAt.IsZero() && !t.Before(e.expireAt)
 }
-
-func (e *entry) expired() bool { return e.isExpiredAt(time.Now()) }
-
-func (e *entry) expiredLocked() bool { return e.expired() }
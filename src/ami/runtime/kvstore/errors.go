@@ -0,0 +1,11 @@
+package kvstore
+
+import "errors"
+
+// ErrNotNumeric is returned by Incr/Decr when the stored value is not an
+// integer (whole-number float64 values, e.g. restored from JSON, are accepted).
+var ErrNotNumeric = errors.New("kvstore: value is not an integer")
+
+// ErrOverflow is returned by Incr/Decr when the result does not fit in an
+// int64; the stored value is left unchanged.
+var ErrOverflow = errors.New("kvstore: integer overflow")
@@ -0,0 +1,60 @@
+package kvstore
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "sync"
+)
+
+const (
+    snapshotName = "snapshot.json"
+    logName      = "log.jsonl"
+)
+
+// FileBackend persists a store in a directory as a snapshot (snapshot.json)
+// plus an append-only log of later puts and deletes (log.jsonl). Compact
+// rewrites the snapshot atomically and truncates the log.
+type FileBackend struct {
+    mu   sync.Mutex
+    dir  string
+    log  *os.File
+    sync bool
+}
+
+// OpenFile opens (creating if needed) a file backend rooted at dir.
+func OpenFile(dir string, opts ...FileOption) (*FileBackend, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil { return nil, err }
+    f, err := os.OpenFile(filepath.Join(dir, logName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+    if err != nil { return nil, err }
+    b := &FileBackend{dir: dir, log: f}
+    for _, o := range opts { o(b) }
+    return b, nil
+}
+
+// Put appends a put operation to the log.
+func (b *FileBackend) Put(r Record) error { return b.append(walOp{Op: "put", Key: r.Key, Rec: &r}) }
+
+// Delete appends a delete operation to the log.
+func (b *FileBackend) Delete(key string) error { return b.append(walOp{Op: "del", Key: key}) }
+
+func (b *FileBackend) append(op walOp) error {
+    line, err := json.Marshal(op)
+    if err != nil { return err }
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if b.log == nil { return os.ErrClosed }
+    if _, err := b.log.Write(append(line, '\n')); err != nil { return err }
+    if b.sync { return b.log.Sync() }
+    return nil
+}
+
+// Close closes the log file.
+func (b *FileBackend) Close() error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if b.log == nil { return nil }
+    err := b.log.Close()
+    b.log = nil
+    return err
+}
@@ -0,0 +1,19 @@
+package kvstore
+
+import (
+    "fmt"
+    "path/filepath"
+    "strings"
+)
+
+// FileBackendFactory returns a BackendFactory that stores each namespace in
+// its own directory under root (e.g., pipeline/node → root/pipeline/node).
+func FileBackendFactory(root string, opts ...FileOption) BackendFactory {
+    return func(ns string) (Backend, error) {
+        rel := filepath.Clean(filepath.FromSlash(ns))
+        if ns == "" || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+            return nil, fmt.Errorf("kvstore: invalid namespace %q", ns)
+        }
+        return OpenFile(filepath.Join(root, rel), opts...)
+    }
+}
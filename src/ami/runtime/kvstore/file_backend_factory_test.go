@@ -0,0 +1,49 @@
+package kvstore
+
+import (
+    "errors"
+    "path/filepath"
+    "testing"
+)
+
+func TestNamespace_FileBackendFactory_Persists(t *testing.T) {
+    root := t.TempDir()
+    ResetRegistry()
+    defer ResetRegistry()
+    SetBackendFactory(FileBackendFactory(root))
+    s := Namespace("p1/n1")
+    s.Put("k", "v")
+    if s.Err() != nil { t.Fatalf("err: %v", s.Err()) }
+    _ = s.Close()
+    ResetRegistry()
+    SetBackendFactory(FileBackendFactory(root))
+    if v, ok := Namespace("p1/n1").Get("k"); !ok || v != "v" { t.Fatalf("k: %v %v", v, ok) }
+    if _, ok := Namespace("p1/n2").Get("k"); ok { t.Fatalf("namespaces must not share state") }
+    if _, ok := reopen(t, filepath.Join(root, "p1", "n1")).Get("k"); !ok { t.Fatalf("expected file under namespace dir") }
+}
+
+func TestNamespace_FileBackendFactory_RejectsEscapes(t *testing.T) {
+    ResetRegistry()
+    defer ResetRegistry()
+    SetBackendFactory(FileBackendFactory(t.TempDir()))
+    if s, err := OpenNamespace("../x"); err == nil || s != nil { t.Fatalf("expected error for escaping namespace: %v %v", s, err) }
+    defer func() {
+        if recover() == nil { t.Fatalf("Namespace must not fall back to memory") }
+    }()
+    _ = Namespace("../x")
+}
+
+// A failed open registers nothing, so the namespace opens once the backend can.
+func TestOpenNamespace_FailureIsNotCached(t *testing.T) {
+    ResetRegistry()
+    defer ResetRegistry()
+    SetBackendFactory(func(string) (Backend, error) { return nil, errors.New("disk unavailable") })
+    if _, err := OpenNamespace("p/n"); err == nil || err.Error() != "disk unavailable" { t.Fatalf("err: %v", err) }
+    root := t.TempDir()
+    SetBackendFactory(FileBackendFactory(root))
+    s, err := OpenNamespace("p/n")
+    if err != nil { t.Fatalf("reopen: %v", err) }
+    s.Put("k", 1)
+    _ = s.Close()
+    if _, ok := reopen(t, filepath.Join(root, "p", "n")).Get("k"); !ok { t.Fatalf("write was not persisted") }
+}
@@ -0,0 +1,37 @@
+package kvstore
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestFileBackend_Compact_TruncatesLog(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.SetCompactEvery(10)
+    for i := 0; i < 25; i++ { s.Put("k", i) }
+    fi, err := os.Stat(filepath.Join(dir, logName))
+    if err != nil { t.Fatal(err) }
+    if fi.Size() == 0 || fi.Size() > 2000 { t.Fatalf("unexpected log size %d", fi.Size()) }
+    if err := s.Compact(); err != nil { t.Fatal(err) }
+    if fi, _ = os.Stat(filepath.Join(dir, logName)); fi.Size() != 0 { t.Fatalf("log not truncated: %d", fi.Size()) }
+    _ = s.Close()
+    if v, ok := reopen(t, dir).Get("k"); !ok || v.(float64) != 24 { t.Fatalf("k: %v %v", v, ok) }
+}
+
+func TestFileBackend_Load_IgnoresTornTail(t *testing.T) {
+    dir := t.TempDir()
+    b, err := OpenFile(dir)
+    if err != nil { t.Fatal(err) }
+    _ = b.Put(Record{Key: "a", Value: "x"})
+    _, _ = b.log.Write([]byte(`{"op":"put","rec":{"key":"b"`))
+    recs, err := b.Load()
+    if err != nil { t.Fatalf("load: %v", err) }
+    if len(recs) != 1 || recs[0].Key != "a" { t.Fatalf("recs: %+v", recs) }
+    _, _ = b.log.Write([]byte("\n"))
+    _ = b.Put(Record{Key: "c"})
+    if _, err := b.Load(); err == nil { t.Fatalf("expected error for corrupt interior line") }
+    _ = b.Close()
+    if err := b.Put(Record{Key: "d"}); err == nil { t.Fatalf("expected error after close") }
+}
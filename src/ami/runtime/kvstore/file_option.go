@@ -0,0 +1,8 @@
+package kvstore
+
+// FileOption configures OpenFile.
+type FileOption func(*FileBackend)
+
+// WithSync fsyncs the log after every write (durable across power loss at
+// the cost of throughput). Without it, writes survive process crashes only.
+func WithSync() FileOption { return func(b *FileBackend) { b.sync = true } }
@@ -0,0 +1,15 @@
+package kvstore
+
+// GetAndDelete returns key's live value and removes it in one step.
+func (s *Store) GetAndDelete(key string) (any, bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    e, ok := s.liveLocked(key)
+    if !ok { return nil, false }
+    s.metrics.Hits++
+    s.deleteLocked(key, e.val)
+    return e.val, true
+}
+
+// GetAndDelete runs Store.GetAndDelete on the default store.
+func GetAndDelete(key string) (any, bool) { return defaultStore.GetAndDelete(key) }
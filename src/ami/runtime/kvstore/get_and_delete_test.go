@@ -0,0 +1,11 @@
+package kvstore
+
+import "testing"
+
+func TestStore_GetAndDelete(t *testing.T) {
+    s := New()
+    s.Put("k", "v")
+    if v, ok := s.GetAndDelete("k"); !ok || v != "v" { t.Fatalf("got %v %v", v, ok) }
+    if _, ok := s.GetAndDelete("k"); ok { t.Fatalf("second take must miss") }
+    if m := s.Metrics(); m.Hits != 1 || m.Misses != 1 || m.CurrentSize != 0 { t.Fatalf("metrics: %+v", m) }
+}
@@ -0,0 +1,55 @@
+package kvstore
+
+import "math"
+
+// Incr atomically adds delta to key's integer value and returns the result,
+// stored as int64. A missing or expired key starts from zero and is created
+// with opts; an existing key keeps its TTL and read budget. Unsigned values
+// above math.MaxInt64 are ErrNotNumeric; a result outside int64 is ErrOverflow.
+func (s *Store) Incr(key string, delta int64, opts ...PutOption) (int64, error) {
+    o := applyOptions(opts)
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    e, ok := s.items[key]
+    if ok && e.isExpiredAt(s.now()) { s.expireLocked(key, e); ok = false }
+    if !ok { s.putLocked(key, delta, o); return delta, nil }
+    n, isInt := toInt64(e.val)
+    if !isInt { return 0, ErrNotNumeric }
+    if (delta > 0 && n > math.MaxInt64-delta) || (delta < 0 && n < math.MinInt64-delta) { return 0, ErrOverflow }
+    n += delta
+    e.val = n
+    s.touchLocked(key)
+    s.persistPutLocked(key, e)
+    s.notifyLocked(ChangePut, key, n)
+    return n, nil
+}
+
+// Decr is Incr with -delta.
+func (s *Store) Decr(key string, delta int64, opts ...PutOption) (int64, error) {
+    if delta == math.MinInt64 { return 0, ErrOverflow } // -delta is not representable
+    return s.Incr(key, -delta, opts...)
+}
+
+// Incr runs Store.Incr on the default store.
+func Incr(key string, delta int64, opts ...PutOption) (int64, error) { return defaultStore.Incr(key, delta, opts...) }
+
+// Decr runs Store.Decr on the default store.
+func Decr(key string, delta int64, opts ...PutOption) (int64, error) { return defaultStore.Decr(key, delta, opts...) }
+
+func toInt64(v any) (int64, bool) {
+    switch n := v.(type) {
+    case int: return int64(n), true
+    case int8: return int64(n), true
+    case int16: return int64(n), true
+    case int32: return int64(n), true
+    case int64: return n, true
+    case uint: if uint64(n) <= math.MaxInt64 { return int64(n), true }
+    case uint64: if n <= math.MaxInt64 { return int64(n), true }
+    case uint8: return int64(n), true
+    case uint16: return int64(n), true
+    case uint32: return int64(n), true
+    case float64:
+        if n == math.Trunc(n) && math.Abs(n) < 1<<53 { return int64(n), true }
+    }
+    return 0, false
+}
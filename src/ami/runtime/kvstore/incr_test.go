@@ -0,0 +1,53 @@
+package kvstore
+
+import (
+    "math"
+    "sync"
+    "testing"
+)
+
+func TestStore_Incr_ConcurrentNoLostUpdates(t *testing.T) {
+    s := New()
+    var wg sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func() { defer wg.Done(); for j := 0; j < 100; j++ { _, _ = s.Incr("n", 1) } }()
+    }
+    wg.Wait()
+    if v, _ := s.Get("n"); v.(int64) != 800 { t.Fatalf("expected 800, got %v", v) }
+    if n, _ := s.Decr("n", 300); n != 500 { t.Fatalf("decr: %d", n) }
+}
+
+func TestStore_Incr_NumericKinds(t *testing.T) {
+    s := New()
+    s.Put("i", 2)
+    s.Put("f", 2.0)
+    s.Put("x", "nope")
+    if n, err := s.Incr("i", 3); err != nil || n != 5 { t.Fatalf("int: %d %v", n, err) }
+    if n, err := s.Incr("f", 1); err != nil || n != 3 { t.Fatalf("float: %d %v", n, err) }
+    if _, err := s.Incr("x", 1); err != ErrNotNumeric { t.Fatalf("expected ErrNotNumeric, got %v", err) }
+    s.Put("frac", 1.5)
+    if _, err := s.Incr("frac", 1); err != ErrNotNumeric { t.Fatalf("expected ErrNotNumeric for fraction") }
+}
+
+func TestIncr_DefaultStore(t *testing.T) {
+    ResetDefault()
+    if n, _ := Incr("c", 2); n != 2 { t.Fatalf("incr: %d", n) }
+    if n, _ := Decr("c", 1); n != 1 { t.Fatalf("decr: %d", n) }
+}
+
+func TestStore_Incr_UnsignedAndOverflow(t *testing.T) {
+    s := New()
+    s.Put("u", uint(4))
+    s.Put("u64", uint64(7))
+    s.Put("huge", uint64(math.MaxUint64))
+    if n, err := s.Incr("u", 1); err != nil || n != 5 { t.Fatalf("uint: %d %v", n, err) }
+    if n, err := s.Incr("u64", 1); err != nil || n != 8 { t.Fatalf("uint64: %d %v", n, err) }
+    if _, err := s.Incr("huge", 1); err != ErrNotNumeric { t.Fatalf("expected ErrNotNumeric, got %v", err) }
+    s.Put("max", int64(math.MaxInt64))
+    if _, err := s.Incr("max", 1); err != ErrOverflow { t.Fatalf("expected ErrOverflow, got %v", err) }
+    if v, _ := s.Get("max"); v != int64(math.MaxInt64) { t.Fatalf("value changed on overflow: %v", v) }
+    s.Put("min", int64(math.MinInt64))
+    if _, err := s.Decr("min", 1); err != ErrOverflow { t.Fatalf("expected ErrOverflow, got %v", err) }
+    if _, err := s.Decr("min", math.MinInt64); err != ErrOverflow { t.Fatalf("expected ErrOverflow for -MinInt64, got %v", err) }
+}
@@ -6,5 +6,7 @@ type Metrics struct {
     Misses      int
     Expirations int
     Evictions   int
+    Conflicts   int // failed CompareAndSwap / PutIfAbsent
+    WatchDrops  int // changes dropped because a watcher's buffer was full
     CurrentSize int
 }
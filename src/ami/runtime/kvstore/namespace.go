@@ -5,7 +5,8 @@ import "sync"
 // Registry tracks namespaced stores by key (e.g., pipeline/node).
 type Registry struct {
     mu     sync.RWMutex
-    stores map[string]*Store
+    stores  map[string]*Store
+    factory BackendFactory
 }
 
 // default registry
@@ -1,16 +1,34 @@
 package kvstore
 
-// Namespace returns (and creates if missing) a store for the given namespace.
-func Namespace(ns string) *Store {
+// OpenNamespace returns (and creates if missing) the store for namespace ns.
+// New stores use the registry's BackendFactory when one is set. When the
+// backend cannot be opened or restored the error is returned and no store is
+// registered, so a later call retries rather than silently running in memory.
+func OpenNamespace(ns string) (*Store, error) {
     defaultRegistry.mu.RLock()
     s, ok := defaultRegistry.stores[ns]
     defaultRegistry.mu.RUnlock()
-    if ok && s != nil { return s }
+    if ok && s != nil { return s, nil }
     defaultRegistry.mu.Lock()
     defer defaultRegistry.mu.Unlock()
-    if s = defaultRegistry.stores[ns]; s != nil { return s }
+    if s = defaultRegistry.stores[ns]; s != nil { return s, nil }
     s = New()
+    if f := defaultRegistry.factory; f != nil {
+        b, err := f(ns)
+        if err != nil { return nil, err }
+        if b != nil {
+            if s, err = NewWithBackend(b); err != nil { return nil, err }
+        }
+    }
     defaultRegistry.stores[ns] = s
-    return s
+    return s, nil
 }
 
+// Namespace is OpenNamespace for callers that treat a backend failure as
+// fatal: it panics with the error instead of returning an in-memory store the
+// caller would take for a durable one.
+func Namespace(ns string) *Store {
+    s, err := OpenNamespace(ns)
+    if err != nil { panic("kvstore: namespace " + ns + ": " + err.Error()) }
+    return s
+}
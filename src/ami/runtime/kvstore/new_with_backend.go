@@ -0,0 +1,27 @@
+package kvstore
+
+// defaultCompactEvery is the number of backend writes between compactions
+// for stores opened with NewWithBackend.
+const defaultCompactEvery = 1024
+
+// NewWithBackend creates a Store backed by b, restoring its persisted entries
+// (expired ones are dropped). Every later mutation is written through to b.
+// If b cannot be loaded it is closed and the error returned.
+func NewWithBackend(b Backend) (*Store, error) {
+    recs, err := b.Load()
+    if err != nil {
+        _ = b.Close()
+        return nil, err
+    }
+    s := New()
+    s.backend = b
+    s.compactEvery = defaultCompactEvery
+    now := s.now()
+    for _, r := range recs {
+        e := r.entry()
+        if e.isExpiredAt(now) { continue }
+        s.items[r.Key] = e
+        s.touchLocked(r.Key)
+    }
+    return s, nil
+}
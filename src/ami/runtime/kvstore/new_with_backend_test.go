@@ -0,0 +1,107 @@
+package kvstore
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func reopen(t *testing.T, dir string) *Store {
+    t.Helper()
+    b, err := OpenFile(dir)
+    if err != nil { t.Fatalf("open: %v", err) }
+    s, err := NewWithBackend(b)
+    if err != nil { t.Fatalf("restore: %v", err) }
+    return s
+}
+
+func TestNewWithBackend_RestoresAfterRestart(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.Put("a", "x")
+    s.Put("b", 2, WithMaxReads(2))
+    s.Put("gone", 1)
+    s.Del("gone")
+    if _, ok := s.Get("b"); !ok { t.Fatalf("b missing") }
+    if err := s.backend.Close(); err != nil { t.Fatal(err) } // simulate crash: no compaction
+    s2 := reopen(t, dir)
+    if v, ok := s2.Get("a"); !ok || v != "x" { t.Fatalf("a: %v %v", v, ok) }
+    if s2.Has("gone") { t.Fatalf("deleted key restored") }
+    if _, ok := s2.Get("b"); !ok { t.Fatalf("b should have one read left") }
+    if _, ok := s2.Get("b"); ok { t.Fatalf("b should be deleted after its reads") }
+    if s2.Err() != nil { t.Fatalf("err: %v", s2.Err()) }
+}
+
+func TestNewWithBackend_TTLSurvivesRestart(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.Put("short", 1, WithTTL(20*time.Millisecond))
+    s.Put("slide", 1, WithTTL(time.Hour), WithSlidingTTL())
+    _ = s.Close()
+    time.Sleep(30 * time.Millisecond)
+    s2 := reopen(t, dir)
+    if s2.Has("short") { t.Fatalf("expired key restored") }
+    s2.mu.RLock()
+    e := s2.items["slide"]
+    s2.mu.RUnlock()
+    if e == nil || !e.sliding || e.ttlDur != time.Hour { t.Fatalf("sliding ttl not restored: %+v", e) }
+}
+
+func TestNewWithBackend_LRUOrderSurvivesRestart(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.Put("a", 1); s.Put("b", 2); s.Put("c", 3)
+    s.Get("a") // b is now least recently used
+    _ = s.Close()
+    s2 := reopen(t, dir)
+    s2.SetCapacity(2)
+    if s2.Has("b") { t.Fatalf("expected b evicted") }
+    if !s2.Has("a") || !s2.Has("c") { t.Fatalf("expected a and c kept") }
+    _ = s2.Close()
+    if reopen(t, dir).Has("b") { t.Fatalf("eviction not persisted") }
+}
+
+// A torn final write is dropped on reopen and cut from the log, so writes
+// made after the restart survive the next one.
+func TestNewWithBackend_ReopenAfterTornWrite(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.Put("a", 1)
+    s.Put("b", 2)
+    _, _ = s.backend.(*FileBackend).log.Write([]byte(`{"op":"put","rec":{"key":"c"`))
+    if err := s.backend.Close(); err != nil { t.Fatal(err) } // crash mid-write
+    s2 := reopen(t, dir)
+    s2.Put("d", 4)
+    s2.Put("e", 5)
+    if err := s2.backend.Close(); err != nil { t.Fatal(err) }
+    s3 := reopen(t, dir)
+    for _, k := range []string{"a", "b", "d", "e"} { if !s3.Has(k) { t.Fatalf("%s missing after second reopen", k) } }
+    if s3.Has("c") { t.Fatalf("torn record restored") }
+    if s3.Err() != nil { t.Fatalf("err: %v", s3.Err()) }
+}
+
+// A backend that fails to load is closed rather than leaked.
+func TestNewWithBackend_ClosesBackendOnLoadError(t *testing.T) {
+    dir := t.TempDir()
+    b, err := OpenFile(dir)
+    if err != nil { t.Fatal(err) }
+    _, _ = b.log.Write([]byte("{bad\n{\"op\":\"del\",\"key\":\"x\"}\n"))
+    if _, err := NewWithBackend(b); err == nil { t.Fatalf("expected load error") }
+    if err := b.Put(Record{Key: "a"}); err == nil { t.Fatalf("backend left open") }
+}
+
+// Plain reads do not append to the log; reads that consume maxReads do.
+func TestNewWithBackend_GetPersistsOnlyMetadataChanges(t *testing.T) {
+    dir := t.TempDir()
+    s := reopen(t, dir)
+    s.Put("a", 1)
+    s.Put("b", 2, WithMaxReads(3))
+    size := func() int64 { st, err := os.Stat(filepath.Join(dir, logName)); if err != nil { t.Fatal(err) }; return st.Size() }
+    before := size()
+    for i := 0; i < 5; i++ { s.Get("a") }
+    if size() != before { t.Fatalf("plain reads grew the log") }
+    s.Get("b")
+    if size() == before { t.Fatalf("maxReads change not persisted") }
+    _ = s.Close()
+}
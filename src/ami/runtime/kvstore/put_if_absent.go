@@ -0,0 +1,18 @@
+package kvstore
+
+// PutIfAbsent stores val only when key is missing or expired and reports
+// whether it did. An existing key counts as a Conflict.
+func (s *Store) PutIfAbsent(key string, val any, opts ...PutOption) bool {
+    o := applyOptions(opts)
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if e, ok := s.items[key]; ok {
+        if !e.isExpiredAt(s.now()) { s.metrics.Conflicts++; return false }
+        s.expireLocked(key, e)
+    }
+    s.putLocked(key, val, o)
+    return true
+}
+
+// PutIfAbsent runs Store.PutIfAbsent on the default store.
+func PutIfAbsent(key string, val any, opts ...PutOption) bool { return defaultStore.PutIfAbsent(key, val, opts...) }
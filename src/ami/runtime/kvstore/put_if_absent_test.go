@@ -0,0 +1,17 @@
+package kvstore
+
+import (
+    "testing"
+    "time"
+)
+
+func TestStore_PutIfAbsent(t *testing.T) {
+    s := New()
+    if !s.PutIfAbsent("k", 1, WithTTL(10*time.Millisecond)) { t.Fatalf("expected first put") }
+    if s.PutIfAbsent("k", 2) { t.Fatalf("expected conflict") }
+    time.Sleep(15 * time.Millisecond)
+    if !s.PutIfAbsent("k", 3) { t.Fatalf("expired key should be replaceable") }
+    if v, _ := s.Get("k"); v != 3 { t.Fatalf("got %v", v) }
+    ResetDefault()
+    if !PutIfAbsent("d", 1) || PutIfAbsent("d", 2) { t.Fatalf("default store put-if-absent") }
+}
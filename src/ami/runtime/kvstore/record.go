@@ -0,0 +1,23 @@
+package kvstore
+
+import "time"
+
+// Record is the persisted form of one entry as exchanged with a Backend.
+// Values are stored as JSON by the file backend, so they decode as JSON types
+// (numbers become float64, objects map[string]any) after a restart.
+type Record struct {
+    Key            string        `json:"key"`
+    Value          any           `json:"value"`
+    ExpireAt       time.Time     `json:"expireAt"`
+    RemainingReads int           `json:"remainingReads,omitempty"`
+    TTL            time.Duration `json:"ttl,omitempty"`
+    Sliding        bool          `json:"sliding,omitempty"`
+}
+
+func recordOf(key string, e *entry) Record {
+    return Record{Key: key, Value: e.val, ExpireAt: e.expireAt, RemainingReads: e.remainingReads, TTL: e.ttlDur, Sliding: e.sliding}
+}
+
+func (r Record) entry() *entry {
+    return &entry{val: r.Value, expireAt: r.ExpireAt, remainingReads: r.RemainingReads, ttlDur: r.TTL, sliding: r.Sliding}
+}
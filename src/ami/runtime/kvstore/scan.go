@@ -0,0 +1,51 @@
+package kvstore
+
+import (
+    "sort"
+    "strings"
+)
+
+// Item is a key/value pair returned by scans.
+type Item struct {
+    Key   string
+    Value any
+}
+
+// Scan returns the live entries whose keys start with prefix, sorted by key.
+// Scans do not count as reads: read budgets, sliding TTL and LRU order are
+// unchanged.
+func (s *Store) Scan(prefix string) []Item {
+    return s.collect(func(k string) bool { return strings.HasPrefix(k, prefix) })
+}
+
+// Range returns the live entries with start <= key < end, sorted by key; an
+// empty end is unbounded.
+func (s *Store) Range(start, end string) []Item {
+    return s.collect(func(k string) bool { return k >= start && (end == "" || k < end) })
+}
+
+// KeysWithPrefix returns the live keys starting with prefix, sorted.
+func (s *Store) KeysWithPrefix(prefix string) []string {
+    items := s.Scan(prefix)
+    out := make([]string, len(items))
+    for i, it := range items { out[i] = it.Key }
+    return out
+}
+
+func (s *Store) collect(match func(string) bool) []Item {
+    now := s.now()
+    s.mu.RLock()
+    out := make([]Item, 0)
+    for k, e := range s.items {
+        if match(k) && !e.isExpiredAt(now) { out = append(out, Item{Key: k, Value: e.val}) }
+    }
+    s.mu.RUnlock()
+    sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+    return out
+}
+
+// Scan runs Store.Scan on the default store.
+func Scan(prefix string) []Item { return defaultStore.Scan(prefix) }
+
+// Range runs Store.Range on the default store.
+func Range(start, end string) []Item { return defaultStore.Range(start, end) }
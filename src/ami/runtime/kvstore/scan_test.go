@@ -0,0 +1,33 @@
+package kvstore
+
+import (
+    "reflect"
+    "testing"
+    "time"
+)
+
+func TestStore_Scan_PrefixSorted(t *testing.T) {
+    s := New()
+    for _, k := range []string{"cfg/b", "cfg/a", "other", "cfg/c"} { s.Put(k, k) }
+    s.Put("cfg/old", 1, WithTTL(time.Nanosecond))
+    time.Sleep(time.Millisecond)
+    got := s.KeysWithPrefix("cfg/")
+    if !reflect.DeepEqual(got, []string{"cfg/a", "cfg/b", "cfg/c"}) { t.Fatalf("got %v", got) }
+    if items := s.Scan("cfg/a"); len(items) != 1 || items[0].Value != "cfg/a" { t.Fatalf("items %v", items) }
+}
+
+func TestStore_Range_HalfOpen(t *testing.T) {
+    s := New()
+    for _, k := range []string{"a", "b", "c", "d"} { s.Put(k, 1, WithMaxReads(1)) }
+    var keys []string
+    for _, it := range s.Range("b", "d") { keys = append(keys, it.Key) }
+    if !reflect.DeepEqual(keys, []string{"b", "c"}) { t.Fatalf("got %v", keys) }
+    if len(s.Range("c", "")) != 2 { t.Fatalf("unbounded end") }
+    if _, ok := s.Get("b"); !ok { t.Fatalf("scans must not consume read budget") }
+}
+
+func TestStore_Keys_Sorted(t *testing.T) {
+    s := New()
+    s.Put("z", 1); s.Put("a", 1); s.Put("m", 1)
+    if got := s.Keys(); !reflect.DeepEqual(got, []string{"a", "m", "z"}) { t.Fatalf("got %v", got) }
+}
@@ -0,0 +1,9 @@
+package kvstore
+
+// SetBackendFactory selects the backend used by Namespace for stores it
+// creates from now on; nil restores in-memory namespaces.
+func SetBackendFactory(f BackendFactory) {
+    defaultRegistry.mu.Lock()
+    defaultRegistry.factory = f
+    defaultRegistry.mu.Unlock()
+}
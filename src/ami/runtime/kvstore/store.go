@@ -2,12 +2,15 @@ package kvstore
 
 import (
     "container/list"
+    "sort"
     "sync"
     "time"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
 )
 
-// Store is an in-memory ephemeral key/value store with TTL and delete-on-read.
-// It is concurrency-safe and intended for per-node usage.
+// Store is a key/value store with TTL and delete-on-read. It is in-memory
+// unless created with NewWithBackend, in which case every mutation is written
+// through to the Backend. It is concurrency-safe and intended for per-node usage.
 type Store struct {
     mu      sync.RWMutex
     items   map[string]*entry
@@ -15,6 +18,14 @@ type Store struct {
     cap     int
     lru     *list.List            // most-recently used at back
     order   map[string]*list.Element
+    backend      Backend
+    compactEvery int
+    writes       int
+    err          error
+    watchers     map[*watcher]struct{}
+    clock        amitime.Clock // nil uses amitime.GetClock()
+    sweepEvery   time.Duration // 0 uses DefaultSweepInterval; < 0 disables
+    sweepStop    chan struct{} // closes the running sweeper, if any
 }
 
 // SetCapacity sets a maximum number of entries; 0 disables eviction.
@@ -26,6 +37,27 @@ func (s *Store) SetCapacity(n int) {
     s.enforceCapacityLocked()
 }
 
+// SetClock sets the clock used for TTLs and change timestamps; nil uses the
+// runtime clock (amitime.GetClock).
+func (s *Store) SetClock(c amitime.Clock) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.clock = c
+    if s.sweepStop != nil { s.restartSweeperLocked() }
+}
+
+// now reads the store's clock.
+func (s *Store) now() time.Time {
+    if s.clock != nil { return s.clock.Now() }
+    return amitime.GetClock().Now()
+}
+
+// clockLocked returns the store's clock, defaulting to the runtime clock.
+func (s *Store) clockLocked() amitime.Clock {
+    if s.clock != nil { return s.clock }
+    return amitime.GetClock()
+}
+
 func (s *Store) touchLocked(key string) {
     if el, ok := s.order[key]; ok {
         s.lru.MoveToBack(el)
@@ -48,8 +80,11 @@ func (s *Store) enforceCapacityLocked() {
         k := el.Value.(string)
         s.lru.Remove(el)
         delete(s.order, k)
+        e := s.items[k]
         delete(s.items, k)
         s.metrics.Evictions++
+        s.persistDelLocked(k)
+        if e != nil { s.notifyLocked(ChangeEvict, k, e.val) }
     }
 }
 
@@ -58,11 +93,17 @@ func (s *Store) Put(key string, val any, opts ...PutOption) {
     o := applyOptions(opts)
     s.mu.Lock()
     defer s.mu.Unlock()
+    s.putLocked(key, val, o)
+}
+
+func (s *Store) putLocked(key string, val any, o putOptions) {
     e := &entry{val: val}
-    if o.ttl > 0 { e.expireAt = time.Now().Add(o.ttl); e.ttlDur = o.ttl; e.sliding = o.sliding }
+    if o.ttl > 0 { e.expireAt = s.now().Add(o.ttl); e.ttlDur = o.ttl; e.sliding = o.sliding }
     if o.maxReads > 0 { e.remainingReads = o.maxReads }
     s.items[key] = e
     s.touchLocked(key)
+    s.persistPutLocked(key, e)
+    s.notifyLocked(ChangePut, key, val)
     s.enforceCapacityLocked()
 }
 
@@ -71,28 +112,45 @@ func (s *Store) Put(key string, val any, opts ...PutOption) {
 func (s *Store) Get(key string) (any, bool) {
     s.mu.Lock()
     defer s.mu.Unlock()
-    e, ok := s.items[key]
-    if !ok { s.metrics.Misses++; return nil, false }
-    if e.expiredLocked() { delete(s.items, key); s.metrics.Expirations++; s.metrics.Misses++; return nil, false }
+    return s.getLocked(key)
+}
+
+func (s *Store) getLocked(key string) (any, bool) {
+    e, ok := s.liveLocked(key)
+    if !ok { return nil, false }
     v := e.val
+    s.metrics.Hits++
+    changed := false
     if e.remainingReads > 0 {
         e.remainingReads--
-        if e.remainingReads == 0 { s.removeKeyLocked(key) }
+        if e.remainingReads == 0 { s.deleteLocked(key, v); return v, true }
+        changed = true
     }
     // Sliding TTL refresh
     if e.sliding && e.ttlDur > 0 {
-        e.expireAt = time.Now().Add(e.ttlDur)
+        e.expireAt = s.now().Add(e.ttlDur)
+        changed = true
     }
     s.touchLocked(key)
-    s.metrics.Hits++
+    // plain reads only reorder the LRU list, which compaction persists
+    if changed { s.persistPutLocked(key, e) }
     return v, true
 }
 
+// liveLocked returns key's entry when present and not expired, counting a
+// miss (and expiring the entry) otherwise.
+func (s *Store) liveLocked(key string) (*entry, bool) {
+    e, ok := s.items[key]
+    if !ok { s.metrics.Misses++; return nil, false }
+    if e.isExpiredAt(s.now()) { s.expireLocked(key, e); s.metrics.Misses++; return nil, false }
+    return e, true
+}
+
 // Del deletes a key if it exists and returns true when removed.
 func (s *Store) Del(key string) bool {
     s.mu.Lock()
     defer s.mu.Unlock()
-    if _, ok := s.items[key]; ok { s.removeKeyLocked(key); return true }
+    if e, ok := s.items[key]; ok { s.deleteLocked(key, e.val); return true }
     return false
 }
 
@@ -102,19 +160,25 @@ func (s *Store) Has(key string) bool {
     e, ok := s.items[key]
     s.mu.RUnlock()
     if !ok { return false }
-    if e.expired() { s.mu.Lock(); delete(s.items, key); s.mu.Unlock(); return false }
+    if e.isExpiredAt(s.now()) {
+        s.mu.Lock()
+        if cur, ok := s.items[key]; ok && cur == e { s.expireLocked(key, e) }
+        s.mu.Unlock()
+        return false
+    }
     return true
 }
 
-// Keys returns a snapshot of existing non-expired keys.
+// Keys returns a sorted snapshot of existing non-expired keys.
 func (s *Store) Keys() []string {
-    now := time.Now()
+    now := s.now()
     s.mu.RLock()
     defer s.mu.RUnlock()
     out := make([]string, 0, len(s.items))
     for k, e := range s.items {
         if !e.isExpiredAt(now) { out = append(out, k) }
     }
+    sort.Strings(out)
     return out
 }
 
@@ -0,0 +1,35 @@
+package kvstore
+
+import (
+    "testing"
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+func TestStore_SetClock_VirtualTTL(t *testing.T) {
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    clk := amitime.NewVirtualClock(start)
+    s := New()
+    s.SetClock(clk)
+    s.Put("a", 1, WithTTL(time.Minute))
+    s.Put("b", 2, WithTTL(time.Minute), WithSlidingTTL())
+    clk.Advance(59 * time.Second)
+    if !s.Has("a") { t.Fatalf("a expired early") }
+    if _, ok := s.Get("b"); !ok { t.Fatalf("b expired early") } // slides b to start+1m59s
+    clk.Advance(time.Second)
+    if s.Has("a") { t.Fatalf("a should expire at exactly one minute") }
+    if !s.Has("b") { t.Fatalf("sliding TTL should have been refreshed") }
+    clk.Advance(59 * time.Second)
+    if n := s.Sweep(); n != 1 { t.Fatalf("sweep removed %d", n) }
+}
+
+func TestStore_DefaultsToRuntimeClock(t *testing.T) {
+    clk := amitime.NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    prev := amitime.SetClock(clk)
+    defer amitime.SetClock(prev)
+    s := New()
+    s.Put("k", "v", WithTTL(time.Second))
+    clk.Advance(time.Second)
+    if s.Has("k") { t.Fatalf("expected TTL to follow the installed runtime clock") }
+}
@@ -0,0 +1,20 @@
+package kvstore
+
+// Close compacts and closes the backend, if any. The store remains usable
+// in memory afterwards but no longer persists.
+func (s *Store) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.backend == nil { return nil }
+    err := s.compactLocked()
+    if cerr := s.backend.Close(); err == nil { err = cerr }
+    s.backend = nil
+    return err
+}
+
+// Err returns the first backend error seen by the store, if any.
+func (s *Store) Err() error {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    return s.err
+}
@@ -0,0 +1,18 @@
+package kvstore
+
+// Compact rewrites the backend's persisted state from the live entries.
+// It is a no-op for in-memory stores.
+func (s *Store) Compact() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.compactLocked()
+}
+
+// SetCompactEvery sets how many backend writes trigger an automatic
+// compaction; 0 disables automatic compaction.
+func (s *Store) SetCompactEvery(n int) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if n < 0 { n = 0 }
+    s.compactEvery = n
+}
@@ -0,0 +1,38 @@
+package kvstore
+
+// persistPutLocked writes key's current entry through to the backend.
+func (s *Store) persistPutLocked(key string, e *entry) {
+    if s.backend == nil { return }
+    s.noteErrLocked(s.backend.Put(recordOf(key, e)))
+}
+
+// persistDelLocked removes key from the backend.
+func (s *Store) persistDelLocked(key string) {
+    if s.backend == nil { return }
+    s.noteErrLocked(s.backend.Delete(key))
+}
+
+func (s *Store) noteErrLocked(err error) {
+    if err != nil && s.err == nil { s.err = err }
+    s.writes++
+    if s.compactEvery > 0 && s.writes >= s.compactEvery && s.writes > len(s.items) { s.compactLocked() }
+}
+
+// liveRecordsLocked returns the non-expired entries in LRU order.
+func (s *Store) liveRecordsLocked() []Record {
+    now := s.now()
+    out := make([]Record, 0, len(s.items))
+    for el := s.lru.Front(); el != nil; el = el.Next() {
+        k := el.Value.(string)
+        if e, ok := s.items[k]; ok && !e.isExpiredAt(now) { out = append(out, recordOf(k, e)) }
+    }
+    return out
+}
+
+func (s *Store) compactLocked() error {
+    s.writes = 0
+    if s.backend == nil { return nil }
+    err := s.backend.Compact(s.liveRecordsLocked())
+    if err != nil && s.err == nil { s.err = err }
+    return err
+}
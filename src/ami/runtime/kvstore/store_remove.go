@@ -0,0 +1,16 @@
+package kvstore
+
+// deleteLocked removes key as an explicit or read-budget deletion.
+func (s *Store) deleteLocked(key string, val any) {
+    s.removeKeyLocked(key)
+    s.persistDelLocked(key)
+    s.notifyLocked(ChangeDelete, key, val)
+}
+
+// expireLocked removes key because its TTL elapsed.
+func (s *Store) expireLocked(key string, e *entry) {
+    s.removeKeyLocked(key)
+    s.persistDelLocked(key)
+    s.metrics.Expirations++
+    s.notifyLocked(ChangeExpire, key, e.val)
+}
@@ -0,0 +1,69 @@
+package kvstore
+
+import (
+    "sort"
+    "time"
+)
+
+// DefaultSweepInterval is how often a watched store sweeps expired entries.
+const DefaultSweepInterval = time.Second
+
+// Sweep removes every expired entry now (emitting expire changes in key
+// order) instead of waiting for the next access, and returns how many were
+// removed.
+func (s *Store) Sweep() int {
+    now := s.now()
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    var keys []string
+    for k, e := range s.items {
+        if e.isExpiredAt(now) { keys = append(keys, k) }
+    }
+    sort.Strings(keys)
+    for _, k := range keys { s.expireLocked(k, s.items[k]) }
+    return len(keys)
+}
+
+// SetSweepInterval sets how often the store sweeps expired entries while it
+// has watchers, so expire changes arrive without an access; d <= 0 disables
+// the background sweep. The default is DefaultSweepInterval.
+func (s *Store) SetSweepInterval(d time.Duration) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if d <= 0 { d = -1 }
+    s.sweepEvery = d
+    s.restartSweeperLocked()
+}
+
+// sweepIntervalLocked returns the effective sweep interval (<= 0 when disabled).
+func (s *Store) sweepIntervalLocked() time.Duration {
+    if s.sweepEvery == 0 { return DefaultSweepInterval }
+    return s.sweepEvery
+}
+
+// restartSweeperLocked stops any running sweeper and starts a new one on the
+// current clock and interval when the store has watchers.
+func (s *Store) restartSweeperLocked() {
+    s.stopSweeperLocked()
+    if len(s.watchers) == 0 { return }
+    d := s.sweepIntervalLocked()
+    if d <= 0 { return }
+    clk := s.clockLocked()
+    tick := clk.NewTicker(d)
+    stop := make(chan struct{})
+    s.sweepStop = stop
+    go func() {
+        defer tick.Stop()
+        for {
+            select {
+            case <-tick.C(): s.Sweep()
+            case <-stop: return
+            }
+        }
+    }()
+}
+
+// stopSweeperLocked stops the background sweeper, if running.
+func (s *Store) stopSweeperLocked() {
+    if s.sweepStop != nil { close(s.sweepStop); s.sweepStop = nil }
+}
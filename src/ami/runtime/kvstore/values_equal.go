@@ -0,0 +1,33 @@
+package kvstore
+
+import "reflect"
+
+// valuesEqual reports whether a and b hold the same value once numbers are
+// normalized: whole numbers compare as int64 and other floats as float64, in
+// nested maps and slices too. Values restored by the file backend decode as
+// JSON types (see Record), so 5 and 5.0 must match across a restart.
+func valuesEqual(a, b any) bool { return reflect.DeepEqual(normalizeValue(a), normalizeValue(b)) }
+
+func normalizeValue(v any) any {
+    if v == nil { return nil }
+    if n, ok := toInt64(v); ok { return n }
+    switch n := v.(type) {
+    case float32: return float64(n)
+    case float64: return n
+    case string, bool: return v
+    }
+    rv := reflect.ValueOf(v)
+    switch rv.Kind() {
+    case reflect.Map:
+        if rv.Type().Key().Kind() != reflect.String { return v }
+        m := make(map[string]any, rv.Len())
+        it := rv.MapRange()
+        for it.Next() { m[it.Key().String()] = normalizeValue(it.Value().Interface()) }
+        return m
+    case reflect.Slice, reflect.Array:
+        out := make([]any, rv.Len())
+        for i := range out { out[i] = normalizeValue(rv.Index(i).Interface()) }
+        return out
+    }
+    return v
+}
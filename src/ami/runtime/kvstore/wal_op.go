@@ -0,0 +1,14 @@
+package kvstore
+
+// walOp is one line of the file backend's append-only log (kv.log.v1).
+type walOp struct {
+    Op  string  `json:"op"` // put | del
+    Key string  `json:"key,omitempty"`
+    Rec *Record `json:"rec,omitempty"`
+}
+
+// snapshotFile is the file backend's compacted state (kv.snapshot.v1).
+type snapshotFile struct {
+    Schema  string   `json:"schema"`
+    Records []Record `json:"records"`
+}
@@ -0,0 +1,49 @@
+package kvstore
+
+import "strings"
+
+// watcher is one Watch subscription.
+type watcher struct {
+    prefix string
+    ch     chan Change
+}
+
+// Watch streams changes to keys with the given prefix ("" watches all keys)
+// over a channel buffered to buf (minimum 1). Delivery never blocks the
+// store: when the buffer is full the change is dropped and counted in
+// Metrics.WatchDrops. While any watch is open the store sweeps expired
+// entries every SetSweepInterval on its clock, so expire changes arrive
+// without an access. The returned cancel func stops the watch and closes
+// the channel.
+func (s *Store) Watch(prefix string, buf int) (<-chan Change, func()) {
+    if buf < 1 { buf = 1 }
+    w := &watcher{prefix: prefix, ch: make(chan Change, buf)}
+    s.mu.Lock()
+    if s.watchers == nil { s.watchers = map[*watcher]struct{}{} }
+    s.watchers[w] = struct{}{}
+    if s.sweepStop == nil { s.restartSweeperLocked() }
+    s.mu.Unlock()
+    cancel := func() {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+        if _, ok := s.watchers[w]; ok { delete(s.watchers, w); close(w.ch) }
+        if len(s.watchers) == 0 { s.stopSweeperLocked() }
+    }
+    return w.ch, cancel
+}
+
+// Watch runs Store.Watch on the default store.
+func Watch(prefix string, buf int) (<-chan Change, func()) { return defaultStore.Watch(prefix, buf) }
+
+func (s *Store) notifyLocked(kind ChangeKind, key string, val any) {
+    if len(s.watchers) == 0 { return }
+    c := Change{Kind: kind, Key: key, Value: val, Time: s.now()}
+    for w := range s.watchers {
+        if !strings.HasPrefix(key, w.prefix) { continue }
+        select {
+        case w.ch <- c:
+        default:
+            s.metrics.WatchDrops++
+        }
+    }
+}
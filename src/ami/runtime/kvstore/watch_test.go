@@ -0,0 +1,100 @@
+package kvstore
+
+import (
+    "testing"
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+func drain(ch <-chan Change) []Change {
+    var out []Change
+    for {
+        select {
+        case c := <-ch: out = append(out, c)
+        default: return out
+        }
+    }
+}
+
+func TestStore_Watch_StreamsPrefixChanges(t *testing.T) {
+    s := New()
+    s.SetCapacity(3)
+    ch, cancel := s.Watch("cfg/", 16)
+    defer cancel()
+    s.Put("cfg/a", 1)
+    s.Put("other", 1)
+    s.Put("cfg/b", 2, WithTTL(time.Nanosecond))
+    time.Sleep(time.Millisecond)
+    s.Sweep()
+    s.Put("cfg/c", 3)
+    s.Put("x", 1) // evicts cfg/a
+    s.Del("cfg/c")
+    var kinds []string
+    for _, c := range drain(ch) { kinds = append(kinds, string(c.Kind)+":"+c.Key) }
+    want := []string{"put:cfg/a", "put:cfg/b", "expire:cfg/b", "put:cfg/c", "evict:cfg/a", "delete:cfg/c"}
+    if len(kinds) != len(want) { t.Fatalf("got %v want %v", kinds, want) }
+    for i := range want { if kinds[i] != want[i] { t.Fatalf("got %v want %v", kinds, want) } }
+}
+
+func TestStore_Watch_DropsWhenFullAndCancelCloses(t *testing.T) {
+    s := New()
+    ch, cancel := s.Watch("", 1)
+    s.Put("a", 1)
+    s.Put("b", 2)
+    if s.Metrics().WatchDrops != 1 { t.Fatalf("expected one drop") }
+    cancel()
+    cancel()
+    <-ch
+    if _, ok := <-ch; ok { t.Fatalf("expected closed channel") }
+    s.Put("c", 3)
+}
+
+func TestNamespace_Watch_SeesOtherWriter(t *testing.T) {
+    ResetRegistry()
+    ch, cancel := Namespace("p/n").Watch("", 4)
+    defer cancel()
+    go Namespace("p/n").Put("k", "v")
+    select {
+    case c := <-ch:
+        if c.Kind != ChangePut || c.Key != "k" || c.Value != "v" { t.Fatalf("change %+v", c) }
+    case <-time.After(time.Second):
+        t.Fatalf("timeout")
+    }
+}
+
+func TestStore_Watch_SweepsExpiredOnClock(t *testing.T) {
+    clk := amitime.NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    s := New()
+    s.SetClock(clk)
+    s.SetSweepInterval(time.Second)
+    s.Put("b", 1, WithTTL(500*time.Millisecond))
+    s.Put("a", 2, WithTTL(500*time.Millisecond))
+    ch, cancel := s.Watch("", 8)
+    defer cancel()
+    clk.Advance(time.Second)
+    var keys []string
+    for len(keys) < 2 {
+        select {
+        case c := <-ch:
+            if c.Kind != ChangeExpire { t.Fatalf("unexpected change %+v", c) }
+            keys = append(keys, c.Key)
+        case <-time.After(2 * time.Second):
+            t.Fatalf("no expire change without access; got %v", keys)
+        }
+    }
+    if keys[0] != "a" || keys[1] != "b" { t.Fatalf("expire order: %v", keys) }
+}
+
+func TestStore_Watch_CancelStopsSweeper(t *testing.T) {
+    clk := amitime.NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    s := New()
+    s.SetClock(clk)
+    _, cancel := s.Watch("", 1)
+    if n := clk.Waiters(); n != 1 { t.Fatalf("sweeper tickers: %d", n) }
+    cancel()
+    for i := 0; clk.Waiters() != 0; i++ {
+        if i > 1000 { t.Fatalf("sweeper still armed after cancel") }
+        time.Sleep(time.Millisecond)
+    }
+}
@@ -0,0 +1,84 @@
+package merge
+
+import "time"
+
+// AggState is the running state of one Aggregate within one window; it is
+// exported so window state can be carried in snapshots.
+type AggState struct {
+    Count  int64     `json:"count"`
+    Sum    float64   `json:"sum,omitempty"`
+    Nums   int64     `json:"nums,omitempty"`
+    Min    any       `json:"min,omitempty"`
+    Max    any       `json:"max,omitempty"`
+    First  any       `json:"first,omitempty"`
+    FirstT time.Time `json:"firstT"`
+    Last   any       `json:"last,omitempty"`
+    LastT  time.Time `json:"lastT"`
+    Seen   bool      `json:"seen,omitempty"`
+}
+
+// add folds value v (present reports whether the field existed) observed at t.
+func (s *AggState) add(v any, present bool, t time.Time) {
+    if !present { return }
+    s.Count++
+    if f, ok := toFloat(v); ok { s.Sum += f; s.Nums++ }
+    if !s.Seen || valueLess(v, s.Min) { s.Min = v }
+    if !s.Seen || valueLess(s.Max, v) { s.Max = v }
+    if !s.Seen || t.Before(s.FirstT) { s.First, s.FirstT = v, t }
+    if !s.Seen || !t.Before(s.LastT) { s.Last, s.LastT = v, t }
+    s.Seen = true
+}
+
+// merge folds o into s (session windows merging).
+func (s *AggState) merge(o AggState) {
+    if !o.Seen { return }
+    if !s.Seen { *s = o; return }
+    s.Count += o.Count
+    s.Sum += o.Sum
+    s.Nums += o.Nums
+    if valueLess(o.Min, s.Min) { s.Min = o.Min }
+    if valueLess(s.Max, o.Max) { s.Max = o.Max }
+    if o.FirstT.Before(s.FirstT) { s.First, s.FirstT = o.First, o.FirstT }
+    if !o.LastT.Before(s.LastT) { s.Last, s.LastT = o.Last, o.LastT }
+}
+
+// result returns the aggregate value for fn.
+func (s *AggState) result(fn string) any {
+    switch fn {
+    case "count":
+        return s.Count
+    case "sum":
+        return s.Sum
+    case "avg":
+        if s.Nums == 0 { return nil }
+        return s.Sum / float64(s.Nums)
+    case "min":
+        return s.Min
+    case "max":
+        return s.Max
+    case "first":
+        return s.First
+    case "last":
+        return s.Last
+    }
+    return nil
+}
+
+func toFloat(v any) (float64, bool) {
+    switch n := v.(type) {
+    case int: return float64(n), true
+    case int32: return float64(n), true
+    case int64: return float64(n), true
+    case float32: return float64(n), true
+    case float64: return n, true
+    }
+    return 0, false
+}
+
+// valueLess orders numbers numerically across kinds and other values via cmp.
+func valueLess(a, b any) bool {
+    if af, ok := toFloat(a); ok {
+        if bf, ok := toFloat(b); ok { return af < bf }
+    }
+    return cmp(a, b) < 0
+}
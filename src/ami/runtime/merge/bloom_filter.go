@@ -0,0 +1,57 @@
+package merge
+
+import (
+    "hash/fnv"
+    "math"
+)
+
+const (
+    defaultBloomCapacity = 100000
+    defaultBloomFPRate   = 0.01
+)
+
+// bloomFilter is a fixed-size Bloom filter using double hashing over FNV-1a.
+type bloomFilter struct {
+    bits []uint64
+    m    uint64 // number of bits
+    k    uint64 // number of hash functions
+    n    int    // keys added
+}
+
+// newBloomFilter sizes a filter for n keys at false-positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+    if n <= 0 { n = defaultBloomCapacity }
+    if p <= 0 || p >= 1 { p = defaultBloomFPRate }
+    m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+    if m < 64 { m = 64 }
+    k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+    if k < 1 { k = 1 }
+    return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+func bloomHashes(key string) (uint64, uint64) {
+    h := fnv.New64a()
+    _, _ = h.Write([]byte(key))
+    h1 := h.Sum64()
+    h2 := (h1 >> 33) | (h1 << 31)
+    h2 ^= 0x9e3779b97f4a7c15
+    return h1, h2 | 1
+}
+
+func (b *bloomFilter) has(key string) bool {
+    h1, h2 := bloomHashes(key)
+    for i := uint64(0); i < b.k; i++ {
+        bit := (h1 + i*h2) % b.m
+        if b.bits[bit/64]&(1<<(bit%64)) == 0 { return false }
+    }
+    return true
+}
+
+func (b *bloomFilter) add(key string) {
+    h1, h2 := bloomHashes(key)
+    for i := uint64(0); i < b.k; i++ {
+        bit := (h1 + i*h2) % b.m
+        b.bits[bit/64] |= 1 << (bit % 64)
+    }
+    b.n++
+}
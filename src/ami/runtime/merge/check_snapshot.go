@@ -0,0 +1,33 @@
+package merge
+
+import (
+    "context"
+    "errors"
+    "os"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// CheckSnapshot reports whether the snapshot at path, if any, can be restored
+// under plan, so callers can fail before starting RunPlanWithCheckpoint.
+func CheckSnapshot(plan Plan, path string) error { return restoreSnapshot(NewOperator(plan), path) }
+
+// restoreSnapshot restores op from path; a missing file is not an error.
+func restoreSnapshot(op *Operator, path string) error {
+    s, err := ReadSnapshot(path)
+    if errors.Is(err, os.ErrNotExist) { return nil }
+    if err != nil { return err }
+    return op.Restore(s)
+}
+
+// drainInput discards in until it closes or ctx is done.
+func drainInput(ctx context.Context, in <-chan ev.Event) {
+    for {
+        select {
+        case _, ok := <-in:
+            if !ok { return }
+        case <-ctx.Done():
+            return
+        }
+    }
+}
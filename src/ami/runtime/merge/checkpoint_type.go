@@ -0,0 +1,10 @@
+package merge
+
+import "time"
+
+// Checkpoint configures periodic operator snapshots for RunPlanWithCheckpoint.
+type Checkpoint struct {
+    Path     string        // snapshot file (merge.snapshot.v1)
+    Interval time.Duration // 0 = only at exit
+    OnError  func(error)   // optional: called when a periodic write fails
+}
@@ -0,0 +1,90 @@
+package merge
+
+import "time"
+
+// dedupSet remembers the dedup keys of one partition within the horizon
+// configured by Dedup. Exact mode keeps keys in first-seen order so TTL and
+// MaxKeys evict from the front; bloom mode keeps a current and a previous
+// filter and rotates them.
+type dedupSet struct {
+    cfg   Dedup
+    keys  map[string]time.Time // exact: key -> first seen
+    order []dedupEntry         // exact: first-seen order, oldest first
+    cur   *bloomFilter         // bloom: active generation (allocated lazily)
+    prev  *bloomFilter         // bloom: retired generation
+    since time.Time            // bloom: when cur was started
+}
+
+type dedupEntry struct {
+    key string
+    at  time.Time
+}
+
+func newDedupSet(cfg Dedup) *dedupSet {
+    return &dedupSet{cfg: cfg, keys: map[string]time.Time{}}
+}
+
+func (d *dedupSet) bloom() bool { return d.cfg.Mode == "bloom" }
+
+// seenOrAdd reports whether key is a duplicate at now; unseen keys are recorded.
+func (d *dedupSet) seenOrAdd(key string, now time.Time) bool {
+    if d.bloom() { return d.bloomSeenOrAdd(key, now) }
+    d.expire(now)
+    if _, ok := d.keys[key]; ok { return true }
+    d.keys[key] = now
+    d.order = append(d.order, dedupEntry{key: key, at: now})
+    if d.cfg.MaxKeys > 0 {
+        for len(d.keys) > d.cfg.MaxKeys && len(d.order) > 0 { d.popFront() }
+    }
+    return false
+}
+
+// expire drops keys first seen at or before now-TTL.
+func (d *dedupSet) expire(now time.Time) {
+    if d.cfg.TTLMs <= 0 { return }
+    cutoff := now.Add(-time.Duration(d.cfg.TTLMs) * time.Millisecond)
+    for len(d.order) > 0 && !d.order[0].at.After(cutoff) { d.popFront() }
+}
+
+func (d *dedupSet) popFront() {
+    e := d.order[0]
+    d.order = d.order[1:]
+    if at, ok := d.keys[e.key]; ok && at.Equal(e.at) { delete(d.keys, e.key) }
+}
+
+func (d *dedupSet) bloomSeenOrAdd(key string, now time.Time) bool {
+    if d.cur == nil { d.cur = newBloomFilter(d.cfg.MaxKeys, d.cfg.FPRate); d.since = now }
+    full := d.cur.n >= d.capacity()
+    aged := d.cfg.TTLMs > 0 && now.Sub(d.since) >= time.Duration(d.cfg.TTLMs)*time.Millisecond
+    if full || aged {
+        d.prev = d.cur
+        d.cur = newBloomFilter(d.cfg.MaxKeys, d.cfg.FPRate)
+        d.since = now
+    }
+    if d.cur.has(key) || (d.prev != nil && d.prev.has(key)) { return true }
+    d.cur.add(key)
+    return false
+}
+
+func (d *dedupSet) capacity() int {
+    if d.cfg.MaxKeys > 0 { return d.cfg.MaxKeys }
+    return defaultBloomCapacity
+}
+
+// len returns the number of remembered keys (bloom: keys added to both generations).
+func (d *dedupSet) len() int {
+    if d.bloom() {
+        n := 0
+        if d.cur != nil { n += d.cur.n }
+        if d.prev != nil { n += d.prev.n }
+        return n
+    }
+    return len(d.keys)
+}
+
+// reset forgets all keys.
+func (d *dedupSet) reset() {
+    d.keys = map[string]time.Time{}
+    d.order = nil
+    d.cur, d.prev = nil, nil
+}
@@ -0,0 +1,45 @@
+package merge
+
+// snapshot records d into ps: live exact keys with their first-seen times,
+// or the bloom filter generations.
+func (d *dedupSet) snapshot(ps *PartitionSnapshot) {
+    if d.bloom() {
+        if d.cur == nil { return }
+        bs := &BloomSnapshot{Cur: append([]uint64(nil), d.cur.bits...), CurN: d.cur.n, Since: d.since}
+        if d.prev != nil { bs.Prev = append([]uint64(nil), d.prev.bits...); bs.PrevN = d.prev.n }
+        ps.Bloom = bs
+        return
+    }
+    for _, e := range d.order {
+        if at, ok := d.keys[e.key]; !ok || !at.Equal(e.at) { continue }
+        ps.Seen = append(ps.Seen, e.key)
+        ps.SeenAt = append(ps.SeenAt, e.at)
+    }
+}
+
+// restore loads the dedup state of ps into d. Keys from snapshots without
+// SeenAt are treated as first seen at the partition's last activity.
+func (d *dedupSet) restore(ps PartitionSnapshot) {
+    d.reset()
+    if d.bloom() {
+        bs := ps.Bloom
+        if bs == nil { return }
+        load := func(bits []uint64, n int) *bloomFilter {
+            b := newBloomFilter(d.cfg.MaxKeys, d.cfg.FPRate)
+            if len(bits) != len(b.bits) { return nil }
+            copy(b.bits, bits)
+            b.n = n
+            return b
+        }
+        d.cur, d.since = load(bs.Cur, bs.CurN), bs.Since
+        if bs.Prev != nil { d.prev = load(bs.Prev, bs.PrevN) }
+        return
+    }
+    for i, k := range ps.Seen {
+        at := ps.Last
+        if i < len(ps.SeenAt) { at = ps.SeenAt[i] }
+        if _, ok := d.keys[k]; ok { continue }
+        d.keys[k] = at
+        d.order = append(d.order, dedupEntry{key: k, at: at})
+    }
+}
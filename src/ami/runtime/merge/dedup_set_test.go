@@ -0,0 +1,73 @@
+package merge
+
+import (
+    "encoding/json"
+    "strconv"
+    "testing"
+    "time"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestDedupSet_Exact_TTLForgetsOldKeys(t *testing.T) {
+    d := newDedupSet(Dedup{TTLMs: 100})
+    t0 := time.Unix(0, 0)
+    if d.seenOrAdd("a", t0) { t.Fatalf("first a is not a duplicate") }
+    if !d.seenOrAdd("a", t0.Add(50*time.Millisecond)) { t.Fatalf("a within ttl must be a duplicate") }
+    if d.seenOrAdd("a", t0.Add(100*time.Millisecond)) { t.Fatalf("a after ttl must be new") }
+    if d.len() != 1 { t.Fatalf("len=%d", d.len()) }
+}
+
+func TestDedupSet_Exact_MaxKeysEvictsOldest(t *testing.T) {
+    d := newDedupSet(Dedup{MaxKeys: 2})
+    now := time.Unix(0, 0)
+    for _, k := range []string{"a", "b", "c"} { d.seenOrAdd(k, now) }
+    if d.len() != 2 { t.Fatalf("len=%d", d.len()) }
+    if !d.seenOrAdd("c", now) || !d.seenOrAdd("b", now) { t.Fatalf("recent keys must be remembered") }
+    if d.seenOrAdd("a", now) { t.Fatalf("oldest key must have been evicted") }
+}
+
+func TestDedupSet_Bloom_BoundedAndRotates(t *testing.T) {
+    d := newDedupSet(Dedup{Mode: "bloom", MaxKeys: 1000, FPRate: 0.01})
+    now := time.Unix(0, 0)
+    for i := 0; i < 1000; i++ { d.seenOrAdd("k"+strconv.Itoa(i), now) }
+    words := len(d.cur.bits)
+    dups := 0
+    for i := 0; i < 1000; i++ { if d.seenOrAdd("k"+strconv.Itoa(i), now) { dups++ } }
+    if dups != 1000 { t.Fatalf("bloom must not forget keys within capacity: %d", dups) }
+    fp := 0
+    for i := 0; i < 1000; i++ { if d.seenOrAdd("x"+strconv.Itoa(i), now) { fp++ } }
+    if fp > 50 { t.Fatalf("false positives too high: %d/1000", fp) }
+    for i := 0; i < 3000; i++ { d.seenOrAdd("y"+strconv.Itoa(i), now) }
+    if len(d.cur.bits) != words || d.prev == nil || len(d.prev.bits) != words { t.Fatalf("filters must stay fixed-size across rotations") }
+    if d.seenOrAdd("k1", now) && d.seenOrAdd("k2", now) && d.seenOrAdd("k3", now) { t.Fatalf("keys from retired generations should be forgotten") }
+}
+
+func TestDedupSet_Bloom_TTLRotates(t *testing.T) {
+    d := newDedupSet(Dedup{Mode: "bloom", MaxKeys: 100, TTLMs: 10})
+    t0 := time.Unix(0, 0)
+    d.seenOrAdd("a", t0)
+    if !d.seenOrAdd("a", t0.Add(15*time.Millisecond)) { t.Fatalf("a should survive one rotation") }
+    d.seenOrAdd("b", t0.Add(30*time.Millisecond))
+    if d.seenOrAdd("a", t0.Add(30*time.Millisecond)) { t.Fatalf("a should be forgotten after two rotations") }
+}
+
+func TestOperator_DedupHorizon_SnapshotRoundTrip(t *testing.T) {
+    for _, mode := range []string{"exact", "bloom"} {
+        p := Plan{}
+        p.Dedup = Dedup{Field: "id", TTLMs: 60000, MaxKeys: 100, Mode: mode}
+        op := NewOperator(p)
+        for _, id := range []string{"1", "2"} { _ = op.Push(ev.Event{Payload: map[string]any{"id": id}}) }
+        b, err := json.Marshal(op.Snapshot())
+        if err != nil { t.Fatal(err) }
+        var s Snapshot
+        if err := json.Unmarshal(b, &s); err != nil { t.Fatal(err) }
+        op2 := NewOperator(p)
+        if err := op2.Restore(s); err != nil { t.Fatalf("%s: restore: %v", mode, err) }
+        _ = op2.Push(ev.Event{Payload: map[string]any{"id": "1"}})
+        _ = op2.Push(ev.Event{Payload: map[string]any{"id": "3"}})
+        n := 0
+        for { if _, ok := op2.Pop(); !ok { break }; n++ }
+        if n != 3 { t.Fatalf("%s: want 2 restored + 1 new event, got %d", mode, n) }
+    }
+}
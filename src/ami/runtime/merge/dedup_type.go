@@ -0,0 +1,19 @@
+package merge
+
+// Dedup configures duplicate suppression keyed on Field (or Plan.Key when
+// Field is empty). By default each partition remembers every key it has seen.
+// TTLMs forgets keys first seen more than TTLMs ago and MaxKeys evicts the
+// oldest keys beyond that count, bounding memory on long-running streams.
+//
+// Mode "bloom" replaces the exact set with two rotating Bloom filters, each
+// sized for MaxKeys (default 100000) keys at FPRate (default 0.01) false
+// positives. The active filter is retired once it holds MaxKeys keys or is
+// TTLMs old, so keys are remembered for at least one and at most two
+// generations. A false positive suppresses a unique event.
+type Dedup struct {
+    Field   string
+    TTLMs   int     // 0 disables time-based expiry
+    MaxKeys int     // 0 is unbounded (exact) or the default capacity (bloom)
+    Mode    string  // exact (default)|bloom
+    FPRate  float64 // bloom false-positive rate; 0 uses the default
+}
@@ -0,0 +1,21 @@
+package merge
+
+import "time"
+
+// eventTime reads an event time value: time.Time, RFC3339 (with optional
+// fractional seconds) strings, or numbers as Unix milliseconds.
+func eventTime(v any) (time.Time, bool) {
+    switch x := v.(type) {
+    case string:
+        if t, err := time.Parse(time.RFC3339Nano, x); err == nil { return t, true }
+        return toTime(x)
+    case int:
+        return time.UnixMilli(int64(x)).UTC(), true
+    case int64:
+        return time.UnixMilli(x).UTC(), true
+    case float64:
+        return time.UnixMilli(int64(x)).UTC(), true
+    default:
+        return toTime(v)
+    }
+}
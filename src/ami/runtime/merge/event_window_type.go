@@ -0,0 +1,28 @@
+package merge
+
+// EventWindow configures event-time windowing keyed off Watermark.Field.
+// Kind is tumbling (SizeMs), sliding (SizeMs every SlideMs) or session
+// (closed after GapMs without events). Windows close when the watermark —
+// the maximum observed event time minus Watermark.LatenessMs — passes their end.
+type EventWindow struct {
+    Kind    string // tumbling|sliding|session
+    SizeMs  int
+    SlideMs int
+    GapMs   int
+}
+
+// Aggregate computes one output field per window: Fn over Field, written as
+// As (default fn or fn_field). Fn is count|sum|min|max|avg|first|last; count
+// without Field counts events.
+type Aggregate struct {
+    Fn    string
+    Field string
+    As    string
+}
+
+// name returns the output field for a.
+func (a Aggregate) name() string {
+    if a.As != "" { return a.As }
+    if a.Field == "" { return a.Fn }
+    return a.Fn + "_" + a.Field
+}
@@ -1,6 +1,6 @@
 package merge
 
 func NewOperator(p Plan) *Operator {
-    return &Operator{plan:p, parts: map[string]*partition{}, rr: make([]string, 0)}
+    return &Operator{plan:p, parts: map[string]*partition{}, rr: make([]string, 0), windows: map[string][]*window{}}
 }
 
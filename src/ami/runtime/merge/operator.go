@@ -20,6 +20,7 @@ type Operator struct{
     emitted  int64
     dropped  int64
     expired  int64
+    windows  map[string][]*window // open event-time windows per partition
 }
 
 var ErrBackpressure = errors.New("merge buffer full")
@@ -41,14 +42,14 @@ func (op *Operator) dedupKey(e ev.Event) (string, bool) {
 func (op *Operator) Push(e ev.Event) error {
     pk := op.partitionKey(e)
     part := op.parts[pk]
-    if part == nil { part = &partition{buf: make([]item,0), seen: map[string]struct{}{}, last: time.Now()}; op.parts[pk]=part; op.rr = append(op.rr, pk) }
+    if part == nil { part = &partition{buf: make([]item,0), seen: newDedupSet(op.plan.Dedup), last: op.now()}; op.parts[pk]=part; op.rr = append(op.rr, pk) }
     // watermark late-arrival handling per LatePolicy
-    if op.plan.Watermark != nil && op.plan.Watermark.Field != "" {
+    if op.plan.EventWindow == nil && op.plan.Watermark != nil && op.plan.Watermark.Field != "" {
         if v, ok := extractPath(e.Payload, op.plan.Watermark.Field); ok {
             if t, ok2 := toTime(v); ok2 {
                 // Any event older than now - lateness is dropped
                 if op.plan.Watermark.LatenessMs > 0 {
-                    if t.Before(time.Now().Add(-time.Duration(op.plan.Watermark.LatenessMs) * time.Millisecond)) {
+                    if t.Before(op.now().Add(-time.Duration(op.plan.Watermark.LatenessMs) * time.Millisecond)) {
                         if op.plan.LatePolicy == "accept" { /* accept late into next windows */ } else { op.dropped++; return nil }
                     }
                 }
@@ -57,9 +58,9 @@ func (op *Operator) Push(e ev.Event) error {
     }
     // dedup
     if dk, ok := op.dedupKey(e); ok {
-        if _, seen := part.seen[dk]; seen { return nil }
-        part.seen[dk] = struct{}{}
+        if part.seen.seenOrAdd(dk, op.now()) { return nil }
     }
+    if op.plan.EventWindow != nil { return op.pushWindowed(pk, e) }
     // backpressure/window
     cap := op.plan.Buffer.Capacity
     if op.plan.Window > 0 && (cap == 0 || op.plan.Window < cap) { cap = op.plan.Window }
@@ -83,24 +84,26 @@ func (op *Operator) Push(e ev.Event) error {
             return nil
         }
     }
-    // extract sort keys
+    op.seq++
+    part.buf = append(part.buf, op.newItem(e, op.seq))
+    op.enqueued++
+    // maintain ordering on insert
+    sort.SliceStable(part.buf, func(i, j int) bool { return less(part.buf[i], part.buf[j], op.plan) })
+    part.last = op.now()
+    return nil
+}
+
+// newItem wraps e with its sort keys and tiebreak key extracted per plan.
+func (op *Operator) newItem(e ev.Event, seq int64) item {
     keys := make([]any, len(op.plan.Sort))
     for i, k := range op.plan.Sort {
         if v, ok := extractPath(e.Payload, k.Field); ok { keys[i] = v } else { keys[i] = nil }
     }
-    // extract tiebreak key when present
     var key any
     if op.plan.Key != "" {
         if v, ok := extractPath(e.Payload, op.plan.Key); ok { key = v }
     }
-    op.seq++
-    it := item{ev: e, keys: keys, seq: op.seq, key: key}
-    part.buf = append(part.buf, it)
-    op.enqueued++
-    // maintain ordering on insert
-    sort.SliceStable(part.buf, func(i, j int) bool { return less(part.buf[i], part.buf[j], op.plan) })
-    part.last = time.Now()
-    return nil
+    return item{ev: e, keys: keys, seq: seq, key: key}
 }
 
 // Pop returns the next event from a non-empty partition, using round-robin for fairness across partitions.
@@ -135,7 +138,7 @@ func (op *Operator) ExpireStale(now time.Time) int {
             for i := range part.buf { zeroizePayload(part.buf[i].ev.Payload) }
             part.buf = part.buf[:0]
             // reset seen map only if dedup is enabled
-            if op.plan.Dedup.Field != "" || op.plan.Key != "" { part.seen = map[string]struct{}{} }
+            if op.plan.Dedup.Field != "" || op.plan.Key != "" { part.seen.reset() }
             part.last = now
         }
     }
@@ -0,0 +1,15 @@
+package merge
+
+import (
+    "time"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+// clock returns the plan's clock, falling back to the runtime clock.
+func (op *Operator) clock() amitime.Clock {
+    if op.plan.Clock != nil { return op.plan.Clock }
+    return amitime.GetClock()
+}
+
+// now reads the operator's clock.
+func (op *Operator) now() time.Time { return op.clock().Now() }
@@ -0,0 +1,33 @@
+package merge
+
+import (
+    "testing"
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestOperator_VirtualClock_TimeoutIsDeterministic(t *testing.T) {
+    clk := amitime.NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    p := Plan{Clock: clk}
+    p.TimeoutMs = 1000
+    op := NewOperator(p)
+    _ = op.Push(ev.Event{Payload: map[string]any{"x": 1}})
+    clk.Advance(999 * time.Millisecond)
+    if n := op.ExpireStale(clk.Now()); n != 0 { t.Fatalf("expired early: %d", n) }
+    clk.Advance(2 * time.Millisecond)
+    if n := op.ExpireStale(clk.Now()); n != 1 { t.Fatalf("expected 1 expired, got %d", n) }
+}
+
+func TestOperator_VirtualClock_LatenessUsesClock(t *testing.T) {
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    clk := amitime.NewVirtualClock(start)
+    p := Plan{Clock: clk, Watermark: &Watermark{Field: "ts", LatenessMs: 1000}, LatePolicy: "drop"}
+    op := NewOperator(p)
+    _ = op.Push(ev.Event{Payload: map[string]any{"ts": start.Add(-500 * time.Millisecond).Format(time.RFC3339Nano)}})
+    clk.Advance(time.Second)
+    _ = op.Push(ev.Event{Payload: map[string]any{"ts": start.Add(-500 * time.Millisecond).Format(time.RFC3339Nano)}})
+    enq, _, drop, _ := op.Stats()
+    if enq != 1 || drop != 1 { t.Fatalf("enqueued=%d dropped=%d", enq, drop) }
+}
@@ -0,0 +1,163 @@
+package merge
+
+import (
+    "sort"
+    "time"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// watermark is the event-time watermark of partition pk: the maximum event
+// time observed in that partition minus the allowed lateness. It is zero
+// until the partition has seen an event time, so a quiet partition's
+// windows are not closed by another partition's progress.
+func (op *Operator) watermark(pk string) time.Time {
+    var max time.Time
+    if part := op.parts[pk]; part != nil { max = part.maxEventTime }
+    if max.IsZero() || op.plan.Watermark == nil { return max }
+    return max.Add(-time.Duration(op.plan.Watermark.LatenessMs) * time.Millisecond)
+}
+
+// pushWindowed folds e into the open event-time windows of partition pk.
+// Events without a readable event time, or whose windows have all closed,
+// are dropped.
+func (op *Operator) pushWindowed(pk string, e ev.Event) error {
+    var t time.Time
+    ok := false
+    if op.plan.Watermark != nil {
+        if v, found := extractPath(e.Payload, op.plan.Watermark.Field); found { t, ok = eventTime(v) }
+    }
+    if !ok { op.dropped++; return nil }
+    part := op.parts[pk]
+    wm := op.watermark(pk)
+    w := op.plan.EventWindow
+    var targets []*window
+    if w.Kind == "session" {
+        targets = op.sessionFor(pk, t, wm)
+    } else {
+        size := time.Duration(w.SizeMs) * time.Millisecond
+        slide := size
+        if w.Kind == "sliding" && w.SlideMs > 0 { slide = time.Duration(w.SlideMs) * time.Millisecond }
+        if size <= 0 { op.dropped++; return nil }
+        for start := alignEpoch(t, slide); start.After(t.Add(-size)); start = start.Add(-slide) {
+            end := start.Add(size)
+            if !wm.IsZero() && !end.After(wm) { continue }
+            targets = append(targets, op.windowAt(pk, start, end))
+        }
+    }
+    if len(targets) == 0 { op.dropped++; return nil }
+    for _, win := range targets {
+        for i, a := range op.plan.Aggregates {
+            v, present := extractPath(e.Payload, a.Field)
+            win.aggs[i].add(v, present, t)
+        }
+        if len(op.plan.Aggregates) == 0 { win.aggs[0].add(e.Payload, true, t) }
+    }
+    if t.After(part.maxEventTime) { part.maxEventTime = t }
+    op.enqueued++
+    return nil
+}
+
+// alignEpoch rounds t down to a multiple of d since the Unix epoch.
+func alignEpoch(t time.Time, d time.Duration) time.Time {
+    ns := t.UnixNano()
+    off := ns % int64(d)
+    if off < 0 { off += int64(d) }
+    return time.Unix(0, ns-off).UTC()
+}
+
+func (op *Operator) newWindow(start, end time.Time) *window {
+    n := len(op.plan.Aggregates)
+    if n == 0 { n = 1 }
+    return &window{start: start, end: end, aggs: make([]AggState, n)}
+}
+
+// windowAt returns (creating) the fixed window [start,end) of partition pk.
+func (op *Operator) windowAt(pk string, start, end time.Time) *window {
+    for _, w := range op.windows[pk] {
+        if w.start.Equal(start) { return w }
+    }
+    w := op.newWindow(start, end)
+    op.windows[pk] = append(op.windows[pk], w)
+    return w
+}
+
+// sessionFor returns the session of partition pk that an event at t joins,
+// merging sessions that the event bridges. It returns nil when the event
+// would only form a session that has already closed.
+func (op *Operator) sessionFor(pk string, t, wm time.Time) []*window {
+    gap := time.Duration(op.plan.EventWindow.GapMs) * time.Millisecond
+    if gap <= 0 { return nil }
+    joined := op.newWindow(t, t.Add(gap))
+    var keep []*window
+    merged := false
+    for _, s := range op.windows[pk] {
+        if s.start.Before(t.Add(gap)) && t.Before(s.end) {
+            if s.start.Before(joined.start) { joined.start = s.start }
+            if s.end.After(joined.end) { joined.end = s.end }
+            for i := range joined.aggs { joined.aggs[i].merge(s.aggs[i]) }
+            merged = true
+            continue
+        }
+        keep = append(keep, s)
+    }
+    if !merged && !wm.IsZero() && !joined.end.After(wm) { return nil }
+    op.windows[pk] = append(keep, joined)
+    return []*window{joined}
+}
+
+// CloseWindows emits and removes every window whose end is at or before its
+// partition's watermark, ordered by end, start and partition.
+func (op *Operator) CloseWindows() []ev.Event {
+    if op.plan.EventWindow == nil { return nil }
+    return op.emitWindows(func(pk string, w *window) bool {
+        wm := op.watermark(pk)
+        return !wm.IsZero() && !w.end.After(wm)
+    })
+}
+
+// FlushWindows emits and removes all open windows (end of input).
+func (op *Operator) FlushWindows() []ev.Event {
+    if op.plan.EventWindow == nil { return nil }
+    return op.emitWindows(func(string, *window) bool { return true })
+}
+
+func (op *Operator) emitWindows(ready func(pk string, w *window) bool) []ev.Event {
+    type closed struct{ pk string; rank int; w *window }
+    var done []closed
+    for rank, pk := range op.rr {
+        var keep []*window
+        for _, w := range op.windows[pk] {
+            if ready(pk, w) { done = append(done, closed{pk, rank, w}) } else { keep = append(keep, w) }
+        }
+        op.windows[pk] = keep
+    }
+    sort.SliceStable(done, func(i, j int) bool {
+        a, b := done[i], done[j]
+        if !a.w.end.Equal(b.w.end) { return a.w.end.Before(b.w.end) }
+        if !a.w.start.Equal(b.w.start) { return a.w.start.Before(b.w.start) }
+        return a.rank < b.rank
+    })
+    out := make([]ev.Event, 0, len(done))
+    for _, c := range done {
+        out = append(out, op.windowEvent(c.pk, c.w))
+        op.emitted++
+    }
+    return out
+}
+
+// windowTimeFormat renders window bounds with fixed millisecond precision.
+const windowTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// windowEvent renders one closed window as an event whose payload carries
+// the window bounds, the partition (when partitioned) and the aggregates.
+func (op *Operator) windowEvent(pk string, w *window) ev.Event {
+    p := map[string]any{"window": map[string]any{
+        "start": w.start.UTC().Format(windowTimeFormat),
+        "end":   w.end.UTC().Format(windowTimeFormat),
+    }}
+    if op.plan.PartitionBy != "" { p["partition"] = pk }
+    for i, a := range op.plan.Aggregates { p[a.name()] = w.aggs[i].result(a.Fn) }
+    if len(op.plan.Aggregates) == 0 { p["count"] = w.aggs[0].Count }
+    return ev.Event{Timestamp: w.end, Payload: p}
+}
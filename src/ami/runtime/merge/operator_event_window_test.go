@@ -0,0 +1,107 @@
+package merge
+
+import (
+    "testing"
+    "time"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func windowPlan(kind string, size, slide, gap, lateness int, aggs ...Aggregate) Plan {
+    p := Plan{PartitionBy: "k"}
+    p.Watermark = &Watermark{Field: "ts", LatenessMs: lateness}
+    p.EventWindow = &EventWindow{Kind: kind, SizeMs: size, SlideMs: slide, GapMs: gap}
+    p.Aggregates = aggs
+    return p
+}
+
+func at(ms int, k string, v float64) ev.Event {
+    return ev.Event{Payload: map[string]any{"ts": ms, "k": k, "v": v}}
+}
+
+func windowStarts(evs []ev.Event) []string {
+    var out []string
+    for _, e := range evs {
+        w := e.Payload.(map[string]any)["window"].(map[string]any)
+        out = append(out, w["start"].(string)[17:23]+"-"+w["end"].(string)[17:23])
+    }
+    return out
+}
+
+func TestOperator_TumblingWindows_CloseOnWatermarkWithAggregates(t *testing.T) {
+    op := NewOperator(windowPlan("tumbling", 1000, 0, 0, 0,
+        Aggregate{Fn: "count"}, Aggregate{Fn: "sum", Field: "v"}, Aggregate{Fn: "avg", Field: "v", As: "mean"},
+        Aggregate{Fn: "min", Field: "v"}, Aggregate{Fn: "max", Field: "v"}, Aggregate{Fn: "first", Field: "v"}, Aggregate{Fn: "last", Field: "v"}))
+    for _, e := range []ev.Event{at(100, "a", 3), at(900, "a", 1), at(500, "b", 7), at(200, "a", 2)} { _ = op.Push(e) }
+    if got := op.CloseWindows(); len(got) != 0 { t.Fatalf("nothing should close yet: %v", got) }
+    _ = op.Push(at(1000, "a", 5)) // a's watermark reaches 1000: only a's [0,1000) closes
+    got := op.CloseWindows()
+    if len(got) != 1 { t.Fatalf("expected 1 window, got %d", len(got)) }
+    a := got[0].Payload.(map[string]any)
+    if a["partition"] != "a" || a["count"] != int64(3) || a["sum_v"] != 6.0 || a["mean"] != 2.0 { t.Fatalf("a: %v", a) }
+    if a["min_v"] != 1.0 || a["max_v"] != 3.0 || a["first_v"] != 3.0 || a["last_v"] != 1.0 { t.Fatalf("a order aggs: %v", a) }
+    if !got[0].Timestamp.Equal(time.UnixMilli(1000)) { t.Fatalf("timestamp: %v", got[0].Timestamp) }
+    _ = op.Push(at(600, "b", 1)) // b's own watermark is still 500: not late
+    _ = op.Push(at(1200, "b", 4))
+    got = op.CloseWindows()
+    if len(got) != 1 || got[0].Payload.(map[string]any)["partition"] != "b" || got[0].Payload.(map[string]any)["count"] != int64(2) { t.Fatalf("b: %v", got) }
+    _ = op.Push(at(10, "a", 9)) // late: its window already closed
+    if _, _, drop, _ := op.Stats(); drop != 1 { t.Fatalf("expected late drop, got %d", drop) }
+    rest := op.FlushWindows()
+    if len(rest) != 2 || rest[0].Payload.(map[string]any)["count"] != int64(1) { t.Fatalf("flush: %v", rest) }
+}
+
+func TestOperator_SlidingWindows_AssignToOverlaps(t *testing.T) {
+    op := NewOperator(windowPlan("sliding", 1000, 500, 0, 0))
+    _ = op.Push(at(700, "a", 0))
+    got := windowStarts(op.FlushWindows())
+    if len(got) != 2 || got[0] != "00.000-01.000" || got[1] != "00.500-01.500" { t.Fatalf("windows: %v", got) }
+}
+
+func TestOperator_SessionWindows_MergeAndClose(t *testing.T) {
+    op := NewOperator(windowPlan("session", 0, 0, 100, 0, Aggregate{Fn: "count"}))
+    _ = op.Push(at(0, "a", 0))
+    _ = op.Push(at(150, "a", 0))
+    _ = op.Push(at(80, "a", 0)) // bridges [0,100) and [150,250)
+    if n := len(op.windows["a"]); n != 1 { t.Fatalf("expected merged session, got %d", n) }
+    _ = op.Push(at(400, "a", 0))
+    got := op.CloseWindows()
+    if len(got) != 1 || got[0].Payload.(map[string]any)["count"] != int64(3) { t.Fatalf("closed: %v", got) }
+    if s := windowStarts(got); s[0] != "00.000-00.250" { t.Fatalf("bounds: %v", s) }
+}
+
+func TestOperator_EventWindow_LatenessDelaysClose(t *testing.T) {
+    op := NewOperator(windowPlan("tumbling", 1000, 0, 0, 500, Aggregate{Fn: "count"}))
+    _ = op.Push(at(900, "a", 0))
+    _ = op.Push(at(1200, "a", 0))
+    if len(op.CloseWindows()) != 0 { t.Fatalf("lateness should hold the window open") }
+    _ = op.Push(at(950, "a", 0)) // late but allowed
+    _ = op.Push(at(1500, "a", 0))
+    got := op.CloseWindows()
+    if len(got) != 1 || got[0].Payload.(map[string]any)["count"] != int64(2) { t.Fatalf("closed: %v", got) }
+}
+
+func TestOperator_EventWindow_SnapshotKeepsOpenWindows(t *testing.T) {
+    p := windowPlan("tumbling", 1000, 0, 0, 0, Aggregate{Fn: "sum", Field: "v"})
+    op := NewOperator(p)
+    _ = op.Push(at(100, "a", 2))
+    op2 := NewOperator(p)
+    if err := op2.Restore(op.Snapshot()); err != nil { t.Fatal(err) }
+    _ = op2.Push(at(200, "a", 3))
+    _ = op2.Push(at(1000, "a", 0))
+    got := op2.CloseWindows()
+    if len(got) != 1 || got[0].Payload.(map[string]any)["sum_v"] != 5.0 { t.Fatalf("restored window: %v", got) }
+}
+
+func TestOperator_EventWindow_SnapshotKeepsPartitionWatermarks(t *testing.T) {
+    p := windowPlan("tumbling", 1000, 0, 0, 0, Aggregate{Fn: "count"})
+    op := NewOperator(p)
+    _ = op.Push(at(1500, "a", 0))
+    _ = op.Push(at(100, "b", 0))
+    op2 := NewOperator(p)
+    if err := op2.Restore(op.Snapshot()); err != nil { t.Fatal(err) }
+    _ = op2.Push(at(200, "b", 0)) // on time for b even though a is past 1000
+    _ = op2.Push(at(300, "a", 0)) // late for a
+    if got := op2.CloseWindows(); len(got) != 0 { t.Fatalf("nothing should close: %v", got) }
+    if _, _, drop, _ := op2.Stats(); drop != 1 { t.Fatalf("drops: %d", drop) }
+}
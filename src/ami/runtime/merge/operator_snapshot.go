@@ -0,0 +1,64 @@
+package merge
+
+import (
+    "fmt"
+    "sort"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// Snapshot captures the operator's current state. Seen keys are listed in
+// first-seen order so identical states produce identical snapshots.
+func (op *Operator) Snapshot() Snapshot {
+    s := Snapshot{Schema: SnapshotSchema, Seq: op.seq, RRIdx: op.rrIdx}
+    s.Stats = Stats{Enqueued: op.enqueued, Emitted: op.emitted, Dropped: op.dropped, Expired: op.expired}
+    for _, pk := range op.rr {
+        part := op.parts[pk]
+        if part == nil { continue }
+        ps := PartitionSnapshot{Key: pk, Last: part.last, MaxEventTime: part.maxEventTime}
+        if part.maxEventTime.After(s.MaxEventTime) { s.MaxEventTime = part.maxEventTime }
+        for _, it := range part.buf {
+            e := it.ev
+            ps.Items = append(ps.Items, ItemSnapshot{Seq: it.seq, ID: e.ID, Timestamp: e.Timestamp, Attempt: e.Attempt, Trace: e.Trace, Payload: e.Payload})
+        }
+        part.seen.snapshot(&ps)
+        s.Partitions = append(s.Partitions, ps)
+        for _, w := range op.windows[pk] {
+            s.Windows = append(s.Windows, WindowSnapshot{Partition: pk, Start: w.start, End: w.end, Aggs: append([]AggState(nil), w.aggs...)})
+        }
+    }
+    return s
+}
+
+// Restore replaces the operator's state with s. Items are re-keyed and
+// re-sorted using the operator's plan, so a snapshot may be restored under a
+// plan with different sort keys.
+func (op *Operator) Restore(s Snapshot) error {
+    if s.Schema != SnapshotSchema { return fmt.Errorf("merge: unsupported snapshot schema %q", s.Schema) }
+    op.parts = map[string]*partition{}
+    op.rr = op.rr[:0]
+    for _, ps := range s.Partitions {
+        part := &partition{buf: make([]item, 0, len(ps.Items)), seen: newDedupSet(op.plan.Dedup), last: ps.Last, maxEventTime: ps.MaxEventTime}
+        // snapshots predating per-partition watermarks only carry the operator-wide maximum
+        if part.maxEventTime.IsZero() { part.maxEventTime = s.MaxEventTime }
+        for _, is := range ps.Items {
+            e := ev.Event{ID: is.ID, Timestamp: is.Timestamp, Attempt: is.Attempt, Trace: is.Trace, Payload: is.Payload}
+            part.buf = append(part.buf, op.newItem(e, is.Seq))
+        }
+        sort.SliceStable(part.buf, func(i, j int) bool { return less(part.buf[i], part.buf[j], op.plan) })
+        part.seen.restore(ps)
+        op.parts[ps.Key] = part
+        op.rr = append(op.rr, ps.Key)
+    }
+    op.windows = map[string][]*window{}
+    for _, ws := range s.Windows {
+        w := op.newWindow(ws.Start, ws.End)
+        copy(w.aggs, ws.Aggs)
+        op.windows[ws.Partition] = append(op.windows[ws.Partition], w)
+    }
+    op.seq = s.Seq
+    op.rrIdx = 0
+    if len(op.rr) > 0 && s.RRIdx > 0 { op.rrIdx = s.RRIdx % len(op.rr) }
+    op.enqueued, op.emitted, op.dropped, op.expired = s.Stats.Enqueued, s.Stats.Emitted, s.Stats.Dropped, s.Stats.Expired
+    return nil
+}
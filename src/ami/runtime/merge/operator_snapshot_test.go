@@ -0,0 +1,33 @@
+package merge
+
+import (
+    "encoding/json"
+    "testing"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestOperator_SnapshotRestore_RoundTripsThroughJSON(t *testing.T) {
+    p := Plan{PartitionBy: "p"}
+    p.Sort = []SortKey{{Field: "n", Order: "asc"}}
+    p.Dedup.Field = "id"
+    op := NewOperator(p)
+    for _, x := range []struct{ p, id string; n int }{{"a", "1", 3}, {"a", "2", 1}, {"b", "3", 2}} {
+        _ = op.Push(ev.Event{ID: x.id, Attempt: 2, Payload: map[string]any{"p": x.p, "id": x.id, "n": x.n}})
+    }
+    b, err := json.Marshal(op.Snapshot())
+    if err != nil { t.Fatal(err) }
+    var s Snapshot
+    if err := json.Unmarshal(b, &s); err != nil { t.Fatal(err) }
+    op2 := NewOperator(p)
+    if err := op2.Restore(s); err != nil { t.Fatalf("restore: %v", err) }
+    if enq, _, _, _ := op2.Stats(); enq != 3 { t.Fatalf("stats not restored: %d", enq) }
+    _ = op2.Push(ev.Event{Payload: map[string]any{"p": "a", "id": "1", "n": 0}}) // duplicate
+    var ids []string
+    for { e, ok := op2.Pop(); if !ok { break }; ids = append(ids, e.ID); if e.Attempt != 2 { t.Fatalf("attempt lost") } }
+    if len(ids) != 3 || ids[0] != "2" || ids[1] != "3" || ids[2] != "1" { t.Fatalf("order after restore: %v", ids) }
+}
+
+func TestOperator_Restore_RejectsUnknownSchema(t *testing.T) {
+    if err := NewOperator(Plan{}).Restore(Snapshot{Schema: "merge.snapshot.v0"}); err == nil { t.Fatalf("expected schema error") }
+}
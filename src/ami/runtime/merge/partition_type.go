@@ -4,7 +4,8 @@ import "time"
 
 type partition struct{
     buf []item
-    seen map[string]struct{}
+    seen *dedupSet
     last time.Time
+    maxEventTime time.Time // highest event time seen (event-time windows)
 }
 
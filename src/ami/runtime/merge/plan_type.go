@@ -1,5 +1,7 @@
 package merge
 
+import amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+
 // Plan is a normalized configuration for a merge operator.
 type Plan struct {
     Buffer struct{
@@ -10,10 +12,13 @@ type Plan struct {
     Sort []SortKey
     Key string
     PartitionBy string
-    Dedup struct{ Field string }
+    Dedup Dedup
     Watermark *Watermark // nil means disabled
     TimeoutMs int // 0 disabled
     Window int // 0 disabled
     LatePolicy string // drop|accept
+    EventWindow *EventWindow // nil means count/processing-time buffering only
+    Aggregates []Aggregate   // per-window outputs when EventWindow is set
+    Clock amitime.Clock      // time source for timeouts, watermarks and dedup TTLs; nil uses amitime.GetClock()
 }
 
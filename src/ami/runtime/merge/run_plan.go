@@ -10,24 +10,29 @@ import (
 func RunPlan(ctx context.Context, plan Plan, in <-chan ev.Event, out chan<- ev.Event) {
     if plan.Watermark != nil && plan.Watermark.LatenessMs > 0 && plan.LatePolicy == "" { plan.LatePolicy = "accept" }
     op := NewOperator(plan)
-    tick := time.NewTicker(10 * time.Millisecond)
+    tick := op.clock().NewTicker(10 * time.Millisecond)
     defer tick.Stop()
     for {
         if flushed := op.FlushWindowExcess(); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
-        if flushed := op.FlushByWatermark(time.Now()); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
+        if flushed := op.FlushByWatermark(op.now()); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
         select {
         case <-ctx.Done():
             for { if e, ok := op.Pop(); ok { out <- e } else { break } }
+            for _, we := range op.FlushWindows() { out <- we }
             return
         case e, ok := <-in:
             if !ok {
                 for { if x, ok := op.Pop(); ok { out <- x } else { break } }
+                for _, we := range op.FlushWindows() { out <- we }
                 return
             }
             if err := op.Push(e); err == ErrBackpressure { if x, ok := op.Pop(); ok { out <- x }; _ = op.Push(e) }
+            for _, we := range op.CloseWindows() { out <- we }
             continue
-        case now := <-tick.C:
-            _ = op.ExpireStale(now)
+        case <-tick.C():
+            // read the clock rather than the tick: a virtual clock advanced in
+            // one jump delivers only its first (stale) tick
+            _ = op.ExpireStale(op.now())
             if flushed := op.FlushWindowExcess(); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
             continue
         default:
@@ -0,0 +1,66 @@
+package merge
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func runCheckpointed(t *testing.T, path string, ids ...string) ([]string, Stats) {
+    t.Helper()
+    p := Plan{}
+    p.Dedup.Field = "id"
+    in := make(chan ev.Event, len(ids))
+    for _, id := range ids { in <- ev.Event{Payload: map[string]any{"id": id}} }
+    close(in)
+    out := make(chan ev.Event, len(ids))
+    var st Stats
+    if err := RunPlanWithCheckpoint(context.Background(), p, in, out, &st, Checkpoint{Path: path}); err != nil { t.Fatal(err) }
+    close(out)
+    var got []string
+    for e := range out { got = append(got, e.Payload.(map[string]any)["id"].(string)) }
+    return got, st
+}
+
+func TestRunPlanWithCheckpoint_ResumesDedupAndStats(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "cp", "collect-0.json")
+    if got, _ := runCheckpointed(t, path, "a", "b"); len(got) != 2 { t.Fatalf("first run: %v", got) }
+    got, st := runCheckpointed(t, path, "b", "c")
+    if len(got) != 1 || got[0] != "c" { t.Fatalf("dedup state not resumed: %v", got) }
+    if st.Enqueued != 3 { t.Fatalf("expected cumulative enqueued=3, got %d", st.Enqueued) }
+}
+
+func TestRunPlanWithCheckpoint_CancelWritesSnapshotAndIntervalSaves(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "c.json")
+    ctx, cancel := context.WithCancel(context.Background())
+    in := make(chan ev.Event, 1)
+    in <- ev.Event{Payload: map[string]any{"id": "x"}}
+    out := make(chan ev.Event, 4)
+    done := make(chan struct{})
+    go func(){ _ = RunPlanWithCheckpoint(ctx, Plan{}, in, out, nil, Checkpoint{Path: path, Interval: 5 * time.Millisecond}); close(done) }()
+    time.Sleep(30 * time.Millisecond)
+    if s, err := ReadSnapshot(path); err != nil || s.Stats.Enqueued != 1 { t.Fatalf("periodic snapshot: %+v %v", s, err) }
+    cancel()
+    <-done
+    if s, err := ReadSnapshot(path); err != nil || s.Schema != SnapshotSchema { t.Fatalf("final snapshot: %+v %v", s, err) }
+}
+
+func TestRunPlanWithCheckpoint_CorruptSnapshotFails(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "c.json")
+    if err := WriteSnapshot(path, Snapshot{Schema: "other"}); err != nil { t.Fatal(err) }
+    if err := CheckSnapshot(Plan{}, path); err == nil { t.Fatalf("CheckSnapshot: expected error") }
+    in := make(chan ev.Event)
+    if err := RunPlanWithCheckpoint(context.Background(), Plan{}, in, make(chan ev.Event), nil, Checkpoint{Path: path}); err == nil {
+        t.Fatalf("expected restore error")
+    }
+    // the failed stage keeps draining its input so blocking senders proceed
+    select {
+    case in <- ev.Event{}:
+    case <-time.After(time.Second):
+        t.Fatalf("input not drained after restore failure")
+    }
+    close(in)
+}
@@ -8,32 +8,72 @@ import (
 
 // RunPlanWithStats is like RunPlan but updates stats at exit.
 func RunPlanWithStats(ctx context.Context, plan Plan, in <-chan ev.Event, out chan<- ev.Event, stats *Stats) {
+    _ = runPlan(ctx, plan, in, out, stats, nil)
+}
+
+// RunPlanWithCheckpoint is RunPlanWithStats with durable state: it resumes
+// from cp.Path when a snapshot exists, writes a snapshot every cp.Interval,
+// and on exit. When ctx is cancelled buffered items are kept in the final
+// snapshot instead of being flushed, so they are emitted after resume rather
+// than twice; when in closes the buffer is flushed first. Stats are
+// cumulative across resumes. It returns an error only when restore fails
+// (see CheckSnapshot); in is then drained in the background, so blocking
+// upstream senders never hang on a failed stage.
+func RunPlanWithCheckpoint(ctx context.Context, plan Plan, in <-chan ev.Event, out chan<- ev.Event, stats *Stats, cp Checkpoint) error {
+    return runPlan(ctx, plan, in, out, stats, &cp)
+}
+
+func runPlan(ctx context.Context, plan Plan, in <-chan ev.Event, out chan<- ev.Event, stats *Stats, cp *Checkpoint) error {
     if plan.Watermark != nil && plan.Watermark.LatenessMs > 0 && plan.LatePolicy == "" { plan.LatePolicy = "accept" }
     op := NewOperator(plan)
-    tick := time.NewTicker(10 * time.Millisecond)
+    var save <-chan time.Time
+    if cp != nil {
+        if err := restoreSnapshot(op, cp.Path); err != nil {
+            go drainInput(ctx, in)
+            return err
+        }
+        if cp.Interval > 0 { t := op.clock().NewTicker(cp.Interval); defer t.Stop(); save = t.C() }
+    }
+    checkpoint := func() {
+        if cp == nil { return }
+        if err := WriteSnapshot(cp.Path, op.Snapshot()); err != nil && cp.OnError != nil { cp.OnError(err) }
+    }
+    setStats := func() {
+        if stats != nil { enq, emit, drop, exp := op.Stats(); stats.Enqueued, stats.Emitted, stats.Dropped, stats.Expired = enq, emit, drop, exp }
+    }
+    tick := op.clock().NewTicker(10 * time.Millisecond)
     defer tick.Stop()
     for {
         if flushed := op.FlushWindowExcess(); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
-        if flushed := op.FlushByWatermark(time.Now()); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
+        if flushed := op.FlushByWatermark(op.now()); len(flushed) > 0 { for _, fe := range flushed { out <- fe } }
         select {
         case <-ctx.Done():
-            if stats != nil { enq, emit, drop, exp := op.Stats(); stats.Enqueued, stats.Emitted, stats.Dropped, stats.Expired = enq, emit, drop, exp }
+            setStats()
+            if cp != nil { checkpoint(); return nil }
             for { if x, ok := op.Pop(); ok { out <- x } else { break } }
-            return
+            for _, we := range op.FlushWindows() { out <- we }
+            return nil
         case e, ok := <-in:
             if !ok {
-                if stats != nil { enq, emit, drop, exp := op.Stats(); stats.Enqueued, stats.Emitted, stats.Dropped, stats.Expired = enq, emit, drop, exp }
+                setStats()
                 for { if x, ok := op.Pop(); ok { out <- x } else { break } }
-                return
+                for _, we := range op.FlushWindows() { out <- we }
+                checkpoint()
+                return nil
             }
             if err := op.Push(e); err == ErrBackpressure { if x, ok := op.Pop(); ok { out <- x }; _ = op.Push(e) }
+            for _, we := range op.CloseWindows() { out <- we }
             continue
-        case now := <-tick.C:
-            _ = op.ExpireStale(now)
+        case <-tick.C():
+            // read the clock rather than the tick: a virtual clock advanced in
+            // one jump delivers only its first (stale) tick
+            _ = op.ExpireStale(op.now())
+            continue
+        case <-save:
+            checkpoint()
             continue
         default:
             if e, ok := op.Pop(); ok { out <- e } else { time.Sleep(1 * time.Millisecond) }
         }
     }
 }
-
@@ -0,0 +1,32 @@
+package merge
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+)
+
+// WriteSnapshot stores s at path atomically (temp file, fsync, rename),
+// creating parent directories as needed.
+func WriteSnapshot(path string, s Snapshot) error {
+    b, err := json.Marshal(s)
+    if err != nil { return err }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { return err }
+    tmp := path + ".tmp"
+    f, err := os.Create(tmp)
+    if err != nil { return err }
+    if _, err := f.Write(b); err != nil { _ = f.Close(); return err }
+    if err := f.Sync(); err != nil { _ = f.Close(); return err }
+    if err := f.Close(); err != nil { return err }
+    return os.Rename(tmp, path)
+}
+
+// ReadSnapshot loads a snapshot written by WriteSnapshot. A missing file
+// returns an error satisfying errors.Is(err, os.ErrNotExist).
+func ReadSnapshot(path string) (Snapshot, error) {
+    var s Snapshot
+    b, err := os.ReadFile(path)
+    if err != nil { return s, err }
+    err = json.Unmarshal(b, &s)
+    return s, err
+}
@@ -0,0 +1,65 @@
+package merge
+
+import "time"
+
+// SnapshotSchema versions the on-disk operator snapshot format.
+const SnapshotSchema = "merge.snapshot.v1"
+
+// Snapshot is the serializable state of an Operator: partitions (in
+// round-robin order) with their buffered items and dedup sets, the sequence
+// counter, the round-robin cursor, stats counters and, for event-time
+// windows, the highest event time seen (per partition, with the overall
+// maximum kept for older readers) and the open windows. Sort and tiebreak keys
+// are not stored; Restore re-extracts them from payloads using the plan.
+type Snapshot struct {
+    Schema     string              `json:"schema"`
+    Seq        int64               `json:"seq"`
+    RRIdx      int                 `json:"rrIdx"`
+    Stats      Stats               `json:"stats"`
+    Partitions []PartitionSnapshot `json:"partitions"`
+    MaxEventTime time.Time         `json:"maxEventTime"`
+    Windows    []WindowSnapshot    `json:"windows,omitempty"`
+}
+
+// WindowSnapshot is one open event-time window.
+type WindowSnapshot struct {
+    Partition string     `json:"partition"`
+    Start     time.Time  `json:"start"`
+    End       time.Time  `json:"end"`
+    Aggs      []AggState `json:"aggs"`
+}
+
+// PartitionSnapshot is the saved state of one partition. Seen lists exact
+// dedup keys oldest first with their first-seen times in SeenAt; Bloom holds
+// the filters of a probabilistic dedup set.
+type PartitionSnapshot struct {
+    Key    string         `json:"key"`
+    Items  []ItemSnapshot `json:"items,omitempty"`
+    Seen   []string       `json:"seen,omitempty"`
+    SeenAt []time.Time    `json:"seenAt,omitempty"`
+    Bloom  *BloomSnapshot `json:"bloom,omitempty"`
+    Last   time.Time      `json:"last"`
+    MaxEventTime time.Time `json:"maxEventTime"`
+}
+
+// BloomSnapshot is the current and previous filter generation of a bloom
+// dedup set. Filter sizes are re-derived from the plan on restore; bits
+// saved under a different size are discarded.
+type BloomSnapshot struct {
+    Cur   []uint64  `json:"cur,omitempty"`
+    CurN  int       `json:"curN,omitempty"`
+    Prev  []uint64  `json:"prev,omitempty"`
+    PrevN int       `json:"prevN,omitempty"`
+    Since time.Time `json:"since"`
+}
+
+// ItemSnapshot is one buffered event with its arrival sequence. Event fields
+// are stored individually so timestamps keep full precision.
+type ItemSnapshot struct {
+    Seq       int64          `json:"seq"`
+    ID        string         `json:"id,omitempty"`
+    Timestamp time.Time      `json:"timestamp"`
+    Attempt   int            `json:"attempt,omitempty"`
+    Trace     map[string]any `json:"trace,omitempty"`
+    Payload   any            `json:"payload,omitempty"`
+}
@@ -0,0 +1,9 @@
+package merge
+
+import "time"
+
+// window is one open event-time window of a partition.
+type window struct {
+    start, end time.Time
+    aggs       []AggState
+}
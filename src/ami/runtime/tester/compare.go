@@ -0,0 +1,75 @@
+package tester
+
+import (
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// Compare reports whether got matches want under opts. Values are compared by
+// their canonical JSON form, so numeric types and map ordering do not matter.
+// When they differ, the returned message describes the first difference.
+func Compare(got, want []any, opts CompareOptions) (bool, string) {
+    g := canonicalAll(got, opts.Ignore)
+    w := canonicalAll(want, opts.Ignore)
+    if !opts.Unordered {
+        for i := 0; i < len(g) && i < len(w); i++ {
+            if g[i] != w[i] { return false, fmt.Sprintf("output %d: got %s, want %s", i, g[i], w[i]) }
+        }
+        if len(g) != len(w) { return false, fmt.Sprintf("got %d outputs, want %d", len(g), len(w)) }
+        return true, ""
+    }
+    sort.Strings(g)
+    sort.Strings(w)
+    var missing, extra []string
+    i, j := 0, 0
+    for i < len(g) || j < len(w) {
+        switch {
+        case j >= len(w) || (i < len(g) && g[i] < w[j]):
+            extra = append(extra, g[i]); i++
+        case i >= len(g) || w[j] < g[i]:
+            missing = append(missing, w[j]); j++
+        default:
+            i++; j++
+        }
+    }
+    if len(missing) == 0 && len(extra) == 0 { return true, "" }
+    var parts []string
+    if len(missing) > 0 { parts = append(parts, "missing "+strings.Join(missing, ", ")) }
+    if len(extra) > 0 { parts = append(parts, "unexpected "+strings.Join(extra, ", ")) }
+    return false, strings.Join(parts, "; ")
+}
+
+func canonicalAll(vs []any, ignore []string) []string {
+    out := make([]string, 0, len(vs))
+    for _, v := range vs { out = append(out, canonical(v, ignore)) }
+    return out
+}
+
+// canonical renders v as JSON after a round-trip (normalizing numbers and
+// key order) with the ignored paths removed.
+func canonical(v any, ignore []string) string {
+    n := normalize(v)
+    for _, p := range ignore { deletePath(n, strings.Split(p, ".")) }
+    b, err := json.Marshal(n)
+    if err != nil { return fmt.Sprintf("%v", v) }
+    return string(b)
+}
+
+// normalize returns the generic JSON form of v (maps, slices, float64, ...);
+// values that do not survive a round-trip are returned unchanged.
+func normalize(v any) any {
+    b, err := json.Marshal(v)
+    if err != nil { return v }
+    var n any
+    if json.Unmarshal(b, &n) != nil { return v }
+    return n
+}
+
+func deletePath(v any, path []string) {
+    m, ok := v.(map[string]any)
+    if !ok || len(path) == 0 { return }
+    if len(path) == 1 { delete(m, path[0]); return }
+    deletePath(m[path[0]], path[1:])
+}
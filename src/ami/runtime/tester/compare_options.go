@@ -0,0 +1,9 @@
+package tester
+
+// CompareOptions controls how emitted outputs are matched against expectations.
+// Unordered matches outputs as a multiset instead of a sequence; Ignore lists
+// dotted field paths (e.g., "ts" or "meta.id") removed from both sides first.
+type CompareOptions struct {
+    Unordered bool
+    Ignore    []string
+}
@@ -0,0 +1,22 @@
+package tester
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestCompare_OrderedUnorderedAndIgnore(t *testing.T) {
+    got := []any{map[string]any{"id": 1, "ts": "a"}, map[string]any{"id": 2, "ts": "b"}}
+    want := []any{map[string]any{"id": 2, "ts": "x"}, map[string]any{"id": 1, "ts": "y"}}
+    if ok, _ := Compare(got, want, CompareOptions{Ignore: []string{"ts"}}); ok { t.Fatalf("ordered compare must fail on reordering") }
+    if ok, diff := Compare(got, want, CompareOptions{Unordered: true, Ignore: []string{"ts"}}); !ok { t.Fatalf("unordered compare: %s", diff) }
+    ok, diff := Compare(got, want[:1], CompareOptions{Unordered: true, Ignore: []string{"ts"}})
+    if ok || !strings.Contains(diff, "unexpected") { t.Fatalf("want unexpected item diff, got %v %q", ok, diff) }
+}
+
+func TestCompare_NestedIgnorePath(t *testing.T) {
+    got := []any{map[string]any{"a": map[string]any{"b": 1, "c": 2}}}
+    want := []any{map[string]any{"a": map[string]any{"b": 9, "c": 2}}}
+    if ok, diff := Compare(got, want, CompareOptions{Ignore: []string{"a.b"}}); !ok { t.Fatalf("nested ignore: %s", diff) }
+    if ok, _ := Compare(got, want, CompareOptions{}); ok { t.Fatalf("expected mismatch without ignore") }
+}
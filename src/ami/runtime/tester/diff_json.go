@@ -0,0 +1,63 @@
+package tester
+
+import (
+    "encoding/json"
+    "fmt"
+    "sort"
+)
+
+// DiffJSON compares two generic JSON values structurally and returns one line
+// per difference, each prefixed with the dotted/indexed path where it occurs
+// (for example `events[2].payload.total: got 3, want 4`). Equal values yield nil.
+func DiffJSON(got, want any) []string {
+    var out []string
+    diffJSON("", normalize(got), normalize(want), &out)
+    return out
+}
+
+func diffJSON(path string, got, want any, out *[]string) {
+    at := path
+    if at == "" { at = "$" }
+    switch w := want.(type) {
+    case map[string]any:
+        g, ok := got.(map[string]any)
+        if !ok { break }
+        keys := make([]string, 0, len(w)+len(g))
+        for k := range w { keys = append(keys, k) }
+        for k := range g { if _, dup := w[k]; !dup { keys = append(keys, k) } }
+        sort.Strings(keys)
+        for _, k := range keys {
+            sub := k
+            if path != "" { sub = path + "." + k }
+            gv, gok := g[k]
+            wv, wok := w[k]
+            switch {
+            case !gok: *out = append(*out, fmt.Sprintf("%s: missing, want %s", sub, render(wv)))
+            case !wok: *out = append(*out, fmt.Sprintf("%s: unexpected %s", sub, render(gv)))
+            default: diffJSON(sub, gv, wv, out)
+            }
+        }
+        return
+    case []any:
+        g, ok := got.([]any)
+        if !ok { break }
+        for i := 0; i < len(g) || i < len(w); i++ {
+            sub := fmt.Sprintf("%s[%d]", path, i)
+            switch {
+            case i >= len(g): *out = append(*out, fmt.Sprintf("%s: missing, want %s", sub, render(w[i])))
+            case i >= len(w): *out = append(*out, fmt.Sprintf("%s: unexpected %s", sub, render(g[i])))
+            default: diffJSON(sub, g[i], w[i], out)
+            }
+        }
+        return
+    }
+    if gs, ws := render(got), render(want); gs != ws {
+        *out = append(*out, fmt.Sprintf("%s: got %s, want %s", at, gs, ws))
+    }
+}
+
+func render(v any) string {
+    b, err := json.Marshal(v)
+    if err != nil { return fmt.Sprintf("%v", v) }
+    return string(b)
+}
@@ -0,0 +1,17 @@
+package tester
+
+import (
+    "encoding/json"
+    "os"
+)
+
+// DiffSnapshot compares s against the golden file at path and returns the
+// structural differences (see DiffJSON). A missing or unreadable golden file is
+// returned as an error.
+func DiffSnapshot(path string, s Snapshot) ([]string, error) {
+    b, err := os.ReadFile(path)
+    if err != nil { return nil, err }
+    var want any
+    if err := json.Unmarshal(b, &want); err != nil { return nil, err }
+    return DiffJSON(s, want), nil
+}
@@ -0,0 +1,80 @@
+package tester
+
+import (
+    "fmt"
+    "math/rand"
+    "sort"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+// genAlphabet is deliberately small so generated strings collide often.
+const genAlphabet = "abc"
+
+// GenValue returns a random value of type t. Scalars are drawn from ranges
+// bounded by size so that duplicates (keys, sort fields) are frequent; Event<T>
+// generates a T payload and enums draw a member name. Unsupported types return an error.
+func GenValue(t types.Type, r *rand.Rand, size int) (any, error) {
+    if size < 1 { size = 1 }
+    switch tt := t.(type) {
+    case types.Primitive:
+        switch tt.K {
+        case types.Bool: return r.Intn(2) == 1, nil
+        case types.Int, types.Int64: return r.Intn(2*size+1) - size/2, nil
+        case types.Float64: return float64(r.Intn(4*size+1)) / 4, nil
+        case types.String:
+            b := make([]byte, r.Intn(4))
+            for i := range b { b[i] = genAlphabet[r.Intn(len(genAlphabet))] }
+            return string(b), nil
+        }
+    case types.Struct:
+        // draw fields in name order so a seed reproduces the same values
+        keys := make([]string, 0, len(tt.Fields))
+        for k := range tt.Fields { keys = append(keys, k) }
+        sort.Strings(keys)
+        m := make(map[string]any, len(tt.Fields))
+        for _, k := range keys {
+            v, err := GenValue(tt.Fields[k], r, size)
+            if err != nil { return nil, fmt.Errorf("%s: %w", k, err) }
+            m[k] = v
+        }
+        return m, nil
+    case types.Enum:
+        if len(tt.Members) > 0 { return tt.Members[r.Intn(len(tt.Members))], nil }
+    case types.Optional:
+        if r.Intn(4) == 0 { return nil, nil }
+        return GenValue(tt.Inner, r, size)
+    case types.Union:
+        if len(tt.Alts) > 0 { return GenValue(tt.Alts[r.Intn(len(tt.Alts))], r, size) }
+    case types.Slice:
+        return genList(tt.Elem, r, size)
+    case types.Generic:
+        switch {
+        case tt.Name == "Event" && len(tt.Args) == 1:
+            return GenValue(tt.Args[0], r, size)
+        case (tt.Name == "slice" || tt.Name == "set") && len(tt.Args) == 1:
+            return genList(tt.Args[0], r, size)
+        case tt.Name == "map" && len(tt.Args) == 2:
+            m := map[string]any{}
+            for i := r.Intn(4); i > 0; i-- {
+                k, err := GenValue(tt.Args[0], r, size)
+                if err != nil { return nil, err }
+                v, err := GenValue(tt.Args[1], r, size)
+                if err != nil { return nil, err }
+                m[fmt.Sprint(k)] = v
+            }
+            return m, nil
+        }
+    }
+    return nil, fmt.Errorf("cannot generate values of type %s", t.String())
+}
+
+func genList(elem types.Type, r *rand.Rand, size int) ([]any, error) {
+    out := make([]any, r.Intn(4))
+    for i := range out {
+        v, err := GenValue(elem, r, size)
+        if err != nil { return nil, err }
+        out[i] = v
+    }
+    return out, nil
+}
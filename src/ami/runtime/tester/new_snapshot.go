@@ -0,0 +1,30 @@
+package tester
+
+import (
+    "strings"
+    "time"
+
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// NewSnapshot builds the snapshot of res for pipeline. Wall-clock timestamps are
+// dropped and the dotted ignore paths are removed from every payload so the
+// snapshot is stable across runs.
+func NewSnapshot(pipeline string, res PipelineResult, ignore []string) Snapshot {
+    s := Snapshot{Schema: "test.snapshot.v1", Pipeline: pipeline, Events: []ev.Event{}, Errors: []errs.Error{}}
+    for _, e := range res.Events {
+        e.Timestamp = time.Time{}
+        if e.Payload != nil && len(ignore) > 0 {
+            p := normalize(e.Payload)
+            for _, path := range ignore { deletePath(p, strings.Split(path, ".")) }
+            e.Payload = p
+        }
+        s.Events = append(s.Events, e)
+    }
+    for _, e := range res.Errors {
+        e.Timestamp = time.Time{}
+        s.Errors = append(s.Errors, e)
+    }
+    return s
+}
@@ -0,0 +1,24 @@
+package tester
+
+import (
+    "fmt"
+    "strings"
+)
+
+// ParseProperty parses a property spec such as `no_loss`, `sorted:ts:desc` or `unique:id`.
+func ParseProperty(s string) (Property, error) {
+    parts := strings.Split(strings.TrimSpace(s), ":")
+    p := Property{Kind: parts[0]}
+    switch p.Kind {
+    case "no_loss", "no_errors":
+        if len(parts) == 1 { return p, nil }
+    case "sorted":
+        if len(parts) == 3 && (parts[2] == "asc" || parts[2] == "desc") { p.Desc = parts[2] == "desc"; parts = parts[:2] }
+        if len(parts) == 2 && parts[1] != "" { p.Field = parts[1]; return p, nil }
+    case "unique":
+        if len(parts) == 2 && parts[1] != "" { p.Field = parts[1]; return p, nil }
+    default:
+        return Property{}, fmt.Errorf("unknown property %q", s)
+    }
+    return Property{}, fmt.Errorf("invalid property %q", s)
+}
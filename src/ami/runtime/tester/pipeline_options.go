@@ -0,0 +1,32 @@
+package tester
+
+import (
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// PipelineOptions selects the compiled pipeline a runtime case drives.
+// Module is the IR module containing Pipeline; its debug artifacts
+// (edges and pipelines JSON) are read from build/debug under the current
+// directory. Workers resolve through Invoker, then Workers, and otherwise
+// through the package's compiled workers library when the build manifest
+// records one; unresolved workers fail the events that reach them with
+// E_WORKER_UNAVAILABLE.
+//
+// Clock, when set, is installed as the process-wide runtime clock for the run
+// (so runs using it must not overlap): input timestamps, timers, merge
+// timeouts and kvstore TTLs then follow virtual time. After the inputs have
+// been consumed the clock is advanced by Advance before ingress closes.
+type PipelineOptions struct {
+    Module   ir.Module
+    Pipeline string
+    Timeout  time.Duration
+    Invoker  rexec.WorkerInvoker
+    Workers  map[string]func(ev.Event) (any, error)
+    Clock    *amitime.VirtualClock
+    Advance  time.Duration
+}
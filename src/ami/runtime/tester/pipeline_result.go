@@ -0,0 +1,24 @@
+package tester
+
+import (
+    "time"
+
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// PipelineResult captures what a pipeline emitted for one runtime case:
+// egress events (and their payloads) in arrival order and errors reported by
+// its stages.
+type PipelineResult struct {
+    Events   []ev.Event
+    Outputs  []any
+    Errors   []errs.Error
+    Duration time.Duration
+}
+
+// HasError reports whether a stage reported code.
+func (r PipelineResult) HasError(code string) bool {
+    for _, e := range r.Errors { if e.Code == code { return true } }
+    return false
+}
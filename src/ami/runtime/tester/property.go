@@ -0,0 +1,13 @@
+package tester
+
+// Property is an invariant checked over one pipeline run:
+//   no_loss          every input payload is emitted at least as often as it was fed
+//   no_errors        no stage reported an error
+//   sorted:<f>[:desc] outputs are ordered by payload field f
+//   unique:<f>       no two outputs share a value of payload field f
+// Fields are dotted payload paths.
+type Property struct {
+    Kind  string
+    Field string
+    Desc  bool
+}
@@ -0,0 +1,69 @@
+package tester
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Check reports the first violation of p by a run that was fed inputs, or nil.
+func (p Property) Check(inputs []any, res PipelineResult) error {
+    switch p.Kind {
+    case "no_errors":
+        if len(res.Errors) > 0 { return fmt.Errorf("no_errors: %s: %s", res.Errors[0].Code, res.Errors[0].Message) }
+    case "no_loss":
+        seen := map[string]int{}
+        for _, o := range res.Outputs { seen[canonical(o, nil)]++ }
+        for _, in := range inputs {
+            k := canonical(in, nil)
+            if seen[k] == 0 { return fmt.Errorf("no_loss: input %s was not emitted", k) }
+            seen[k]--
+        }
+    case "sorted":
+        for i := 1; i < len(res.Outputs); i++ {
+            a, aok := fieldAt(res.Outputs[i-1], p.Field)
+            b, bok := fieldAt(res.Outputs[i], p.Field)
+            if !aok { return fmt.Errorf("sorted:%s: output %d lacks the field", p.Field, i-1) }
+            if !bok { return fmt.Errorf("sorted:%s: output %d lacks the field", p.Field, i) }
+            c := compareValues(a, b)
+            if (!p.Desc && c > 0) || (p.Desc && c < 0) {
+                return fmt.Errorf("sorted:%s: output %d (%s) out of order after %s", p.Field, i, render(b), render(a))
+            }
+        }
+    case "unique":
+        first := map[string]int{}
+        for i, o := range res.Outputs {
+            v, ok := fieldAt(o, p.Field)
+            if !ok { continue }
+            k := render(v)
+            if j, dup := first[k]; dup { return fmt.Errorf("unique:%s: outputs %d and %d share %s", p.Field, j, i, k) }
+            first[k] = i
+        }
+    }
+    return nil
+}
+
+// fieldAt returns the value at a dotted path of a payload.
+func fieldAt(v any, path string) (any, bool) {
+    cur := normalize(v)
+    for _, k := range strings.Split(path, ".") {
+        m, ok := cur.(map[string]any)
+        if !ok { return nil, false }
+        if cur, ok = m[k]; !ok { return nil, false }
+    }
+    return cur, true
+}
+
+// compareValues orders numbers numerically, strings lexically and anything
+// else by its JSON rendering.
+func compareValues(a, b any) int {
+    if x, ok := a.(float64); ok {
+        if y, ok := b.(float64); ok {
+            switch { case x < y: return -1; case x > y: return 1 }
+            return 0
+        }
+    }
+    if x, ok := a.(string); ok {
+        if y, ok := b.(string); ok { return strings.Compare(x, y) }
+    }
+    return strings.Compare(render(a), render(b))
+}
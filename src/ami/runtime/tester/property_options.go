@@ -0,0 +1,19 @@
+package tester
+
+import "github.com/sam-caldwell/ami/src/ami/compiler/types"
+
+// PropertyOptions configures a property-based run of a pipeline.
+type PropertyOptions struct {
+    // Payload is the ingress payload type events are generated from.
+    Payload types.Type
+    // Properties are checked after every run.
+    Properties []Property
+    // Seed makes the generated sequence reproducible.
+    Seed int64
+    // Runs is the number of generated inputs to try (default 100).
+    Runs int
+    // MaxEvents bounds the number of events per run (default 20).
+    MaxEvents int
+    // ShrinkBudget bounds the pipeline executions spent shrinking (default 200).
+    ShrinkBudget int
+}
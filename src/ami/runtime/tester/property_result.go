@@ -0,0 +1,15 @@
+package tester
+
+// PropertyResult reports a property-based run. On failure Counterexample holds
+// the shrunk inputs and Failure the violation they produce; rerunning with the
+// same Seed regenerates the original failing input (run number Run).
+type PropertyResult struct {
+    OK             bool
+    Seed           int64
+    Runs           int
+    Run            int
+    Failure        string
+    Counterexample []any
+    Original       int // events in the failing input before shrinking
+    Shrinks        int // successful shrink steps
+}
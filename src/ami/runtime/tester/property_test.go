@@ -0,0 +1,81 @@
+package tester
+
+import (
+    "context"
+    "math/rand"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+func TestParseProperty(t *testing.T) {
+    for _, s := range []string{"no_loss", "no_errors", "sorted:ts", "sorted:a.b:desc", "unique:id"} {
+        if _, err := ParseProperty(s); err != nil { t.Fatalf("%s: %v", s, err) }
+    }
+    for _, s := range []string{"", "sorted", "unique:", "sorted:ts:up", "bogus:x", "no_loss:x"} {
+        if _, err := ParseProperty(s); err == nil { t.Fatalf("%q: expected error", s) }
+    }
+    if p, _ := ParseProperty("sorted:ts:desc"); p.Field != "ts" || !p.Desc { t.Fatalf("parse: %+v", p) }
+}
+
+func TestProperty_Check(t *testing.T) {
+    in := []any{map[string]any{"id": 1, "ts": 2}, map[string]any{"id": 1, "ts": 1}}
+    res := PipelineResult{Outputs: in}
+    if err := (Property{Kind: "no_loss"}).Check(in, res); err != nil { t.Fatalf("no_loss: %v", err) }
+    if err := (Property{Kind: "no_loss"}).Check(in, PipelineResult{Outputs: in[:1]}); err == nil { t.Fatalf("no_loss must fail on a dropped event") }
+    if err := (Property{Kind: "sorted", Field: "ts"}).Check(in, res); err == nil { t.Fatalf("sorted must fail") }
+    if err := (Property{Kind: "sorted", Field: "ts", Desc: true}).Check(in, res); err != nil { t.Fatalf("sorted desc: %v", err) }
+    if err := (Property{Kind: "unique", Field: "id"}).Check(in, res); err == nil || !strings.Contains(err.Error(), "outputs 0 and 1") { t.Fatalf("unique: %v", err) }
+}
+
+func TestGenValue_SeedReproducible(t *testing.T) {
+    ty := types.MustParse("Event<Struct{id:int,name:string,tags:slice<string>,score:Optional<float64>}>")
+    a, err := GenValue(ty, rand.New(rand.NewSource(7)), 10)
+    if err != nil { t.Fatalf("gen: %v", err) }
+    b, _ := GenValue(ty, rand.New(rand.NewSource(7)), 10)
+    if canonical(a, nil) != canonical(b, nil) { t.Fatalf("same seed produced %v and %v", a, b) }
+    if _, ok := a.(map[string]any)["id"].(int); !ok { t.Fatalf("struct field id: %#v", a) }
+    if _, err := GenValue(types.MustParse("Widget"), rand.New(rand.NewSource(1)), 10); err == nil { t.Fatalf("expected unsupported type error") }
+}
+
+func TestRunProperty_ShrinksCounterexample(t *testing.T) {
+    // a "pipeline" that loses every event whose id is negative
+    exec := func(_ context.Context, in []any) (PipelineResult, error) {
+        var res PipelineResult
+        for _, x := range in {
+            if x.(map[string]any)["id"].(int) < 0 { continue }
+            res.Outputs = append(res.Outputs, x)
+        }
+        return res, nil
+    }
+    opts := PropertyOptions{Payload: types.MustParse("Struct{id:int,name:string}"), Properties: []Property{{Kind: "no_loss"}}, Seed: 42}
+    r, err := RunProperty(context.Background(), opts, exec)
+    if err != nil { t.Fatalf("run: %v", err) }
+    if r.OK || r.Seed != 42 || r.Run == 0 { t.Fatalf("expected failure: %+v", r) }
+    if len(r.Counterexample) != 1 { t.Fatalf("want 1-event counterexample, got %v", r.Counterexample) }
+    ce := r.Counterexample[0].(map[string]any)
+    if ce["id"].(int) != -1 || ce["name"] != "" { t.Fatalf("not minimal: %v", ce) }
+    again, _ := RunProperty(context.Background(), opts, exec)
+    if again.Run != r.Run || again.Original != r.Original { t.Fatalf("seed not reproducible: %+v vs %+v", r, again) }
+}
+
+func TestRunProperty_DedupPipeline(t *testing.T) {
+    m := compileModule(t, "package app\npipeline P(){ ingress; Collect merge.Dedup(\"id\"), merge.Buffer(64, \"block\"); egress; ingress -> Collect; Collect -> egress }\n")
+    exec := func(ctx context.Context, in []any) (PipelineResult, error) {
+        return RunPipeline(ctx, PipelineOptions{Module: m, Pipeline: "P"}, in)
+    }
+    opts := PropertyOptions{Payload: types.MustParse("Event<Struct{id:int}>"), Seed: 1, Runs: 20}
+    opts.Properties = []Property{{Kind: "unique", Field: "id"}, {Kind: "no_errors"}}
+    if r, err := RunProperty(context.Background(), opts, exec); err != nil || !r.OK { t.Fatalf("dedup keeps ids unique: %+v %v", r, err) }
+    opts.Properties = []Property{{Kind: "no_loss"}}
+    r, err := RunProperty(context.Background(), opts, exec)
+    if err != nil || r.OK { t.Fatalf("dedup must lose duplicates: %+v %v", r, err) }
+    if len(r.Counterexample) != 2 { t.Fatalf("want two duplicate events, got %v", r.Counterexample) }
+}
+
+func TestGenValue_EnumDrawsMember(t *testing.T) {
+    v, err := GenValue(types.Enum{Name: "app.Color", Members: []string{"Red", "Green"}}, rand.New(rand.NewSource(3)), 10)
+    if err != nil { t.Fatalf("gen: %v", err) }
+    if s, _ := v.(string); s != "Red" && s != "Green" { t.Fatalf("enum value: %#v", v) }
+}
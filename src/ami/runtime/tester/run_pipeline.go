@@ -0,0 +1,102 @@
+package tester
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// RunPipeline feeds inputs (event payloads, or events.Event values) through
+// the named pipeline on an exec.Engine and collects its outputs until the
+// pipeline drains. It returns an error when the pipeline cannot start or does
+// not drain before opts.Timeout; worker failures are reported in Errors.
+func RunPipeline(ctx context.Context, opts PipelineOptions, inputs []any) (PipelineResult, error) {
+    start := time.Now()
+    if opts.Timeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+        defer cancel()
+    }
+    ctx, cancel := context.WithCancel(ctx)
+    defer cancel()
+    if !rexec.HasPipeline(".", opts.Module.Package, opts.Pipeline) {
+        return PipelineResult{}, fmt.Errorf("pipeline not found: %s.%s", opts.Module.Package, opts.Pipeline)
+    }
+    sched := opts.Module
+    for _, p := range opts.Module.Pipelines {
+        // each Collect holds a scheduler worker for the whole run
+        if n := len(p.Collect) + 1; p.Name == opts.Pipeline && sched.Concurrency < n { sched.Concurrency = n }
+    }
+    eng, err := rexec.NewEngineFromModule(sched)
+    if err != nil { return PipelineResult{}, err }
+    defer eng.Close()
+    stamp := start
+    if opts.Clock != nil {
+        prev := amitime.SetClock(opts.Clock)
+        defer amitime.SetClock(prev)
+        stamp = opts.Clock.Now()
+    }
+    in := make(chan ev.Event, len(inputs))
+    for i, x := range inputs {
+        e, ok := x.(ev.Event)
+        if !ok { e = ev.Event{ID: fmt.Sprintf("in-%d", i), Timestamp: stamp.UTC(), Payload: x} }
+        in <- e
+    }
+    if opts.Clock == nil || opts.Advance <= 0 { close(in) } else { go advanceThenClose(ctx, opts.Clock, opts.Advance, in) }
+    errCh := make(chan errs.Error, 64)
+    xopts := rexec.ExecOptions{Invoker: opts.Invoker, Workers: opts.Workers, ErrorChan: errCh, RequireWorkers: true}
+    out, stats, err := eng.RunPipelineWithStats(ctx, opts.Module, opts.Pipeline, in, nil, "", "", xopts)
+    if err != nil { return PipelineResult{}, err }
+    go func(){ for range stats {} }()
+    var res PipelineResult
+    for {
+        select {
+        case e, ok := <-out:
+            if !ok {
+                drainErrors(errCh, &res)
+                res.Duration = time.Since(start)
+                return res, nil
+            }
+            if ee, isErr := e.Payload.(errs.Error); isErr { res.Errors = append(res.Errors, ee); continue }
+            res.Events = append(res.Events, e)
+            res.Outputs = append(res.Outputs, e.Payload)
+        case ee := <-errCh:
+            res.Errors = append(res.Errors, ee)
+        case <-ctx.Done():
+            drainErrors(errCh, &res)
+            res.Duration = time.Since(start)
+            return res, ctx.Err()
+        }
+    }
+}
+
+func drainErrors(ch <-chan errs.Error, res *PipelineResult) {
+    for {
+        select {
+        case ee := <-ch: res.Errors = append(res.Errors, ee)
+        default: return
+        }
+    }
+}
+
+// advanceThenClose waits for ingress to consume the queued inputs, lets the
+// stages settle, advances clk by d and only then closes in, so timeouts and
+// timers due within d fire while the pipeline is still running.
+func advanceThenClose(ctx context.Context, clk *amitime.VirtualClock, d time.Duration, in chan ev.Event) {
+    defer close(in)
+    for len(in) > 0 {
+        if ctx.Err() != nil { return }
+        time.Sleep(time.Millisecond)
+    }
+    time.Sleep(settleDelay)
+    clk.Advance(d)
+    time.Sleep(settleDelay)
+}
+
+// settleDelay is the real time given to stages to react around a virtual advance.
+const settleDelay = 10 * time.Millisecond
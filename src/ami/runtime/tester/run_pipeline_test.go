@@ -0,0 +1,63 @@
+package tester
+
+import (
+    "context"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/driver"
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+    diag "github.com/sam-caldwell/ami/src/schemas/diag"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// compileModule compiles code as package app in a temp dir (the working
+// directory for the rest of the test) and returns its IR module.
+func compileModule(t *testing.T, code string) ir.Module {
+    t.Helper()
+    wd, _ := os.Getwd()
+    dir := t.TempDir()
+    if err := os.Chdir(dir); err != nil { t.Fatal(err) }
+    t.Cleanup(func(){ _ = os.Chdir(wd) })
+    fs := &source.FileSet{}
+    fs.AddFile("app.ami", code)
+    arts, diags := driver.Compile(workspace.DefaultWorkspace(), []driver.Package{{Name: "app", Files: fs}}, driver.Options{Debug: true, EmitLLVMOnly: true})
+    for _, d := range diags { if d.Level == diag.Error { t.Fatalf("compile: %s %s", d.Code, d.Message) } }
+    if len(arts.IR) == 0 { t.Fatalf("no IR compiled") }
+    b, err := os.ReadFile(filepath.Clean(arts.IR[0]))
+    if err != nil { t.Fatal(err) }
+    var m ir.Module
+    if err := json.Unmarshal(b, &m); err != nil { t.Fatal(err) }
+    return m
+}
+
+func TestRunPipeline_DedupCollect(t *testing.T) {
+    m := compileModule(t, "package app\npipeline P(){ ingress; Collect merge.Dedup(\"id\"), merge.Buffer(16, \"block\"); egress; ingress -> Collect; Collect -> egress }\n")
+    in := []any{map[string]any{"id": 1}, map[string]any{"id": 1}, map[string]any{"id": 2}}
+    res, err := RunPipeline(context.Background(), PipelineOptions{Module: m, Pipeline: "P", Timeout: 2 * time.Second}, in)
+    if err != nil { t.Fatalf("run: %v", err) }
+    want := []any{map[string]any{"id": 1}, map[string]any{"id": 2}}
+    if ok, msg := Compare(res.Outputs, want, CompareOptions{}); !ok { t.Fatalf("outputs: %s", msg) }
+}
+
+func TestRunPipeline_WorkerRegistryAndUnresolved(t *testing.T) {
+    m := compileModule(t, "package app\nfunc W(ev Event<int>) (Event<int>, error) { var e error\n    return ev, e }\npipeline P(){ ingress; Transform(W); egress; ingress -> Transform; Transform -> egress }\n")
+    double := func(e ev.Event) (any, error) { p := e.Payload.(map[string]any); return map[string]any{"v": p["v"].(int) * 2}, nil }
+    res, err := RunPipeline(context.Background(), PipelineOptions{Module: m, Pipeline: "P", Timeout: 2 * time.Second, Workers: map[string]func(ev.Event) (any, error){"W": double}}, []any{map[string]any{"v": 2}})
+    if err != nil { t.Fatalf("run: %v", err) }
+    if ok, msg := Compare(res.Outputs, []any{map[string]any{"v": 4}}, CompareOptions{}); !ok { t.Fatalf("outputs: %s", msg) }
+    res, err = RunPipeline(context.Background(), PipelineOptions{Module: m, Pipeline: "P", Timeout: 2 * time.Second}, []any{map[string]any{"v": 2}})
+    if err != nil { t.Fatalf("run: %v", err) }
+    if len(res.Outputs) != 0 || !res.HasError("E_WORKER_UNAVAILABLE") { t.Fatalf("unresolved worker must fail: %+v", res) }
+}
+
+func TestRunPipeline_UnknownPipeline(t *testing.T) {
+    if _, err := RunPipeline(context.Background(), PipelineOptions{Module: compileModule(t, "package app\npipeline P(){ ingress; egress; ingress -> egress }\n"), Pipeline: "Nope"}, nil); err == nil {
+        t.Fatalf("expected error for unknown pipeline")
+    }
+}
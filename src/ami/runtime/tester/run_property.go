@@ -0,0 +1,51 @@
+package tester
+
+import (
+    "context"
+    "errors"
+    "math/rand"
+)
+
+// RunProperty generates opts.Runs random inputs of opts.Payload from opts.Seed,
+// executes each through exec and checks opts.Properties. The first violating
+// input is shrunk to a minimal counterexample. An error is returned only when
+// generation fails or exec fails to run the pipeline.
+func RunProperty(ctx context.Context, opts PropertyOptions, exec func(context.Context, []any) (PipelineResult, error)) (PropertyResult, error) {
+    if opts.Payload == nil { return PropertyResult{}, errors.New("property: payload type required") }
+    if opts.Runs <= 0 { opts.Runs = 100 }
+    if opts.MaxEvents <= 0 { opts.MaxEvents = 20 }
+    if opts.ShrinkBudget <= 0 { opts.ShrinkBudget = 200 }
+    check := func(inputs []any) (string, error) {
+        res, err := exec(ctx, inputs)
+        if err != nil { return "", err }
+        for _, p := range opts.Properties {
+            if v := p.Check(inputs, res); v != nil { return v.Error(), nil }
+        }
+        return "", nil
+    }
+    rng := rand.New(rand.NewSource(opts.Seed))
+    out := PropertyResult{Seed: opts.Seed}
+    for run := 1; run <= opts.Runs; run++ {
+        n := 1 + rng.Intn(opts.MaxEvents)
+        inputs := make([]any, n)
+        for i := range inputs {
+            v, err := GenValue(opts.Payload, rng, opts.MaxEvents)
+            if err != nil { return out, err }
+            inputs[i] = v
+        }
+        out.Runs = run
+        failure, err := check(inputs)
+        if err != nil { return out, err }
+        if failure == "" { continue }
+        shrunk, steps := shrinkInputs(inputs, func(cand []any) bool {
+            f, err := check(cand)
+            if err != nil || f == "" { return false }
+            failure = f
+            return true
+        }, opts.ShrinkBudget)
+        out.Run, out.Failure, out.Counterexample, out.Original, out.Shrinks = run, failure, shrunk, n, steps
+        return out, nil
+    }
+    out.OK = true
+    return out, nil
+}
@@ -0,0 +1,88 @@
+package tester
+
+import (
+    "math"
+    "sort"
+)
+
+// shrinkInputs minimizes a failing input: it first drops runs of events
+// (halving the chunk size down to single events), then simplifies individual
+// values, repeating until no candidate fails or budget executions are spent.
+func shrinkInputs(inputs []any, fails func([]any) bool, budget int) ([]any, int) {
+    cur := inputs
+    steps := 0
+    try := func(cand []any) bool {
+        if budget <= 0 { return false }
+        budget--
+        if !fails(cand) { return false }
+        cur = cand; steps++
+        return true
+    }
+    for improved := true; improved && budget > 0; {
+        improved = false
+        for chunk := len(cur) / 2; chunk >= 1; chunk /= 2 {
+            for i := 0; i+chunk <= len(cur) && budget > 0; {
+                cand := append(append([]any{}, cur[:i]...), cur[i+chunk:]...)
+                if try(cand) { improved = true } else { i += chunk }
+            }
+        }
+        for i := 0; i < len(cur) && budget > 0; i++ {
+            for _, sv := range shrinkValue(cur[i]) {
+                cand := append([]any{}, cur...)
+                cand[i] = sv
+                if try(cand) { improved = true; break }
+            }
+        }
+    }
+    return cur, steps
+}
+
+// shrinkValue returns strictly simpler candidates for v, simplest first.
+func shrinkValue(v any) []any {
+    switch x := v.(type) {
+    case bool:
+        if x { return []any{false} }
+    case int:
+        if x != 0 { return []any{0, x / 2} }
+    case int64:
+        if x != 0 { return []any{int64(0), x / 2} }
+    case float64:
+        if x != 0 {
+            out := []any{0.0}
+            if t := math.Trunc(x); t != x { out = append(out, t) } else if math.Abs(x) >= 2 { out = append(out, math.Trunc(x/2)) }
+            return out
+        }
+    case string:
+        if x != "" { return []any{"", x[:len(x)/2]} }
+    case []any:
+        var out []any
+        for i := range x { out = append(out, append(append([]any{}, x[:i]...), x[i+1:]...)) }
+        for i := range x {
+            for _, sv := range shrinkValue(x[i]) {
+                c := append([]any{}, x...)
+                c[i] = sv
+                out = append(out, c)
+            }
+        }
+        return out
+    case map[string]any:
+        var out []any
+        for _, k := range sortedKeys(x) {
+            for _, sv := range shrinkValue(x[k]) {
+                c := make(map[string]any, len(x))
+                for kk, vv := range x { c[kk] = vv }
+                c[k] = sv
+                out = append(out, c)
+            }
+        }
+        return out
+    }
+    return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m { keys = append(keys, k) }
+    sort.Strings(keys)
+    return keys
+}
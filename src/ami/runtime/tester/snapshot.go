@@ -0,0 +1,15 @@
+package tester
+
+import (
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+// Snapshot is the golden-file form (test.snapshot.v1) of one pipeline run:
+// the egress events.v1 stream and the errors.v1 stream, in arrival order.
+type Snapshot struct {
+    Schema   string       `json:"schema"`
+    Pipeline string       `json:"pipeline"`
+    Events   []ev.Event   `json:"events"`
+    Errors   []errs.Error `json:"errors"`
+}
@@ -0,0 +1,14 @@
+package tester
+
+import "sort"
+
+// Sorted returns a copy of s with events and errors ordered by their canonical
+// JSON form, for pipelines whose emission order is not deterministic.
+func (s Snapshot) Sorted() Snapshot {
+    out := s
+    out.Events = append(out.Events[:0:0], s.Events...)
+    out.Errors = append(out.Errors[:0:0], s.Errors...)
+    sort.SliceStable(out.Events, func(i, j int) bool { return render(out.Events[i]) < render(out.Events[j]) })
+    sort.SliceStable(out.Errors, func(i, j int) bool { return render(out.Errors[i]) < render(out.Errors[j]) })
+    return out
+}
@@ -0,0 +1,56 @@
+package tester
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    errs "github.com/sam-caldwell/ami/src/schemas/errors"
+    ev "github.com/sam-caldwell/ami/src/schemas/events"
+)
+
+func TestSnapshot_WriteAndDiff(t *testing.T) {
+    res := PipelineResult{
+        Events: []ev.Event{{ID: "in-0", Timestamp: time.Now(), Payload: map[string]any{"id": 1, "at": "now"}}},
+        Errors: []errs.Error{{Timestamp: time.Now(), Level: "error", Code: "E_WORKER", Message: "boom"}},
+    }
+    path := filepath.Join(t.TempDir(), "testdata", "p.snap.json")
+    s := NewSnapshot("P", res, []string{"at"})
+    if err := WriteSnapshot(path, s); err != nil { t.Fatalf("write: %v", err) }
+    b, _ := os.ReadFile(path)
+    if strings.Contains(string(b), "timestamp") || strings.Contains(string(b), "\"at\"") { t.Fatalf("snapshot not normalized:\n%s", b) }
+    res.Events[0].Timestamp = time.Now().Add(time.Hour)
+    if d, err := DiffSnapshot(path, NewSnapshot("P", res, []string{"at"})); err != nil || len(d) != 0 { t.Fatalf("stable rerun: %v %v", d, err) }
+    res.Events[0].Payload = map[string]any{"id": 2}
+    res.Events = append(res.Events, ev.Event{ID: "in-1", Payload: 3})
+    d, err := DiffSnapshot(path, NewSnapshot("P", res, nil))
+    if err != nil { t.Fatalf("diff: %v", err) }
+    want := []string{"events[0].payload.id: got 2, want 1", "events[1]: unexpected"}
+    for _, w := range want {
+        found := false
+        for _, line := range d { if strings.HasPrefix(line, w) { found = true } }
+        if !found { t.Fatalf("missing %q in diff %v", w, d) }
+    }
+}
+
+func TestSnapshot_DiffMissingGolden(t *testing.T) {
+    if _, err := DiffSnapshot(filepath.Join(t.TempDir(), "none.json"), Snapshot{}); err == nil { t.Fatalf("expected error") }
+}
+
+func TestDiffJSON_Paths(t *testing.T) {
+    got := map[string]any{"a": []any{1, map[string]any{"b": "x"}}, "c": true}
+    want := map[string]any{"a": []any{1, map[string]any{"b": "y"}, 3}}
+    d := DiffJSON(got, want)
+    if len(d) != 3 { t.Fatalf("want 3 diffs, got %v", d) }
+    if d[0] != `a[1].b: got "x", want "y"` || d[1] != "a[2]: missing, want 3" || d[2] != "c: unexpected true" { t.Fatalf("diff: %v", d) }
+    if DiffJSON(1, 1) != nil { t.Fatalf("equal scalars must not differ") }
+}
+
+func TestSnapshot_SortedIsOrderIndependent(t *testing.T) {
+    a := NewSnapshot("P", PipelineResult{Events: []ev.Event{{ID: "in-1", Payload: 2}, {ID: "in-0", Payload: 1}}}, nil)
+    b := NewSnapshot("P", PipelineResult{Events: []ev.Event{{ID: "in-0", Payload: 1}, {ID: "in-1", Payload: 2}}}, nil)
+    if d := DiffJSON(a.Sorted(), b.Sorted()); d != nil { t.Fatalf("sorted snapshots differ: %v", d) }
+    if a.Events[0].ID != "in-1" { t.Fatalf("Sorted must not modify the receiver") }
+}
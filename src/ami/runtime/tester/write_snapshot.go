@@ -0,0 +1,15 @@
+package tester
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+)
+
+// WriteSnapshot writes s as indented JSON to path, creating parent directories.
+func WriteSnapshot(path string, s Snapshot) error {
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { return err }
+    b, err := json.MarshalIndent(s, "", "  ")
+    if err != nil { return err }
+    return os.WriteFile(path, append(b, '\n'), 0o644)
+}
@@ -14,6 +14,9 @@ type AuditReport struct {
     Mismatched []string
     // ParseErrors captures import constraint parse errors as strings; requirements with parse errors are skipped.
     ParseErrors []string
+    // SignatureFailures lists cached name@version entries (that satisfy constraints) failing
+    // signature verification against the workspace trust configuration, as "<key>: <reason>".
+    SignatureFailures []string
     // SumFound indicates whether ami.sum existed and was parsed.
     SumFound bool
 }
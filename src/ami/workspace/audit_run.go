@@ -4,12 +4,14 @@ import (
     "errors"
     "os"
     "path/filepath"
+    "sort"
 )
 
 // AuditDependencies loads ami.workspace under dir, collects remote requirements, cross-checks them against
 // ami.sum, and filters integrity issues to only versions that satisfy constraints. It returns an AuditReport
 // suitable for consumption by CLI or other packages. This function performs no I/O beyond reading workspace
 // and ami.sum; cache location is inferred by Manifest.Validate using AMI_PACKAGE_CACHE or HOME.
+// When the workspace configures trust, cached satisfying versions are also signature-verified.
 func AuditDependencies(dir string) (AuditReport, error) {
     var rep AuditReport
     // Load workspace
@@ -42,6 +44,26 @@ func AuditDependencies(dir string) (AuditReport, error) {
     miss, mis, err := CrossCheckRequirementsIntegrity(&m, reqs)
     if err != nil { return rep, err }
     rep.MissingInCache, rep.Mismatched = miss, mis
+
+    // Verify signatures of cached satisfying versions when trust is configured
+    if ws.Trust.Enabled() {
+        cache, err := DefaultCacheRoot()
+        if err != nil { return rep, err }
+        seen := map[string]bool{}
+        for _, r := range reqs {
+            for _, v := range m.Versions(r.Name) {
+                key := r.Name + "@" + v
+                if seen[key] || !Satisfies(v, r.Constraint) { continue }
+                seen[key] = true
+                dir := filepath.Join(cache, r.Name, v)
+                if st, err := os.Stat(dir); err != nil || !st.IsDir() { continue }
+                if _, err := VerifyPackage(dir, key, ws.Trust); err != nil {
+                    rep.SignatureFailures = append(rep.SignatureFailures, err.Error())
+                }
+            }
+        }
+        sort.Strings(rep.SignatureFailures)
+    }
     return rep, nil
 }
 
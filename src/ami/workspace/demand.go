@@ -0,0 +1,19 @@
+package workspace
+
+import "strings"
+
+// Demand is a version constraint on a remote import path together with the
+// chain of requirers that declared it, starting at a workspace package
+// (e.g. By = ["app", "util@v1.3.0"]).
+type Demand struct {
+    Path       string
+    Constraint string
+    By         []string
+}
+
+// String renders the demand as `<constraint> (<requirer> -> ...)`.
+func (d Demand) String() string {
+    c := d.Constraint
+    if c == "" { c = "==latest" }
+    return c + " (" + strings.Join(d.By, " -> ") + ")"
+}
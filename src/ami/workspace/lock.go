@@ -0,0 +1,15 @@
+package workspace
+
+// LockSchema identifies the ami.lock format written by Lock.Save.
+const LockSchema = "ami.lock/v1"
+
+// Lock is the ami.lock file: the resolved dependency graph of a workspace.
+// Packages holds one entry per selected remote import, sorted by source;
+// Edges holds every requirement, sorted by requirer then target. Node IDs
+// in Edges are workspace package names or `<source>@<version>` of a locked
+// package.
+type Lock struct {
+    Schema   string        `json:"schema"`
+    Packages []LockPackage `json:"packages"`
+    Edges    []LockEdge    `json:"edges"`
+}
@@ -0,0 +1,50 @@
+package workspace
+
+import (
+    "fmt"
+    "sort"
+)
+
+// LockDiff lists how want differs from have, one line per change, sorted:
+//   + <source>@<version>            package added
+//   - <source>@<version>            package removed
+//   ~ <source>: <old> -> <new>      version, commit or hash changed
+//   + edge <from> -> <to> (<c>)     requirement added (likewise - for removed)
+// An empty result means the graphs are identical.
+func LockDiff(have, want Lock) []string {
+    var out []string
+    old := map[string]LockPackage{}
+    for _, p := range have.Packages { old[p.Source] = p }
+    cur := map[string]LockPackage{}
+    for _, p := range want.Packages { cur[p.Source] = p }
+    for src, p := range cur {
+        o, ok := old[src]
+        switch {
+        case !ok:
+            out = append(out, "+ "+p.ID())
+        case o.Version != p.Version:
+            out = append(out, fmt.Sprintf("~ %s: %s -> %s", src, o.Version, p.Version))
+        case o.Commit != p.Commit:
+            out = append(out, fmt.Sprintf("~ %s@%s: commit %s -> %s", src, p.Version, o.Commit, p.Commit))
+        case o.Hash != p.Hash:
+            out = append(out, fmt.Sprintf("~ %s@%s: sha256 %s -> %s", src, p.Version, o.Hash, p.Hash))
+        }
+    }
+    for src, o := range old {
+        if _, ok := cur[src]; !ok { out = append(out, "- "+o.ID()) }
+    }
+    edge := func(e LockEdge) string {
+        c := e.Constraint
+        if c == "" { c = "==latest" }
+        return fmt.Sprintf("edge %s -> %s (%s)", e.From, e.To, c)
+    }
+    key := func(e LockEdge) LockEdge { e.Version = ""; return e }
+    oldEdges := map[LockEdge]bool{}
+    for _, e := range have.Edges { oldEdges[key(e)] = true }
+    curEdges := map[LockEdge]bool{}
+    for _, e := range want.Edges { curEdges[key(e)] = true }
+    for e := range curEdges { if !oldEdges[e] { out = append(out, "+ "+edge(e)) } }
+    for e := range oldEdges { if !curEdges[e] { out = append(out, "- "+edge(e)) } }
+    sort.Strings(out)
+    return out
+}
@@ -0,0 +1,10 @@
+package workspace
+
+// LockEdge records that From required the import path To under Constraint
+// (empty for ==latest) and that Version was selected for it.
+type LockEdge struct {
+    From       string `json:"from"`
+    To         string `json:"to"`
+    Constraint string `json:"constraint,omitempty"`
+    Version    string `json:"version"`
+}
@@ -0,0 +1,30 @@
+package workspace
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+)
+
+// Load reads ami.lock from path.
+func (l *Lock) Load(path string) error {
+    b, err := os.ReadFile(path)
+    if err != nil { return err }
+    var in Lock
+    if err := json.Unmarshal(b, &in); err != nil { return fmt.Errorf("invalid ami.lock: %w", err) }
+    if in.Schema != LockSchema { return fmt.Errorf("invalid ami.lock: unsupported schema %q", in.Schema) }
+    *l = in
+    return nil
+}
+
+// Save writes the lock to path as indented JSON with packages and edges in
+// canonical order, so identical graphs produce identical files.
+func (l *Lock) Save(path string) error {
+    if l.Schema == "" { l.Schema = LockSchema }
+    if l.Packages == nil { l.Packages = []LockPackage{} }
+    if l.Edges == nil { l.Edges = []LockEdge{} }
+    l.sort()
+    b, err := json.MarshalIndent(l, "", "  ")
+    if err != nil { return err }
+    return os.WriteFile(path, append(b, '\n'), 0o644)
+}
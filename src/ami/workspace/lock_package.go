@@ -0,0 +1,16 @@
+package workspace
+
+// LockPackage records one selected remote import. Source is the import path
+// as written in ami.workspace (the repository URL for git sources) and Name
+// its cache/ami.sum name. Commit is the resolved commit for git sources;
+// Hash is the sha256 of the package contents as recorded in ami.sum.
+type LockPackage struct {
+    Name    string `json:"name"`
+    Source  string `json:"source"`
+    Version string `json:"version"`
+    Commit  string `json:"commit,omitempty"`
+    Hash    string `json:"sha256"`
+}
+
+// ID returns the node ID of the package in Lock.Edges.
+func (p LockPackage) ID() string { return p.Source + "@" + p.Version }
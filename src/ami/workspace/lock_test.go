@@ -0,0 +1,72 @@
+package workspace
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func testLock(t *testing.T) Lock {
+    t.Helper()
+    src := &fakeSource{
+        versions: map[string][]string{"util": {"v1.3.0"}, "log": {"v1.1.0", "v1.2.0"}},
+        imports:  map[string][]string{"util@v1.3.0": {"log ~1.1.0"}},
+    }
+    res, err := Resolve(wsImporting("util ^1.0.0", "log >= 1.0.0"), src)
+    if err != nil { t.Fatalf("resolve: %v", err) }
+    l, err := NewLock(res, func(path, version string) (LockPackage, error) {
+        return LockPackage{Name: path, Hash: "h-" + path + version}, nil
+    })
+    if err != nil { t.Fatalf("lock: %v", err) }
+    return l
+}
+
+func TestNewLock_RecordsPackagesAndEdges(t *testing.T) {
+    l := testLock(t)
+    wantPkgs := []LockPackage{
+        {Name: "log", Source: "log", Version: "v1.1.0", Hash: "h-logv1.1.0"},
+        {Name: "util", Source: "util", Version: "v1.3.0", Hash: "h-utilv1.3.0"},
+    }
+    if !reflect.DeepEqual(l.Packages, wantPkgs) { t.Fatalf("packages: %+v", l.Packages) }
+    wantEdges := []LockEdge{
+        {From: "app", To: "log", Constraint: ">= 1.0.0", Version: "v1.1.0"},
+        {From: "app", To: "util", Constraint: "^1.0.0", Version: "v1.3.0"},
+        {From: "util@v1.3.0", To: "log", Constraint: "~1.1.0", Version: "v1.1.0"},
+    }
+    if !reflect.DeepEqual(l.Edges, wantEdges) { t.Fatalf("edges: %+v", l.Edges) }
+}
+
+func TestLock_SaveLoadRoundTrip(t *testing.T) {
+    l := testLock(t)
+    path := filepath.Join(t.TempDir(), "ami.lock")
+    if err := l.Save(path); err != nil { t.Fatalf("save: %v", err) }
+    var got Lock
+    if err := got.Load(path); err != nil { t.Fatalf("load: %v", err) }
+    if !reflect.DeepEqual(got, l) { t.Fatalf("round trip:\n got %+v\nwant %+v", got, l) }
+    if err := os.WriteFile(path, []byte(`{"schema":"ami.lock/v0"}`), 0o644); err != nil { t.Fatal(err) }
+    if err := got.Load(path); err == nil { t.Fatalf("expected schema error") }
+}
+
+func TestLock_WhyListsEveryChain(t *testing.T) {
+    l := testLock(t)
+    want := [][]string{{"app", "log@v1.1.0"}, {"app", "util@v1.3.0", "log@v1.1.0"}}
+    if got := l.Why("log"); !reflect.DeepEqual(got, want) { t.Fatalf("why: %v", got) }
+    if got := l.Why("missing"); got != nil { t.Fatalf("why missing: %v", got) }
+}
+
+func TestLockDiff(t *testing.T) {
+    have := testLock(t)
+    if d := LockDiff(have, have); len(d) != 0 { t.Fatalf("self diff: %v", d) }
+    want := testLock(t)
+    want.Packages[0].Version = "v1.2.0"
+    want.Packages[1].Hash = "other"
+    want.Edges = want.Edges[:2]
+    got := LockDiff(have, want)
+    exp := []string{
+        "- edge util@v1.3.0 -> log (~1.1.0)",
+        "~ log: v1.1.0 -> v1.2.0",
+        "~ util@v1.3.0: sha256 h-utilv1.3.0 -> other",
+    }
+    if !reflect.DeepEqual(got, exp) { t.Fatalf("diff: %q", got) }
+}
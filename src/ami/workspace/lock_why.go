@@ -0,0 +1,46 @@
+package workspace
+
+import (
+    "sort"
+    "strings"
+)
+
+// Why returns every requirement chain from a workspace package to the locked
+// package whose name or source is pkg, e.g. ["app", "util@v1.3.0", "log@v1.1.0"].
+// Locked packages are shown as <name>@<version>. It returns nil when pkg is
+// not locked.
+func (l Lock) Why(pkg string) [][]string {
+    byID := map[string]LockPackage{}
+    var target string
+    for _, p := range l.Packages {
+        byID[p.ID()] = p
+        if p.Name == pkg || p.Source == pkg { target = p.ID() }
+    }
+    if target == "" { return nil }
+    label := func(id string) string {
+        if p, ok := byID[id]; ok { return p.Name + "@" + p.Version }
+        return id
+    }
+    into := map[string][]string{}
+    for _, e := range l.Edges {
+        to := e.To + "@" + e.Version
+        into[to] = append(into[to], e.From)
+    }
+    var out [][]string
+    var walk func(id string, tail []string, seen map[string]bool)
+    walk = func(id string, tail []string, seen map[string]bool) {
+        chain := append([]string{label(id)}, tail...)
+        if _, locked := byID[id]; !locked {
+            out = append(out, chain)
+            return
+        }
+        seen[id] = true
+        for _, from := range into[id] {
+            if !seen[from] { walk(from, chain, seen) }
+        }
+        delete(seen, id)
+    }
+    walk(target, nil, map[string]bool{})
+    sort.Slice(out, func(i, j int) bool { return strings.Join(out[i], " ") < strings.Join(out[j], " ") })
+    return out
+}
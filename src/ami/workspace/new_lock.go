@@ -0,0 +1,39 @@
+package workspace
+
+import "sort"
+
+// NewLock builds a Lock from a resolution. describe supplies the name,
+// commit and hash of each selected path@version; Source and Version are
+// filled in from the resolution. Requirements on unresolved paths are
+// omitted.
+func NewLock(res Resolution, describe func(path, version string) (LockPackage, error)) (Lock, error) {
+    l := Lock{Schema: LockSchema, Packages: []LockPackage{}, Edges: []LockEdge{}}
+    for path, ver := range res.Selected {
+        p, err := describe(path, ver)
+        if err != nil { return Lock{}, err }
+        p.Source, p.Version = path, ver
+        l.Packages = append(l.Packages, p)
+    }
+    seen := map[LockEdge]bool{}
+    for _, d := range res.Requirements {
+        ver, ok := res.Selected[d.Path]
+        if !ok || len(d.By) == 0 { continue }
+        e := LockEdge{From: d.By[len(d.By)-1], To: d.Path, Constraint: d.Constraint, Version: ver}
+        if seen[e] { continue }
+        seen[e] = true
+        l.Edges = append(l.Edges, e)
+    }
+    l.sort()
+    return l, nil
+}
+
+// sort orders packages by source and edges by requirer, target and constraint.
+func (l *Lock) sort() {
+    sort.Slice(l.Packages, func(i, j int) bool { return l.Packages[i].Source < l.Packages[j].Source })
+    sort.Slice(l.Edges, func(i, j int) bool {
+        a, b := l.Edges[i], l.Edges[j]
+        if a.From != b.From { return a.From < b.From }
+        if a.To != b.To { return a.To < b.To }
+        return a.Constraint < b.Constraint
+    })
+}
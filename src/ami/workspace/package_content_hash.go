@@ -0,0 +1,36 @@
+package workspace
+
+import (
+    "crypto/sha256"
+    "encoding/binary"
+    "encoding/hex"
+    "os"
+    "path/filepath"
+)
+
+// PackageContentHash returns the canonical content hash signed by ami.sig
+// over every file of the package except the root ami.sig itself and VCS
+// metadata directories (.git, .hg, .svn), which the package cache never
+// holds. Unlike HashDir each file is framed as a record (path length, path,
+// content length, content; lengths as big-endian uint64), so files cannot be
+// merged, split or renamed without changing the digest.
+func PackageContentHash(root string) (string, error) {
+    files, err := packageFiles(root,
+        func(name string) bool { return name == ".git" || name == ".hg" || name == ".svn" },
+        func(rel string) bool { return rel == SignatureFile })
+    if err != nil { return "", err }
+    h := sha256.New()
+    var n [8]byte
+    for _, rel := range files {
+        b, err := os.ReadFile(filepath.Join(root, rel))
+        if err != nil { return "", err }
+        rel = filepath.ToSlash(rel)
+        binary.BigEndian.PutUint64(n[:], uint64(len(rel)))
+        _, _ = h.Write(n[:])
+        _, _ = h.Write([]byte(rel))
+        binary.BigEndian.PutUint64(n[:], uint64(len(b)))
+        _, _ = h.Write(n[:])
+        _, _ = h.Write(b)
+    }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
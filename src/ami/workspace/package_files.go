@@ -0,0 +1,30 @@
+package workspace
+
+import (
+    "io/fs"
+    "path/filepath"
+    "sort"
+)
+
+// packageFiles returns the sorted relative paths of the regular files under
+// root. Directories (other than root) for which skipDir reports true are not
+// descended, and files for which skipFile reports true are omitted; either
+// may be nil.
+func packageFiles(root string, skipDir func(name string) bool, skipFile func(rel string) bool) ([]string, error) {
+    var files []string
+    err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+        if err != nil { return err }
+        if d.IsDir() {
+            if path != root && skipDir != nil && skipDir(d.Name()) { return filepath.SkipDir }
+            return nil
+        }
+        rel, err := filepath.Rel(root, path)
+        if err != nil { return err }
+        if skipFile != nil && skipFile(rel) { return nil }
+        files = append(files, rel)
+        return nil
+    })
+    if err != nil { return nil, err }
+    sort.Strings(files)
+    return files, nil
+}
@@ -0,0 +1,13 @@
+package workspace
+
+// Resolution is the outcome of Resolve. Selected maps each remote import path
+// to its chosen version and Chains to the requirers that introduced it;
+// Unresolved lists paths the VersionSource had no source for, sorted.
+// Requirements holds every demand of the final selection: workspace imports
+// first, then the imports of each selected version in selection order.
+type Resolution struct {
+    Selected     map[string]string
+    Chains       map[string][]string
+    Unresolved   []string
+    Requirements []Demand
+}
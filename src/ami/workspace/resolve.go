@@ -0,0 +1,194 @@
+package workspace
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    amisemver "github.com/sam-caldwell/ami/src/ami/semver"
+)
+
+// Resolve selects one version for every remote import reachable from the
+// workspace packages so that all constraints hold: those declared in
+// ami.workspace and those declared by the selected versions themselves.
+// Import paths are decided in the order they are first required and
+// candidates tried highest first (pre-releases only when a constraint on the path names one), with
+// backtracking when a choice leads to a conflict. Constraint bounds come from
+// semver.Bounds and are combined with semver.Intersect. When no consistent
+// set exists, the first conflict found is returned as a *ResolveConflict;
+// source and constraint syntax errors are returned as-is.
+func Resolve(ws *Workspace, src VersionSource) (Resolution, error) {
+    r := &resolver{
+        src: src, selected: map[string]string{}, chains: map[string][]string{},
+        versions: map[string][]string{}, known: map[string]bool{}, imports: map[string][]Demand{}, unresolved: map[string]bool{},
+    }
+    for _, e := range ws.Packages {
+        p := e.Package
+        NormalizeImports(&p)
+        name := p.Name
+        if name == "" { name = e.Key }
+        ds, err := remoteDemands(p.Import, []string{name})
+        if err != nil { return Resolution{}, err }
+        r.roots = append(r.roots, ds...)
+    }
+    if err := r.solve(); err != nil { return Resolution{}, err }
+    res := Resolution{Selected: r.selected, Chains: r.chains, Requirements: r.demands()}
+    for p := range r.unresolved { res.Unresolved = append(res.Unresolved, p) }
+    sort.Strings(res.Unresolved)
+    return res, nil
+}
+
+// resolver holds the search state of Resolve. order records selection order so
+// demands are listed in the order they were introduced.
+type resolver struct {
+    src        VersionSource
+    roots      []Demand
+    selected   map[string]string
+    order      []string
+    chains     map[string][]string
+    versions   map[string][]string
+    known      map[string]bool
+    imports    map[string][]Demand
+    unresolved map[string]bool
+}
+
+// demands returns the workspace demands followed by those of each selected version.
+func (r *resolver) demands() []Demand {
+    out := append([]Demand(nil), r.roots...)
+    for _, p := range r.order {
+        for _, d := range r.imports[p+"@"+r.selected[p]] {
+            d.By = r.chains[p]
+            out = append(out, d)
+        }
+    }
+    return out
+}
+
+func (r *resolver) solve() error {
+    byPath := map[string][]Demand{}
+    next := ""
+    for _, d := range r.demands() {
+        byPath[d.Path] = append(byPath[d.Path], d)
+        if _, ok := r.selected[d.Path]; next == "" && !ok && !r.unresolved[d.Path] { next = d.Path }
+    }
+    if next == "" { return nil }
+    vers, err := r.available(next)
+    if err != nil { return err }
+    if !r.known[next] {
+        r.unresolved[next] = true
+        err := r.solve()
+        // a backtracking caller may pick versions that never demand next
+        if err != nil { delete(r.unresolved, next) }
+        return err
+    }
+    demands := byPath[next]
+    cands := candidates(vers, demands)
+    if len(cands) == 0 { return &ResolveConflict{Path: next, Demands: demands, Available: vers} }
+    var first error
+    for _, v := range cands {
+        if err := r.loadImports(next, v); err != nil { return err }
+        r.selected[next] = v
+        r.order = append(r.order, next)
+        r.chains[next] = append(append([]string(nil), demands[0].By...), next+"@"+v)
+        err := r.check()
+        if err == nil { err = r.solve() }
+        if err == nil { return nil }
+        if _, ok := err.(*ResolveConflict); !ok { return err }
+        if first == nil { first = err }
+        delete(r.selected, next)
+        delete(r.chains, next)
+        r.order = r.order[:len(r.order)-1]
+    }
+    return first
+}
+
+// check verifies that every selected version satisfies the current demands on it.
+func (r *resolver) check() error {
+    byPath := map[string][]Demand{}
+    for _, d := range r.demands() { byPath[d.Path] = append(byPath[d.Path], d) }
+    for _, p := range r.order {
+        v, _ := amisemver.ParseVersion(r.selected[p])
+        for _, d := range byPath[p] {
+            if b := demandBound(d); !amisemver.Contains(b, v) {
+                return &ResolveConflict{Path: p, Demands: byPath[p], Available: r.versions[p]}
+            }
+        }
+    }
+    return nil
+}
+
+func (r *resolver) available(path string) ([]string, error) {
+    if vers, ok := r.versions[path]; ok { return vers, nil }
+    vers, known, err := r.src.Versions(path)
+    if err != nil { return nil, fmt.Errorf("list versions of %s: %w", path, err) }
+    r.versions[path], r.known[path] = vers, known
+    return vers, nil
+}
+
+func (r *resolver) loadImports(path, version string) error {
+    key := path + "@" + version
+    if _, ok := r.imports[key]; ok { return nil }
+    entries, err := r.src.Imports(path, version)
+    if err != nil { return fmt.Errorf("read imports of %s: %w", key, err) }
+    ds, err := remoteDemands(entries, []string{key})
+    if err != nil { return err }
+    if ds == nil { ds = []Demand{} }
+    r.imports[key] = ds
+    return nil
+}
+
+// remoteDemands converts the remote entries of an import list into demands by
+// the given requirers, validating constraint syntax.
+func remoteDemands(entries []string, by []string) ([]Demand, error) {
+    var out []Demand
+    for _, ent := range entries {
+        path, c := ParseImportEntry(ent)
+        if path == "" || strings.HasPrefix(path, "./") { continue }
+        if c != "" {
+            if _, err := amisemver.ParseConstraint(c); err != nil {
+                return nil, fmt.Errorf("%s: import %q: %v", strings.Join(by, " -> "), ent, err)
+            }
+        }
+        out = append(out, Demand{Path: path, Constraint: c, By: by})
+    }
+    return out, nil
+}
+
+// candidates returns the versions inside every demand's bound, highest first.
+func candidates(versions []string, demands []Demand) []string {
+    b := amisemver.Bound{LowerInclusive: true}
+    pre := false
+    for _, d := range demands {
+        var ok bool
+        if b, ok = amisemver.Intersect(b, demandBound(d)); !ok { return nil }
+        if strings.Contains(d.Constraint, "-") { pre = true }
+    }
+    type cand struct {
+        raw string
+        v   amisemver.Version
+    }
+    var cs []cand
+    seen := map[string]bool{}
+    for _, raw := range versions {
+        if !amisemver.ValidateVersion(raw) { continue }
+        v, err := amisemver.ParseVersion(raw)
+        if err != nil || (v.Pre != "" && !pre) || !amisemver.Contains(b, v) { continue }
+        k := fmt.Sprintf("%d.%d.%d-%s", v.Major, v.Minor, v.Patch, v.Pre)
+        if seen[k] { continue }
+        seen[k] = true
+        cs = append(cs, cand{raw, v})
+    }
+    sort.SliceStable(cs, func(i, j int) bool { return amisemver.Compare(cs[i].v, cs[j].v) > 0 })
+    out := make([]string, len(cs))
+    for i, c := range cs { out[i] = c.raw }
+    return out
+}
+
+// demandBound returns the version interval a demand allows; ==latest and
+// empty constraints are unbounded.
+func demandBound(d Demand) amisemver.Bound {
+    if c, err := amisemver.ParseConstraint(d.Constraint); err == nil {
+        if b, ok := amisemver.Bounds(c); ok { return b }
+    }
+    return amisemver.Bound{LowerInclusive: true}
+}
@@ -0,0 +1,18 @@
+package workspace
+
+import "strings"
+
+// ResolveConflict reports a remote package for which no available version
+// satisfies every constraint placed on it. Demands lists those constraints in
+// the order they were introduced; Available lists the versions considered.
+type ResolveConflict struct {
+    Path      string
+    Demands   []Demand
+    Available []string
+}
+
+func (e *ResolveConflict) Error() string {
+    parts := make([]string, len(e.Demands))
+    for i, d := range e.Demands { parts[i] = d.String() }
+    return "unsatisfiable constraints on " + e.Path + ": " + strings.Join(parts, ", ")
+}
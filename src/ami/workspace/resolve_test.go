@@ -0,0 +1,89 @@
+package workspace
+
+import (
+    "errors"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+// fakeSource serves versions and per-version imports from maps.
+type fakeSource struct {
+    versions map[string][]string
+    imports  map[string][]string // key: path@version
+    calls    []string
+}
+
+func (f *fakeSource) Versions(path string) ([]string, bool, error) {
+    v, ok := f.versions[path]
+    return v, ok, nil
+}
+
+func (f *fakeSource) Imports(path, version string) ([]string, error) {
+    f.calls = append(f.calls, path+"@"+version)
+    return f.imports[path+"@"+version], nil
+}
+
+func wsImporting(imports ...string) *Workspace {
+    return &Workspace{Packages: PackageList{{Key: "main", Package: Package{Name: "app", Version: "1.0.0", Root: "./src", Import: append([]string{"./lib"}, imports...)}}}}
+}
+
+func TestResolve_TransitiveHighestSatisfying(t *testing.T) {
+    src := &fakeSource{
+        versions: map[string][]string{
+            "util": {"v1.0.0", "v1.3.0", "v2.0.0", "v1.4.0-rc.1"},
+            "log":  {"v0.9.0", "v1.1.0", "v1.2.0"},
+        },
+        imports: map[string][]string{"util@v1.3.0": {"log ~1.1.0", "./internal"}},
+    }
+    res, err := Resolve(wsImporting("util ^1.0.0", "log"), src)
+    if err != nil { t.Fatalf("resolve: %v", err) }
+    if !reflect.DeepEqual(res.Selected, map[string]string{"util": "v1.3.0", "log": "v1.1.0"}) { t.Fatalf("selected: %v", res.Selected) }
+    if strings.Join(res.Chains["log"], " -> ") != "app -> log@v1.1.0" { t.Fatalf("chain: %v", res.Chains["log"]) }
+}
+
+func TestResolve_BacktracksToCompatibleVersion(t *testing.T) {
+    // util@v1.3.0 needs log ^2, which conflicts with the workspace; v1.2.0 fits.
+    src := &fakeSource{
+        versions: map[string][]string{"util": {"v1.2.0", "v1.3.0"}, "log": {"v1.0.0", "v2.0.0"}},
+        imports:  map[string][]string{"util@v1.3.0": {"log ^2.0.0"}, "util@v1.2.0": {"log >=1.0.0"}},
+    }
+    res, err := Resolve(wsImporting("util ^1.0.0", "log ^1.0.0"), src)
+    if err != nil { t.Fatalf("resolve: %v", err) }
+    if res.Selected["util"] != "v1.2.0" || res.Selected["log"] != "v1.0.0" { t.Fatalf("selected: %v", res.Selected) }
+}
+
+func TestResolve_ConflictReportsRequirerChains(t *testing.T) {
+    src := &fakeSource{
+        versions: map[string][]string{"util": {"v1.3.0"}, "log": {"v1.0.0", "v2.0.0"}},
+        imports:  map[string][]string{"util@v1.3.0": {"log >= 2.0.0"}},
+    }
+    _, err := Resolve(wsImporting("log ^1.0.0", "util"), src)
+    var rc *ResolveConflict
+    if !errors.As(err, &rc) { t.Fatalf("want conflict, got %v", err) }
+    if rc.Path != "log" || len(rc.Demands) != 2 { t.Fatalf("conflict: %+v", rc) }
+    want := "unsatisfiable constraints on log: ^1.0.0 (app), >= 2.0.0 (app -> util@v1.3.0)"
+    if rc.Error() != want { t.Fatalf("message:\n got %s\nwant %s", rc.Error(), want) }
+}
+
+func TestResolve_UnknownPathsAndErrors(t *testing.T) {
+    src := &fakeSource{versions: map[string][]string{"util": {"v1.0.0"}}}
+    res, err := Resolve(wsImporting("util", "ghost ^1.0.0"), src)
+    if err != nil { t.Fatalf("resolve: %v", err) }
+    if !reflect.DeepEqual(res.Unresolved, []string{"ghost"}) || res.Selected["util"] != "v1.0.0" { t.Fatalf("res: %+v", res) }
+    if _, err := Resolve(wsImporting("util <=1.0.0"), src); err == nil || !strings.Contains(err.Error(), `app: import "util <=1.0.0"`) { t.Fatalf("syntax: %v", err) }
+    _, err = Resolve(wsImporting("util ^3.0.0"), src)
+    var rc *ResolveConflict
+    if !errors.As(err, &rc) || !reflect.DeepEqual(rc.Available, []string{"v1.0.0"}) { t.Fatalf("no candidates: %v", err) }
+}
+
+func TestResolve_BacktrackForgetsUnresolved(t *testing.T) {
+    // util@v1.3.0 imports the unknown ghost and then conflicts; v1.2.0 does not need ghost.
+    src := &fakeSource{
+        versions: map[string][]string{"util": {"v1.2.0", "v1.3.0"}, "log": {"v1.0.0"}},
+        imports:  map[string][]string{"util@v1.3.0": {"ghost", "log ^2.0.0"}, "util@v1.2.0": {"log ^1.0.0"}},
+    }
+    res, err := Resolve(wsImporting("util"), src)
+    if err != nil { t.Fatalf("resolve: %v", err) }
+    if res.Selected["util"] != "v1.2.0" || len(res.Unresolved) != 0 { t.Fatalf("res: %+v", res) }
+}
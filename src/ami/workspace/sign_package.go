@@ -0,0 +1,28 @@
+package workspace
+
+import (
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/json"
+    "os"
+    "path/filepath"
+)
+
+// SignPackage signs the package rooted at dir as release pkg (name@version)
+// with priv and writes the detached signature to dir/ami.sig, replacing any
+// previous one.
+func SignPackage(dir, pkg string, priv ed25519.PrivateKey) (Signature, error) {
+    h, err := PackageContentHash(dir)
+    if err != nil { return Signature{}, err }
+    sig := Signature{
+        Schema:    SignatureSchema,
+        Package:   pkg,
+        PublicKey: base64.StdEncoding.EncodeToString(priv.Public().(ed25519.PublicKey)),
+        Sha256:    h,
+        Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, signedMessage(pkg, h))),
+    }
+    b, err := json.MarshalIndent(sig, "", "  ")
+    if err != nil { return Signature{}, err }
+    if err := os.WriteFile(filepath.Join(dir, SignatureFile), append(b, '\n'), 0o644); err != nil { return Signature{}, err }
+    return sig, nil
+}
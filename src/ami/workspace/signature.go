@@ -0,0 +1,24 @@
+package workspace
+
+// SignatureFile is the name of the detached signature at a package root.
+const SignatureFile = "ami.sig"
+
+// SignatureSchema identifies the ami.sig format.
+const SignatureSchema = "ami.sig/v1"
+
+// Signature is the content of ami.sig: an ed25519 signature by PublicKey
+// (standard base64) over the message "ami.sig/v1 <Package> sha256:<Sha256>",
+// where Package is the signed release as name@version and Sha256 is the
+// package's canonical content hash (see PackageContentHash). Binding the
+// release keeps a signature from verifying for another package or version.
+type Signature struct {
+    Schema    string `json:"schema"`
+    Package   string `json:"package"`
+    PublicKey string `json:"publicKey"`
+    Sha256    string `json:"sha256"`
+    Signature string `json:"signature"`
+}
+
+// signedMessage returns the bytes covered by a signature of release pkg
+// (name@version) with content hash h.
+func signedMessage(pkg, h string) []byte { return []byte(SignatureSchema + " " + pkg + " sha256:" + h) }
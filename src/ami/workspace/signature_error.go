@@ -0,0 +1,15 @@
+package workspace
+
+// SignatureError reports a package that failed signature verification.
+// Reason is one of: missing, invalid, package, untrusted, digest, signature.
+type SignatureError struct {
+    Package string
+    Reason  string
+    Detail  string
+}
+
+func (e *SignatureError) Error() string {
+    msg := "signature " + e.Reason + " for " + e.Package
+    if e.Detail != "" { msg += ": " + e.Detail }
+    return msg
+}
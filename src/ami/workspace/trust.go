@@ -0,0 +1,17 @@
+package workspace
+
+// Trust configures package signature verification (ami.workspace `trust:`).
+// When Keys is non-empty, every dependency must carry an ami.sig signed by one
+// of them; AllowUnsigned relaxes that to checking only signed dependencies.
+type Trust struct {
+    Require       bool         `yaml:"require,omitempty"`
+    AllowUnsigned bool         `yaml:"allowUnsigned,omitempty"`
+    Keys          []TrustedKey `yaml:"keys,omitempty"`
+}
+
+// Enabled reports whether any verification is configured.
+func (t Trust) Enabled() bool { return t.Require || len(t.Keys) > 0 }
+
+// Required reports whether unsigned dependencies are rejected: always when
+// Require is set, and by default whenever trust keys are configured.
+func (t Trust) Required() bool { return t.Require || (len(t.Keys) > 0 && !t.AllowUnsigned) }
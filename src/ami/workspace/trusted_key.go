@@ -0,0 +1,21 @@
+package workspace
+
+import (
+    "crypto/ed25519"
+    "encoding/base64"
+    "fmt"
+)
+
+// TrustedKey names a publisher's ed25519 public key (standard base64).
+type TrustedKey struct {
+    Name string `yaml:"name"`
+    Key  string `yaml:"key"`
+}
+
+// PublicKey decodes Key.
+func (k TrustedKey) PublicKey() (ed25519.PublicKey, error) {
+    b, err := base64.StdEncoding.DecodeString(k.Key)
+    if err != nil { return nil, fmt.Errorf("key is not base64: %v", err) }
+    if len(b) != ed25519.PublicKeySize { return nil, fmt.Errorf("key must be %d bytes, got %d", ed25519.PublicKeySize, len(b)) }
+    return ed25519.PublicKey(b), nil
+}
@@ -75,6 +75,19 @@ func (w Workspace) Validate() []string {
             errs = append(errs, fmt.Sprintf("packages.%s.root must be workspace-relative and not escape", p.Key))
         }
     }
+    // trust: every key must decode to an ed25519 public key; require needs keys
+    // and excludes allowUnsigned
+    for i, k := range w.Trust.Keys {
+        if _, err := k.PublicKey(); err != nil {
+            errs = append(errs, fmt.Sprintf("trust.keys[%d] (%s): %v", i, k.Name, err))
+        }
+    }
+    if w.Trust.Require && len(w.Trust.Keys) == 0 {
+        errs = append(errs, "trust.require needs at least one trust.keys entry")
+    }
+    if w.Trust.Require && w.Trust.AllowUnsigned {
+        errs = append(errs, "trust.require and trust.allowUnsigned are mutually exclusive")
+    }
     // packages.main requirement relaxed for current phase.
     return errs
 }
@@ -0,0 +1,56 @@
+package workspace
+
+import (
+    "bytes"
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "os"
+    "path/filepath"
+)
+
+// VerifyPackage checks the ami.sig of the package rooted at dir, expected to be
+// release pkg (name@version), against the trust configuration. It returns the
+// signer's key name, or "" when verification is disabled (see Trust.Enabled)
+// or the package is unsigned and t does not require signatures (see
+// Trust.Required). Failures are *SignatureError:
+//   missing    no ami.sig while signatures are required
+//   invalid    ami.sig cannot be parsed
+//   package    ami.sig was issued for another name@version
+//   untrusted  the signing key is not in t.Keys
+//   digest     the package content does not match the signed hash
+//   signature  the signature does not verify
+func VerifyPackage(dir, pkg string, t Trust) (string, error) {
+    if !t.Enabled() { return "", nil }
+    b, err := os.ReadFile(filepath.Join(dir, SignatureFile))
+    if errors.Is(err, os.ErrNotExist) {
+        if t.Required() { return "", &SignatureError{Package: pkg, Reason: "missing"} }
+        return "", nil
+    }
+    if err != nil { return "", err }
+    var sig Signature
+    if err := json.Unmarshal(b, &sig); err != nil || sig.Schema != SignatureSchema {
+        detail := "unsupported schema"
+        if err != nil { detail = err.Error() }
+        return "", &SignatureError{Package: pkg, Reason: "invalid", Detail: detail}
+    }
+    if sig.Package != pkg { return "", &SignatureError{Package: pkg, Reason: "package", Detail: "signed for " + sig.Package} }
+    pub, err1 := base64.StdEncoding.DecodeString(sig.PublicKey)
+    raw, err2 := base64.StdEncoding.DecodeString(sig.Signature)
+    if err1 != nil || err2 != nil || len(pub) != ed25519.PublicKeySize {
+        return "", &SignatureError{Package: pkg, Reason: "invalid", Detail: "malformed key or signature"}
+    }
+    signer := ""
+    for _, k := range t.Keys {
+        if kb, err := k.PublicKey(); err == nil && bytes.Equal(kb, pub) { signer = k.Name; break }
+    }
+    if signer == "" { return "", &SignatureError{Package: pkg, Reason: "untrusted", Detail: "key " + sig.PublicKey} }
+    h, err := PackageContentHash(dir)
+    if err != nil { return "", err }
+    if h != sig.Sha256 { return "", &SignatureError{Package: pkg, Reason: "digest", Detail: "signed " + sig.Sha256 + ", content " + h} }
+    if !ed25519.Verify(ed25519.PublicKey(pub), signedMessage(pkg, h), raw) {
+        return "", &SignatureError{Package: pkg, Reason: "signature"}
+    }
+    return signer, nil
+}
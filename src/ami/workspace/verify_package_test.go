@@ -0,0 +1,117 @@
+package workspace
+
+import (
+    "crypto/ed25519"
+    "encoding/base64"
+    "errors"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func newSignedPackage(t *testing.T) (string, ed25519.PrivateKey, TrustedKey) {
+    t.Helper()
+    pub, priv, err := ed25519.GenerateKey(nil)
+    if err != nil { t.Fatalf("keygen: %v", err) }
+    dir := t.TempDir()
+    if err := os.WriteFile(filepath.Join(dir, "lib.ami"), []byte("package lib\n"), 0o644); err != nil { t.Fatal(err) }
+    if _, err := SignPackage(dir, "lib@v1.0.0", priv); err != nil { t.Fatalf("sign: %v", err) }
+    return dir, priv, TrustedKey{Name: "acme", Key: base64.StdEncoding.EncodeToString(pub)}
+}
+
+func signatureReason(err error) string {
+    var se *SignatureError
+    if errors.As(err, &se) { return se.Reason }
+    return ""
+}
+
+func TestVerifyPackage_TrustedSignature(t *testing.T) {
+    dir, _, key := newSignedPackage(t)
+    signer, err := VerifyPackage(dir, "lib@v1.0.0", Trust{Keys: []TrustedKey{key}})
+    if err != nil || signer != "acme" { t.Fatalf("verify: %q %v", signer, err) }
+    // the signature file itself is excluded from the signed hash
+    h1, _ := PackageContentHash(dir)
+    _ = os.Remove(filepath.Join(dir, SignatureFile))
+    if h2, _ := PackageContentHash(dir); h1 != h2 { t.Fatalf("content hash depends on ami.sig") }
+}
+
+func TestVerifyPackage_Failures(t *testing.T) {
+    dir, _, key := newSignedPackage(t)
+    other, _, _ := ed25519.GenerateKey(nil)
+    untrusted := Trust{Keys: []TrustedKey{{Name: "other", Key: base64.StdEncoding.EncodeToString(other)}}}
+    if _, err := VerifyPackage(dir, "lib@v1.0.0", untrusted); signatureReason(err) != "untrusted" { t.Fatalf("untrusted: %v", err) }
+
+    trust := Trust{Require: true, Keys: []TrustedKey{key}}
+    if err := os.WriteFile(filepath.Join(dir, "lib.ami"), []byte("package lib // changed\n"), 0o644); err != nil { t.Fatal(err) }
+    _, err := VerifyPackage(dir, "lib@v1.0.0", trust)
+    if signatureReason(err) != "digest" || !strings.Contains(err.Error(), "signature digest for lib@v1.0.0") { t.Fatalf("digest: %v", err) }
+
+    if err := os.WriteFile(filepath.Join(dir, SignatureFile), []byte("{"), 0o644); err != nil { t.Fatal(err) }
+    if _, err := VerifyPackage(dir, "lib@v1.0.0", trust); signatureReason(err) != "invalid" { t.Fatalf("invalid: %v", err) }
+
+    _ = os.Remove(filepath.Join(dir, SignatureFile))
+    if _, err := VerifyPackage(dir, "lib@v1.0.0", trust); signatureReason(err) != "missing" { t.Fatalf("missing: %v", err) }
+    // trust keys alone require signatures; allowUnsigned opts out
+    trust.Require = false
+    if _, err := VerifyPackage(dir, "lib@v1.0.0", trust); signatureReason(err) != "missing" { t.Fatalf("missing with keys: %v", err) }
+    trust.AllowUnsigned = true
+    if signer, err := VerifyPackage(dir, "lib@v1.0.0", trust); err != nil || signer != "" { t.Fatalf("unsigned allowed: %q %v", signer, err) }
+}
+
+// A signature binds the release: it does not verify for another version or
+// package, even with an identical tree.
+func TestVerifyPackage_BindsNameAndVersion(t *testing.T) {
+    dir, priv, key := newSignedPackage(t)
+    trust := Trust{Keys: []TrustedKey{key}}
+    for _, pkg := range []string{"lib@v2.0.0", "other@v1.0.0"} {
+        _, err := VerifyPackage(dir, pkg, trust)
+        if signatureReason(err) != "package" || !strings.Contains(err.Error(), "signed for lib@v1.0.0") { t.Fatalf("%s: %v", pkg, err) }
+    }
+    // rewriting the recorded package does not help: the message covers it
+    if _, err := SignPackage(dir, "lib@v1.0.0", priv); err != nil { t.Fatalf("sign: %v", err) }
+    b, _ := os.ReadFile(filepath.Join(dir, SignatureFile))
+    retagged := strings.Replace(string(b), `"package": "lib@v1.0.0"`, `"package": "lib@v2.0.0"`, 1)
+    if err := os.WriteFile(filepath.Join(dir, SignatureFile), []byte(retagged), 0o644); err != nil { t.Fatal(err) }
+    if _, err := VerifyPackage(dir, "lib@v2.0.0", trust); signatureReason(err) != "signature" { t.Fatalf("retagged: %v", err) }
+}
+
+func TestVerifyPackage_ForgedSignature(t *testing.T) {
+    dir, _, key := newSignedPackage(t)
+    b, _ := os.ReadFile(filepath.Join(dir, SignatureFile))
+    // keep the trusted public key but substitute a signature by another key
+    _, other, _ := ed25519.GenerateKey(nil)
+    h, _ := PackageContentHash(dir)
+    forged := base64.StdEncoding.EncodeToString(ed25519.Sign(other, signedMessage("lib@v1.0.0", h)))
+    s := string(b)
+    i := strings.Index(s, `"signature": "`) + len(`"signature": "`)
+    s = s[:i] + forged + s[i+strings.Index(s[i:], `"`):]
+    if err := os.WriteFile(filepath.Join(dir, SignatureFile), []byte(s), 0o644); err != nil { t.Fatal(err) }
+    if _, err := VerifyPackage(dir, "lib@v1.0.0", Trust{Keys: []TrustedKey{key}}); signatureReason(err) != "signature" { t.Fatalf("forged: %v", err) }
+}
+
+func TestWorkspace_ValidateTrust(t *testing.T) {
+    ws := DefaultWorkspace()
+    ws.Trust = Trust{Require: true}
+    ws.Trust.Keys = []TrustedKey{{Name: "bad", Key: "not-base64!"}}
+    errs := strings.Join(ws.Validate(), "\n")
+    if !strings.Contains(errs, "trust.keys[0] (bad)") { t.Fatalf("errors: %s", errs) }
+    ws.Trust.Keys = nil
+    if errs := strings.Join(ws.Validate(), "\n"); !strings.Contains(errs, "trust.require needs") { t.Fatalf("errors: %s", errs) }
+    ws.Trust.AllowUnsigned = true
+    if errs := strings.Join(ws.Validate(), "\n"); !strings.Contains(errs, "mutually exclusive") { t.Fatalf("errors: %s", errs) }
+}
+
+// Merging files must change the signed digest even when the concatenation
+// of paths and contents is unchanged.
+func TestVerifyPackage_MergedFilesFailDigest(t *testing.T) {
+    pub, priv, _ := ed25519.GenerateKey(nil)
+    key := TrustedKey{Name: "acme", Key: base64.StdEncoding.EncodeToString(pub)}
+    dir := t.TempDir()
+    if err := os.WriteFile(filepath.Join(dir, "a.ami"), []byte("X"), 0o644); err != nil { t.Fatal(err) }
+    if err := os.WriteFile(filepath.Join(dir, "b.ami"), []byte("Y"), 0o644); err != nil { t.Fatal(err) }
+    if _, err := SignPackage(dir, "lib@v1.0.0", priv); err != nil { t.Fatalf("sign: %v", err) }
+    _ = os.Remove(filepath.Join(dir, "b.ami"))
+    if err := os.WriteFile(filepath.Join(dir, "a.ami"), []byte("Xb.amiY"), 0o644); err != nil { t.Fatal(err) }
+    if _, err := VerifyPackage(dir, "lib@v1.0.0", Trust{Keys: []TrustedKey{key}}); signatureReason(err) != "digest" { t.Fatalf("merged: %v", err) }
+}
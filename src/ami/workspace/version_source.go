@@ -0,0 +1,12 @@
+package workspace
+
+// VersionSource supplies what Resolve needs to know about remote packages.
+// Versions lists the versions available for an import path; known=false means
+// the source has nothing for that path, which Resolve reports as unresolved
+// rather than as a conflict. Imports returns the import entries declared by
+// the packages of path at version (local "./" entries included; Resolve
+// ignores them).
+type VersionSource interface {
+    Versions(path string) (versions []string, known bool, err error)
+    Imports(path, version string) ([]string, error)
+}
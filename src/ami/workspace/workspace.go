@@ -6,4 +6,5 @@ type Workspace struct {
     Version   string     `yaml:"version"`
     Toolchain Toolchain  `yaml:"toolchain"`
     Packages  PackageList `yaml:"packages"`
+    Trust     Trust       `yaml:"trust,omitempty"`
 }
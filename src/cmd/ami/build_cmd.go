@@ -13,6 +13,7 @@ var buildDebugStrict bool
 var buildBackend string
 var buildNoErrorPipe bool
 var buildErrorPipeHuman bool
+var buildLocked bool
 
 // newBuildCmd returns the `ami build` subcommand.
 func newBuildCmd() *cobra.Command {
@@ -20,7 +21,7 @@ func newBuildCmd() *cobra.Command {
     cmd := &cobra.Command{
         Use:   "build",
         Short: "Validate workspace and build (phase: validation)",
-        Example: "\n  # Human output\n  ami build\n\n  # JSON diagnostics (machine-parsable)\n  ami build --json\n\n  # Write debug artifacts under build/debug/\n  ami build --verbose\n",
+        Example: "\n  # Human output\n  ami build\n\n  # JSON diagnostics (machine-parsable)\n  ami build --json\n\n  # Write debug artifacts under build/debug/\n  ami build --verbose\n\n  # Fail unless dependency resolution reproduces ami.lock (CI)\n  ami build --locked\n",
         RunE: func(cmd *cobra.Command, args []string) error {
             // Resolve absolute working directory for deterministic behavior.
             wd, err := os.Getwd()
@@ -37,5 +38,6 @@ func newBuildCmd() *cobra.Command {
     cmd.Flags().StringVar(&buildBackend, "backend", "", "codegen backend to use (overrides workspace); e.g., 'llvm'")
     cmd.Flags().BoolVar(&buildNoErrorPipe, "no-errorpipe", false, "suppress default ErrorPipeline emission on compiler errors")
     cmd.Flags().BoolVar(&buildErrorPipeHuman, "errorpipe-human", false, "also echo concise human error lines to stderr when compiler errors occur")
+    cmd.Flags().BoolVar(&buildLocked, "locked", false, "fail if dependency resolution would change ami.lock")
     return cmd
 }
@@ -170,6 +170,11 @@ func runBuildImpl(out io.Writer, dir string, jsonOut bool, verbose bool) error {
     _ = codegen.SelectDefaultBackend(backendName)
 
 
+    // --locked: resolution must reproduce ami.lock exactly
+    if buildLocked {
+        if err := runLockedCheck(out, dir, jsonOut); err != nil { return err }
+    }
+
     // For this phase, stop after validation.
     // Enforce dependency availability per workspace requirements (scaffold via audit).
     rep, aerr := workspace.AuditDependencies(dir)
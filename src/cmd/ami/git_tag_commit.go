@@ -0,0 +1,28 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "strings"
+)
+
+// gitTagCommit returns the commit id a tag points to in a repo URL or local
+// path, using `git ls-remote` and preferring the peeled (^{}) entry so that
+// annotated tags resolve to their commit rather than the tag object.
+func gitTagCommit(cloneArg, tag string) (string, error) {
+    ref := "refs/tags/" + tag
+    cmd := exec.Command("git", "ls-remote", cloneArg, ref, ref+"^{}")
+    cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_SSH_COMMAND=ssh -oBatchMode=yes -oStrictHostKeyChecking=no -oConnectTimeout=2")
+    out, err := cmd.CombinedOutput()
+    if err != nil { return "", fmt.Errorf("git ls-remote: %v: %s", err, string(out)) }
+    id := ""
+    for _, line := range strings.Split(string(out), "\n") {
+        f := strings.Fields(line)
+        if len(f) != 2 { continue }
+        if f[1] == ref+"^{}" { return f[0], nil }
+        if f[1] == ref { id = f[0] }
+    }
+    if id == "" { return "", fmt.Errorf("tag %s not found", tag) }
+    return id, nil
+}
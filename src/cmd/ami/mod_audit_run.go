@@ -28,11 +28,18 @@ func runModAudit(out io.Writer, dir string, jsonOut bool) error {
         MissingInCache:  rep.MissingInCache,
         Mismatched:      rep.Mismatched,
         ParseErrors:     rep.ParseErrors,
+        SignatureFailures: rep.SignatureFailures,
         SumFound:        rep.SumFound,
         Timestamp:       time.Now().UTC().Format(time.RFC3339Nano),
     }
+    // Signature failures are fatal: the publisher of a dependency is not trusted.
+    var sigErr error
+    if len(res.SignatureFailures) > 0 {
+        sigErr = exit.New(exit.Signature, "signature verification failed: %s", strings.Join(res.SignatureFailures, "; "))
+    }
     if jsonOut {
-        return json.NewEncoder(out).Encode(res)
+        if err := json.NewEncoder(out).Encode(res); err != nil { return err }
+        return sigErr
     }
     // Human summary
     if len(res.ParseErrors) > 0 {
@@ -54,8 +61,11 @@ func runModAudit(out io.Writer, dir string, jsonOut bool) error {
     if len(res.Mismatched) > 0 {
         _, _ = fmt.Fprintf(out, "mismatched: %s\n", strings.Join(res.Mismatched, ", "))
     }
-    if len(res.MissingInSum)+len(res.Unsatisfied)+len(res.MissingInCache)+len(res.Mismatched)+len(res.ParseErrors) == 0 {
+    for _, f := range res.SignatureFailures {
+        _, _ = fmt.Fprintf(out, "%s\n", f)
+    }
+    if len(res.MissingInSum)+len(res.Unsatisfied)+len(res.MissingInCache)+len(res.Mismatched)+len(res.ParseErrors)+len(res.SignatureFailures) == 0 {
         _, _ = fmt.Fprintln(out, "ok: all requirements satisfied and present in cache")
     }
-    return nil
+    return sigErr
 }
@@ -10,10 +10,12 @@ import (
     "strings"
 
     "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
 )
 
 // modGetGit clones a git repository (git+ssh or file+git) at a specific tag
-// into the package cache and updates ami.sum accordingly.
+// into the package cache and updates ami.sum accordingly. When the workspace
+// configures trust, the package must pass signature verification first.
 func modGetGit(out io.Writer, dir string, src string, jsonOut bool) error {
     // Parse URL and optional tag
     var repoURL, tag string
@@ -56,6 +58,14 @@ func modGetGit(out io.Writer, dir string, src string, jsonOut bool) error {
         }
     }
 
+    // Load the trust configuration up front: an unreadable ami.workspace must
+    // not silently disable signature verification.
+    var ws workspace.Workspace
+    if err := ws.Load(filepath.Join(dir, "ami.workspace")); err != nil && !os.IsNotExist(err) {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modGetResult{Source: src, Name: name, Version: version, Message: "workspace invalid"}) }
+        return exit.New(exit.User, "workspace invalid: %v", err)
+    }
+
     // Temp directory for clone
     tmp, err := os.MkdirTemp("", "ami-modget-")
     if err != nil {
@@ -92,6 +102,15 @@ func modGetGit(out io.Writer, dir string, src string, jsonOut bool) error {
         return exit.New(exit.IO, "copy failed: %v", err)
     }
 
+    // Verify the publisher signature when ami.workspace configures trust;
+    // a rejected package is removed from the cache and ami.sum is left as is.
+    signer, err := workspace.VerifyPackage(dest, name+"@"+version, ws.Trust)
+    if err != nil {
+        _ = os.RemoveAll(dest)
+        if jsonOut { _ = json.NewEncoder(out).Encode(modGetResult{Source: src, Name: name, Version: version, Message: err.Error()}) }
+        return exit.New(exit.Signature, "%v", err)
+    }
+
     // Update ami.sum
     sumPath := filepath.Join(dir, "ami.sum")
     sum := map[string]any{"schema": "ami.sum/v1"}
@@ -129,7 +148,7 @@ func modGetGit(out io.Writer, dir string, src string, jsonOut bool) error {
     }
 
     if jsonOut {
-        return json.NewEncoder(out).Encode(modGetResult{Source: src, Name: name, Version: version, Path: dest, Message: "ok"})
+        return json.NewEncoder(out).Encode(modGetResult{Source: src, Name: name, Version: version, Path: dest, Signer: signer, Message: "ok"})
     }
     _, _ = fmt.Fprintf(out, "fetched %s@%s -> %s\n", name, version, dest)
     return nil
@@ -5,6 +5,7 @@ type modGetResult struct {
     Name    string `json:"name"`
     Version string `json:"version"`
     Path    string `json:"path"`
+    Signer  string `json:"signer,omitempty"`
     Message string `json:"message,omitempty"`
 }
 
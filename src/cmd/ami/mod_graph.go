@@ -0,0 +1,18 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModGraphCmd returns `ami mod graph`.
+func newModGraphCmd() *cobra.Command {
+    var jsonOut bool
+    cmd := &cobra.Command{
+        Use:   "graph",
+        Short: "Print the dependency graph recorded in ami.lock",
+        Example: "\n  # One requirement per line: <requirer> <package>@<version> <constraint>\n  ami mod graph\n\n  # JSON output\n  ami mod graph --json\n",
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModGraph(cmd.OutOrStdout(), ".", jsonOut)
+        },
+    }
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
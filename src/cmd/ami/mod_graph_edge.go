@@ -0,0 +1,9 @@
+package main
+
+// modGraphEdge is one requirement in `ami mod graph` output. From and To are
+// shown as <name>@<version> for locked packages and by name for workspace packages.
+type modGraphEdge struct {
+    From       string `json:"from"`
+    To         string `json:"to"`
+    Constraint string `json:"constraint,omitempty"`
+}
@@ -0,0 +1,6 @@
+package main
+
+type modGraphResult struct {
+    Edges   []modGraphEdge `json:"edges"`
+    Message string         `json:"message,omitempty"`
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// runModGraph prints the edges of dir/ami.lock in lock order.
+func runModGraph(out io.Writer, dir string, jsonOut bool) error {
+    var lock workspace.Lock
+    if err := lock.Load(filepath.Join(dir, "ami.lock")); err != nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modGraphResult{Edges: []modGraphEdge{}, Message: "ami.lock not found or invalid"}) }
+        return exit.New(exit.User, "load ami.lock: %v (run 'ami mod update')", err)
+    }
+    label := map[string]string{}
+    for _, p := range lock.Packages { label[p.ID()] = p.Name + "@" + p.Version }
+    edges := []modGraphEdge{}
+    for _, e := range lock.Edges {
+        from := e.From
+        if l, ok := label[from]; ok { from = l }
+        to := e.To + "@" + e.Version
+        if l, ok := label[to]; ok { to = l }
+        edges = append(edges, modGraphEdge{From: from, To: to, Constraint: e.Constraint})
+    }
+    if jsonOut { return json.NewEncoder(out).Encode(modGraphResult{Edges: edges}) }
+    for _, e := range edges {
+        c := e.Constraint
+        if c == "" { c = "==latest" }
+        _, _ = fmt.Fprintf(out, "%s %s %s\n", e.From, e.To, c)
+    }
+    return nil
+}
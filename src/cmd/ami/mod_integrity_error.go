@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+// modIntegrityError reports a package tree whose content hash differs from
+// the one recorded in ami.sum; callers map it to exit.Integrity.
+type modIntegrityError struct {
+    Name    string
+    Version string
+    Where   string
+    Have    string
+    Want    string
+}
+
+func (e *modIntegrityError) Error() string {
+    return fmt.Sprintf("integrity: %s@%s in %s: sha256 %s does not match ami.sum %s", e.Name, e.Version, e.Where, e.Have, e.Want)
+}
+
+// checkSumEntry hashes dir and compares it to the ami.sum entry for
+// name@version, if any; where names dir in the error.
+func checkSumEntry(sum map[string]map[string]string, name, version, dir, where string) error {
+    want := sum[name][version]
+    if want == "" { return nil }
+    h, err := hashDir(dir)
+    if err != nil { return err }
+    if h != want { return &modIntegrityError{Name: name, Version: version, Where: where, Have: h, Want: want} }
+    return nil
+}
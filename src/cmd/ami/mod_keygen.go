@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModKeygenCmd returns `ami mod keygen <file>`.
+func newModKeygenCmd() *cobra.Command {
+    var jsonOut bool
+    cmd := &cobra.Command{
+        Use:   "keygen <file>",
+        Short: "Generate an ed25519 key pair for signing packages",
+        Example: "\n  # Write a private key and print the public key for trust.keys\n  ami mod keygen ~/.ami/release.key\n",
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModKeygen(cmd.OutOrStdout(), args[0], jsonOut)
+        },
+    }
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
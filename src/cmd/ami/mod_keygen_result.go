@@ -0,0 +1,7 @@
+package main
+
+type modKeygenResult struct {
+    Path      string `json:"path"`
+    PublicKey string `json:"publicKey"`
+    Message   string `json:"message,omitempty"`
+}
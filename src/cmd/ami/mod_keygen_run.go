@@ -0,0 +1,35 @@
+package main
+
+import (
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+)
+
+// runModKeygen writes a new ed25519 private key (standard base64, mode 0600)
+// to path and prints the matching public key. An existing file is never
+// overwritten.
+func runModKeygen(out io.Writer, path string, jsonOut bool) error {
+    pub, priv, err := ed25519.GenerateKey(nil)
+    if err != nil { return exit.New(exit.Internal, "keygen: %v", err) }
+    f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+    if err != nil {
+        code := exit.IO
+        if errors.Is(err, os.ErrExist) { code = exit.User }
+        if jsonOut { _ = json.NewEncoder(out).Encode(modKeygenResult{Path: path, Message: err.Error()}) }
+        return exit.New(code, "write key: %v", err)
+    }
+    _, err = f.WriteString(base64.StdEncoding.EncodeToString(priv) + "\n")
+    if cerr := f.Close(); err == nil { err = cerr }
+    if err != nil { return exit.New(exit.IO, "write key: %v", err) }
+    res := modKeygenResult{Path: path, PublicKey: base64.StdEncoding.EncodeToString(pub)}
+    if jsonOut { return json.NewEncoder(out).Encode(res) }
+    _, _ = fmt.Fprintf(out, "private key: %s\npublic key: %s\n", res.Path, res.PublicKey)
+    return nil
+}
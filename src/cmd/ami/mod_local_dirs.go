@@ -0,0 +1,19 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+)
+
+// modLocalDirs returns the file-based package trees consulted before git, in
+// preference order: the workspace vendor/ tree (see `ami mod vendor`) and the
+// proxy directory named by AMI_MODULE_PROXY. Both use the cache layout
+// <name>/<version>/; missing directories are skipped.
+func modLocalDirs(dir string) []string {
+    var out []string
+    for _, d := range []string{filepath.Join(dir, "vendor"), os.Getenv("AMI_MODULE_PROXY")} {
+        if d == "" { continue }
+        if st, err := os.Stat(d); err == nil && st.IsDir() { out = append(out, d) }
+    }
+    return out
+}
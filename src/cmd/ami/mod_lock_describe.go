@@ -0,0 +1,41 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// modLockDescriber returns the workspace.NewLock callback used by `ami mod
+// update` and --locked checks. Git sources are hashed from their cache
+// directory and their tag resolved to a commit; other paths take the sha256
+// recorded for them in ami.sum. When prev locks the same source@version with
+// the same hash, its commit is reused so offline builders need not reach git;
+// a package served from vendor/ or the proxy is locked without a commit when
+// git is unreachable.
+func modLockDescriber(src *modUpdateSource, prev *workspace.Lock) func(path, version string) (workspace.LockPackage, error) {
+    return func(path, version string) (workspace.LockPackage, error) {
+        p := workspace.LockPackage{Name: modPackageName(path)}
+        if !isGitSource(path) {
+            p.Hash = src.manifest.Packages[p.Name][version]
+            return p, nil
+        }
+        h, err := hashDir(filepath.Join(src.cache, p.Name, version))
+        if err != nil { return p, err }
+        p.Hash = h
+        for _, lp := range prev.Packages {
+            if lp.Source == path && lp.Version == version && lp.Hash == h && lp.Commit != "" {
+                p.Commit = lp.Commit
+                return p, nil
+            }
+        }
+        if p.Commit, err = gitTagCommit(gitCloneArg(path), version); err != nil {
+            for _, root := range src.local {
+                if st, serr := os.Stat(filepath.Join(root, p.Name, version)); serr == nil && st.IsDir() { return p, nil }
+            }
+            return p, err
+        }
+        return p, nil
+    }
+}
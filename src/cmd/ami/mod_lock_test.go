@@ -0,0 +1,80 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// setupLockedWorkspace writes a workspace whose two packages both import lib
+// and an ami.sum offering lib v1.2.3, then runs `ami mod update`.
+func setupLockedWorkspace(t *testing.T, name string) string {
+    t.Helper()
+    dir := filepath.Join("build", "test", "mod_lock", name)
+    _ = os.RemoveAll(dir)
+    if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    ws := workspace.DefaultWorkspace()
+    ws.Packages = workspace.PackageList{
+        {Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{"lib ^1.0.0"}}},
+        {Key: "tools", Package: workspace.Package{Name: "tools", Version: "1.0.0", Root: "./src", Import: []string{"lib ~1.2.0"}}},
+    }
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    sum := []byte(`{ "schema": "ami.sum/v1", "packages": { "lib": { "v1.2.3": "aaa" } } }`)
+    if err := os.WriteFile(filepath.Join(dir, "ami.sum"), sum, 0o644); err != nil { t.Fatalf("write sum: %v", err) }
+    old := os.Getenv("AMI_PACKAGE_CACHE")
+    t.Cleanup(func() { _ = os.Setenv("AMI_PACKAGE_CACHE", old) })
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(dir, "cache"))
+    var buf bytes.Buffer
+    if err := runModUpdate(&buf, dir, true); err != nil { t.Fatalf("runModUpdate: %v; out=%s", err, buf.String()) }
+    return dir
+}
+
+func TestModUpdate_WritesLockGraph(t *testing.T) {
+    dir := setupLockedWorkspace(t, "write")
+    var lock workspace.Lock
+    if err := lock.Load(filepath.Join(dir, "ami.lock")); err != nil { t.Fatalf("load lock: %v", err) }
+    if len(lock.Packages) != 1 || lock.Packages[0].Version != "v1.2.3" || lock.Packages[0].Hash != "aaa" {
+        t.Fatalf("packages: %+v", lock.Packages)
+    }
+    var buf bytes.Buffer
+    if err := runModGraph(&buf, dir, false); err != nil { t.Fatalf("graph: %v", err) }
+    if got, want := buf.String(), "app lib@v1.2.3 ^1.0.0\ntools lib@v1.2.3 ~1.2.0\n"; got != want {
+        t.Fatalf("graph:\n%s\nwant:\n%s", got, want)
+    }
+    buf.Reset()
+    if err := runModWhy(&buf, dir, "lib", true); err != nil { t.Fatalf("why: %v", err) }
+    var why modWhyResult
+    if err := json.Unmarshal(buf.Bytes(), &why); err != nil { t.Fatalf("json: %v; out=%s", err, buf.String()) }
+    if why.Version != "v1.2.3" || len(why.Chains) != 2 || strings.Join(why.Chains[1], " ") != "tools lib@v1.2.3" {
+        t.Fatalf("why: %+v", why)
+    }
+    if err := runModWhy(&bytes.Buffer{}, dir, "nope", false); exit.UnwrapCode(err) != exit.User {
+        t.Fatalf("why unknown: %v", err)
+    }
+}
+
+func TestLockedCheck_FailsWhenResolutionChanges(t *testing.T) {
+    dir := setupLockedWorkspace(t, "locked")
+    var buf bytes.Buffer
+    if err := runLockedCheck(&buf, dir, false); err != nil { t.Fatalf("locked check on fresh lock: %v; out=%s", err, buf.String()) }
+
+    // a newer matching version in ami.sum changes the selection
+    sum := []byte(`{ "schema": "ami.sum/v1", "packages": { "lib": { "v1.2.3": "aaa", "v1.2.9": "bbb" } } }`)
+    if err := os.WriteFile(filepath.Join(dir, "ami.sum"), sum, 0o644); err != nil { t.Fatalf("write sum: %v", err) }
+    err := runLockedCheck(&buf, dir, false)
+    if exit.UnwrapCode(err) != exit.Integrity || !strings.Contains(buf.String(), "~ lib: v1.2.3 -> v1.2.9") {
+        t.Fatalf("expected lock mismatch; err=%v out=%s", err, buf.String())
+    }
+
+    _ = os.Remove(filepath.Join(dir, "ami.lock"))
+    buf.Reset()
+    if err := runLockedCheck(&buf, dir, true); err == nil || !strings.Contains(buf.String(), "E_INTEGRITY_LOCK") {
+        t.Fatalf("expected missing lock error; err=%v out=%s", err, buf.String())
+    }
+}
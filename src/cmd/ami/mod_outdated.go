@@ -0,0 +1,18 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModOutdatedCmd returns `ami mod outdated`.
+func newModOutdatedCmd() *cobra.Command {
+    var jsonOut bool
+    cmd := &cobra.Command{
+        Use:   "outdated",
+        Short: "List requirements with newer versions available",
+        Example: "\n  # Current, newest compatible and newest overall version per import\n  ami mod outdated\n\n  # JSON output\n  ami mod outdated --json\n",
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModOutdated(cmd.OutOrStdout(), ".", jsonOut)
+        },
+    }
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/semver"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// collectModOutdated lists every remote import of the workspace at dir with
+// its current, newest compatible and newest overall versions. Candidate
+// versions come from the same sources as `ami mod update` (vendor/, the
+// proxy, git tags, ami.sum).
+func collectModOutdated(dir string, ws *workspace.Workspace) ([]modOutdatedEntry, error) {
+    cache, err := workspace.DefaultCacheRoot()
+    if err != nil { return nil, err }
+    var manifest workspace.Manifest
+    if err := manifest.Load(filepath.Join(dir, "ami.sum")); err != nil && !os.IsNotExist(err) { return nil, err }
+    var lock workspace.Lock
+    _ = lock.Load(filepath.Join(dir, "ami.lock")) // optional
+    locked := map[string]string{}
+    for _, p := range lock.Packages { locked[p.Source] = p.Version }
+    src := newModUpdateSource(dir, cache, &manifest)
+
+    avail := map[string][]string{}
+    out := []modOutdatedEntry{}
+    for _, e := range ws.Packages {
+        p := e.Package
+        workspace.NormalizeImports(&p)
+        name := p.Name
+        if name == "" { name = e.Key }
+        for _, ent := range p.Import {
+            path, c := workspace.ParseImportEntry(ent)
+            if path == "" || strings.HasPrefix(path, "./") { continue }
+            con := semver.Constraint{Latest: true}
+            if c != "" {
+                if con, err = semver.ParseConstraint(c); err != nil { return nil, fmt.Errorf("%s: import %q: %v", name, ent, err) }
+            }
+            vers, ok := avail[path]
+            if !ok {
+                if vers, _, err = src.Versions(path); err != nil { return nil, fmt.Errorf("list versions of %s: %w", path, err) }
+                avail[path] = vers
+            }
+            pre := strings.Contains(c, "-")
+            o := modOutdatedEntry{Package: name, Path: path, Constraint: c, Entry: ent}
+            o.Latest, _ = selectHighestSemver(vers, pre)
+            o.Compatible, _ = selectHighestSemver(satisfyingVersions(vers, con), pre)
+            if o.Current = locked[path]; o.Current == "" {
+                o.Current, _ = selectHighestSemver(satisfyingVersions(manifest.Versions(modPackageName(path)), con), pre)
+            }
+            o.Outdated = o.Current != "" && (semverLess(o.Current, o.Compatible) || semverLess(o.Current, o.Latest))
+            out = append(out, o)
+        }
+    }
+    return out, nil
+}
+
+// satisfyingVersions filters vers to those satisfying c.
+func satisfyingVersions(vers []string, c semver.Constraint) []string {
+    var out []string
+    for _, v := range vers { if semver.Satisfies(v, c) { out = append(out, v) } }
+    return out
+}
+
+// semverLess reports a < b; invalid or empty versions never compare less.
+func semverLess(a, b string) bool {
+    va, err1 := semver.ParseVersion(a)
+    vb, err2 := semver.ParseVersion(b)
+    return err1 == nil && err2 == nil && semver.Compare(va, vb) < 0
+}
@@ -0,0 +1,17 @@
+package main
+
+// modOutdatedEntry describes one remote import of a workspace package.
+// Current is the version in ami.lock (else the highest satisfying version in
+// ami.sum); Compatible is the newest available version satisfying Constraint
+// and Latest the newest available overall. Pre-releases are considered only
+// when Constraint names one. Entry is the import as written in ami.workspace.
+type modOutdatedEntry struct {
+    Package    string `json:"package"`
+    Path       string `json:"path"`
+    Constraint string `json:"constraint,omitempty"`
+    Current    string `json:"current,omitempty"`
+    Compatible string `json:"compatible,omitempty"`
+    Latest     string `json:"latest,omitempty"`
+    Outdated   bool   `json:"outdated"`
+    Entry      string `json:"-"`
+}
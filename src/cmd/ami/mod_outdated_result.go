@@ -0,0 +1,6 @@
+package main
+
+type modOutdatedResult struct {
+    Requirements []modOutdatedEntry `json:"requirements"`
+    Message      string             `json:"message,omitempty"`
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// runModOutdated reports, per remote import, the current version and the
+// newest versions available within and beyond its constraint.
+func runModOutdated(out io.Writer, dir string, jsonOut bool) error {
+    var ws workspace.Workspace
+    if err := ws.Load(filepath.Join(dir, "ami.workspace")); err != nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modOutdatedResult{Requirements: []modOutdatedEntry{}, Message: "workspace not found or invalid"}) }
+        return exit.New(exit.User, "workspace invalid: %v", err)
+    }
+    entries, err := collectModOutdated(dir, &ws)
+    if err != nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modOutdatedResult{Requirements: []modOutdatedEntry{}, Message: err.Error()}) }
+        return exit.New(exit.Network, "outdated: %v", err)
+    }
+    if jsonOut { return json.NewEncoder(out).Encode(modOutdatedResult{Requirements: entries}) }
+    dash := func(s string) string { if s == "" { return "-" }; return s }
+    for _, e := range entries {
+        mark := ""
+        if e.Outdated { mark = "\toutdated" }
+        _, _ = fmt.Fprintf(out, "%s\t%s\t%s\tcurrent=%s\tcompatible=%s\tlatest=%s%s\n", e.Package, e.Path, dash(e.Constraint), dash(e.Current), dash(e.Compatible), dash(e.Latest), mark)
+    }
+    return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModSignCmd returns `ami mod sign <dir> --key <file> --package <name>@<version>`.
+func newModSignCmd() *cobra.Command {
+    var jsonOut bool
+    var keyPath, pkg string
+    cmd := &cobra.Command{
+        Use:   "sign <dir>",
+        Short: "Write a detached ed25519 signature (ami.sig) for a package",
+        Example: "\n  # Sign the package rooted at ./src before tagging a release\n  ami mod sign ./src --key ~/.ami/release.key --package lib@v1.4.0\n",
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModSign(cmd.OutOrStdout(), args[0], keyPath, pkg, jsonOut)
+        },
+    }
+    cmd.Flags().StringVar(&keyPath, "key", "", "private key file written by 'ami mod keygen'")
+    _ = cmd.MarkFlagRequired("key")
+    cmd.Flags().StringVar(&pkg, "package", "", "release being signed, as name@version (the name importers resolve it by)")
+    _ = cmd.MarkFlagRequired("package")
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
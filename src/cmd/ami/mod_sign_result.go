@@ -0,0 +1,9 @@
+package main
+
+type modSignResult struct {
+    Path      string `json:"path"`
+    Package   string `json:"package,omitempty"`
+    Sha256    string `json:"sha256,omitempty"`
+    PublicKey string `json:"publicKey,omitempty"`
+    Message   string `json:"message,omitempty"`
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+    "crypto/ed25519"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/semver"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// runModSign signs the package rooted at dir as release pkg (name@version)
+// with the private key in keyPath, writing dir/ami.sig (see
+// workspace.SignPackage). The version is recorded with a leading v, as
+// resolution names it.
+func runModSign(out io.Writer, dir, keyPath, pkg string, jsonOut bool) error {
+    fail := func(code exit.Code, msg string) error {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modSignResult{Path: dir, Message: msg}) }
+        return exit.New(code, "%s", msg)
+    }
+    if st, err := os.Stat(dir); err != nil || !st.IsDir() { return fail(exit.User, "package directory not found: "+dir) }
+    at := strings.LastIndex(pkg, "@")
+    if at <= 0 || !semver.ValidateVersion(pkg[at+1:]) {
+        return fail(exit.User, "invalid --package: expected name@version, e.g. lib@v1.4.0")
+    }
+    pkg = pkg[:at] + "@v" + strings.TrimPrefix(pkg[at+1:], "v")
+    b, err := os.ReadFile(keyPath)
+    if err != nil { return fail(exit.User, fmt.Sprintf("read key: %v", err)) }
+    raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+    if err != nil || len(raw) != ed25519.PrivateKeySize {
+        return fail(exit.User, "invalid key: expected a base64 ed25519 private key from 'ami mod keygen'")
+    }
+    sig, err := workspace.SignPackage(dir, pkg, ed25519.PrivateKey(raw))
+    if err != nil { return fail(exit.IO, fmt.Sprintf("sign: %v", err)) }
+    res := modSignResult{Path: filepath.Join(dir, workspace.SignatureFile), Package: sig.Package, Sha256: sig.Sha256, PublicKey: sig.PublicKey}
+    if jsonOut { return json.NewEncoder(out).Encode(res) }
+    _, _ = fmt.Fprintf(out, "signed %s as %s sha256:%s key %s\n", res.Path, res.Package, res.Sha256, res.PublicKey)
+    return nil
+}
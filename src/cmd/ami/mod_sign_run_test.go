@@ -0,0 +1,132 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// keygen + sign a proxy package, then verify it through mod update and mod audit
+// against trusted and untrusted workspace keys.
+func TestModSign_VerifiedByUpdateAndAudit(t *testing.T) {
+    base := filepath.Join("build", "test", "mod_sign", "flow")
+    _ = os.RemoveAll(base)
+    keyPath := filepath.Join(base, "release.key")
+    if err := os.MkdirAll(base, 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    var buf bytes.Buffer
+    if err := runModKeygen(&buf, keyPath, true); err != nil { t.Fatalf("keygen: %v", err) }
+    var kg modKeygenResult
+    if err := json.Unmarshal(buf.Bytes(), &kg); err != nil || kg.PublicKey == "" { t.Fatalf("keygen json: %v %s", err, buf.String()) }
+    if err := runModKeygen(&bytes.Buffer{}, keyPath, false); exit.UnwrapCode(err) != exit.User { t.Fatalf("keygen overwrite: %v", err) }
+
+    pkg := filepath.Join(base, "proxy", "lib", "v1.0.0")
+    if err := os.MkdirAll(pkg, 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    if err := os.WriteFile(filepath.Join(pkg, "lib.ami"), []byte("package lib\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    buf.Reset()
+    if err := runModSign(&buf, pkg, keyPath, "lib@1.0.0", false); err != nil { t.Fatalf("sign: %v", err) }
+    if _, err := os.Stat(filepath.Join(pkg, workspace.SignatureFile)); err != nil { t.Fatalf("ami.sig: %v", err) }
+
+    dir := filepath.Join(base, "ws")
+    if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    writeWS := func(key string) {
+        ws := workspace.DefaultWorkspace()
+        ws.Packages = workspace.PackageList{{Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{"lib ^1.0.0"}}}}
+        ws.Trust = workspace.Trust{Require: true, Keys: []workspace.TrustedKey{{Name: "acme", Key: key}}}
+        if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    }
+    oldCache, oldProxy := os.Getenv("AMI_PACKAGE_CACHE"), os.Getenv("AMI_MODULE_PROXY")
+    defer func() { _ = os.Setenv("AMI_PACKAGE_CACHE", oldCache); _ = os.Setenv("AMI_MODULE_PROXY", oldProxy) }()
+    absProxy, _ := filepath.Abs(filepath.Join(base, "proxy"))
+    _ = os.Setenv("AMI_MODULE_PROXY", absProxy)
+
+    // untrusted key: update fails with the signature exit code and writes nothing
+    other := workspace.TrustedKey{Key: "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="}
+    writeWS(other.Key)
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache1"))
+    buf.Reset()
+    err := runModUpdate(&buf, dir, true)
+    if exit.UnwrapCode(err) != exit.Signature || !strings.Contains(buf.String(), "signature untrusted for lib@v1.0.0") {
+        t.Fatalf("expected untrusted signature; err=%v out=%s", err, buf.String())
+    }
+    if _, err := os.Stat(filepath.Join(dir, "ami.sum")); !os.IsNotExist(err) { t.Fatalf("ami.sum written despite signature failure") }
+
+    // trusted key: update succeeds and reports the signer
+    writeWS(kg.PublicKey)
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache2"))
+    buf.Reset()
+    if err := runModUpdate(&buf, dir, true); err != nil { t.Fatalf("update: %v; out=%s", err, buf.String()) }
+    var res modUpdateResult
+    if err := json.Unmarshal(buf.Bytes(), &res); err != nil { t.Fatalf("json: %v", err) }
+    if len(res.Selected) != 1 || res.Selected[0].Signer != "acme" { t.Fatalf("selected: %+v", res.Selected) }
+    buf.Reset()
+    if err := runModAudit(&buf, dir, false); err != nil { t.Fatalf("audit: %v; out=%s", err, buf.String()) }
+
+    // tampering with the cached copy is caught by mod audit
+    if err := os.WriteFile(filepath.Join(base, "cache2", "lib", "v1.0.0", "lib.ami"), []byte("package lib // evil\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    buf.Reset()
+    err = runModAudit(&buf, dir, true)
+    var ar modAuditResult
+    _ = json.Unmarshal(buf.Bytes(), &ar)
+    if exit.UnwrapCode(err) != exit.Signature || len(ar.SignatureFailures) != 1 || !strings.Contains(ar.SignatureFailures[0], "signature digest") {
+        t.Fatalf("expected digest failure; err=%v out=%s", err, buf.String())
+    }
+}
+
+func TestModSign_RejectsBadKey(t *testing.T) {
+    dir := t.TempDir()
+    key := filepath.Join(dir, "bad.key")
+    if err := os.WriteFile(key, []byte("nope"), 0o600); err != nil { t.Fatal(err) }
+    var buf bytes.Buffer
+    if err := runModSign(&buf, dir, key, "lib@v1.0.0", true); exit.UnwrapCode(err) != exit.User || !strings.Contains(buf.String(), "invalid key") {
+        t.Fatalf("expected invalid key; err=%v out=%s", err, buf.String())
+    }
+}
+
+func TestModSign_RejectsBadPackage(t *testing.T) {
+    dir := t.TempDir()
+    key := filepath.Join(dir, "release.key")
+    if err := runModKeygen(&bytes.Buffer{}, key, false); err != nil { t.Fatalf("keygen: %v", err) }
+    for _, pkg := range []string{"lib", "@v1.0.0", "lib@latest"} {
+        var buf bytes.Buffer
+        if err := runModSign(&buf, dir, key, pkg, true); exit.UnwrapCode(err) != exit.User || !strings.Contains(buf.String(), "invalid --package") {
+            t.Fatalf("%q: err=%v out=%s", pkg, err, buf.String())
+        }
+    }
+}
+
+// Verification fails closed: a broken ami.workspace stops `mod get`, and a
+// selected package absent from the cache cannot pass `trust.require`.
+func TestModVerify_FailsClosed(t *testing.T) {
+    base := filepath.Join("build", "test", "mod_sign", "fail_closed")
+    _ = os.RemoveAll(base)
+    dir := filepath.Join(base, "ws")
+    if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    if err := os.WriteFile(filepath.Join(dir, "ami.workspace"), []byte("trust: [unterminated\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    var buf bytes.Buffer
+    if err := modGetGit(&buf, dir, "file+git:///nonexistent/lib.git#v1.0.0", true); exit.UnwrapCode(err) != exit.User || !strings.Contains(buf.String(), "workspace invalid") {
+        t.Fatalf("mod get with broken workspace: err=%v out=%s", err, buf.String())
+    }
+
+    ws := workspace.DefaultWorkspace()
+    ws.Packages = workspace.PackageList{{Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{"example.com/lib ^1.0.0"}}}}
+    ws.Trust = workspace.Trust{Require: true, Keys: []workspace.TrustedKey{{Name: "acme", Key: "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="}}}
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    sum := workspace.Manifest{Schema: "ami.sum/v1"}
+    sum.Set("example.com/lib", "v1.0.0", strings.Repeat("0", 64))
+    if err := sum.Save(filepath.Join(dir, "ami.sum")); err != nil { t.Fatalf("save sum: %v", err) }
+    oldCache, oldProxy := os.Getenv("AMI_PACKAGE_CACHE"), os.Getenv("AMI_MODULE_PROXY")
+    defer func() { _ = os.Setenv("AMI_PACKAGE_CACHE", oldCache); _ = os.Setenv("AMI_MODULE_PROXY", oldProxy) }()
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache"))
+    _ = os.Setenv("AMI_MODULE_PROXY", "")
+    buf.Reset()
+    err := runModUpdate(&buf, dir, true)
+    if exit.UnwrapCode(err) != exit.Signature || !strings.Contains(buf.String(), "signature missing for example.com/lib@v1.0.0") {
+        t.Fatalf("expected missing signature; err=%v out=%s", err, buf.String())
+    }
+}
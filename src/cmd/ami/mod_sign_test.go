@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func Test_newModSignCmd_exists(t *testing.T) { if newModSignCmd() == nil { t.Fatal("nil") } }
+
+func Test_newModKeygenCmd_exists(t *testing.T) { if newModKeygenCmd() == nil { t.Fatal("nil") } }
+
+func Test_modSignResult_zero(t *testing.T) { var _ modSignResult; var _ modKeygenResult }
@@ -0,0 +1,26 @@
+package main
+
+import "github.com/sam-caldwell/ami/src/ami/workspace"
+
+// modUpdateConflict reports an unsatisfiable set of constraints on one import path.
+type modUpdateConflict struct {
+    Path      string            `json:"path"`
+    Demands   []modUpdateDemand `json:"demands"`
+    Available []string          `json:"available"`
+}
+
+// modUpdateDemand is one constraint and the chain of requirers that declared it.
+type modUpdateDemand struct {
+    Constraint string   `json:"constraint"`
+    By         []string `json:"requiredBy"`
+}
+
+func newModUpdateConflict(rc *workspace.ResolveConflict) *modUpdateConflict {
+    c := &modUpdateConflict{Path: rc.Path, Available: append([]string{}, rc.Available...)}
+    for _, d := range rc.Demands {
+        ct := d.Constraint
+        if ct == "" { ct = "==latest" }
+        c.Demands = append(c.Demands, modUpdateDemand{Constraint: ct, By: d.By})
+    }
+    return c
+}
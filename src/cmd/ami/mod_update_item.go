@@ -4,5 +4,7 @@ type modUpdateItem struct {
     Name    string `json:"name"`
     Version string `json:"version"`
     Path    string `json:"path"`
+    Source  string `json:"source,omitempty"`
+    Signer  string `json:"signer,omitempty"`
 }
 
@@ -4,3 +4,5 @@ import "testing"
 
 func Test_modUpdateItem_zero(t *testing.T) { var _ modUpdateItem }
 
+
+func Test_modUpdateConflict_zero(t *testing.T) { var _ modUpdateConflict; var _ modUpdateDemand }
@@ -0,0 +1,133 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// Conflicting constraints across packages fail with the requirer of each and leave ami.sum untouched.
+func TestModUpdate_ReportsConflictWithRequirers(t *testing.T) {
+    dir := filepath.Join("build", "test", "mod_update", "conflict")
+    _ = os.RemoveAll(dir)
+    if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    ws := workspace.DefaultWorkspace()
+    ws.Packages = workspace.PackageList{
+        {Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{"lib ^1.0.0"}}},
+        {Key: "tools", Package: workspace.Package{Name: "tools", Version: "1.0.0", Root: "./src", Import: []string{"lib >= 2.0.0"}}},
+    }
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    sum := []byte(`{ "schema": "ami.sum/v1", "packages": { "lib": { "v1.4.0": "aaa", "v2.0.0": "bbb" } } }`)
+    if err := os.WriteFile(filepath.Join(dir, "ami.sum"), sum, 0o644); err != nil { t.Fatalf("write sum: %v", err) }
+    old := os.Getenv("AMI_PACKAGE_CACHE")
+    defer os.Setenv("AMI_PACKAGE_CACHE", old)
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(dir, "cache"))
+
+    var buf bytes.Buffer
+    if err := runModUpdate(&buf, dir, true); err == nil { t.Fatalf("expected conflict error; out=%s", buf.String()) }
+    var res modUpdateResult
+    if err := json.Unmarshal(buf.Bytes(), &res); err != nil { t.Fatalf("json: %v; out=%s", err, buf.String()) }
+    if res.Conflict == nil || res.Conflict.Path != "lib" || len(res.Conflict.Demands) != 2 {
+        t.Fatalf("unexpected conflict: %+v", res.Conflict)
+    }
+    if got := res.Conflict.Demands[1].By; len(got) != 1 || got[0] != "tools" {
+        t.Fatalf("requiredBy: %v", got)
+    }
+    b, _ := os.ReadFile(filepath.Join(dir, "ami.sum"))
+    if !bytes.Equal(b, sum) { t.Fatalf("ami.sum rewritten on conflict: %s", b) }
+
+    buf.Reset()
+    _ = runModUpdate(&buf, dir, false)
+    if !strings.Contains(buf.String(), "conflict: lib") || !strings.Contains(buf.String(), "required by tools") {
+        t.Fatalf("human output: %s", buf.String())
+    }
+}
+
+// Git sources are listed by tag, fetched and their own imports resolved transitively.
+func TestModUpdate_ResolvesGitSourcesTransitively(t *testing.T) {
+    if os.Getenv("AMI_E2E_ENABLE_GIT") != "1" {
+        t.Skip("git tests disabled; set AMI_E2E_ENABLE_GIT=1 to enable")
+    }
+    if _, err := exec.LookPath("git"); err != nil { t.Skip("git not found in PATH") }
+    base := filepath.Join("build", "test", "mod_update", "resolve_git")
+    _ = os.RemoveAll(base)
+    absBase, _ := filepath.Abs(base)
+    newRepo := func(name string, files map[string]string, tags ...string) string {
+        repo := filepath.Join(absBase, name+".git")
+        if err := os.MkdirAll(repo, 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+        run := func(args ...string) {
+            ctx, cancel := context.WithTimeout(context.Background(), 30_000_000_000)
+            defer cancel()
+            cmd := exec.CommandContext(ctx, "git", args...)
+            cmd.Dir = repo
+            cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+            if out, err := cmd.CombinedOutput(); err != nil { t.Fatalf("git %v: %v\n%s", args, err, out) }
+        }
+        run("init")
+        for _, tag := range tags {
+            for p, c := range files {
+                if err := os.WriteFile(filepath.Join(repo, p), []byte(strings.ReplaceAll(c, "$TAG", tag)), 0o644); err != nil { t.Fatalf("write: %v", err) }
+            }
+            run("add", ".")
+            run("-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "--allow-empty", "-m", tag)
+            run("tag", tag)
+        }
+        return "file+git://" + repo
+    }
+    log := newRepo("log", map[string]string{"a.txt": "$TAG"}, "v1.0.0", "v1.1.0", "v2.0.0")
+    util := newRepo("util", map[string]string{
+        "ami.workspace": "version: 1.0.0\npackages:\n  - main:\n      name: util\n      version: 1.0.0\n      root: ./src\n      import:\n        - " + log + " ^1.0.0\n",
+    }, "v1.0.0", "v1.2.0")
+
+    ws := filepath.Join(base, "ws")
+    if err := os.MkdirAll(filepath.Join(ws, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    w := workspace.DefaultWorkspace()
+    w.Packages = workspace.PackageList{
+        {Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{util + " ^1.0.0"}}},
+    }
+    if err := w.Save(filepath.Join(ws, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    old := os.Getenv("AMI_PACKAGE_CACHE")
+    defer os.Setenv("AMI_PACKAGE_CACHE", old)
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache"))
+
+    var buf bytes.Buffer
+    if err := runModUpdate(&buf, ws, true); err != nil { t.Fatalf("runModUpdate: %v; out=%s", err, buf.String()) }
+    var res modUpdateResult
+    if err := json.Unmarshal(buf.Bytes(), &res); err != nil { t.Fatalf("json: %v; out=%s", err, buf.String()) }
+    got := map[string]string{}
+    for _, s := range res.Selected { got[s.Name] = s.Version }
+    if got["util"] != "v1.2.0" || got["log"] != "v1.1.0" { t.Fatalf("selected: %+v", res.Selected) }
+    var m workspace.Manifest
+    if err := m.Load(filepath.Join(ws, "ami.sum")); err != nil { t.Fatalf("load sum: %v", err) }
+    if len(m.Versions("log")) != 1 || len(m.Versions("util")) != 1 { t.Fatalf("sum: %+v", m.Packages) }
+    var lock workspace.Lock
+    if err := lock.Load(filepath.Join(ws, "ami.lock")); err != nil { t.Fatalf("load lock: %v", err) }
+    if len(lock.Packages) != 2 || lock.Packages[0].Commit == "" || len(lock.Edges) != 2 { t.Fatalf("lock: %+v", lock) }
+    if err := runLockedCheck(&buf, ws, false); err != nil { t.Fatalf("locked: %v; out=%s", err, buf.String()) }
+}
+
+// A cached tree that no longer matches ami.sum fails the update with the
+// integrity exit code and leaves ami.sum untouched.
+func TestModUpdate_TamperedCacheFailsIntegrity(t *testing.T) {
+    dir := setupModUpgrade(t, "tampered_cache")
+    var buf bytes.Buffer
+    if err := runModUpdate(&buf, dir, true); err != nil { t.Fatalf("update: %v; out=%s", err, buf.String()) }
+    sumPath := filepath.Join(dir, "ami.sum")
+    before, _ := os.ReadFile(sumPath)
+    cache := os.Getenv("AMI_PACKAGE_CACHE")
+    if err := os.WriteFile(filepath.Join(cache, "lib", "v1.4.0", "lib.ami"), []byte("package lib // evil\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    buf.Reset()
+    err := runModUpdate(&buf, dir, true)
+    if exit.UnwrapCode(err) != exit.Integrity || !strings.Contains(buf.String(), "does not match ami.sum") {
+        t.Fatalf("expected integrity failure; err=%v out=%s", err, buf.String())
+    }
+    if after, _ := os.ReadFile(sumPath); !bytes.Equal(before, after) { t.Fatalf("ami.sum rewritten") }
+}
@@ -5,5 +5,9 @@ type modUpdateResult struct {
     Message  string          `json:"message,omitempty"`
     Audit    *modAuditEmbed  `json:"audit,omitempty"`
     Selected []modUpdateItem `json:"selected,omitempty"`
+    // Unresolved lists remote imports with neither a git source nor ami.sum versions.
+    Unresolved        []string           `json:"unresolved,omitempty"`
+    Conflict          *modUpdateConflict `json:"conflict,omitempty"`
+    SignatureFailures []string           `json:"signatureFailures,omitempty"`
 }
 
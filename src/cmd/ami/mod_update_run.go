@@ -2,6 +2,7 @@ package main
 
 import (
     "encoding/json"
+    "errors"
     "fmt"
     "io"
     "os"
@@ -15,8 +16,12 @@ import (
 
 // types moved to mod_update_types.go
 
-// runModUpdate copies local workspace packages to the cache and refreshes ami.sum.
-// Remote resolution (git+ssh) and constraint solving are deferred to later phases.
+// runModUpdate copies local workspace packages to the cache, resolves remote
+// requirements to a consistent version set (see workspace.Resolve) and
+// refreshes ami.sum and ami.lock. Git sources are listed by tag and fetched into the cache;
+// unsatisfiable constraints are reported with their chains of requirers and
+// leave both files untouched, as do packages failing signature verification
+// against the workspace trust configuration (exit.Signature).
 func runModUpdate(out io.Writer, dir string, jsonOut bool) error {
     // Pre-check: audit current workspace/sum/cache to surface issues before update.
     auditRep, _ := workspace.AuditDependencies(dir) // best-effort; non-fatal
@@ -74,18 +79,91 @@ func runModUpdate(out io.Writer, dir string, jsonOut bool) error {
         updated = append(updated, modUpdateItem{Name: p.Name, Version: p.Version, Path: dst})
     }
 
-    // For remote requirements, compute best satisfying versions among existing manifest entries
-    // (non-destructive; selection is reported, not enforced here).
-    reqs, _ := workspace.CollectRemoteRequirements(&ws)
+    // Resolve remote requirements: workspace imports plus those declared by the
+    // selected versions, materializing candidates into the cache as needed
+    // (vendor/ and AMI_MODULE_PROXY first, then git).
+    src := newModUpdateSource(dir, cache, &manifest)
+    res, err := workspace.Resolve(&ws, src)
+    if err != nil {
+        var rc *workspace.ResolveConflict
+        if errors.As(err, &rc) {
+            if jsonOut {
+                _ = json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Message: "unsatisfiable constraints", Conflict: newModUpdateConflict(rc)})
+            } else {
+                _, _ = fmt.Fprintf(out, "conflict: %s\n", rc.Path)
+                for _, d := range newModUpdateConflict(rc).Demands {
+                    _, _ = fmt.Fprintf(out, "  %s required by %s\n", d.Constraint, strings.Join(d.By, " -> "))
+                }
+            }
+            return exit.New(exit.User, "%v", err)
+        }
+        var ie *modIntegrityError
+        if errors.As(err, &ie) {
+            if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Message: ie.Error()}) }
+            return exit.New(exit.Integrity, "%v", err)
+        }
+        if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Message: "resolve failed"}) }
+        return exit.New(exit.Network, "resolve: %v", err)
+    }
     var selected []modUpdateItem
-    for _, r := range reqs {
-        vers := manifest.Versions(r.Name)
-        if len(vers) == 0 { continue }
-        // include prereleases only if the constraint specifies a prerelease component
-        includePre := strings.Contains(r.Constraint.Version, "-")
-        if best, ok := workspace.HighestSatisfying(vers, r.Constraint, includePre); ok {
-            selected = append(selected, modUpdateItem{Name: r.Name, Version: best})
+    for path, ver := range res.Selected {
+        item := modUpdateItem{Name: modPackageName(path), Version: ver}
+        if isGitSource(path) {
+            // fetched while resolving; record the exact tree in ami.sum
+            item.Path, item.Source = filepath.Join(cache, item.Name, ver), path
+            h, err := hashDir(item.Path)
+            if err != nil {
+                if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Message: "hash failed"}) }
+                return exit.New(exit.IO, "hash failed: %v", err)
+            }
+            if want := manifest.Packages[item.Name][ver]; want != "" && want != h {
+                ie := &modIntegrityError{Name: item.Name, Version: ver, Where: "the package cache", Have: h, Want: want}
+                if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Message: ie.Error()}) }
+                return exit.New(exit.Integrity, "%v", ie)
+            }
+            manifest.Set(item.Name, ver, h)
+        } else if manifest.Packages[item.Name][ver] == "" {
+            // materialized from vendor/ or the proxy without an ami.sum entry yet
+            if h, err := hashDir(filepath.Join(cache, item.Name, ver)); err == nil { manifest.Set(item.Name, ver, h) }
         }
+        selected = append(selected, item)
+    }
+    sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+
+    // Verify publisher signatures (ami.workspace trust) before recording anything.
+    var sigFailures []string
+    for i, it := range selected {
+        pdir := filepath.Join(cache, it.Name, it.Version)
+        if st, err := os.Stat(pdir); err != nil || !st.IsDir() {
+            // a package that cannot be inspected cannot be trusted
+            if ws.Trust.Enabled() {
+                sigFailures = append(sigFailures, (&workspace.SignatureError{Package: it.Name + "@" + it.Version, Reason: "missing", Detail: "not in the package cache " + cache}).Error())
+            }
+            continue
+        }
+        signer, err := workspace.VerifyPackage(pdir, it.Name+"@"+it.Version, ws.Trust)
+        if err != nil {
+            sigFailures = append(sigFailures, err.Error())
+            continue
+        }
+        selected[i].Signer = signer
+    }
+    if len(sigFailures) > 0 {
+        if jsonOut {
+            _ = json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Selected: selected, Message: "signature verification failed", SignatureFailures: sigFailures})
+        } else {
+            for _, f := range sigFailures { _, _ = fmt.Fprintln(out, f) }
+        }
+        return exit.New(exit.Signature, "signature verification failed: %s", strings.Join(sigFailures, "; "))
+    }
+
+    // Describe the resolved graph for ami.lock (--locked builds, `ami mod why`).
+    var prev workspace.Lock
+    _ = prev.Load(filepath.Join(dir, "ami.lock")) // best-effort: reuse locked commits offline
+    lock, err := workspace.NewLock(res, modLockDescriber(src, &prev))
+    if err != nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Message: "lock failed"}) }
+        return exit.New(exit.Network, "lock: %v", err)
     }
 
     // Persist ami.sum using canonical Manifest writer
@@ -93,6 +171,10 @@ func runModUpdate(out io.Writer, dir string, jsonOut bool) error {
         if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Message: "write ami.sum failed"}) }
         return exit.New(exit.IO, "write ami.sum: %v", err)
     }
+    if err := lock.Save(filepath.Join(dir, "ami.lock")); err != nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modUpdateResult{Message: "write ami.lock failed"}) }
+        return exit.New(exit.IO, "write ami.lock: %v", err)
+    }
 
     // Sort updated for deterministic output
     sort.Slice(updated, func(i, j int) bool {
@@ -103,7 +185,7 @@ func runModUpdate(out io.Writer, dir string, jsonOut bool) error {
     })
 
     if jsonOut {
-        return json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Selected: selected, Message: "ok", Audit: embedAudit(auditRep)})
+        return json.NewEncoder(out).Encode(modUpdateResult{Updated: updated, Selected: selected, Unresolved: res.Unresolved, Message: "ok", Audit: embedAudit(auditRep)})
     }
     // Human-readable audit summary (non-fatal)
     if len(auditRep.ParseErrors) > 0 { _, _ = fmt.Fprintf(out, "audit: parse errors: %d\n", len(auditRep.ParseErrors)) }
@@ -120,6 +202,9 @@ func runModUpdate(out io.Writer, dir string, jsonOut bool) error {
             _, _ = fmt.Fprintf(out, "select %s@%s\n", s.Name, s.Version)
         }
     }
+    for _, u := range res.Unresolved {
+        _, _ = fmt.Fprintf(out, "unresolved %s: no git source or ami.sum versions\n", u)
+    }
     return nil
 }
 
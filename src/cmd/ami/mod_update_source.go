@@ -0,0 +1,123 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/semver"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// modUpdateSource supplies versions and per-version imports to the resolver
+// used by `ami mod update`. Versions present in a local tree (vendor/ or the
+// AMI_MODULE_PROXY directory, see modLocalDirs) are materialized from there,
+// verified against ami.sum, without touching the network. Git sources
+// (git+ssh://, file+git://) also list their semver tags, merged with the
+// local versions (the local ones alone when the remote is unreachable), and
+// other versions are fetched into the package cache so the ami.workspace of
+// each candidate version can be read; other import paths are served from
+// their local versions, else the versions recorded in ami.sum and whatever
+// the cache holds for them.
+type modUpdateSource struct {
+    cache    string
+    manifest *workspace.Manifest
+    local    []string
+}
+
+// newModUpdateSource returns the source for the workspace at dir.
+func newModUpdateSource(dir, cache string, manifest *workspace.Manifest) *modUpdateSource {
+    return &modUpdateSource{cache: cache, manifest: manifest, local: modLocalDirs(dir)}
+}
+
+func (s *modUpdateSource) Versions(path string) ([]string, bool, error) {
+    local := s.localVersions(modPackageName(path))
+    if !isGitSource(path) {
+        if len(local) > 0 { return local, true, nil }
+        vers := s.manifest.Versions(path)
+        return vers, len(vers) > 0, nil
+    }
+    tags, err := listGitTags(gitCloneArg(path))
+    if err != nil {
+        // an unreachable remote still leaves the local versions to choose from
+        if len(local) > 0 { return local, true, nil }
+        return nil, true, err
+    }
+    vers := local
+    seen := map[string]bool{}
+    for _, v := range local { seen[v] = true }
+    for _, t := range tags {
+        if semver.ValidateVersion(t) && !seen[t] { seen[t] = true; vers = append(vers, t) }
+    }
+    return vers, true, nil
+}
+
+func (s *modUpdateSource) Imports(path, version string) ([]string, error) {
+    dir := filepath.Join(s.cache, modPackageName(path), version)
+    if err := s.fetch(path, version); err != nil { return nil, err }
+    var ws workspace.Workspace
+    if err := ws.Load(filepath.Join(dir, "ami.workspace")); err != nil {
+        // a version without a (readable) workspace declares no imports
+        if os.IsNotExist(err) { return nil, nil }
+        return nil, err
+    }
+    var out []string
+    for _, e := range ws.Packages { out = append(out, e.Package.Import...) }
+    return out, nil
+}
+
+// localVersions lists the semver version directories of name across the local trees.
+func (s *modUpdateSource) localVersions(name string) []string {
+    var out []string
+    seen := map[string]bool{}
+    for _, root := range s.local {
+        ents, _ := os.ReadDir(filepath.Join(root, name))
+        for _, e := range ents {
+            if e.IsDir() && semver.ValidateVersion(e.Name()) && !seen[e.Name()] {
+                seen[e.Name()] = true
+                out = append(out, e.Name())
+            }
+        }
+    }
+    return out
+}
+
+// fetch materializes path@version in the cache: from the first local tree
+// holding it, else by cloning git sources; other paths are left as they are.
+// Cached, local and freshly cloned trees alike must match ami.sum when it
+// records name@version (a *modIntegrityError otherwise), so a tampered cache
+// or a force-moved tag is never recorded as the locked content.
+func (s *modUpdateSource) fetch(path, version string) error {
+    name := modPackageName(path)
+    dest := filepath.Join(s.cache, name, version)
+    if st, err := os.Stat(dest); err == nil && st.IsDir() {
+        return checkSumEntry(s.manifest.Packages, name, version, dest, "the package cache")
+    }
+    for _, root := range s.local {
+        src := filepath.Join(root, name, version)
+        if st, err := os.Stat(src); err != nil || !st.IsDir() { continue }
+        if err := checkSumEntry(s.manifest.Packages, name, version, src, root); err != nil { return err }
+        return copyDir(src, dest)
+    }
+    if !isGitSource(path) { return nil }
+    if err := fetchGitToCache(path, version, dest); err != nil { return err }
+    if err := checkSumEntry(s.manifest.Packages, name, version, dest, path); err != nil {
+        _ = os.RemoveAll(dest)
+        return err
+    }
+    return nil
+}
+
+// gitCloneArg returns the argument git expects for a source: the absolute
+// path of a file+git:// source, or the URL itself.
+func gitCloneArg(src string) string {
+    if strings.HasPrefix(src, "file+git://") { return strings.TrimPrefix(src, "file+git://") }
+    return src
+}
+
+// modPackageName returns the cache/ami.sum name for an import path: the
+// repository base name (without .git) for git sources, else the path itself.
+func modPackageName(path string) string {
+    if !isGitSource(path) { return path }
+    return strings.TrimSuffix(filepath.Base(gitCloneArg(path)), ".git")
+}
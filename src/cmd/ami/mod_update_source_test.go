@@ -0,0 +1,43 @@
+package main
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "sort"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+func TestModUpdateSource_Versions_MergesLocalAndGitTags(t *testing.T) {
+    if _, err := exec.LookPath("git"); err != nil { t.Skip("git not available") }
+    root := t.TempDir()
+    repo := filepath.Join(root, "lib")
+    for _, c := range [][]string{
+        {"init", "-q"},
+        {"-c", "user.email=t@example.com", "-c", "user.name=T", "-c", "commit.gpgSign=false", "commit", "--allow-empty", "-q", "-m", "init"},
+        {"-c", "tag.gpgSign=false", "tag", "v1.0.0"},
+        {"-c", "tag.gpgSign=false", "tag", "v2.0.0"},
+    } {
+        cmd := exec.Command("git", c...)
+        cmd.Dir = repo
+        if err := os.MkdirAll(repo, 0o755); err != nil { t.Fatal(err) }
+        if out, err := cmd.CombinedOutput(); err != nil { t.Fatalf("git %v: %v\n%s", c, err, out) }
+    }
+    ws := filepath.Join(root, "ws")
+    for _, v := range []string{"v1.0.0", "v1.1.0"} {
+        if err := os.MkdirAll(filepath.Join(ws, "vendor", "lib", v), 0o755); err != nil { t.Fatal(err) }
+    }
+    t.Setenv("AMI_MODULE_PROXY", "")
+    s := newModUpdateSource(ws, filepath.Join(root, "cache"), &workspace.Manifest{})
+    vers, known, err := s.Versions("file+git://" + repo)
+    if err != nil || !known { t.Fatalf("versions: %v known=%v", err, known) }
+    sort.Strings(vers)
+    if strings.Join(vers, ",") != "v1.0.0,v1.1.0,v2.0.0" { t.Fatalf("versions: %v", vers) }
+    // an unreachable remote falls back to the vendored versions
+    if err := os.RemoveAll(repo); err != nil { t.Fatal(err) }
+    vers, _, err = s.Versions("file+git://" + repo)
+    if err != nil || strings.Join(vers, ",") != "v1.0.0,v1.1.0" { t.Fatalf("offline versions: %v %v", vers, err) }
+}
@@ -0,0 +1,20 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModUpgradeCmd returns `ami mod upgrade`.
+func newModUpgradeCmd() *cobra.Command {
+    var jsonOut bool
+    var major bool
+    cmd := &cobra.Command{
+        Use:   "upgrade",
+        Short: "Raise import constraints to the newest versions and refresh ami.sum",
+        Example: "\n  # Raise constraints to the newest compatible versions\n  ami mod upgrade\n\n  # Also cross major versions\n  ami mod upgrade --major --json\n",
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModUpgrade(cmd.OutOrStdout(), ".", major, jsonOut)
+        },
+    }
+    cmd.Flags().BoolVar(&major, "major", false, "upgrade to the newest version even when it breaks the constraint")
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
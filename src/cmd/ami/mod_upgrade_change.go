@@ -0,0 +1,9 @@
+package main
+
+// modUpgradeChange records one rewritten import entry of a workspace package.
+type modUpgradeChange struct {
+    Package string `json:"package"`
+    Path    string `json:"path"`
+    From    string `json:"from"`
+    To      string `json:"to"`
+}
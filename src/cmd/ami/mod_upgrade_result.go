@@ -0,0 +1,7 @@
+package main
+
+type modUpgradeResult struct {
+    Changes []modUpgradeChange `json:"changes"`
+    Update  *modUpdateResult   `json:"update,omitempty"`
+    Message string             `json:"message,omitempty"`
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/semver"
+)
+
+// upgradeConstraint raises the version named by constraint c to target,
+// keeping the operator, spacing and v-prefix style. A `>` bound becomes `>=` so
+// the result admits target. It returns "" when c is empty, "latest", an upper
+// bound (`<`, `<=`), unsupported, or already at or above target.
+func upgradeConstraint(c, target string) string {
+    // raising an upper bound would widen the range past what the import allows
+    if strings.HasPrefix(strings.TrimSpace(c), "<") { return "" }
+    con, err := semver.ParseConstraint(c)
+    if err != nil || con.Latest || !semverLess(con.Version, target) { return "" }
+    i := strings.IndexFunc(c, func(r rune) bool { return r >= '0' && r <= '9' })
+    if i < 0 { return "" }
+    op := c[:i]
+    if con.Op == ">" { op = strings.Replace(op, ">", ">=", 1) }
+    return op + strings.TrimPrefix(target, "v")
+}
+
+// rewriteImportEntries replaces whole import list items in workspace text:
+// a line whose `- ` item (optionally quoted, optionally followed by a comment)
+// equals a key of repl. Formatting and comments elsewhere are preserved.
+func rewriteImportEntries(text string, repl map[string]string) string {
+    lines := strings.SplitAfter(text, "\n")
+    for i, ln := range lines {
+        body := strings.TrimRight(ln, "\r\n")
+        eol := ln[len(body):]
+        trimmed := strings.TrimLeft(body, " \t")
+        if !strings.HasPrefix(trimmed, "- ") { continue }
+        item := strings.TrimLeft(trimmed[2:], " \t")
+        indent := body[:len(body)-len(item)]
+        if j := strings.Index(item, " #"); j >= 0 { item = item[:j] }
+        item = strings.TrimRight(item, " \t")
+        tail := body[len(indent)+len(item):]
+        quote := ""
+        if len(item) >= 2 && (item[0] == '"' || item[0] == '\'') && item[len(item)-1] == item[0] {
+            quote, item = item[:1], item[1:len(item)-1]
+        }
+        if to, ok := repl[item]; ok { lines[i] = indent + quote + to + quote + tail + eol }
+    }
+    return strings.Join(lines, "")
+}
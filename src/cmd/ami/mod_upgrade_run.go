@@ -0,0 +1,99 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// runModUpgrade raises each remote import constraint to the newest compatible
+// version (the newest overall with major), rewrites the entries in
+// ami.workspace in place and refreshes ami.sum and ami.lock via `ami mod
+// update`. If the update fails the original ami.workspace is restored.
+func runModUpgrade(out io.Writer, dir string, major bool, jsonOut bool) error {
+    fail := func(code exit.Code, res modUpgradeResult, format string, args ...any) error {
+        if jsonOut {
+            if res.Changes == nil { res.Changes = []modUpgradeChange{} }
+            res.Message = fmt.Sprintf(format, args...)
+            _ = json.NewEncoder(out).Encode(res)
+        }
+        return exit.New(code, format, args...)
+    }
+    wsPath := filepath.Join(dir, "ami.workspace")
+    var ws workspace.Workspace
+    if err := ws.Load(wsPath); err != nil { return fail(exit.User, modUpgradeResult{}, "workspace invalid: %v", err) }
+    orig, err := os.ReadFile(wsPath)
+    if err != nil { return fail(exit.IO, modUpgradeResult{}, "read workspace: %v", err) }
+    entries, err := collectModOutdated(dir, &ws)
+    if err != nil { return fail(exit.Network, modUpgradeResult{}, "upgrade: %v", err) }
+
+    res := modUpgradeResult{Changes: []modUpgradeChange{}}
+    repl := map[string]string{}
+    for _, e := range entries {
+        target := e.Compatible
+        if major { target = e.Latest }
+        c := upgradeConstraint(e.Constraint, target)
+        if c == "" { continue }
+        to := e.Entry[:len(e.Entry)-len(e.Constraint)] + c
+        repl[e.Entry] = to
+        res.Changes = append(res.Changes, modUpgradeChange{Package: e.Package, Path: e.Path, From: e.Entry, To: to})
+    }
+    if len(res.Changes) == 0 {
+        if jsonOut { return json.NewEncoder(out).Encode(res) }
+        _, _ = fmt.Fprintln(out, "all import constraints are current")
+        return nil
+    }
+    restore := func() { _ = os.WriteFile(wsPath, orig, 0o644) }
+    if err := os.WriteFile(wsPath, []byte(rewriteImportEntries(string(orig), repl)), 0o644); err != nil {
+        return fail(exit.IO, res, "write workspace: %v", err)
+    }
+    // confirm the text rewrite landed on every entry (e.g. not flow-style YAML)
+    var after workspace.Workspace
+    if err := after.Load(wsPath); err != nil {
+        restore()
+        return fail(exit.User, res, "rewrite workspace: %v", err)
+    }
+    for _, ch := range res.Changes {
+        if !packageImports(&after, ch.Package, ch.To) {
+            restore()
+            return fail(exit.User, res, "cannot rewrite import %q of package %s in ami.workspace; edit it by hand", ch.From, ch.Package)
+        }
+    }
+
+    if !jsonOut {
+        for _, ch := range res.Changes { _, _ = fmt.Fprintf(out, "%s: %s -> %s\n", ch.Package, ch.From, ch.To) }
+        if err := runModUpdate(out, dir, false); err != nil {
+            restore()
+            return err
+        }
+        return nil
+    }
+    var buf bytes.Buffer
+    uerr := runModUpdate(&buf, dir, true)
+    var upd modUpdateResult
+    if json.Unmarshal(buf.Bytes(), &upd) == nil { res.Update = &upd }
+    if uerr != nil {
+        restore()
+        res.Message = uerr.Error()
+        _ = json.NewEncoder(out).Encode(res)
+        return uerr
+    }
+    return json.NewEncoder(out).Encode(res)
+}
+
+// packageImports reports whether the named workspace package imports entry.
+func packageImports(ws *workspace.Workspace, name, entry string) bool {
+    for _, e := range ws.Packages {
+        p := e.Package
+        workspace.NormalizeImports(&p)
+        if p.Name != name && e.Key != name { continue }
+        for _, ent := range p.Import { if ent == entry { return true } }
+    }
+    return false
+}
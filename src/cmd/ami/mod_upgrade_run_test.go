@@ -0,0 +1,89 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+const modUpgradeLib = "git+ssh://git.invalid/org/lib.git"
+
+// setupModUpgrade creates a workspace importing lib ^1.0.0 and a proxy
+// directory offering lib v1.0.0, v1.4.0 and v2.1.0.
+func setupModUpgrade(t *testing.T, name string) string {
+    t.Helper()
+    base := filepath.Join("build", "test", "mod_upgrade", name)
+    _ = os.RemoveAll(base)
+    dir := filepath.Join(base, "ws")
+    proxy := filepath.Join(base, "proxy")
+    for _, v := range []string{"v1.0.0", "v1.4.0", "v2.1.0"} {
+        if err := os.MkdirAll(filepath.Join(proxy, "lib", v), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+        if err := os.WriteFile(filepath.Join(proxy, "lib", v, "lib.ami"), []byte("package lib // "+v+"\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    }
+    if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    ws := workspace.DefaultWorkspace()
+    ws.Packages = workspace.PackageList{
+        {Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{modUpgradeLib + " ^1.0.0"}}},
+    }
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    oldCache, oldProxy := os.Getenv("AMI_PACKAGE_CACHE"), os.Getenv("AMI_MODULE_PROXY")
+    t.Cleanup(func() { _ = os.Setenv("AMI_PACKAGE_CACHE", oldCache); _ = os.Setenv("AMI_MODULE_PROXY", oldProxy) })
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache"))
+    absProxy, _ := filepath.Abs(proxy)
+    _ = os.Setenv("AMI_MODULE_PROXY", absProxy)
+    return dir
+}
+
+func lockedVersion(t *testing.T, dir string) string {
+    t.Helper()
+    var lock workspace.Lock
+    if err := lock.Load(filepath.Join(dir, "ami.lock")); err != nil { t.Fatalf("lock: %v", err) }
+    for _, p := range lock.Packages { if p.Name == "lib" { return p.Version } }
+    return ""
+}
+
+func TestModOutdated_ReportsCompatibleAndLatest(t *testing.T) {
+    dir := setupModUpgrade(t, "outdated")
+    var buf bytes.Buffer
+    if err := runModUpdate(&buf, dir, true); err != nil { t.Fatalf("update: %v; out=%s", err, buf.String()) }
+    buf.Reset()
+    if err := runModOutdated(&buf, dir, true); err != nil { t.Fatalf("outdated: %v; out=%s", err, buf.String()) }
+    var res modOutdatedResult
+    if err := json.Unmarshal(buf.Bytes(), &res); err != nil { t.Fatalf("json: %v; out=%s", err, buf.String()) }
+    if len(res.Requirements) != 1 { t.Fatalf("requirements: %+v", res.Requirements) }
+    r := res.Requirements[0]
+    if r.Package != "app" || r.Path != modUpgradeLib || r.Constraint != "^1.0.0" || r.Current != "v1.4.0" || r.Compatible != "v1.4.0" || r.Latest != "v2.1.0" || !r.Outdated {
+        t.Fatalf("entry: %+v", r)
+    }
+    buf.Reset()
+    if err := runModOutdated(&buf, dir, false); err != nil { t.Fatalf("outdated human: %v", err) }
+    if !strings.Contains(buf.String(), "latest=v2.1.0") || !strings.Contains(buf.String(), "outdated") { t.Fatalf("human: %s", buf.String()) }
+}
+
+func TestModUpgrade_CompatibleThenMajor(t *testing.T) {
+    dir := setupModUpgrade(t, "upgrade")
+    var buf bytes.Buffer
+    if err := runModUpgrade(&buf, dir, false, true); err != nil { t.Fatalf("upgrade: %v; out=%s", err, buf.String()) }
+    var res modUpgradeResult
+    if err := json.Unmarshal(buf.Bytes(), &res); err != nil { t.Fatalf("json: %v; out=%s", err, buf.String()) }
+    if len(res.Changes) != 1 || res.Changes[0].To != modUpgradeLib+" ^1.4.0" || res.Update == nil { t.Fatalf("changes: %+v", res) }
+    if v := lockedVersion(t, dir); v != "v1.4.0" { t.Fatalf("locked %s", v) }
+
+    buf.Reset()
+    if err := runModUpgrade(&buf, dir, true, true); err != nil { t.Fatalf("upgrade --major: %v; out=%s", err, buf.String()) }
+    var ws workspace.Workspace
+    if err := ws.Load(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("load: %v", err) }
+    if imp := ws.Packages[0].Package.Import; len(imp) != 1 || imp[0] != modUpgradeLib+" ^2.1.0" { t.Fatalf("imports: %v", imp) }
+    if v := lockedVersion(t, dir); v != "v2.1.0" { t.Fatalf("locked %s", v) }
+    var sum workspace.Manifest
+    if err := sum.Load(filepath.Join(dir, "ami.sum")); err != nil || !sum.Has("lib", "v2.1.0") { t.Fatalf("ami.sum: %v %+v", err, sum.Packages) }
+
+    // nothing left to raise
+    buf.Reset()
+    if err := runModUpgrade(&buf, dir, true, false); err != nil || !strings.Contains(buf.String(), "current") { t.Fatalf("noop: %v; out=%s", err, buf.String()) }
+}
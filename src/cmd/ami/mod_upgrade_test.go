@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func Test_newModOutdatedCmd_exists(t *testing.T) { if newModOutdatedCmd() == nil { t.Fatal("nil") } }
+
+func Test_newModUpgradeCmd_exists(t *testing.T) { if newModUpgradeCmd() == nil { t.Fatal("nil") } }
+
+func Test_upgradeConstraint(t *testing.T) {
+    cases := []struct{ name, in, target, want string }{
+        {"caret", "^1.0.0", "v1.4.0", "^1.4.0"},
+        {"caret_current", "^1.4.0", "v1.4.0", ""},
+        {"tilde", "~1.2.3", "v1.2.9", "~1.2.9"},
+        {"tilde_current", "~1.2.3", "v1.2.3", ""},
+        {"gte_spaced_v", ">= v1.2.0", "v2.0.0", ">= v2.0.0"},
+        {"gt_becomes_gte", ">1.0.0", "v2.0.0", ">=2.0.0"},
+        {"gt_spaced_v", "> v1.0.0", "v3.0.0", ">= v3.0.0"},
+        {"exact", "1.0.0", "v1.4.0", "1.4.0"},
+        {"exact_v", "v1.0.0", "v1.4.0", "v1.4.0"},
+        {"equals_unsupported", "=1.0.0", "v1.4.0", ""},
+        {"lt_major", "<2.0.0", "v3.0.0", ""},
+        {"lte_major", "<=2.0.0", "v3.0.0", ""},
+        {"lt_spaced", "< v2.0.0", "v1.5.0", ""},
+        {"latest", "==latest", "v9.0.0", ""},
+        {"latest_word", "latest", "v9.0.0", ""},
+        {"empty", "", "v1.0.0", ""},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            if got := upgradeConstraint(tc.in, tc.target); got != tc.want { t.Fatalf("upgradeConstraint(%q,%q)=%q want %q", tc.in, tc.target, got, tc.want) }
+        })
+    }
+}
+
+func Test_rewriteImportEntries_PreservesFormatting(t *testing.T) {
+    in := "packages:\n  - main:\n      import:\n        - ./util\n        - \"lib ^1.0.0\" # pinned\n        - lib ^1.0.0\n"
+    want := "packages:\n  - main:\n      import:\n        - ./util\n        - \"lib ^1.4.0\" # pinned\n        - lib ^1.4.0\n"
+    if got := rewriteImportEntries(in, map[string]string{"lib ^1.0.0": "lib ^1.4.0"}); got != want { t.Fatalf("got:\n%s", got) }
+}
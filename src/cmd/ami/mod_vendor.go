@@ -0,0 +1,18 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModVendorCmd returns `ami mod vendor`.
+func newModVendorCmd() *cobra.Command {
+    var jsonOut bool
+    cmd := &cobra.Command{
+        Use:   "vendor",
+        Short: "Copy locked dependencies into the workspace vendor/ tree",
+        Example: "\n  # Materialize every package in ami.lock under vendor/<name>/<version>/\n  ami mod vendor\n\n  # JSON output\n  ami mod vendor --json\n",
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModVendor(cmd.OutOrStdout(), ".", jsonOut)
+        },
+    }
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
@@ -0,0 +1,8 @@
+package main
+
+type modVendorItem struct {
+    Name    string `json:"name"`
+    Version string `json:"version"`
+    Source  string `json:"source"`
+    Sha256  string `json:"sha256"`
+}
@@ -0,0 +1,7 @@
+package main
+
+type modVendorResult struct {
+    Path     string          `json:"path"`
+    Packages []modVendorItem `json:"packages"`
+    Message  string          `json:"message,omitempty"`
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// runModVendor rebuilds dir/vendor from ami.lock: each locked package is
+// materialized into the package cache if needed (existing vendor tree, proxy,
+// then git), verified against both its ami.lock and ami.sum hashes, and copied
+// to vendor/<name>/<version>/. vendor/ami.sum records the vendored hashes.
+// The new tree is built in a staging directory and renamed into place, so the
+// vendor tree is left untouched when any package fails verification or copying.
+func runModVendor(out io.Writer, dir string, jsonOut bool) error {
+    vendor := filepath.Join(dir, "vendor")
+    fail := func(code exit.Code, msg string) error {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modVendorResult{Path: vendor, Packages: []modVendorItem{}, Message: msg}) }
+        return exit.New(code, "%s", msg)
+    }
+    var lock workspace.Lock
+    if err := lock.Load(filepath.Join(dir, "ami.lock")); err != nil {
+        return fail(exit.User, fmt.Sprintf("load ami.lock: %v (run 'ami mod update')", err))
+    }
+    var manifest workspace.Manifest
+    if err := manifest.Load(filepath.Join(dir, "ami.sum")); err != nil {
+        return fail(exit.User, fmt.Sprintf("load ami.sum: %v (run 'ami mod update')", err))
+    }
+    cache, err := workspace.DefaultCacheRoot()
+    if err != nil { return fail(exit.IO, fmt.Sprintf("cache: %v", err)) }
+    src := newModUpdateSource(dir, cache, &manifest)
+
+    items := []modVendorItem{}
+    for _, p := range lock.Packages {
+        key := p.Name + "@" + p.Version
+        if err := src.fetch(p.Source, p.Version); err != nil { return fail(exit.Network, fmt.Sprintf("fetch %s: %v", key, err)) }
+        h, err := hashDir(filepath.Join(cache, p.Name, p.Version))
+        if err != nil { return fail(exit.IO, fmt.Sprintf("%s not in cache: %v", key, err)) }
+        if sum := manifest.Packages[p.Name][p.Version]; sum != h || p.Hash != h {
+            return fail(exit.Integrity, fmt.Sprintf("integrity: %s sha256 %s; ami.sum %q, ami.lock %q", key, h, sum, p.Hash))
+        }
+        items = append(items, modVendorItem{Name: p.Name, Version: p.Version, Source: p.Source, Sha256: h})
+    }
+
+    // build the new tree beside vendor/ and rename it into place, so a failed
+    // copy leaves the previous vendor tree intact
+    stage, err := os.MkdirTemp(dir, ".vendor-")
+    if err != nil { return fail(exit.IO, fmt.Sprintf("stage vendor: %v", err)) }
+    defer func() { _ = os.RemoveAll(stage) }()
+    sum := workspace.Manifest{Schema: "ami.sum/v1", Packages: map[string]map[string]string{}}
+    for _, it := range items {
+        if err := copyDir(filepath.Join(cache, it.Name, it.Version), filepath.Join(stage, it.Name, it.Version)); err != nil {
+            return fail(exit.IO, fmt.Sprintf("copy %s@%s: %v", it.Name, it.Version, err))
+        }
+        sum.Set(it.Name, it.Version, it.Sha256)
+    }
+    if err := sum.Save(filepath.Join(stage, "ami.sum")); err != nil { return fail(exit.IO, fmt.Sprintf("write vendor/ami.sum: %v", err)) }
+    if err := os.Chmod(stage, 0o755); err != nil { return fail(exit.IO, fmt.Sprintf("stage vendor: %v", err)) }
+    if err := replaceDir(stage, vendor); err != nil { return fail(exit.IO, fmt.Sprintf("replace vendor: %v", err)) }
+
+    if jsonOut { return json.NewEncoder(out).Encode(modVendorResult{Path: vendor, Packages: items}) }
+    for _, it := range items { _, _ = fmt.Fprintf(out, "vendored %s@%s\n", it.Name, it.Version) }
+    return nil
+}
+
+// replaceDir renames stage to dst. An existing dst is moved aside first and
+// restored when the rename fails; it is removed once stage is in place.
+func replaceDir(stage, dst string) error {
+    old := dst + ".old"
+    if err := os.RemoveAll(old); err != nil { return err }
+    if err := os.Rename(dst, old); err != nil && !os.IsNotExist(err) { return err }
+    if err := os.Rename(stage, dst); err != nil {
+        _ = os.Rename(old, dst)
+        return err
+    }
+    return os.RemoveAll(old)
+}
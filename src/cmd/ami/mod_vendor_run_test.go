@@ -0,0 +1,97 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// An air-gapped workspace resolves from the proxy directory, vendors the
+// result and later builds --locked from vendor/ alone.
+func TestModVendor_OfflineProxyThenVendor(t *testing.T) {
+    base := filepath.Join("build", "test", "mod_vendor", "offline")
+    _ = os.RemoveAll(base)
+    dir := filepath.Join(base, "ws")
+    proxy := filepath.Join(base, "proxy")
+    for _, v := range []string{"v1.0.0", "v1.1.0", "v2.0.0"} {
+        if err := os.MkdirAll(filepath.Join(proxy, "lib", v), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+        if err := os.WriteFile(filepath.Join(proxy, "lib", v, "lib.ami"), []byte("package lib // "+v+"\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    }
+    if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    ws := workspace.DefaultWorkspace()
+    ws.Packages = workspace.PackageList{
+        {Key: "main", Package: workspace.Package{Name: "app", Version: "1.0.0", Root: "./src", Import: []string{"git+ssh://git.invalid/org/lib.git ^1.0.0"}}},
+    }
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    oldCache, oldProxy := os.Getenv("AMI_PACKAGE_CACHE"), os.Getenv("AMI_MODULE_PROXY")
+    defer func() { _ = os.Setenv("AMI_PACKAGE_CACHE", oldCache); _ = os.Setenv("AMI_MODULE_PROXY", oldProxy) }()
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache1"))
+    absProxy, _ := filepath.Abs(proxy)
+    _ = os.Setenv("AMI_MODULE_PROXY", absProxy)
+
+    var buf bytes.Buffer
+    if err := runModUpdate(&buf, dir, true); err != nil { t.Fatalf("update: %v; out=%s", err, buf.String()) }
+    var sum workspace.Manifest
+    if err := sum.Load(filepath.Join(dir, "ami.sum")); err != nil || sum.Packages["lib"]["v1.1.0"] == "" {
+        t.Fatalf("ami.sum: %v %+v", err, sum.Packages)
+    }
+
+    buf.Reset()
+    if err := runModVendor(&buf, dir, true); err != nil { t.Fatalf("vendor: %v; out=%s", err, buf.String()) }
+    var res modVendorResult
+    if err := json.Unmarshal(buf.Bytes(), &res); err != nil { t.Fatalf("json: %v; out=%s", err, buf.String()) }
+    if len(res.Packages) != 1 || res.Packages[0].Version != "v1.1.0" || res.Packages[0].Sha256 != sum.Packages["lib"]["v1.1.0"] {
+        t.Fatalf("vendored: %+v", res.Packages)
+    }
+    var vsum workspace.Manifest
+    if err := vsum.Load(filepath.Join(dir, "vendor", "ami.sum")); err != nil || !vsum.Has("lib", "v1.1.0") { t.Fatalf("vendor/ami.sum: %v %+v", err, vsum.Packages) }
+
+    // re-vendoring replaces the tree and leaves no staging directories behind
+    stale := filepath.Join(dir, "vendor", "old", "v0.1.0")
+    if err := os.MkdirAll(stale, 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    buf.Reset()
+    if err := runModVendor(&buf, dir, true); err != nil { t.Fatalf("re-vendor: %v; out=%s", err, buf.String()) }
+    if _, err := os.Stat(stale); !os.IsNotExist(err) { t.Fatalf("stale vendor entry kept: %v", err) }
+    ents, _ := os.ReadDir(dir)
+    for _, e := range ents {
+        if strings.HasPrefix(e.Name(), ".vendor-") || e.Name() == "vendor.old" { t.Fatalf("leftover %s", e.Name()) }
+    }
+
+    // no proxy, empty cache: vendor/ alone reproduces the lock
+    _ = os.Setenv("AMI_MODULE_PROXY", "")
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache2"))
+    buf.Reset()
+    if err := runLockedCheck(&buf, dir, false); err != nil { t.Fatalf("locked from vendor: %v; out=%s", err, buf.String()) }
+
+    // a tampered vendor tree fails verification against ami.sum
+    _ = os.Setenv("AMI_PACKAGE_CACHE", filepath.Join(base, "cache3"))
+    if err := os.WriteFile(filepath.Join(dir, "vendor", "lib", "v1.1.0", "lib.ami"), []byte("package lib // evil\n"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    err := runLockedCheck(&buf, dir, false)
+    if exit.UnwrapCode(err) != exit.Integrity || !strings.Contains(err.Error(), "does not match ami.sum") {
+        t.Fatalf("expected integrity failure, got %v", err)
+    }
+}
+
+// A failed swap restores the previous vendor tree.
+func Test_replaceDir_RestoresOnFailure(t *testing.T) {
+    dir := t.TempDir()
+    dst := filepath.Join(dir, "vendor")
+    if err := os.MkdirAll(dst, 0o755); err != nil { t.Fatalf("mkdir: %v", err) }
+    if err := os.WriteFile(filepath.Join(dst, "ami.sum"), []byte("keep"), 0o644); err != nil { t.Fatalf("write: %v", err) }
+    if err := replaceDir(filepath.Join(dir, "missing"), dst); err == nil { t.Fatal("expected error for missing stage") }
+    if b, err := os.ReadFile(filepath.Join(dst, "ami.sum")); err != nil || string(b) != "keep" { t.Fatalf("vendor not restored: %v %q", err, b) }
+}
+
+func TestModVendor_RequiresLock(t *testing.T) {
+    dir := t.TempDir()
+    var buf bytes.Buffer
+    if err := runModVendor(&buf, dir, true); exit.UnwrapCode(err) != exit.User || !strings.Contains(buf.String(), "ami mod update") {
+        t.Fatalf("expected user error; err=%v out=%s", err, buf.String())
+    }
+}
@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func Test_newModVendorCmd_exists(t *testing.T) { if newModVendorCmd() == nil { t.Fatal("nil") } }
+
+func Test_modVendorResult_zero(t *testing.T) { var _ modVendorResult; var _ modVendorItem }
@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newModWhyCmd returns `ami mod why <pkg>`.
+func newModWhyCmd() *cobra.Command {
+    var jsonOut bool
+    cmd := &cobra.Command{
+        Use:   "why <package>",
+        Short: "Explain why a package is in ami.lock",
+        Example: "\n  # Show every requirement chain leading to a package\n  ami mod why log\n\n  # JSON output\n  ami mod why git+ssh://git@github.com/org/log.git --json\n",
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            return runModWhy(cmd.OutOrStdout(), ".", args[0], jsonOut)
+        },
+    }
+    cmd.Flags().BoolVar(&jsonOut, "json", false, "emit machine-parsable JSON output")
+    return cmd
+}
@@ -0,0 +1,8 @@
+package main
+
+type modWhyResult struct {
+    Package string     `json:"package"`
+    Version string     `json:"version,omitempty"`
+    Chains  [][]string `json:"chains"`
+    Message string     `json:"message,omitempty"`
+}
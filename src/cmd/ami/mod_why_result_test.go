@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func Test_modWhyResult_zero(t *testing.T) { var _ modWhyResult }
+
+func Test_modGraphResult_zero(t *testing.T) { var _ modGraphResult; var _ modGraphEdge }
@@ -0,0 +1,35 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/exit"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// runModWhy prints every requirement chain in dir/ami.lock from a workspace
+// package to pkg (a locked name or source), one chain per line.
+func runModWhy(out io.Writer, dir, pkg string, jsonOut bool) error {
+    var lock workspace.Lock
+    if err := lock.Load(filepath.Join(dir, "ami.lock")); err != nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modWhyResult{Package: pkg, Chains: [][]string{}, Message: "ami.lock not found or invalid"}) }
+        return exit.New(exit.User, "load ami.lock: %v (run 'ami mod update')", err)
+    }
+    chains := lock.Why(pkg)
+    if chains == nil {
+        if jsonOut { _ = json.NewEncoder(out).Encode(modWhyResult{Package: pkg, Chains: [][]string{}, Message: "not in ami.lock"}) }
+        return exit.New(exit.User, "%s is not in ami.lock", pkg)
+    }
+    res := modWhyResult{Package: pkg, Chains: chains}
+    for _, p := range lock.Packages {
+        if p.Name == pkg || p.Source == pkg { res.Version = p.Version }
+    }
+    if jsonOut { return json.NewEncoder(out).Encode(res) }
+    _, _ = fmt.Fprintf(out, "# %s@%s\n", pkg, res.Version)
+    for _, c := range chains { _, _ = fmt.Fprintln(out, strings.Join(c, " -> ")) }
+    return nil
+}
@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func Test_newModWhyCmd_exists(t *testing.T) { if newModWhyCmd() == nil { t.Fatal("nil") } }
+
+func Test_newModGraphCmd_exists(t *testing.T) { if newModGraphCmd() == nil { t.Fatal("nil") } }
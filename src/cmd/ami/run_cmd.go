@@ -16,6 +16,7 @@ import (
     ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
     "github.com/sam-caldwell/ami/src/ami/compiler/source"
     rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
     rmerge "github.com/sam-caldwell/ami/src/ami/runtime/merge"
     "github.com/sam-caldwell/ami/src/ami/workspace"
     ev "github.com/sam-caldwell/ami/src/schemas/events"
@@ -37,6 +38,9 @@ func newRunCmd() *cobra.Command {
     var format string
     var filterExpr string
     var transformExpr string
+    var checkpointDir string
+    var checkpointInterval string
+    var virtualTime string
     cmd := &cobra.Command{
         Use:   "run",
         Short: "Simulate a pipeline with merge Collect nodes using IR + runtime executor",
@@ -75,10 +79,22 @@ func newRunCmd() *cobra.Command {
             defer eng.Close()
             in := make(chan ev.Event, 1024)
             // timeout / cancellation
+            if virtualTime != "" {
+                at, err := parseVirtualStart(virtualTime)
+                if err != nil { return fmt.Errorf("invalid --virtual-time: %v", err) }
+                _, stop := startVirtualClock(at)
+                defer stop()
+            }
             base := context.Background()
             ctx, cancel := context.WithCancel(base)
             defer cancel()
             if timeout != "" { if d, e := time.ParseDuration(timeout); e == nil { c, cancel2 := context.WithTimeout(ctx, d); ctx = c; defer cancel2() } }
+            var cpEvery time.Duration
+            if checkpointInterval != "" {
+                d, err := time.ParseDuration(checkpointInterval)
+                if err != nil { return fmt.Errorf("invalid --checkpoint-interval: %v", err) }
+                cpEvery = d
+            }
             // decide single collect or full pipeline; wire stats emitter when requested
             var out <-chan ev.Event
             statsCh := make(chan map[string]any, 16)
@@ -102,11 +118,16 @@ func newRunCmd() *cobra.Command {
                 out = oc
             } else {
                 var oc <-chan ev.Event
-                if stats {
-                    outCh, sc, err := eng.RunPipelineWithStats(ctx, module, pipeline, in, emitStage, filterExpr, transformExpr, rexec.ExecOptions{SourceType: srcType, TimerInterval: parseRate(rate), TimerCount: count})
+                // filter/transform expressions and checkpoints only apply on the stats-aware executor
+                if stats || filterExpr != "none" || transformExpr != "none" || checkpointDir != "" {
+                    outCh, sc, err := eng.RunPipelineWithStats(ctx, module, pipeline, in, emitStage, filterExpr, transformExpr, rexec.ExecOptions{SourceType: srcType, TimerInterval: parseRate(rate), TimerCount: count, Checkpoint: rexec.CheckpointOptions{Dir: checkpointDir, Interval: cpEvery}})
                     if err != nil { return err }
                     oc = outCh
                     stageStats = sc
+                    if !stats {
+                        go func(){ for range sc {} }()
+                        go func(){ for range statsCh {} }()
+                    }
                 } else {
                     outCh, err := eng.RunPipeline(ctx, module, pipeline, in)
                     if err != nil { return err }
@@ -129,8 +150,8 @@ func newRunCmd() *cobra.Command {
                 go func(){
                     max := count
                     for i := 0; max == 0 || i < max; i++ {
-                        in <- ev.Event{Payload: map[string]any{"i": i, "ts": time.Now().UTC()}}
-                        time.Sleep(d)
+                        in <- ev.Event{Payload: map[string]any{"i": i, "ts": amitime.GetClock().Now().UTC()}}
+                        amitime.Sleep(d)
                     }
                     close(in); close(done)
                 }()
@@ -221,8 +242,12 @@ func newRunCmd() *cobra.Command {
     cmd.Flags().StringVar(&sink, "sink", "stdout", "sink: stdout|file")
     cmd.Flags().StringVar(&sinkPath, "sink-path", "", "sink file path when --sink=file")
     cmd.Flags().StringVar(&format, "format", "jsonl", "output format: jsonl|pretty")
-    cmd.Flags().StringVar(&filterExpr, "filter", "none", "filter DSL stub (e.g., drop_even)")
-    cmd.Flags().StringVar(&transformExpr, "transform", "none", "transform DSL stub (e.g., add_field:flag)")
+    cmd.Flags().StringVar(&filterExpr, "filter", "none", "filter expression over event payload (e.g., 'i % 2 == 1 && has(user)')")
+    cmd.Flags().StringVar(&checkpointDir, "checkpoint-dir", "", "persist Collect (merge) state here and resume from it on start (ami run only; built binaries do not checkpoint)")
+    cmd.Flags().StringVar(&checkpointInterval, "checkpoint-interval", "", "snapshot Collect state at this interval (e.g., 5s); default only at exit")
+    cmd.Flags().StringVar(&transformExpr, "transform", "none", "transform statements over event payload (e.g., 'set flag = true; del tmp')")
+    cmd.Flags().StringVar(&virtualTime, "virtual-time", "", "run on a virtual clock starting at this RFC 3339 instant (bare flag: "+virtualEpoch.Format(time.RFC3339)+"); timers fire without real waiting")
+    cmd.Flags().Lookup("virtual-time").NoOptDefVal = virtualEpoch.Format(time.RFC3339)
     return cmd
 }
 
@@ -0,0 +1,38 @@
+package main
+
+import (
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+// virtualDriveStep is the real-time pause between virtual clock jumps, giving
+// stages time to react to the timers that just fired.
+const virtualDriveStep = time.Millisecond
+
+// startVirtualClock installs a virtual clock reading start as the runtime clock
+// and drives it until stop is called: while timers are pending the clock jumps
+// straight to the earliest deadline, so timer sources, merge timeouts and TTLs
+// run as fast as the pipeline keeps up, with deterministic timestamps. stop
+// restores the previous clock.
+func startVirtualClock(start time.Time) (*amitime.VirtualClock, func()) {
+    clk := amitime.NewVirtualClock(start)
+    prev := amitime.SetClock(clk)
+    done := make(chan struct{})
+    exited := make(chan struct{})
+    go func(){
+        defer close(exited)
+        for {
+            select {
+            case <-done:
+                return
+            case <-time.After(virtualDriveStep):
+                clk.AdvanceNext()
+            }
+        }
+    }()
+    return clk, func(){ close(done); <-exited; amitime.SetClock(prev) }
+}
+
+// parseVirtualStart parses the --virtual-time start instant (RFC 3339).
+func parseVirtualStart(s string) (time.Time, error) { return time.Parse(time.RFC3339Nano, s) }
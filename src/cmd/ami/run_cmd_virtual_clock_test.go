@@ -0,0 +1,22 @@
+package main
+
+import (
+    "testing"
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+func TestStartVirtualClock_DrivesTimersAndRestores(t *testing.T) {
+    at, err := parseVirtualStart("2024-01-01T00:00:00Z")
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if _, err := parseVirtualStart("tomorrow"); err == nil { t.Fatalf("expected parse error") }
+    clk, stop := startVirtualClock(at)
+    if amitime.GetClock() != amitime.Clock(clk) { t.Fatalf("virtual clock not installed") }
+    began := time.Now()
+    amitime.Sleep(24 * time.Hour) // returns once the driver jumps to the deadline
+    if time.Since(began) > 2*time.Second { t.Fatalf("virtual sleep waited in real time") }
+    if got := clk.Now(); !got.Equal(at.Add(24 * time.Hour)) { t.Fatalf("now=%v", got) }
+    stop()
+    if _, ok := amitime.GetClock().(amitime.SystemClock); !ok { t.Fatalf("stop should restore the system clock") }
+}
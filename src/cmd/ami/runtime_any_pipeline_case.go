@@ -0,0 +1,6 @@
+package main
+
+func anyPipelineCase(cases []runtimeCase) bool {
+    for _, c := range cases { if c.Spec.Pipeline != "" { return true } }
+    return false
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+    "time"
+
+    amitime "github.com/sam-caldwell/ami/src/ami/runtime/host/time"
+)
+
+// virtualEpoch is where every virtual case clock starts, so event timestamps
+// and timer ticks are identical from run to run.
+var virtualEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// caseClock returns a fresh virtual clock for c when virtual time is selected
+// (ami test --virtual-time, or advance= on the case), otherwise nil.
+func caseClock(c runtimeCase) *amitime.VirtualClock {
+    if !currentTestOptions.VirtualTime && c.Spec.Advance <= 0 { return nil }
+    return amitime.NewVirtualClock(virtualEpoch)
+}
+
+// usesVirtualTime reports whether any pipeline case runs on a virtual clock.
+func usesVirtualTime(cases []runtimeCase) bool {
+    for _, c := range cases {
+        if c.Spec.Pipeline == "" { continue }
+        if currentTestOptions.VirtualTime || c.Spec.Advance > 0 { return true }
+    }
+    return false
+}
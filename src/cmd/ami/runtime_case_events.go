@@ -0,0 +1,12 @@
+package main
+
+import "encoding/json"
+
+// caseEvents decodes a pragma JSON value: an array lists events, any other value is one event.
+func caseEvents(s string) ([]any, error) {
+    if s == "" { return nil, nil }
+    var v any
+    if err := json.Unmarshal([]byte(s), &v); err != nil { return nil, err }
+    if arr, ok := v.([]any); ok { return arr, nil }
+    return []any{v}, nil
+}
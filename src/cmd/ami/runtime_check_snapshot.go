@@ -0,0 +1,40 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/runtime/tester"
+)
+
+// maxSnapshotDiffLines bounds the snapshot diff reported for one case.
+const maxSnapshotDiffLines = 20
+
+// checkSnapshot diffs the run's events.v1/errors.v1 streams against the case's
+// golden file, or rewrites the golden file when `ami test --update` is set.
+func checkSnapshot(dir string, c runtimeCase, res tester.PipelineResult, r pipelineCaseResult) pipelineCaseResult {
+    fail := func(err error) pipelineCaseResult {
+        r.ok, r.err, r.errCode, r.errMsg = false, err, "E_TEST_SNAPSHOT", err.Error()
+        return r
+    }
+    path, err := snapshotPath(dir, c)
+    if err != nil { return fail(err) }
+    rel := path
+    if p, err := filepath.Rel(dir, path); err == nil { rel = p }
+    s := tester.NewSnapshot(c.Spec.Pipeline, res, c.Spec.Ignore)
+    if c.Spec.Unordered { s = s.Sorted() }
+    if currentTestOptions.Update {
+        if err := tester.WriteSnapshot(path, s); err != nil { return fail(fmt.Errorf("write snapshot %s: %v", rel, err)) }
+        return r
+    }
+    d, err := tester.DiffSnapshot(path, s)
+    if os.IsNotExist(err) { return fail(fmt.Errorf("snapshot %s missing; run 'ami test --update' to record it", rel)) }
+    if err != nil { return fail(fmt.Errorf("read snapshot %s: %v", rel, err)) }
+    if len(d) == 0 { return r }
+    if n := len(d); n > maxSnapshotDiffLines { d = append(d[:maxSnapshotDiffLines], fmt.Sprintf("... (%d more)", n-maxSnapshotDiffLines)) }
+    r.ok = false
+    r.diff = "snapshot " + rel + ":\n    " + strings.Join(d, "\n    ")
+    return r
+}
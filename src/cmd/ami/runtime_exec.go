@@ -13,6 +13,7 @@ import (
     "strings"
     "time"
 
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
     "github.com/sam-caldwell/ami/src/ami/runtime/tester"
     "github.com/sam-caldwell/ami/src/ami/runtime/errorpipe"
     "github.com/sam-caldwell/ami/src/ami/runtime/kvstore"
@@ -51,17 +52,32 @@ func runRuntime(dir string, jsonOut bool, verbose bool, out ioWriter, cases []ru
     if jsonOut {
         _ = json.NewEncoder(out).Encode(map[string]any{"schema":"test.v1","type":"run_start","timeout_ms": currentTestOptions.TimeoutMs, "parallel": currentTestOptions.Parallel})
     }
+    // Pipeline cases run against the compiled workspace; exec resolves debug
+    // artifacts relative to the working directory, so stay in dir for the run.
+    var mods map[string]ir.Module
+    var compileErr error
+    var workers runtimeWorkers
+    if anyPipelineCase(cases) {
+        if abs, err := filepath.Abs(dir); err == nil { dir = abs }
+        if oldwd, err := os.Getwd(); err == nil {
+            defer func(){ _ = os.Chdir(oldwd) }()
+        }
+        if compileErr = os.Chdir(dir); compileErr == nil {
+            mods, compileErr = compileRuntimePipelines(dir, cases)
+        }
+        if compileErr == nil { workers = buildRuntimeWorkers(mods) }
+    }
     // Worker pool
     par := currentTestOptions.Parallel
-    if par <= 0 { par = 1 }
+    if par <= 0 || usesVirtualTime(cases) { par = 1 } // the runtime clock is process-wide
     ch := make(chan runtimeCase)
-type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode string; errMsg string; dur time.Duration }
+    type result struct{ c runtimeCase; ok bool; skipped bool; reason string; err error; errCode string; errMsg string; diff string; dur time.Duration }
     res := make(chan result)
     // start workers
     for i := 0; i < par; i++ {
         go func() {
             for c := range ch {
-                if c.Spec.SkipReason != "" { res <- result{c: c, ok: true, skipped: true, dur: 0}; continue }
+                if c.Spec.SkipReason != "" { res <- result{c: c, ok: true, skipped: true, reason: c.Spec.SkipReason, dur: 0}; continue }
                 // Validate fixtures
                 valid := true
                 for _, fx := range c.Spec.Fixtures {
@@ -72,8 +88,8 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
                 }
                 if !valid { res <- result{c: c, ok: false, skipped: false, err: errors.New("invalid fixtures"), dur: 0}; continue }
                 // KV pre-ops
-                var st *kvstore.Store
-                if c.Spec.KvNS != "" { st = kvstore.Namespace(c.Spec.KvNS) } else { st = kvstore.Default() }
+                st, err := caseKvStore(c)
+                if err != nil { res <- result{c: c, err: err, errCode: "E_KV_OPEN", errMsg: err.Error()}; continue }
                 if len(c.Spec.KvPut) > 0 {
                     for k, v := range c.Spec.KvPut { st.Put(k, v) }
                 }
@@ -82,6 +98,32 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
                 to := time.Duration(currentTestOptions.TimeoutMs) * time.Millisecond
                 if c.Spec.TimeoutMs > 0 { to = time.Duration(c.Spec.TimeoutMs) * time.Millisecond }
                 if to > 0 { var cancel context.CancelFunc; ctx, cancel = context.WithTimeout(ctx, to); defer cancel() }
+                if c.Spec.Property != nil && c.Spec.Pipeline == "" {
+                    res <- result{c: c, err: errors.New("property case requires pipeline=<name>"), errCode: "E_TEST_PROPERTY", errMsg: "property case requires pipeline=<name>"}; continue
+                }
+                if c.Spec.Pipeline != "" {
+                    if compileErr != nil { res <- result{c: c, err: compileErr, errCode: "E_COMPILE", errMsg: compileErr.Error()}; continue }
+                    m, found := mods[c.Spec.Package]
+                    if !found {
+                        err := fmt.Errorf("package not found for pipeline %s: %q", c.Spec.Pipeline, c.Spec.Package)
+                        res <- result{c: c, err: err, errCode: "E_TEST_PIPELINE", errMsg: err.Error()}; continue
+                    }
+                    // Transform workers run from the package's compiled workers library.
+                    if pipelineHasWorkers(c.Spec.Package, c.Spec.Pipeline) {
+                        if workers.skip != "" { res <- result{c: c, ok: true, skipped: true, reason: workers.skip}; continue }
+                        if err := workers.errs[c.Spec.Package]; err != nil {
+                            res <- result{c: c, err: err, errCode: "E_LINK_FAIL", errMsg: err.Error()}; continue
+                        }
+                    }
+                    inv := workers.invokers[c.Spec.Package]
+                    var pr pipelineCaseResult
+                    if c.Spec.Property != nil { pr = runPropertyCase(ctx, c, m, inv, to) } else { pr = runPipelineCase(ctx, dir, c, m, inv, to) }
+                    if len(c.Spec.KvGet) > 0 {
+                        for _, k := range c.Spec.KvGet { _, _ = st.Get(k) }
+                    }
+                    res <- result{c: c, ok: pr.ok, err: pr.err, errCode: pr.errCode, errMsg: pr.errMsg, diff: pr.diff, dur: pr.dur}
+                    continue
+                }
                 // Parse input JSON
                 var input map[string]any
                 if c.Spec.InputJSON != "" { _ = json.Unmarshal([]byte(c.Spec.InputJSON), &input) }
@@ -124,6 +166,8 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
         if jsonOut {
             _ = json.NewEncoder(out).Encode(map[string]any{"schema":"test.v1","type":"test_start","file": r.c.File, "case": r.c.Name})
             ev := map[string]any{"schema":"test.v1","type":"test_end","file": r.c.File, "case": r.c.Name, "ok": r.ok, "skipped": r.skipped, "duration_ms": r.dur.Milliseconds()}
+            if r.diff != "" { ev["diff"] = r.diff }
+            if r.reason != "" { ev["skip_reason"] = r.reason }
             if r.err != nil {
                 ev["error"] = r.err.Error()
                 // Default ErrorPipeline: write errors.v1 to stderr unless suppressed
@@ -137,9 +181,7 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
             }
             _ = json.NewEncoder(out).Encode(ev)
             // Optional KV diag events
-            if currentTestOptions.KvEvents || r.c.Spec.KvEmit {
-                st := kvstore.Default()
-                if r.c.Spec.KvNS != "" { st = kvstore.Namespace(r.c.Spec.KvNS) }
+            if st, err := caseKvStore(r.c); err == nil && (currentTestOptions.KvEvents || r.c.Spec.KvEmit) {
                 mts := st.Metrics()
                 // Metrics diag
                 _ = json.NewEncoder(out).Encode(diag.Record{Timestamp: time.Now().UTC(), Level: diag.Info, Code: "KV_METRICS", Message: "kv metrics", File: r.c.File, Data: map[string]any{"case": r.c.Name, "ns": r.c.Spec.KvNS, "hits": mts.Hits, "misses": mts.Misses, "expirations": mts.Expirations, "evictions": mts.Evictions, "currentSize": mts.CurrentSize}})
@@ -152,6 +194,10 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
         if !jsonOut {
             st := "OK"; if r.skipped { st = "SKIP" } else if !r.ok { st = "FAIL" }
             _, _ = fmt.Fprintf(out, "test: runtime %s %s %s\n", r.c.File, r.c.Name, st)
+            if r.skipped && r.reason != "" { _, _ = fmt.Fprintf(out, "  reason: %s\n", r.reason) }
+            if !r.ok && !r.skipped {
+                if r.diff != "" { _, _ = fmt.Fprintf(out, "  diff: %s\n", r.diff) } else if r.err != nil && r.c.Spec.Pipeline != "" { _, _ = fmt.Fprintf(out, "  error: %v\n", r.err) }
+            }
         }
         if currentTestOptions.Failfast && !r.ok && !r.skipped {
             // drain and break
@@ -168,8 +214,8 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
         for _, cs := range cases {
             if !(verbose || cs.Spec.KvEmit) { continue }
             base := strings.ReplaceAll(cs.File, string(os.PathSeparator), "_") + "_" + cs.Name
-            st := kvstore.Default()
-            if cs.Spec.KvNS != "" { st = kvstore.Namespace(cs.Spec.KvNS) }
+            st, err := caseKvStore(cs)
+            if err != nil { continue }
             mts := st.Metrics()
             mobj := map[string]any{"schema":"kv.metrics.v1","timestamp": time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), "hits": mts.Hits, "misses": mts.Misses, "expirations": mts.Expirations, "evictions": mts.Evictions, "currentSize": mts.CurrentSize}
             _ = writeJSONFile(filepath.Join(kvDir, base+".metrics.json"), mobj)
@@ -183,3 +229,10 @@ type result struct{ c runtimeCase; ok bool; skipped bool; err error; errCode str
 
 // ioWriter moved to runtime_iowriter.go
 // anyKvEmit moved to runtime_any_kv_emit.go
+
+// caseKvStore returns the store a runtime case uses: its kv namespace, or the
+// default store.
+func caseKvStore(c runtimeCase) (*kvstore.Store, error) {
+    if c.Spec.KvNS == "" { return kvstore.Default(), nil }
+    return kvstore.OpenNamespace(c.Spec.KvNS)
+}
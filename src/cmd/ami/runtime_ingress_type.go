@@ -0,0 +1,41 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// ingressPayloadType resolves the ingress event type of pipeline from the debug
+// artifacts under ./build/debug: the `type(...)` recorded on its ingress step,
+// else the parameter type of the worker of its first Transform step. Returns ""
+// when neither is known.
+func ingressPayloadType(m ir.Module, pipeline string) string {
+    type step struct{ Name string; Args []string; Edge struct{ Type string } }
+    type pipeList struct{ Pipelines []struct{ Name string; Steps []step } }
+    paths, _ := filepath.Glob(filepath.Join("build", "debug", "ir", m.Package, "*.pipelines.json"))
+    for _, p := range paths {
+        b, err := os.ReadFile(p)
+        if err != nil { continue }
+        var pl pipeList
+        if json.Unmarshal(b, &pl) != nil { continue }
+        for _, pe := range pl.Pipelines {
+            if pe.Name != pipeline { continue }
+            for _, st := range pe.Steps {
+                if st.Name == "ingress" && st.Edge.Type != "" { return st.Edge.Type }
+            }
+            for _, st := range pe.Steps {
+                if st.Name != "Transform" || len(st.Args) == 0 { continue }
+                w := strings.Trim(st.Args[0], `"'`)
+                for _, f := range m.Functions {
+                    if f.Name == w && len(f.Params) > 0 { return f.Params[0].Type }
+                }
+                return ""
+            }
+        }
+    }
+    return ""
+}
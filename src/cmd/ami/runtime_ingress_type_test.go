@@ -0,0 +1,26 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+func TestIngressPayloadType_IngressThenWorkerParam(t *testing.T) {
+    wd, _ := os.Getwd()
+    dir := t.TempDir()
+    if err := os.Chdir(dir); err != nil { t.Fatal(err) }
+    defer func(){ _ = os.Chdir(wd) }()
+    irDir := filepath.Join("build", "debug", "ir", "app")
+    if err := os.MkdirAll(irDir, 0o755); err != nil { t.Fatal(err) }
+    pl := `{"schema":"pipelines.v1","pipelines":[
+ {"name":"Typed","steps":[{"name":"ingress","edge":{"type":"Event<int>"}},{"name":"egress"}]},
+ {"name":"Work","steps":[{"name":"ingress","edge":{}},{"name":"Transform","args":["W"]},{"name":"egress"}]}]}`
+    if err := os.WriteFile(filepath.Join(irDir, "u.pipelines.json"), []byte(pl), 0o644); err != nil { t.Fatal(err) }
+    m := ir.Module{Package: "app", Functions: []ir.Function{{Name: "W", Params: []ir.Value{{ID: "ev", Type: "Event<Struct{id:int}>"}}}}}
+    if got := ingressPayloadType(m, "Typed"); got != "Event<int>" { t.Fatalf("ingress type: %q", got) }
+    if got := ingressPayloadType(m, "Work"); got != "Event<Struct{id:int}>" { t.Fatalf("worker param type: %q", got) }
+    if got := ingressPayloadType(m, "Missing"); got != "" { t.Fatalf("unknown pipeline: %q", got) }
+}
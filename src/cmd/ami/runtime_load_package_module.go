@@ -0,0 +1,27 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+)
+
+// loadPackageModule merges the per-unit IR modules of pkg written under
+// ./build/debug/ir/<pkg> into one module carrying all of the package's functions and pipelines.
+func loadPackageModule(pkg string) ir.Module {
+    m := ir.Module{Package: pkg}
+    paths, _ := filepath.Glob(filepath.Join("build", "debug", "ir", pkg, "*.ir.json"))
+    for _, p := range paths {
+        b, err := os.ReadFile(p)
+        if err != nil { continue }
+        var u ir.Module
+        if err := json.Unmarshal(b, &u); err != nil { continue }
+        if m.Concurrency == 0 { m.Concurrency = u.Concurrency }
+        if m.Schedule == "" { m.Schedule = u.Schedule }
+        m.Functions = append(m.Functions, u.Functions...)
+        m.Pipelines = append(m.Pipelines, u.Pipelines...)
+    }
+    return m
+}
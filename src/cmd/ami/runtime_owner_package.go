@@ -0,0 +1,21 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+// ownerPackage returns the workspace package whose root contains file (relative to dir).
+func ownerPackage(dir string, ws workspace.Workspace, file string) string {
+    abs := filepath.Clean(filepath.Join(dir, file))
+    best, bestLen := "", -1
+    for _, e := range ws.Packages {
+        root := filepath.Clean(filepath.Join(dir, e.Package.Root))
+        if abs != root && !strings.HasPrefix(abs, root+string(os.PathSeparator)) { continue }
+        if len(root) > bestLen { best, bestLen = e.Package.Name, len(root) }
+    }
+    return best
+}
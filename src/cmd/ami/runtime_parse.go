@@ -7,11 +7,13 @@ import (
     "path/filepath"
     "strconv"
     "strings"
+    "time"
 )
 
 // parseRuntimeCases scans for `*_test.ami` files and collects runtime cases based on pragmas.
 // Simplified rule: a file must include at least one `#pragma test:case <name>` and a single
 // `#pragma test:runtime ...` block that applies to all cases in the file. `test:skip` applies to all cases.
+// Each `#pragma test:property <name> ...` line declares a standalone generative case.
 func parseRuntimeCases(root string) ([]runtimeCase, error) {
     var cases []runtimeCase
     err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
@@ -20,6 +22,7 @@ func parseRuntimeCases(root string) ([]runtimeCase, error) {
         f, err := os.Open(p); if err != nil { return nil }
         defer f.Close()
         var names []string
+        var props []runtimeCase
         spec := runtimeSpec{}
         scan := bufio.NewScanner(f)
         for scan.Scan() {
@@ -48,6 +51,31 @@ func parseRuntimeCases(root string) ([]runtimeCase, error) {
                 if v := kv["output"]; v != "" { spec.ExpectJSON = v }
                 if v := kv["expect_error"]; v != "" { spec.ExpectError = v }
                 if v := kv["timeout"]; v != "" { if n, e := strconv.Atoi(v); e == nil { spec.TimeoutMs = n } }
+                if v := kv["pipeline"]; v != "" { spec.Pipeline = v }
+                if v := kv["package"]; v != "" { spec.Package = v }
+                if v := kv["order"]; v == "unordered" { spec.Unordered = true }
+                if v := kv["snapshot"]; v != "" { spec.Snapshot = v }
+                if v := kv["advance"]; v != "" { if d, e := time.ParseDuration(v); e == nil && d > 0 { spec.Advance = d } }
+                if v := kv["ignore"]; v != "" {
+                    for _, f := range strings.Split(v, ",") {
+                        f = strings.TrimSpace(f)
+                        if f != "" { spec.Ignore = append(spec.Ignore, f) }
+                    }
+                }
+            } else if strings.HasPrefix(body, "property ") {
+                rest := strings.Fields(strings.TrimPrefix(body, "property "))
+                if len(rest) == 0 || strings.Contains(rest[0], "=") { continue }
+                kv := parseKV(strings.Join(rest[1:], " "))
+                ps := &propertySpec{Type: kv["type"]}
+                for _, c := range strings.Split(kv["check"], ",") {
+                    if c = strings.TrimSpace(c); c != "" { ps.Checks = append(ps.Checks, c) }
+                }
+                if n, e := strconv.Atoi(kv["runs"]); e == nil { ps.Runs = n }
+                if n, e := strconv.Atoi(kv["events"]); e == nil { ps.MaxEvents = n }
+                if n, e := strconv.ParseInt(kv["seed"], 10, 64); e == nil { ps.Seed, ps.SeedSet = n, true }
+                pc := runtimeCase{Name: rest[0], Spec: runtimeSpec{Pipeline: kv["pipeline"], Package: kv["package"], Property: ps}}
+                if n, e := strconv.Atoi(kv["timeout"]); e == nil { pc.Spec.TimeoutMs = n }
+                props = append(props, pc)
             } else if strings.HasPrefix(body, "kv ") {
                 rest := strings.TrimSpace(strings.TrimPrefix(body, "kv "))
                 kv := parseKV(rest)
@@ -73,11 +101,18 @@ func parseRuntimeCases(root string) ([]runtimeCase, error) {
                 if v := kv["emit"]; v == "true" || v == "1" { spec.KvEmit = true }
             }
         }
-        if len(names) == 0 { return nil }
+        if len(names) == 0 && len(props) == 0 { return nil }
         // Validate JSON snippets early
         if spec.InputJSON != "" { var tmp any; _ = json.Unmarshal([]byte(spec.InputJSON), &tmp) }
         if rel, e := filepath.Rel(root, p); e == nil { p = rel }
         for _, n := range names { cases = append(cases, runtimeCase{File: p, Name: n, Spec: spec}) }
+        // property cases carry their own spec; file-wide skip and fixtures still apply
+        for _, pc := range props {
+            pc.File = p
+            pc.Spec.SkipReason = spec.SkipReason
+            pc.Spec.Fixtures = spec.Fixtures
+            cases = append(cases, pc)
+        }
         return nil
     })
     return cases, err
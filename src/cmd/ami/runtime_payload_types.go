@@ -0,0 +1,39 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/types"
+)
+
+// payloadTypes returns a resolver over the type declarations recorded in
+// ./build/debug/ir/<pkg>/ir.types.index.json, so that declared payload types
+// (e.g., Event<Order>) expand to their structure for value generation. Missing
+// or unreadable indexes yield an empty resolver.
+func payloadTypes(pkg string) *types.Resolver {
+    r := types.NewResolver(pkg)
+    var idx struct {
+        Units []struct {
+            Decls []struct {
+                Name, Kind, Type, Resolved string
+                Members          []string
+            }
+        }
+    }
+    b, err := os.ReadFile(filepath.Join("build", "debug", "ir", pkg, "ir.types.index.json"))
+    if err != nil || json.Unmarshal(b, &idx) != nil { return r }
+    for _, u := range idx.Units {
+        for _, d := range u.Decls {
+            name := strings.TrimPrefix(d.Name, pkg+".")
+            if d.Kind == "enum" { r.DefineEnum(pkg, name, d.Members); continue }
+            // prefer the resolved form: it already expands imported types
+            text := d.Type
+            if d.Resolved != "" { text = d.Resolved }
+            if t, err := types.Parse(text); err == nil { r.Define(pkg, name, t, d.Kind == "alias") }
+        }
+    }
+    return r
+}
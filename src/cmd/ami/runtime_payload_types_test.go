@@ -0,0 +1,24 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestPayloadTypes_FromTypesIndex(t *testing.T) {
+    wd, _ := os.Getwd()
+    dir := t.TempDir()
+    if err := os.Chdir(dir); err != nil { t.Fatal(err) }
+    defer func(){ _ = os.Chdir(wd) }()
+    irDir := filepath.Join("build", "debug", "ir", "app")
+    if err := os.MkdirAll(irDir, 0o755); err != nil { t.Fatal(err) }
+    idx := `{"schema":"ir.types.index.v1","package":"app","units":[{"unit":"u","types":[],"decls":[
+ {"name":"app.Order","kind":"struct","type":"Struct{id:int,total:lib.Money,color:Color}","resolved":"Struct{color:app.Color,id:int,total:Struct{amount:int64}}","exported":true},
+ {"name":"app.Color","kind":"enum","exported":true,"members":["Red","Green"]}]}]}`
+    if err := os.WriteFile(filepath.Join(irDir, "ir.types.index.json"), []byte(idx), 0o644); err != nil { t.Fatal(err) }
+    ty, err := payloadTypes("app").Parse("Event<Order>")
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if got := ty.String(); got != "Event<Struct{color:app.Color,id:int,total:Struct{amount:int64}}>" { t.Fatalf("resolved: %s", got) }
+    if got := payloadTypes("missing").Resolve(ty).String(); got != ty.String() { t.Fatalf("missing index should be a no-op: %s", got) }
+}
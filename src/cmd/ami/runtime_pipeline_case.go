@@ -0,0 +1,45 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+    "github.com/sam-caldwell/ami/src/ami/runtime/tester"
+)
+
+// runPipelineCase feeds the case input through pipeline c.Spec.Pipeline of m and
+// checks the emitted events (or the expected error code) against the case spec.
+// Input and output JSON hold either one event payload or an array of payloads.
+// Snapshot cases additionally diff the full run against their golden file.
+// Cases run on a fresh virtual clock under --virtual-time or with advance=.
+// Transform workers resolve through inv, the package's compiled workers library.
+func runPipelineCase(ctx context.Context, dir string, c runtimeCase, m ir.Module, inv rexec.WorkerInvoker, to time.Duration) pipelineCaseResult {
+    inputs, err := caseEvents(c.Spec.InputJSON)
+    if err != nil { return pipelineCaseResult{err: fmt.Errorf("input: %w", err), errCode: "E_TEST_INPUT", errMsg: err.Error()} }
+    res, err := tester.RunPipeline(ctx, tester.PipelineOptions{Module: m, Pipeline: c.Spec.Pipeline, Timeout: to, Invoker: inv, Clock: caseClock(c), Advance: c.Spec.Advance}, inputs)
+    r := pipelineCaseResult{err: err, dur: res.Duration}
+    if err != nil {
+        r.errCode, r.errMsg = "E_RUNTIME", err.Error()
+        if errors.Is(err, context.DeadlineExceeded) { r.errCode = "E_TIMEOUT" }
+    } else if len(res.Errors) > 0 {
+        r.errCode, r.errMsg = res.Errors[0].Code, res.Errors[0].Message
+        r.err = fmt.Errorf("%s: %s", r.errCode, r.errMsg)
+    }
+    if c.Spec.ExpectError != "" {
+        r.ok = res.HasError(c.Spec.ExpectError) || r.errCode == c.Spec.ExpectError
+        if r.ok { r.err = nil }
+    } else if r.err == nil {
+        r.ok = true
+        if c.Spec.ExpectJSON != "" {
+            want, err := caseEvents(c.Spec.ExpectJSON)
+            if err != nil { return pipelineCaseResult{err: fmt.Errorf("output: %w", err), errCode: "E_TEST_INPUT", errMsg: err.Error(), dur: r.dur} }
+            r.ok, r.diff = tester.Compare(res.Outputs, want, tester.CompareOptions{Unordered: c.Spec.Unordered, Ignore: c.Spec.Ignore})
+        }
+    }
+    if r.ok && c.Spec.Snapshot != "" { r = checkSnapshot(dir, c, res, r) }
+    return r
+}
@@ -0,0 +1,13 @@
+package main
+
+import "time"
+
+// pipelineCaseResult is the outcome of one runtime case run against a compiled pipeline.
+type pipelineCaseResult struct{
+    ok      bool
+    err     error
+    errCode string
+    errMsg  string
+    diff    string
+    dur     time.Duration
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+
+    "github.com/sam-caldwell/ami/src/ami/compiler/driver"
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    "github.com/sam-caldwell/ami/src/ami/compiler/source"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+    "github.com/sam-caldwell/ami/src/schemas/diag"
+)
+
+// compileRuntimePipelines compiles the workspace under dir (the current working
+// directory) so debug artifacts for pipeline cases exist under ./build/debug, and
+// resolves each pipeline case's package in place. It returns the merged IR module
+// per package; a non-nil error fails every pipeline case.
+func compileRuntimePipelines(dir string, cases []runtimeCase) (map[string]ir.Module, error) {
+    var ws workspace.Workspace
+    if err := ws.Load(filepath.Join(dir, "ami.workspace")); err != nil {
+        return nil, fmt.Errorf("load workspace: %v", err)
+    }
+    var pkgs []driver.Package
+    for _, e := range ws.Packages {
+        pdir := filepath.Clean(filepath.Join(dir, e.Package.Root))
+        var fs source.FileSet
+        _ = filepath.WalkDir(pdir, func(path string, d os.DirEntry, err error) error {
+            if err != nil || d.IsDir() || filepath.Ext(path) != ".ami" { return nil }
+            if b, err := os.ReadFile(path); err == nil { fs.AddFile(path, string(b)) }
+            return nil
+        })
+        if len(fs.Files) == 0 { continue }
+        pkgs = append(pkgs, driver.Package{Name: e.Package.Name, Files: &fs})
+    }
+    if len(pkgs) == 0 { return nil, errors.New("no packages with .ami sources in workspace") }
+    _, diags := driver.Compile(ws, pkgs, driver.Options{Debug: true, EmitLLVMOnly: true})
+    for _, d := range diags {
+        if d.Level == diag.Error { return nil, fmt.Errorf("compile: %s: %s (%s)", d.Code, d.Message, d.File) }
+    }
+    mods := map[string]ir.Module{}
+    for i := range cases {
+        sp := &cases[i].Spec
+        if sp.Pipeline == "" { continue }
+        if sp.Package == "" { sp.Package = ownerPackage(dir, ws, cases[i].File) }
+        for _, e := range ws.Packages {
+            if e.Package.Name == sp.Package { sp.PackageRoot = filepath.Clean(filepath.Join(dir, e.Package.Root)) }
+        }
+        if _, ok := mods[sp.Package]; ok || sp.Package == "" { continue }
+        mods[sp.Package] = loadPackageModule(sp.Package)
+    }
+    return mods, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+    "github.com/sam-caldwell/ami/src/ami/runtime/tester"
+)
+
+// runPropertyCase generates events of the pipeline's ingress payload type, runs
+// them through the compiled pipeline and checks the case's properties. A
+// violation is reported with its seed and the shrunk counterexample. Transform
+// workers resolve through inv, as in runPipelineCase.
+func runPropertyCase(ctx context.Context, c runtimeCase, m ir.Module, inv rexec.WorkerInvoker, to time.Duration) pipelineCaseResult {
+    start := time.Now()
+    ps := c.Spec.Property
+    fail := func(err error) pipelineCaseResult {
+        return pipelineCaseResult{err: err, errCode: "E_TEST_PROPERTY", errMsg: err.Error(), dur: time.Since(start)}
+    }
+    if len(ps.Checks) == 0 { return fail(errors.New("property case requires check=<property,...>")) }
+    opts := tester.PropertyOptions{Seed: ps.Seed, Runs: ps.Runs, MaxEvents: ps.MaxEvents}
+    if !ps.SeedSet { opts.Seed = time.Now().UnixNano() }
+    for _, s := range ps.Checks {
+        p, err := tester.ParseProperty(s)
+        if err != nil { return fail(err) }
+        opts.Properties = append(opts.Properties, p)
+    }
+    tyText := ps.Type
+    if tyText == "" { tyText = ingressPayloadType(m, c.Spec.Pipeline) }
+    if tyText == "" { return fail(fmt.Errorf("cannot resolve the ingress payload type of %s; annotate ingress with type(\"Event<T>\") or set type=", c.Spec.Pipeline)) }
+    ty, err := payloadTypes(m.Package).Parse(tyText)
+    if err != nil { return fail(fmt.Errorf("payload type %q: %v", tyText, err)) }
+    opts.Payload = ty
+    exec := func(ctx context.Context, in []any) (tester.PipelineResult, error) {
+        return tester.RunPipeline(ctx, tester.PipelineOptions{Module: m, Pipeline: c.Spec.Pipeline, Timeout: to, Invoker: inv, Clock: caseClock(c)}, in)
+    }
+    res, err := tester.RunProperty(ctx, opts, exec)
+    if err != nil {
+        if res.Runs == 0 { return fail(err) }
+        r := fail(fmt.Errorf("run %d (seed=%d): %w", res.Runs, opts.Seed, err))
+        if errors.Is(err, context.DeadlineExceeded) { r.errCode = "E_TIMEOUT" }
+        return r
+    }
+    r := pipelineCaseResult{ok: res.OK, dur: time.Since(start)}
+    if !res.OK {
+        ce, _ := json.Marshal(res.Counterexample)
+        r.diff = fmt.Sprintf("property failed on run %d (seed=%d): %s\n    counterexample (%d events, shrunk from %d): %s\n    reproduce with seed=%d",
+            res.Run, res.Seed, res.Failure, len(res.Counterexample), res.Original, ce, res.Seed)
+    }
+    return r
+}
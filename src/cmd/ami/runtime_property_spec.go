@@ -0,0 +1,11 @@
+package main
+
+// propertySpec configures a `#pragma test:property` case.
+type propertySpec struct{
+    Checks    []string // property specs (see tester.ParseProperty)
+    Runs      int      // generated inputs to try (0 = default)
+    Seed      int64
+    SeedSet   bool     // Seed given explicitly; otherwise a fresh seed is drawn
+    MaxEvents int      // events per generated input (0 = default)
+    Type      string   // payload type override; default resolves the ingress type
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// snapshotPath resolves the golden file of a snapshot case: <package root>/testdata/<name>,
+// with `.snap.json` appended when name has no extension. Without a package root the
+// test file's directory is used. Names escaping testdata are rejected.
+func snapshotPath(dir string, c runtimeCase) (string, error) {
+    root := c.Spec.PackageRoot
+    if root == "" { root = filepath.Dir(filepath.Join(dir, c.File)) }
+    name := c.Spec.Snapshot
+    if filepath.Ext(name) == "" { name += ".snap.json" }
+    td := filepath.Join(root, "testdata")
+    p := filepath.Clean(filepath.Join(td, name))
+    if !strings.HasPrefix(p, td+string(os.PathSeparator)) { return "", fmt.Errorf("snapshot %q escapes testdata", c.Spec.Snapshot) }
+    return p, nil
+}
@@ -1,5 +1,7 @@
 package main
 
+import "time"
+
 type runtimeSpec struct{
     InputJSON   string
     ExpectJSON  string
@@ -7,6 +9,15 @@ type runtimeSpec struct{
     TimeoutMs   int    // per-case override
     Fixtures    []fixtureSpec
     SkipReason  string
+    // Pipeline under test; empty selects the identity tester.
+    Pipeline    string
+    Package     string   // package owning Pipeline (default: package whose root holds the file)
+    Unordered   bool     // compare outputs as a multiset
+    Ignore      []string // dotted payload fields excluded from comparison
+    Snapshot    string   // golden file name under the package's testdata directory
+    Advance     time.Duration // virtual time advanced once inputs are consumed (implies a virtual clock)
+    PackageRoot string   // resolved root directory of Package (set at compile time)
+    Property    *propertySpec // generative case declared by `#pragma test:property`
     // KV harness integration
     KvNS    string            // namespace "pipeline/node" or arbitrary
     KvPut   map[string]string // key=value pairs
@@ -0,0 +1,117 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "runtime"
+    "strings"
+
+    llvme "github.com/sam-caldwell/ami/src/ami/compiler/codegen/llvm"
+    ir "github.com/sam-caldwell/ami/src/ami/compiler/ir"
+    rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+)
+
+// runtimeWorkers holds the compiled workers each package's pipeline cases call.
+// skip is set when no library can be built on this host (no native toolchain,
+// or a Go build without cgo); errs records packages whose library failed to build.
+type runtimeWorkers struct {
+    invokers map[string]rexec.WorkerInvoker
+    errs     map[string]error
+    skip     string
+}
+
+// buildRuntimeWorkers builds a workers library for every package in mods and
+// resolves workers from it through their ami_worker_core_<name> symbols.
+func buildRuntimeWorkers(mods map[string]ir.Module) runtimeWorkers {
+    rw := runtimeWorkers{invokers: map[string]rexec.WorkerInvoker{}, errs: map[string]error{}}
+    if rexec.NewDLSOInvoker("", "") == nil { // the constructor returns nil without cgo
+        rw.skip = "compiled workers need a cgo-enabled ami build"
+        return rw
+    }
+    clang, err := llvme.FindClang()
+    if err != nil {
+        rw.skip = "compiled workers need a native toolchain: " + err.Error()
+        return rw
+    }
+    for pkg := range mods {
+        lib, err := buildRuntimeWorkersLib(clang, pkg)
+        if err != nil { rw.errs[pkg] = err; continue }
+        rw.invokers[pkg] = rexec.NewDLSOInvoker(lib, "ami_worker_core_")
+    }
+    return rw
+}
+
+// buildRuntimeWorkersLib compiles the debug LLVM units of pkg under
+// ./build/debug/llvm/<pkg>, retargeted to the host, together with the runtime
+// and the GPU shims, and links them into ./build/test/lib/<pkg>/libworkers.{so,dylib}.
+// It returns the absolute library path.
+func buildRuntimeWorkersLib(clang, pkg string) (string, error) {
+    lls, _ := filepath.Glob(filepath.Join("build", "debug", "llvm", pkg, "*.ll"))
+    if len(lls) == 0 { return "", fmt.Errorf("no LLVM units for package %s under build/debug/llvm", pkg) }
+    triple := llvme.TripleFor(runtime.GOOS, runtime.GOARCH)
+    objDir := filepath.Join("build", "test", "obj", pkg)
+    if err := os.MkdirAll(objDir, 0o755); err != nil { return "", err }
+    var srcs []string
+    for _, ll := range lls {
+        b, err := os.ReadFile(ll)
+        if err != nil { return "", err }
+        lines := strings.Split(string(b), "\n")
+        for i, l := range lines {
+            if strings.HasPrefix(l, "target triple = ") { lines[i] = "target triple = \"" + triple + "\"" }
+        }
+        out := filepath.Join(objDir, filepath.Base(ll))
+        if err := os.WriteFile(out, []byte(strings.Join(lines, "\n")), 0o644); err != nil { return "", err }
+        srcs = append(srcs, out)
+    }
+    rt, err := llvme.WriteRuntimeLL(objDir, triple, false)
+    if err != nil { return "", err }
+    shim := filepath.Join(objDir, "gpu_shims.c")
+    if err := os.WriteFile(shim, []byte(gpuShimsC), 0o644); err != nil { return "", err }
+    var objs []string
+    for _, src := range append(srcs, rt, shim) {
+        lang := "ir"
+        if filepath.Ext(src) == ".c" { lang = "c" }
+        o := strings.TrimSuffix(src, filepath.Ext(src)) + ".o"
+        if out, err := exec.Command(clang, "-x", lang, "-target", triple, "-fPIC", "-c", src, "-o", o).CombinedOutput(); err != nil {
+            return "", toolFailure("compile "+src, out)
+        }
+        objs = append(objs, o)
+    }
+    libDir := filepath.Join("build", "test", "lib", pkg)
+    if err := os.MkdirAll(libDir, 0o755); err != nil { return "", err }
+    lib, args := filepath.Join(libDir, "libworkers.so"), []string{"-target", triple, "-shared", "-fPIC"}
+    if runtime.GOOS == "darwin" { lib, args = filepath.Join(libDir, "libworkers.dylib"), []string{"-target", triple, "-dynamiclib"} }
+    args = append(append(args, objs...), "-lm", "-o", lib)
+    if out, err := exec.Command(clang, args...).CombinedOutput(); err != nil {
+        return "", toolFailure("link "+lib, out)
+    }
+    return filepath.Abs(lib)
+}
+
+// toolFailure reports a failed clang invocation with its output.
+func toolFailure(what string, out []byte) error {
+    return fmt.Errorf("%s: clang failed: %s", what, strings.TrimSpace(string(out)))
+}
+
+// pipelineHasWorkers reports whether pipeline of pkg has a Transform step,
+// per the debug artifacts under ./build/debug/ir/<pkg>.
+func pipelineHasWorkers(pkg, pipeline string) bool {
+    type pipeList struct{ Pipelines []struct{ Name string; Steps []struct{ Name string } } }
+    paths, _ := filepath.Glob(filepath.Join("build", "debug", "ir", pkg, "*.pipelines.json"))
+    for _, p := range paths {
+        b, err := os.ReadFile(p)
+        if err != nil { continue }
+        var pl pipeList
+        if json.Unmarshal(b, &pl) != nil { continue }
+        for _, pe := range pl.Pipelines {
+            if pe.Name != pipeline { continue }
+            for _, st := range pe.Steps {
+                if st.Name == "Transform" { return true }
+            }
+        }
+    }
+    return false
+}
@@ -17,15 +17,21 @@ func newTestCmd() *cobra.Command {
     var kvEvents bool
     var noErrorPipe bool
     var errorPipeHuman bool
+    var update bool
+    var virtualTime bool
+    var locked bool
     cmd := &cobra.Command{
         Use:   "test [path]",
         Short: "Run project tests and write logs/manifests",
-        Example: "\n  # Run tests in current directory\n  ami test\n\n  # Run tests in a specific path\n  ami test ./subdir\n\n  # Stream JSON events and summary\n  ami test --json\n\n  # Write test.log and test.manifest under build/test/\n  ami test --verbose\n",
+        Example: "\n  # Run tests in current directory\n  ami test\n\n  # Run tests in a specific path\n  ami test ./subdir\n\n  # Stream JSON events and summary\n  ami test --json\n\n  # Write test.log and test.manifest under build/test/\n  ami test --verbose\n\n  # Rewrite runtime golden snapshots\n  ami test --update\n\n  # Run pipeline cases on a deterministic virtual clock\n  ami test --virtual-time\n\n  # Fail unless dependency resolution reproduces ami.lock (CI)\n  ami test --locked\n",
         RunE: func(cmd *cobra.Command, args []string) error {
             dir := "."
             if len(args) > 0 { dir = args[0] }
             if checkEvents { return runCheckEvents(cmd.OutOrStdout()) }
-            setTestOptions(TestOptions{TimeoutMs: timeoutMs, Parallel: parallel, Failfast: failfast, RunPattern: runPattern, KvMetrics: kvMetrics, KvDump: kvDump, KvEvents: kvEvents, SuppressErrorPipe: noErrorPipe, ErrorPipeHuman: errorPipeHuman})
+            if locked {
+                if err := runLockedCheck(cmd.OutOrStdout(), dir, jsonOut); err != nil { return err }
+            }
+            setTestOptions(TestOptions{TimeoutMs: timeoutMs, Parallel: parallel, Failfast: failfast, RunPattern: runPattern, KvMetrics: kvMetrics, KvDump: kvDump, KvEvents: kvEvents, SuppressErrorPipe: noErrorPipe, ErrorPipeHuman: errorPipeHuman, Update: update, VirtualTime: virtualTime})
             return runTest(cmd.OutOrStdout(), dir, jsonOut, verbose, pkgs)
         },
     }
@@ -43,6 +49,9 @@ func newTestCmd() *cobra.Command {
     cmd.Flags().BoolVar(&kvEvents, "kv-events", false, "stream kv metrics/dump as diag.v1 JSON events in --json mode")
     cmd.Flags().BoolVar(&noErrorPipe, "no-errorpipe", false, "suppress default ErrorPipeline emission (quiet CI)")
     cmd.Flags().BoolVar(&errorPipeHuman, "errorpipe-human", false, "also echo concise human error lines to stderr when runtime errors occur")
+    cmd.Flags().BoolVar(&update, "update", false, "rewrite golden snapshots of runtime cases that declare snapshot=<name>")
+    cmd.Flags().BoolVar(&virtualTime, "virtual-time", false, "run pipeline cases on a virtual clock (deterministic timestamps, timers and timeouts; forces serial runtime cases)")
+    cmd.Flags().BoolVar(&locked, "locked", false, "fail if dependency resolution would change ami.lock")
     // alias: pkg-parallel maps to go test -p (same as --packages)
     cmd.Flags().IntVar(&pkgs, "pkg-parallel", 0, "alias for --packages: go test package concurrency (-p)")
     _ = cmd.Flags().MarkHidden("pkg-parallel")
@@ -11,6 +11,8 @@ type TestOptions struct {
     KvEvents    bool
     SuppressErrorPipe bool
     ErrorPipeHuman    bool
+    Update            bool // rewrite runtime golden snapshots
+    VirtualTime       bool // run pipeline cases on a virtual clock
 }
 
 var currentTestOptions TestOptions
@@ -0,0 +1,66 @@
+package main
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    llvme "github.com/sam-caldwell/ami/src/ami/compiler/codegen/llvm"
+    rexec "github.com/sam-caldwell/ami/src/ami/runtime/exec"
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+func TestRuntimeCLI_PipelineCases(t *testing.T) {
+    dir := t.TempDir()
+    ws := workspace.DefaultWorkspace()
+    ws.Packages[0].Package.Name = "app"
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    mustWrite := func(rel, content string){
+        p := filepath.Join(dir, rel)
+        if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { t.Fatal(err) }
+        if err := os.WriteFile(p, []byte(content), 0o644); err != nil { t.Fatal(err) }
+    }
+    mustWrite("src/app.ami", `package app
+func W(ev Event<int>) (int, error) {
+    return 7, nil }
+pipeline Dedup(){ ingress; Collect merge.Dedup("id"), merge.Buffer(16, "block"); egress; ingress -> Collect; Collect -> egress }
+pipeline Work(){ ingress; Transform(W); egress; ingress -> Transform; Transform -> egress }
+`)
+    // pass: dedup drops the repeated id
+    mustWrite("src/dedup_test.ami", `package app
+#pragma test:case dedup
+#pragma test:runtime pipeline=Dedup input=[{"id":1,"v":"a"},{"id":1,"v":"b"},{"id":2,"v":"c"}] output=[{"id":1,"v":"a"},{"id":2,"v":"c"}]
+`)
+    // pass: unordered with an ignored field
+    mustWrite("src/masked_test.ami", `package app
+#pragma test:case masked
+#pragma test:runtime pipeline=Dedup order=unordered ignore=v input=[{"id":1,"v":"a"},{"id":2,"v":"c"}] output=[{"id":2,"v":"x"},{"id":1,"v":"y"}]
+`)
+    // fail: the pipeline does not emit the expected events
+    mustWrite("src/mismatch_test.ami", `package app
+#pragma test:case mismatch
+#pragma test:runtime pipeline=Dedup input=[{"id":1},{"id":1}] output=[{"id":1},{"id":1}]
+`)
+    // pass: W runs from the compiled workers library (skipped without a toolchain)
+    mustWrite("src/worker_test.ami", `package app
+#pragma test:case worker
+#pragma test:runtime pipeline=Work input=[1,2] output=[7,7]
+`)
+    var out bytes.Buffer
+    setTestOptions(TestOptions{Parallel: 2})
+    if err := runTest(&out, dir, false, false, 0); err == nil {
+        t.Fatalf("expected failure due to mismatch; out=\n%s", out.String())
+    }
+    s := out.String()
+    want := "test: runtime ok=3 fail=1 skip=0"
+    if _, err := llvme.FindClang(); err != nil || rexec.NewDLSOInvoker("", "") == nil {
+        want = "test: runtime ok=2 fail=1 skip=1"
+        if !strings.Contains(s, "worker SKIP\n  reason: compiled workers need") { t.Fatalf("worker skip reason missing: %s", s) }
+    } else if _, err := os.Stat(filepath.Join(dir, "build", "test", "lib", "app")); err != nil {
+        t.Fatalf("workers library not built: %v", err)
+    }
+    if !strings.Contains(s, want) { t.Fatalf("summary missing or incorrect (want %q): %s", want, s) }
+    if !strings.Contains(s, "mismatch FAIL") || !strings.Contains(s, "diff:") { t.Fatalf("mismatch diff not reported: %s", s) }
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+func TestRuntimeCLI_PropertyCases(t *testing.T) {
+    dir := t.TempDir()
+    ws := workspace.DefaultWorkspace()
+    ws.Packages[0].Package.Name = "app"
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    mustWrite := func(rel, content string){
+        p := filepath.Join(dir, rel)
+        if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { t.Fatal(err) }
+        if err := os.WriteFile(p, []byte(content), 0o644); err != nil { t.Fatal(err) }
+    }
+    mustWrite("src/app.ami", `package app
+pipeline Dedup(){ ingress type("Event<Struct{id:int,v:string}>"); Collect merge.Dedup("id"), merge.Buffer(64, "block"); egress; ingress -> Collect; Collect -> egress }
+`)
+    mustWrite("src/dedup_test.ami", `package app
+#pragma test:property unique_ids pipeline=Dedup check=unique:id,no_errors runs=25 seed=3
+#pragma test:property lossless pipeline=Dedup check=no_loss runs=25 seed=3
+#pragma test:property untyped pipeline=Dedup check=no_loss type=Widget
+`)
+    var out bytes.Buffer
+    setTestOptions(TestOptions{Parallel: 1})
+    if err := runTest(&out, dir, false, false, 0); err == nil { t.Fatalf("expected failures; out=\n%s", out.String()) }
+    s := out.String()
+    if !strings.Contains(s, "test: runtime ok=1 fail=2 skip=0") { t.Fatalf("summary: %s", s) }
+    if !strings.Contains(s, "unique_ids OK") { t.Fatalf("unique_ids should pass: %s", s) }
+    if !strings.Contains(s, "(seed=3): no_loss") || !strings.Contains(s, "counterexample (2 events") { t.Fatalf("shrunk counterexample missing: %s", s) }
+    if !strings.Contains(s, "cannot generate values of type Widget") { t.Fatalf("type error missing: %s", s) }
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+func TestRuntimeCLI_SnapshotRecordAndDiff(t *testing.T) {
+    dir := t.TempDir()
+    ws := workspace.DefaultWorkspace()
+    ws.Packages[0].Package.Name = "app"
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    src := filepath.Join(dir, "src")
+    if err := os.MkdirAll(src, 0o755); err != nil { t.Fatal(err) }
+    code := `package app
+pipeline Dedup(){ ingress; Collect merge.Dedup("id"), merge.Buffer(16, "block"); egress; ingress -> Collect; Collect -> egress }
+`
+    if err := os.WriteFile(filepath.Join(src, "app.ami"), []byte(code), 0o644); err != nil { t.Fatal(err) }
+    tc := `package app
+#pragma test:case dedup
+#pragma test:runtime pipeline=Dedup snapshot=dedup input=[{"id":1,"v":"a"},{"id":1,"v":"b"},{"id":2,"v":"c"}]
+`
+    if err := os.WriteFile(filepath.Join(src, "dedup_test.ami"), []byte(tc), 0o644); err != nil { t.Fatal(err) }
+    golden := filepath.Join(src, "testdata", "dedup.snap.json")
+    run := func(update bool) (string, error) {
+        var out bytes.Buffer
+        setTestOptions(TestOptions{Parallel: 1, Update: update})
+        defer setTestOptions(TestOptions{})
+        err := runTest(&out, dir, false, false, 0)
+        return out.String(), err
+    }
+    // missing golden fails
+    if s, err := run(false); err == nil || !strings.Contains(s, "--update") { t.Fatalf("missing snapshot must fail: %v\n%s", err, s) }
+    // --update records it
+    if s, err := run(true); err != nil { t.Fatalf("update: %v\n%s", err, s) }
+    b, err := os.ReadFile(golden)
+    if err != nil { t.Fatalf("golden not written: %v", err) }
+    if !strings.Contains(string(b), `"schema": "test.snapshot.v1"`) || !strings.Contains(string(b), `"v": "c"`) { t.Fatalf("golden content:\n%s", b) }
+    // rerun matches
+    if s, err := run(false); err != nil { t.Fatalf("rerun: %v\n%s", err, s) }
+    // edited golden reports a structural diff
+    if err := os.WriteFile(golden, bytes.Replace(b, []byte(`"v": "c"`), []byte(`"v": "z"`), 1), 0o644); err != nil { t.Fatal(err) }
+    s, err := run(false)
+    if err == nil { t.Fatalf("expected mismatch failure") }
+    if !strings.Contains(s, `events[1].payload.v: got "c", want "z"`) { t.Fatalf("diff missing:\n%s", s) }
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/sam-caldwell/ami/src/ami/workspace"
+)
+
+func TestRuntimeCLI_VirtualTimeCases(t *testing.T) {
+    dir := t.TempDir()
+    ws := workspace.DefaultWorkspace()
+    ws.Packages[0].Package.Name = "app"
+    if err := ws.Save(filepath.Join(dir, "ami.workspace")); err != nil { t.Fatalf("save ws: %v", err) }
+    src := filepath.Join(dir, "src")
+    if err := os.MkdirAll(src, 0o755); err != nil { t.Fatal(err) }
+    code := `package app
+pipeline Dedup(){ ingress; Collect merge.Dedup("id"), merge.Buffer(16, "block"); egress; ingress -> Collect; Collect -> egress }
+`
+    if err := os.WriteFile(filepath.Join(src, "app.ami"), []byte(code), 0o644); err != nil { t.Fatal(err) }
+    tc := `package app
+#pragma test:case advanced
+#pragma test:runtime pipeline=Dedup advance=90s input=[{"id":1},{"id":1},{"id":2}] output=[{"id":1},{"id":2}]
+`
+    if err := os.WriteFile(filepath.Join(src, "advance_test.ami"), []byte(tc), 0o644); err != nil { t.Fatal(err) }
+    cases, err := parseRuntimeCases(dir)
+    if err != nil || len(cases) != 1 { t.Fatalf("parse: %v (%d cases)", err, len(cases)) }
+    if cases[0].Spec.Advance != 90*time.Second { t.Fatalf("advance=%v", cases[0].Spec.Advance) }
+    if !usesVirtualTime(cases) { t.Fatalf("advance= should select a virtual clock") }
+    if c := caseClock(cases[0]); c == nil || !c.Now().Equal(virtualEpoch) { t.Fatalf("case clock should start at the virtual epoch") }
+    for _, vt := range []bool{false, true} {
+        var out bytes.Buffer
+        setTestOptions(TestOptions{Parallel: 4, VirtualTime: vt, TimeoutMs: 5000})
+        err := runTest(&out, dir, false, false, 0)
+        setTestOptions(TestOptions{})
+        if err != nil || !strings.Contains(out.String(), "test: runtime ok=1 fail=0 skip=0") {
+            t.Fatalf("virtual-time=%v: %v\n%s", vt, err, out.String())
+        }
+    }
+}